@@ -3,6 +3,8 @@
 
 package constants
 
+import "time"
+
 const (
 	// Bot related constants
 	BotUserName    = "servicenow"
@@ -28,6 +30,19 @@ const (
 	SysQueryParamFields                       = "sysparm_fields"
 	SysQueryParamDisplayValue                 = "sysparm_display_value"
 	SysQueryParamText                         = "sysparm_text"
+	SysQueryParamExcludeReferenceLink         = "sysparm_exclude_reference_link"
+	OnCallQueryParamGroupIDs                  = "group_ids"
+	OnCallQueryParamDateTime                  = "date_time"
+
+	// ServiceNow returns the date-time fields in UTC in this layout when the display values are not requested
+	ServiceNowDateTimeLayout = "2006-01-02 15:04:05"
+
+	// Version of the application installed in ServiceNow by the update set shipped with the plugin
+	ServiceNowForMattermostNotificationsAppVersion = "1.1.0"
+
+	// States of the change requests in ServiceNow
+	ChangeStateScheduled = "-2"
+	ChangeStateImplement = "-1"
 
 	UpdateSetNotUploadedMessage = "it looks like the notifications have not been configured in ServiceNow by uploading and committing the update set."
 
@@ -40,24 +55,137 @@ const (
 	RecordTypeTask                   = "task"
 	RecordTypeChangeTask             = "change_task"
 	RecordTypeFollowOnTask           = "cert_follow_on_task"
+	RecordTypeApproval               = "sysapproval_approver"
+	RecordTypeTaskSLA                = "task_sla"
+	RecordTypeGroupMember            = "sys_user_grmember"
+	RecordTypeGroup                  = "sys_user_group"
+	RecordTypeUser                   = "sys_user"
+	RecordTypeDictionary             = "sys_dictionary"
+	RecordTypeRequest                = "sc_request"
+	RecordTypeRequestItem            = "sc_req_item"
+	RecordTypeConfigurationItem      = "cmdb_ci"
 	SubscriptionEventPriority        = "priority"
 	SubscriptionEventState           = "state"
 	SubscriptionEventCommented       = "commented"
 	SubscriptionEventAssignedTo      = "assigned_to"
 	SubscriptionEventAssignmentGroup = "assignment_group"
 	SubscriptionEventCreated         = "created"
-	BulkSubscription                 = "Bulk"
+	SubscriptionEventStage           = "stage"
+	SubscriptionEventSLA             = "sla"
+
+	// SubscriptionEventDiagnostic is sent by the diagnostics to the Site URL for checking that it is routed to the plugin. It is never posted.
+	SubscriptionEventDiagnostic = "diagnostic"
+
+	BulkSubscription = "Bulk"
+
+	// Personal subscription events
+	PersonalSubscriptionEventAssignedToMe       = "assigned_to_me"
+	PersonalSubscriptionEventAssignedToMyGroups = "assigned_to_my_groups"
+	PersonalSubscriptionEventCommentedOnMine    = "commented_on_my_records"
+	PersonalSubscriptionEventApprovals          = "approvals"
+	PersonalSubscriptionEventMyRequests         = "my_requests"
+	PersonalNotificationDedupeSeconds           = 10
+	SLAWarningDedupeSeconds                     = 30 * 24 * 60 * 60
+
+	// Mention settings of the subscriptions
+	MentionSettingOff              = "off"
+	MentionSettingAssignee         = "assignee"
+	MentionSettingAssigneeAndGroup = "assignee_and_group"
+	MaxGroupMentions               = 25
+
+	// Service account modes
+	ServiceAccountModeDisabled          = "disabled"
+	ServiceAccountModeClientCredentials = "client_credentials"
+	ServiceAccountModeAdminToken        = "admin_token"
+
+	// Formats of the exported subscriptions
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+
+	// Statuses of the imported subscriptions
+	ImportStatusReady     = "ready"
+	ImportStatusCreated   = "created"
+	ImportStatusDuplicate = "duplicate"
+	ImportStatusFailed    = "failed"
+
+	// Actions which can be applied on many subscriptions at once
+	BulkActionDelete     = "delete"
+	BulkActionDeactivate = "deactivate"
+	BulkActionReactivate = "reactivate"
+	BulkActionEditEvents = "edit_events"
+
+	// Statuses of the subscriptions updated in bulk
+	BulkStatusSucceeded = "succeeded"
+	BulkStatusFailed    = "failed"
 
 	// Filters
 	FilterCreatedByMe     = "me"
 	FilterCreatedByAnyone = "anyone"
 	FilterAllChannels     = "all_channels"
+	FlagPage              = "--page"
+	FlagRecordType        = "--record-type"
+	FlagType              = "--type"
+	FlagEvent             = "--event"
+	FlagCSV               = "--csv"
+	FlagChannel           = "--channel"
+	FlagTeam              = "--team"
+	FlagMap               = "--map"
+	FlagApply             = "--apply"
+	FlagAll               = "--all"
+	FlagSummary           = "--summary"
+	FlagUrgency           = "--urgency"
+	FlagGroup             = "--group"
+	FlagCaller            = "--caller"
+	FlagDescription       = "--description"
+	FlagCI                = "--ci"
+	FlagWindow            = "--window"
+	FlagInstance          = "--instance"
+	FilterTypeRecord      = "record"
+	FilterTypeBulk        = "bulk"
 
 	// Used for storing the token in the request context to pass from one middleware to another
 	// #nosec G101 -- This is a false positive. The below line is not a hardcoded credential
 	ContextTokenKey ServiceNowOAuthToken = "ServiceNow-Oauth-Token"
 
+	// Used for storing the ID of the instance which sent a webhook request
+	ContextInstanceKey ServiceNowInstance = "ServiceNow-Instance"
+
+	// DefaultInstanceID is the ID of the instance configured by the ServiceNow settings of the plugin
+	DefaultInstanceID  = "default"
+	QueryParamInstance = "instance"
+	InstanceIDRegex    = "^[a-z0-9-]+$"
+
 	DefaultPage                                = 0
+	SubscriptionsWorkerPoolSize                = 10
+	MaxRecordsPerBatch                         = 100
+	MaxLookupCacheEntries                      = 5000
+	LookupCacheStatesTTL                       = time.Hour
+	LookupCacheCatalogItemsTTL                 = 10 * time.Minute
+	LookupCacheGroupsTTL                       = 10 * time.Minute
+	LookupCacheRecordTTL                       = 2 * time.Minute
+	MaxMuteDuration                            = 7 * 24 * time.Hour
+	MuteSummaryJobInterval                     = time.Minute
+	SLAWarningJobInterval                      = 5 * time.Minute
+	MaxSLAsPerBatch                            = 500
+	MaxMutedEventsInSummary                    = 50
+	MaxCommentsLengthInDialog                  = 3000
+	MaxRecordLinkPreviews                      = 3
+	MaxCatalogItemsInSearch                    = 10
+	MaxCatalogReferenceMatches                 = 2
+	MaxRequestItemsInList                      = 25
+	MaxKnowledgeArticlesInSearch               = 5
+	MaxKnowledgeArticleLength                  = 12000
+	KnowledgeSnippetLength                     = 240
+	MaxConfigurationItemsInSearch              = 10
+	MaxConfigurationItemRecords                = 10
+	MaxKnowledgeArticleSuggestions             = 3
+	MaxScheduledChanges                        = 50
+	OnCallNextLookupDays                       = 7
+	OnCallNextHourlyLookupHours                = 24
+	DefaultChangeWindow                        = 7 * 24 * time.Hour
+	MaxChangeWindow                            = 90 * 24 * time.Hour
+	SubscriptionsCSVFileName                   = "subscriptions.csv"
+	SubscriptionsExportFileName                = "subscriptions_export.%s"
 	DefaultPerPage                             = 20
 	MaxPerPage                                 = 100
 	CharacterThresholdForSearchingRecords      = 3
@@ -68,6 +196,8 @@ const (
 	QueryParamUserID                           = "user_id"
 	QueryParamSubscriptionType                 = "subscription_type"
 	QueryParamSearchTerm                       = "search"
+	QueryParamTeamID                           = "team_id"
+	QueryParamFormat                           = "format"
 	PathParamSubscriptionID                    = "subscription_id"
 	PathParamTeamID                            = "team_id"
 	PathParamRecordType                        = "record_type"
@@ -83,6 +213,63 @@ const (
 	FieldAssignmentGroup      = "assignment_group"
 	FieldKnowledgeBase        = "knowledge_base"
 	FieldCategory             = "category"
+	FieldCloseCode            = "close_code"
+	FieldCloseNotes           = "close_notes"
+	FieldGroup                = "group"
+	FieldUser                 = "user"
+	FieldName                 = "name"
+	FieldStage                = "stage"
+	FieldState                = "state"
+	FieldRequest              = "request"
+	FieldRequestedFor         = "requested_for"
+	FieldDueDate              = "due_date"
+	FieldApproval             = "approval"
+	FieldActive               = "active"
+	FieldOpenedAt             = "opened_at"
+	FieldText                 = "text"
+	FieldScope                = "scope"
+	FieldVersion              = "version"
+	FieldWorkflowState        = "workflow_state"
+	FieldKnowledgeBaseRef     = "kb_knowledge_base"
+	FieldKnowledgeCategoryRef = "kb_category"
+	FieldClassName            = "sys_class_name"
+	FieldOwnedBy              = "owned_by"
+	FieldSupportGroup         = "support_group"
+	FieldOperationalStatus    = "operational_status"
+	FieldEnvironment          = "environment"
+	FieldConfigurationItem    = "cmdb_ci"
+	FieldPriority             = "priority"
+	FieldStartDate            = "start_date"
+	FieldEndDate              = "end_date"
+	FieldConfigurationItemRef = "cmdb_ci.name"
+	FieldEmail                = "email"
+	FieldTask                 = "task"
+	FieldSLA                  = "sla"
+	FieldBusinessPercentage   = "business_percentage"
+	FieldBusinessTimeLeft     = "business_time_left"
+	FieldHasBreached          = "has_breached"
+	FieldElement              = "element"
+	FieldDisplay              = "display"
+
+	// Incident states
+	IncidentStateResolved = "6"
+	IncidentStateClosed   = "7"
+
+	// Knowledge articles
+	KnowledgeWorkflowStatePublished = "published"
+	KnowledgeActionView             = "view"
+	KnowledgeActionShare            = "share"
+	TextQueryOperator               = "123TEXTQUERY321"
+
+	// Configuration items
+	ConfigurationItemActionShare     = "share"
+	ConfigurationItemActionRecords   = "records"
+	ConfigurationItemActionSubscribe = "subscribe"
+
+	// Approval states
+	ApprovalStateRequested = "requested"
+	ApprovalStateApproved  = "approved"
+	ApprovalStateRejected  = "rejected"
 
 	// Websocket events
 	WSEventConnect                        = "connect"
@@ -91,9 +278,6 @@ const (
 	WSEventOpenEditSubscriptionModal      = "edit_subscription"
 	WSEventSubscriptionDeleted            = "subscription_deleted"
 	WSEventOpenSearchAndShareRecordsModal = "search_and_share_record"
-	WSEventOpenCommentModal               = "comment_modal"
-	WSEventOpenUpdateStateModal           = "update_state"
-	WSEventOpenCreateIncidentModal        = "create_incident"
 
 	// API Errors
 	APIErrorIDNotConnected               = "not_connected"
@@ -110,10 +294,46 @@ const (
 	APIErrorRefreshTokenExpired          = "Your connection with ServiceNow has expired. Please reconnect your account."
 	APIErrorCreateIncident               = "Error in creating the incident"
 	APIErrorSearchingCatalogItems        = "Error in searching for catalog items in ServiceNow"
+	APIErrorIDMissingRequiredFields      = "missing_required_fields"
+	APIErrorMissingRequiredFields        = "Some fields are required for moving the record to the selected state."
+	APIErrorApprovalNotAllowed           = "You are not the approver of this record."
+	APIErrorIDInvalidPersonalEvent       = "invalid_personal_subscription_event"
 
 	// Slack attachment context constants
 	ContextNameRecordType = "record_type"
 	ContextNameRecordID   = "record_id"
+	ContextNameApprovalID = "approval_id"
+	ContextNameAction     = "action"
+	ContextNameNumber     = "number"
+	ContextNameItemID     = "item_id"
+	ContextNameArticleID  = "article_id"
+	ContextNameInstanceID = "instance_id"
+
+	// Types of the variables of the service catalog items
+	CatalogVariableTypeYesNo              = 1
+	CatalogVariableTypeMultiLineText      = 2
+	CatalogVariableTypeMultipleChoice     = 3
+	CatalogVariableTypeSelectBox          = 5
+	CatalogVariableTypeSingleLineText     = 6
+	CatalogVariableTypeCheckBox           = 7
+	CatalogVariableTypeReference          = 8
+	CatalogVariableTypeLabel              = 11
+	CatalogVariableTypeBreak              = 12
+	CatalogVariableTypeWideSingleLineText = 16
+	CatalogVariableTypeLookupSelectBox    = 18
+	CatalogVariableTypeContainerStart     = 19
+	CatalogVariableTypeContainerEnd       = 20
+	CatalogVariableTypeContainerSplit     = 24
+	CatalogVariableTypeEmail              = 26
+
+	// Interactive dialog elements
+	DialogElementComments         = "comments"
+	DialogElementState            = "state"
+	DialogElementShortDescription = "short_description"
+	DialogElementDescription      = "description"
+	DialogElementCaller           = "caller"
+	DialogElementAddToCart        = "mm_add_to_cart"
+	DialogElementResolve          = "resolve"
 
 	// Slash commands
 	CommandHelp           = "help"
@@ -128,6 +348,35 @@ const (
 	SubCommandDelete      = "delete"
 	CommandIncident       = "incident"
 	SubCommandCreate      = "create"
+	SubCommandPersonal    = "personal"
+	SubCommandMentions    = "mentions"
+	CommandAdmin          = "admin"
+	CommandCatalog        = "catalog"
+	CommandRequests       = "requests"
+	CommandKnowledge      = "kb"
+	CommandCI             = "ci"
+	CommandChanges        = "changes"
+	CommandOnCall         = "oncall"
+	CommandInstance       = "instance"
+	SubCommandDefault     = "default"
+	SubCommandUpcoming    = "upcoming"
+	SubCommandConflicts   = "conflicts"
+	SubCommandService     = "service-account"
+	SubCommandStatus      = "status"
+	SubCommandSet         = "set"
+	SubCommandUnset       = "unset"
+	SubCommandTransfer    = "transfer"
+	SubCommandReassign    = "reassign"
+	SubCommandDiagnose    = "diagnose"
+	SubCommandExport      = "export"
+	SubCommandImport      = "import"
+	SubCommandClone       = "clone"
+	SubCommandBulk        = "bulk"
+	SubCommandDeactivate  = "deactivate"
+	SubCommandReactivate  = "reactivate"
+	SubCommandEvents      = "events"
+	SubCommandMute        = "mute"
+	SubCommandUnmute      = "unmute"
 )
 
 // #nosec G101 -- This is a false positive. The below line is not a hardcoded credential
@@ -150,6 +399,7 @@ const (
 	ErrorGeneric                          = "Something went wrong."
 	ErrorGetUsers                         = "Failed to get the users."
 	ErrorEmptyShortDescription            = "short description should not be empty"
+	ErrorCallerNotConnected               = "The caller has not connected their ServiceNow account."
 	ErrorGetBotChannel                    = "Couldn't get the bot's DM channel"
 	ErrorSearchTermThreshold              = "The search term must be at least %d characters long."
 	ErrorGetUser                          = "Unable to get the user"
@@ -165,8 +415,15 @@ const (
 	ErrorGetComments                      = "Error in getting all comments"
 	ErrorCreateComment                    = "Error in creating the comment"
 	ErrorSearchingRecord                  = "Error in searching for records in ServiceNow"
+	ErrorSearchingKnowledgeArticles       = "Error in searching for knowledge articles in ServiceNow"
+	ErrorAddWorkNote                      = "Error in adding the work note"
+	ErrorSearchingConfigurationItems      = "Error in searching for configuration items in ServiceNow"
+	ErrorGetConfigurationItemRecords      = "Error in getting the open records of the configuration item"
 	ErrorGetRecord                        = "Error in getting record from ServiceNow"
 	ErrorGetStates                        = "Error in getting the states"
+	ErrorOpenDialog                       = "Unable to open the dialog"
+	ErrorGetCatalogItem                   = "Error in getting the catalog item"
+	ErrorOrderCatalogItem                 = "Error in ordering the catalog item"
 	ErrorUpdateState                      = "Error in updating the state"
 	ErrorACLRestrictsRecordRetrieval      = "ACL restricts the record retrieval"
 	ErrorHandlingNestedFields             = "Error in handling the nested fields"
@@ -176,14 +433,136 @@ const (
 	ErrorChannelPermissionsForUser        = "unable to get the channel permissions for a user"
 	ErrorNoActiveSubscriptions            = "You don't have any active subscriptions."
 	ErrorInvalidChannelType               = "invalid channel type for performing action"
+	ErrorUpdateApproval                   = "Error in updating the approval"
+	ErrorGetMattermostUserForApprover     = "Error in getting the Mattermost user for the approver"
+	ErrorGetPersonalSubscription          = "Error in getting the personal subscription"
+	ErrorPersonalNotificationInstance     = "Personal notifications are only supported for the default ServiceNow instance"
+	ErrorStorePersonalSubscription        = "Error in storing the personal subscription"
+	ErrorGetSubscriptionSettings          = "Error in getting the settings of the subscription"
+	ErrorStoreSubscriptionSettings        = "Error in storing the settings of the subscription"
+	ErrorInvalidServiceAccountMode        = "service account mode is not valid"
+	ErrorInvalidSLAThresholds             = "SLA warning thresholds should be comma separated percentages between 1 and 100"
+	ErrorMarkSLAWarning                   = "Error in marking the SLA warning as sent"
+	ErrorGetSLAs                          = "Error in getting the SLAs of the subscribed records"
+	ErrorServiceAccountNotConfigured      = "service account is not configured"
+	ErrorServiceAccountReadOnly           = "service account can only be used for read-only calls"
+	ErrorGetServiceAccount                = "Error in getting the service account"
+	ErrorStoreServiceAccount              = "Error in storing the service account"
+	ErrorInvalidExportFormat              = "format is not valid"
+	ErrorExportSubscriptions              = "Error in exporting the subscriptions"
+	ErrorImportSubscriptions              = "Error in importing the subscriptions"
+	ErrorDuplicateInImport                = "subscription is repeated in the import"
+	ErrorTransferSubscription             = "Error in transferring the subscription"
+	ErrorBulkUpdateSubscriptions          = "Error in updating the subscriptions"
+	ErrorSubscriptionNotFound             = "subscription doesn't exist"
+	ErrorDuplicateActiveSubscription      = "an active subscription for the same record already exists in the channel"
+	ErrorInvalidMuteDuration              = "duration is not valid. Use a duration like 30m, 2h or 1d up to 7d"
+	ErrorGetMute                          = "Error in getting the muted notifications"
+	ErrorStoreMute                        = "Error in muting the notifications"
+	ErrorStoreMutedEvent                  = "Error in storing the muted notification for the summary"
+	ErrorGetUserByUsername                = "Error in getting the user by username"
+	ErrorInvalidChangeWindow              = "window is not valid. Use a window like 12h or 7d up to 90d"
+	ErrorGetScheduledChanges              = "Error in getting the scheduled changes"
+	ErrorGetOnCall                        = "Error in getting the on-call users"
+	ErrorInvalidInstances                 = "additional ServiceNow instances should be a JSON array of instances"
+	ErrorInvalidInstance                  = "additional ServiceNow instance %q should have a unique ID of lowercase letters, digits and dashes, a base URL, OAuth client ID and secret and a webhook secret"
+	ErrorUnknownInstance                  = "ServiceNow instance doesn't exist"
+	ErrorGetChannelInstance               = "Error in getting the default ServiceNow instance of the channel"
+	ErrorStoreApprovalPost                = "Error in storing the approval post"
+	ErrorGetApprovalPost                  = "Error in getting the approval post"
 )
 
 // kv store keys prefix
 const (
-	UserKeyPrefix          = "user_"
-	OAuth2KeyPrefix        = "oauth2_"
-	DeleteAllUsersMutexKey = "delete_all_users_mutex"
-	DeleteAllUsersKey      = "delete_all_users"
+	UserKeyPrefix                 = "user_"
+	OAuth2KeyPrefix               = "oauth2_"
+	ServiceNowUserIDKeyPrefix     = "snu_"
+	ServiceNowUsernameKeyPrefix   = "snun_"
+	PersonalSubscriptionKeyPrefix = "psub_"
+	PersonalNotificationKeyPrefix = "pnotif_"
+	SubscriptionSettingsKeyPrefix = "subset_"
+	LookupCacheKeyPrefix          = "lookup_"
+	MuteKeyPrefix                 = "mute_"
+	SLAWarningKeyPrefix           = "slawarn_"
+	MutedEventsKeyPrefix          = "mutedev_"
+	InstanceUserKeyPrefix         = "instuser_"
+	InstanceUserIndexKeyPrefix    = "instsnu_"
+	ChannelInstanceKeyPrefix      = "chinst_"
+	ApprovalPostKeyPrefix         = "apprpost_"
+	DeleteAllUsersMutexKey        = "delete_all_users_mutex"
+	DeleteAllUsersKey             = "delete_all_users"
+	UserIndexesMigrationKey       = "migration_user_indexes_v2"
+	UserIndexesMigrationMutexKey  = "migration_user_indexes_mutex"
+	ServiceAccountKey             = "service_account"
+	ConnectedUsersIndexKey        = "connected_users_index"
+	ConnectedUsersMutexKey        = "connected_users_mutex"
+	MutesWithSummaryIndexKey      = "mutes_with_summary_index"
+	MutesWithSummaryMutexKey      = "mutes_with_summary_mutex"
+	MutedEventsMutexKey           = "muted_events_mutex"
+	MuteSummaryJobKey             = "mute_summary_job"
+	SLAWarningJobKey              = "sla_warning_job"
+)
+
+// Messages and limits of the slash commands
+const (
+	// Service catalog
+	CatalogOrderQuantity             = "1"
+	CatalogItemNotFoundMessage       = "No catalog items found matching %q."
+	CatalogUnsupportedItemMessage    = "The catalog item **%s** has fields which can't be filled from Mattermost. [Order it in ServiceNow](%s)."
+	CatalogAddedToCartMessage        = "**%s** has been added to your cart."
+	CatalogCartName                  = "Your cart"
+	CatalogMaxTextLength             = 150
+	CatalogMaxTextAreaLength         = 3000
+	CatalogDialogElementYes          = "true"
+	CatalogDialogElementNo           = "false"
+	CatalogDialogElementOptionYes    = "Yes"
+	CatalogDialogElementOptionNo     = "No"
+	CatalogDialogAddToCartHelpText   = "Add the item to your cart instead of ordering it now. The cart can be submitted from the message sent to you by the bot."
+	CatalogUserReferenceHelpText     = "Only the users who have connected their ServiceNow account can be selected."
+	CatalogReferenceHelpText         = "Enter the name of the record as shown in ServiceNow. It is looked up when the order is submitted."
+	CatalogUserNotConnectedMessage   = "The user has not connected their ServiceNow account."
+	CatalogReferenceNotFoundMessage  = "No record named %q was found in ServiceNow."
+	CatalogReferenceAmbiguousMessage = "Several records are named %q in ServiceNow. Order the item in ServiceNow to pick one of them."
+	CatalogReferenceScriptPrefix     = "javascript:"
+
+	// Requests
+	NoOpenRequestsMessage   = "You don't have any open requests."
+	RequestsSubscribeFooter = "\n\nTo get a DM when the stage of your requested items changes, run `/servicenow subscriptions personal add %s`."
+
+	// Knowledge articles
+	KnowledgeArticleNotFoundMessage = "No knowledge articles found matching %q."
+	KnowledgeArticleSharedMessage   = "The article has been shared in the channel."
+
+	// Configuration items
+	ConfigurationItemNotFoundMessage  = "No configuration items found matching %q."
+	ConfigurationItemSharedMessage    = "The configuration item has been shared in the channel."
+	ConfigurationItemDuplicateMessage = "This channel is already subscribed to the incidents of this configuration item."
+
+	// Change requests
+	NoScheduledChangesMessage   = "No changes are scheduled in the next %s."
+	NoConflictingChangesMessage = "No changes of %s are scheduled in the next %s."
+	ScheduledChangesLimitNote   = "\n\nOnly the first %d changes are shown. Use a smaller window to see the rest."
+
+	// On-call
+	OnCallGroupRequiredMessage  = "Assignment group is required. Run `/servicenow oncall [assignment group]`."
+	OnCallGroupNotFoundMessage  = "Group %s doesn't exist in ServiceNow."
+	IncidentNotAssignedMessage  = "The incident is not assigned to any group."
+	NoOneOnCallMessage          = "No one from %s is on call right now."
+	OnCallPagedMessage          = "Paged the users on call for %s: %s."
+	OnCallNotPagedMessage       = "These users on call for %s couldn't be paged on Mattermost: %s."
+	OnCallPageChannelMessage    = "%s, you are being paged by @%s for the incident [%s](%s): %s"
+	OnCallPageDirectMessage     = "You are being paged by @%s for the incident [%s](%s): %s"
+	OnCallNextTimeLayout        = "Jan 2 15:04"
+	OnCallEscalationLevelSuffix = " (escalation level %d)"
+
+	// ServiceNow instances
+	UnknownInstanceMessage           = "ServiceNow instance `%s` doesn't exist. Run `/servicenow instance list` to see the available instances."
+	MissingInstanceMessage           = "Missing value for `--instance`. Run `/servicenow instance list` to see the available instances."
+	InstanceNotSupportedMessage      = "The command `%s` can only be run on the default ServiceNow instance."
+	InstanceModalNotSupportedMessage = "Subscriptions can only be added or edited from the modal on the default ServiceNow instance."
+	InstanceDefaultSetMessage        = "`%s` is now the default ServiceNow instance of this channel."
+	InstanceNotConnectedMessage      = "You are not connected to the ServiceNow instance `%s`.\n[Click here to link your ServiceNow account.](%s%s?%s=%s)"
+	InstanceDisconnectedMessage      = "Disconnected your ServiceNow account of the instance `%s`."
 )
 
 var (
@@ -196,16 +575,20 @@ var (
 		RecordTypeIncident:      true,
 		RecordTypeProblem:       true,
 		RecordTypeChangeRequest: true,
+		RecordTypeRequestItem:   true,
 	}
 
 	ValidRecordTypesForSearching = map[string]bool{
-		RecordTypeIncident:      true,
-		RecordTypeProblem:       true,
-		RecordTypeChangeRequest: true,
-		RecordTypeKnowledge:     true,
-		RecordTypeTask:          true,
-		RecordTypeChangeTask:    true,
-		RecordTypeFollowOnTask:  true,
+		RecordTypeIncident:          true,
+		RecordTypeProblem:           true,
+		RecordTypeChangeRequest:     true,
+		RecordTypeKnowledge:         true,
+		RecordTypeTask:              true,
+		RecordTypeChangeTask:        true,
+		RecordTypeFollowOnTask:      true,
+		RecordTypeRequest:           true,
+		RecordTypeRequestItem:       true,
+		RecordTypeConfigurationItem: true,
 	}
 
 	ValidSubscriptionEvents = map[string]bool{
@@ -215,6 +598,7 @@ var (
 		SubscriptionEventCommented:       true,
 		SubscriptionEventAssignedTo:      true,
 		SubscriptionEventAssignmentGroup: true,
+		SubscriptionEventStage:           true,
 	}
 
 	FormattedEventNames = map[string]string{
@@ -224,12 +608,73 @@ var (
 		SubscriptionEventCommented:       "New comment",
 		SubscriptionEventAssignedTo:      "Assigned to changed",
 		SubscriptionEventAssignmentGroup: "Assignment group changed",
+		SubscriptionEventStage:           "Stage changed",
+		SubscriptionEventSLA:             "SLA warning",
+	}
+
+	ValidPersonalSubscriptionEvents = map[string]bool{
+		PersonalSubscriptionEventAssignedToMe:       true,
+		PersonalSubscriptionEventAssignedToMyGroups: true,
+		PersonalSubscriptionEventCommentedOnMine:    true,
+		PersonalSubscriptionEventApprovals:          true,
+		PersonalSubscriptionEventMyRequests:         true,
+	}
+
+	SubscriptionTypesForFilters = map[string]string{
+		FilterTypeRecord: SubscriptionTypeRecord,
+		FilterTypeBulk:   SubscriptionTypeBulk,
+	}
+
+	ValidExportFormats = map[string]bool{
+		ExportFormatJSON: true,
+		ExportFormatCSV:  true,
+	}
+
+	// InstanceCommands are the commands which can be run on the ServiceNow instances other than the default one
+	InstanceCommands = map[string]bool{
+		CommandConnect:       true,
+		CommandDisconnect:    true,
+		CommandSubscriptions: true,
+		CommandUnsubscribe:   true,
+		CommandRequests:      true,
+		CommandChanges:       true,
+		CommandOnCall:        true,
+	}
+
+	ValidBulkActions = map[string]bool{
+		BulkActionDelete:     true,
+		BulkActionDeactivate: true,
+		BulkActionReactivate: true,
+		BulkActionEditEvents: true,
+	}
+
+	ValidServiceAccountModes = map[string]bool{
+		ServiceAccountModeDisabled:          true,
+		ServiceAccountModeClientCredentials: true,
+		ServiceAccountModeAdminToken:        true,
+	}
+
+	ValidMentionSettings = map[string]bool{
+		MentionSettingOff:              true,
+		MentionSettingAssignee:         true,
+		MentionSettingAssigneeAndGroup: true,
+	}
+
+	FormattedPersonalSubscriptionEvents = map[string]string{
+		PersonalSubscriptionEventAssignedToMe:       "Records assigned to me",
+		PersonalSubscriptionEventAssignedToMyGroups: "Records assigned to my groups",
+		PersonalSubscriptionEventCommentedOnMine:    "New comments on records opened by me",
+		PersonalSubscriptionEventApprovals:          "Approvals requested from me",
+		PersonalSubscriptionEventMyRequests:         "Stage changes of the items requested for me",
 	}
 
 	FormattedRecordTypes = map[string]string{
-		RecordTypeProblem:       "Problem",
-		RecordTypeIncident:      "Incident",
-		RecordTypeChangeRequest: "Change Request",
+		RecordTypeProblem:           "Problem",
+		RecordTypeIncident:          "Incident",
+		RecordTypeChangeRequest:     "Change Request",
+		RecordTypeRequest:           "Request",
+		RecordTypeRequestItem:       "Requested Item",
+		RecordTypeConfigurationItem: "Configuration Item",
 	}
 
 	RecordTypesSupportingComments = map[string]bool{
@@ -239,6 +684,8 @@ var (
 		RecordTypeTask:          true,
 		RecordTypeChangeTask:    true,
 		RecordTypeFollowOnTask:  true,
+		RecordTypeRequest:       true,
+		RecordTypeRequestItem:   true,
 	}
 
 	RecordTypesSupportingStateUpdation = map[string]bool{
@@ -247,6 +694,35 @@ var (
 		RecordTypeChangeTask:   true,
 		RecordTypeFollowOnTask: true,
 	}
+
+	// StateTransitionRequiredFields maps a record type and a target state to the fields that ServiceNow makes mandatory for that transition
+	StateTransitionRequiredFields = map[string]map[string][]string{
+		RecordTypeIncident: {
+			IncidentStateResolved: {FieldCloseCode, FieldCloseNotes},
+			IncidentStateClosed:   {FieldCloseCode, FieldCloseNotes},
+		},
+	}
+
+	// ValidIncidentUrgencies contains the urgencies of an incident in ServiceNow, where 1 is high and 3 is low
+	ValidIncidentUrgencies = map[string]bool{
+		"1": true,
+		"2": true,
+		"3": true,
+	}
+
+	// FormattedFieldLabels maps the ServiceNow field names to the labels used by ServiceNow while reporting errors for them
+	FormattedFieldLabels = map[string]string{
+		FieldCloseCode:  "Resolution code",
+		FieldCloseNotes: "Resolution notes",
+	}
+
+	// FormattedChangeStates contains the labels of the states of the scheduled change requests
+	FormattedChangeStates = map[string]string{
+		ChangeStateScheduled: "Scheduled",
+		ChangeStateImplement: "Implement",
+	}
 )
 
 type ServiceNowOAuthToken string
+
+type ServiceNowInstance string
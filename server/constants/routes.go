@@ -11,10 +11,16 @@ const (
 	PathGetAllSubscriptions    = PathCreateSubscription
 	PathDeleteSubscription     = PathCreateSubscription + "/{subscription_id:" + ServiceNowSysIDRegex + "}"
 	PathEditSubscription       = PathDeleteSubscription
+	PathExportSubscriptions    = PathCreateSubscription + "/export"
+	PathImportSubscriptions    = PathCreateSubscription + "/import"
+	PathCloneSubscriptions     = PathCreateSubscription + "/clone"
+	PathBulkSubscriptions      = PathCreateSubscription + "/bulk"
 	PathGetUserChannelsForTeam = "/channels/{team_id:[A-Za-z0-9]+}"
 	PathSearchRecords          = "/records/{record_type}"
 	PathGetSingleRecord        = "/records/{record_type}/{record_id:" + ServiceNowSysIDRegex + "}"
 	PathProcessNotification    = "/notification"
+	PathProcessApproval        = PathProcessNotification + "/approval"
+	PathProcessPersonal        = PathProcessNotification + "/personal"
 	PathGetConnected           = "/connected"
 	PathGetConfig              = "/config"
 	PathShareRecord            = "/share/{channel_id:[A-Za-z0-9]+}"
@@ -26,6 +32,21 @@ const (
 	PathSearchCatalogItems     = "/catalog"
 	PathGetUsers               = "/users"
 	PathCreateIncident         = "/incident"
+	PathApprovalAction         = "/approval-action"
+	PathApprovalRejectDialog   = "/approval-reject-dialog"
+	PathCommentDialog          = "/comment-dialog"
+	PathStateDialog            = "/state-dialog"
+	PathIncidentDialog         = "/incident-dialog"
+	PathCatalogOrderAction     = "/catalog-order"
+	PathCatalogOrderDialog     = "/catalog-order-dialog"
+	PathCatalogSubmitCart      = "/catalog-submit-cart"
+	PathKnowledgeArticleAction = "/kb-article"
+	PathKnowledgeSolvedAction  = "/kb-solved"
+	PathKnowledgeSolvedDialog  = "/kb-solved-dialog"
+	PathConfigItemAction       = "/ci"
+	PathPageOnCallAction       = "/page-oncall"
+	PathPersonalSubscriptions  = "/personal-subscriptions"
+	PathDiagnostics            = "/diagnostics"
 
 	// ServiceNow API paths
 	PathActivateSubscriptions         = "api/now/table/" + ServiceNowForMattermostNotificationsAppID + "_servicenow_for_mattermost_notifications_auth"
@@ -33,7 +54,13 @@ const (
 	PathGetRecordsFromServiceNow      = "api/now/table/{tableName}"
 	PathGetStatesFromServiceNow       = "api/" + ServiceNowForMattermostNotificationsAppID + "/getstates/{record_type}"
 	PathGetCatalogItemsFromServiceNow = "api/sn_sc/servicecatalog/items"
+	PathGetCatalogItemFromServiceNow  = PathGetCatalogItemsFromServiceNow + "/{sys_id}"
+	PathOrderNowInServiceNow          = PathGetCatalogItemFromServiceNow + "/order_now"
+	PathAddToCartInServiceNow         = PathGetCatalogItemFromServiceNow + "/add_to_cart"
+	PathSubmitOrderInServiceNow       = "api/sn_sc/servicecatalog/cart/submit_order"
 	PathGetUserFromServiceNow         = "/api/now/table/sys_user"
+	PathGetOnCallFromServiceNow       = "api/now/on_call_rota/whoisoncall"
+	PathGetAppFromServiceNow          = "api/now/table/sys_app"
 
 	// ServiceNow URLs
 	PathServiceNowURL = "/now/nav/ui/classic/params/target"
@@ -41,6 +68,7 @@ const (
 	PathSysUserGroup  = "/sys_user_group.do?sys_id=%s"
 	PathKnowledgeBase = PathServiceNowURL + "/kb_knowledge_base.do%%3Fsys_id=%s"
 	PathCategory      = PathServiceNowURL + "/kb_category.do%%3Fsys_id=%s"
+	PathCatalogItem   = "%s/sp?id=sc_cat_item&sys_id=%s"
 	PathRecordList    = "%s/nav_to.do?uri=%s_list.do%%3Fsysparm_query=active=true"
 	PathRecord        = "%s/nav_to.do?uri=%s.do%%3Fsys_id=%s%%26sysparm_stack=%s_list.do%%3Fsysparm_query=active=true"
 )
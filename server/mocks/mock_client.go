@@ -9,6 +9,8 @@ import (
 
 	serializer "github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
 
+	time "time"
+
 	testing "testing"
 )
 
@@ -38,6 +40,36 @@ func (_m *Client) ActivateSubscriptions() (int, error) {
 	return r0, r1
 }
 
+// AddCatalogItemToCartInServiceNow provides a mock function with given fields: itemID, payload
+func (_m *Client) AddCatalogItemToCartInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCart, int, error) {
+	ret := _m.Called(itemID, payload)
+
+	var r0 *serializer.ServiceNowCart
+	if rf, ok := ret.Get(0).(func(string, *serializer.ServiceNowCatalogOrderPayload) *serializer.ServiceNowCart); ok {
+		r0 = rf(itemID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceNowCart)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, *serializer.ServiceNowCatalogOrderPayload) int); ok {
+		r1 = rf(itemID, payload)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, *serializer.ServiceNowCatalogOrderPayload) error); ok {
+		r2 = rf(itemID, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // AddComment provides a mock function with given fields: recordType, recordID, payload
 func (_m *Client) AddComment(recordType string, recordID string, payload *serializer.ServiceNowCommentPayload) (int, error) {
 	ret := _m.Called(recordType, recordID, payload)
@@ -59,6 +91,27 @@ func (_m *Client) AddComment(recordType string, recordID string, payload *serial
 	return r0, r1
 }
 
+// AddWorkNote provides a mock function with given fields: recordType, recordID, payload
+func (_m *Client) AddWorkNote(recordType string, recordID string, payload *serializer.ServiceNowWorkNotePayload) (int, error) {
+	ret := _m.Called(recordType, recordID, payload)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, string, *serializer.ServiceNowWorkNotePayload) int); ok {
+		r0 = rf(recordType, recordID, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *serializer.ServiceNowWorkNotePayload) error); ok {
+		r1 = rf(recordType, recordID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CheckForDuplicateSubscription provides a mock function with given fields: _a0
 func (_m *Client) CheckForDuplicateSubscription(_a0 *serializer.SubscriptionPayload) (bool, int, error) {
 	ret := _m.Called(_a0)
@@ -198,6 +251,36 @@ func (_m *Client) EditSubscription(subscriptionID string, subscription *serializ
 	return r0, r1, r2
 }
 
+// GetActiveSLAsFromServiceNow provides a mock function with given fields: recordIDs
+func (_m *Client) GetActiveSLAsFromServiceNow(recordIDs []string) ([]*serializer.ServiceNowTaskSLA, int, error) {
+	ret := _m.Called(recordIDs)
+
+	var r0 []*serializer.ServiceNowTaskSLA
+	if rf, ok := ret.Get(0).(func([]string) []*serializer.ServiceNowTaskSLA); ok {
+		r0 = rf(recordIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowTaskSLA)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func([]string) int); ok {
+		r1 = rf(recordIDs)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func([]string) error); ok {
+		r2 = rf(recordIDs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAllComments provides a mock function with given fields: recordType, recordID
 func (_m *Client) GetAllComments(recordType string, recordID string) (*serializer.ServiceNowComment, int, error) {
 	ret := _m.Called(recordType, recordID)
@@ -258,6 +341,126 @@ func (_m *Client) GetAllSubscriptions(channelID string, userID string, subscript
 	return r0, r1, r2
 }
 
+// GetCatalogItemFromServiceNow provides a mock function with given fields: itemID
+func (_m *Client) GetCatalogItemFromServiceNow(itemID string) (*serializer.ServiceNowCatalogItemDetails, int, error) {
+	ret := _m.Called(itemID)
+
+	var r0 *serializer.ServiceNowCatalogItemDetails
+	if rf, ok := ret.Get(0).(func(string) *serializer.ServiceNowCatalogItemDetails); ok {
+		r0 = rf(itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceNowCatalogItemDetails)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(itemID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(itemID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetGroupByNameFromServiceNow provides a mock function with given fields: name
+func (_m *Client) GetGroupByNameFromServiceNow(name string) (*serializer.ServiceNowGroup, int, error) {
+	ret := _m.Called(name)
+
+	var r0 *serializer.ServiceNowGroup
+	if rf, ok := ret.Get(0).(func(string) *serializer.ServiceNowGroup); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceNowGroup)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(name)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetGroupMembersFromServiceNow provides a mock function with given fields: groupID
+func (_m *Client) GetGroupMembersFromServiceNow(groupID string) ([]string, int, error) {
+	ret := _m.Called(groupID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(groupID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(groupID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetInactiveSubscriptions provides a mock function with given fields: channelID, limit, offset
+func (_m *Client) GetInactiveSubscriptions(channelID string, limit string, offset string) ([]*serializer.SubscriptionResponse, int, error) {
+	ret := _m.Called(channelID, limit, offset)
+
+	var r0 []*serializer.SubscriptionResponse
+	if rf, ok := ret.Get(0).(func(string, string, string) []*serializer.SubscriptionResponse); ok {
+		r0 = rf(channelID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.SubscriptionResponse)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, string, string) int); ok {
+		r1 = rf(channelID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, string) error); ok {
+		r2 = rf(channelID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMe provides a mock function with given fields: userEmail
 func (_m *Client) GetMe(userEmail string) (*serializer.ServiceNowUser, int, error) {
 	ret := _m.Called(userEmail)
@@ -288,6 +491,124 @@ func (_m *Client) GetMe(userEmail string) (*serializer.ServiceNowUser, int, erro
 	return r0, r1, r2
 }
 
+// GetNotificationsAppVersion provides a mock function with given fields:
+func (_m *Client) GetNotificationsAppVersion() (string, int, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOnCallUsersFromServiceNow provides a mock function with given fields: groupID, at
+func (_m *Client) GetOnCallUsersFromServiceNow(groupID string, at time.Time) ([]*serializer.ServiceNowOnCallUser, int, error) {
+	ret := _m.Called(groupID, at)
+
+	var r0 []*serializer.ServiceNowOnCallUser
+	if rf, ok := ret.Get(0).(func(string, time.Time) []*serializer.ServiceNowOnCallUser); ok {
+		r0 = rf(groupID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowOnCallUser)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, time.Time) int); ok {
+		r1 = rf(groupID, at)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, time.Time) error); ok {
+		r2 = rf(groupID, at)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOpenRecordsOfConfigurationItemFromServiceNow provides a mock function with given fields: recordType, itemID
+func (_m *Client) GetOpenRecordsOfConfigurationItemFromServiceNow(recordType string, itemID string) ([]*serializer.ServiceNowRecord, int, error) {
+	ret := _m.Called(recordType, itemID)
+
+	var r0 []*serializer.ServiceNowRecord
+	if rf, ok := ret.Get(0).(func(string, string) []*serializer.ServiceNowRecord); ok {
+		r0 = rf(recordType, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowRecord)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, string) int); ok {
+		r1 = rf(recordType, itemID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(recordType, itemID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOpenRequestItemsFromServiceNow provides a mock function with given fields: requestedFor
+func (_m *Client) GetOpenRequestItemsFromServiceNow(requestedFor string) ([]*serializer.ServiceNowRequestItem, int, error) {
+	ret := _m.Called(requestedFor)
+
+	var r0 []*serializer.ServiceNowRequestItem
+	if rf, ok := ret.Get(0).(func(string) []*serializer.ServiceNowRequestItem); ok {
+		r0 = rf(requestedFor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowRequestItem)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(requestedFor)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(requestedFor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetRecordFromServiceNow provides a mock function with given fields: tableName, sysID
 func (_m *Client) GetRecordFromServiceNow(tableName string, sysID string) (*serializer.ServiceNowRecord, int, error) {
 	ret := _m.Called(tableName, sysID)
@@ -318,6 +639,126 @@ func (_m *Client) GetRecordFromServiceNow(tableName string, sysID string) (*seri
 	return r0, r1, r2
 }
 
+// GetRecordsFromServiceNow provides a mock function with given fields: tableName, sysIDs
+func (_m *Client) GetRecordsFromServiceNow(tableName string, sysIDs []string) ([]*serializer.ServiceNowPartialRecord, int, error) {
+	ret := _m.Called(tableName, sysIDs)
+
+	var r0 []*serializer.ServiceNowPartialRecord
+	if rf, ok := ret.Get(0).(func(string, []string) []*serializer.ServiceNowPartialRecord); ok {
+		r0 = rf(tableName, sysIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowPartialRecord)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, []string) int); ok {
+		r1 = rf(tableName, sysIDs)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, []string) error); ok {
+		r2 = rf(tableName, sysIDs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReferenceRecordsFromServiceNow provides a mock function with given fields: tableName, qualifier, value
+func (_m *Client) GetReferenceRecordsFromServiceNow(tableName string, qualifier string, value string) ([]*serializer.ServiceNowReferenceRecord, int, error) {
+	ret := _m.Called(tableName, qualifier, value)
+
+	var r0 []*serializer.ServiceNowReferenceRecord
+	if rf, ok := ret.Get(0).(func(string, string, string) []*serializer.ServiceNowReferenceRecord); ok {
+		r0 = rf(tableName, qualifier, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowReferenceRecord)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, string, string) int); ok {
+		r1 = rf(tableName, qualifier, value)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, string, string) error); ok {
+		r2 = rf(tableName, qualifier, value)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRequestItemsFromServiceNow provides a mock function with given fields: requestID
+func (_m *Client) GetRequestItemsFromServiceNow(requestID string) ([]*serializer.ServiceNowRequestItem, int, error) {
+	ret := _m.Called(requestID)
+
+	var r0 []*serializer.ServiceNowRequestItem
+	if rf, ok := ret.Get(0).(func(string) []*serializer.ServiceNowRequestItem); ok {
+		r0 = rf(requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowRequestItem)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(requestID)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(requestID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetScheduledChangesFromServiceNow provides a mock function with given fields: from, to, configurationItem
+func (_m *Client) GetScheduledChangesFromServiceNow(from time.Time, to time.Time, configurationItem string) ([]*serializer.ServiceNowChangeRequest, int, error) {
+	ret := _m.Called(from, to, configurationItem)
+
+	var r0 []*serializer.ServiceNowChangeRequest
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, string) []*serializer.ServiceNowChangeRequest); ok {
+		r0 = rf(from, to, configurationItem)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowChangeRequest)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, string) int); ok {
+		r1 = rf(from, to, configurationItem)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(time.Time, time.Time, string) error); ok {
+		r2 = rf(from, to, configurationItem)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetStatesFromServiceNow provides a mock function with given fields: recordType
 func (_m *Client) GetStatesFromServiceNow(recordType string) ([]*serializer.ServiceNowState, int, error) {
 	ret := _m.Called(recordType)
@@ -378,6 +819,66 @@ func (_m *Client) GetSubscription(subscriptionID string) (*serializer.Subscripti
 	return r0, r1, r2
 }
 
+// GetSubscriptionAuthDetails provides a mock function with given fields:
+func (_m *Client) GetSubscriptionAuthDetails() ([]*serializer.SubscriptionAuthPayload, int, error) {
+	ret := _m.Called()
+
+	var r0 []*serializer.SubscriptionAuthPayload
+	if rf, ok := ret.Get(0).(func() []*serializer.SubscriptionAuthPayload); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.SubscriptionAuthPayload)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// OrderCatalogItemInServiceNow provides a mock function with given fields: itemID, payload
+func (_m *Client) OrderCatalogItemInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCatalogOrder, int, error) {
+	ret := _m.Called(itemID, payload)
+
+	var r0 *serializer.ServiceNowCatalogOrder
+	if rf, ok := ret.Get(0).(func(string, *serializer.ServiceNowCatalogOrderPayload) *serializer.ServiceNowCatalogOrder); ok {
+		r0 = rf(itemID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceNowCatalogOrder)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string, *serializer.ServiceNowCatalogOrderPayload) int); ok {
+		r1 = rf(itemID, payload)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, *serializer.ServiceNowCatalogOrderPayload) error); ok {
+		r2 = rf(itemID, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SearchCatalogItemsInServiceNow provides a mock function with given fields: searchTerm, limit, offset
 func (_m *Client) SearchCatalogItemsInServiceNow(searchTerm string, limit string, offset string) ([]*serializer.ServiceNowCatalogItem, int, error) {
 	ret := _m.Called(searchTerm, limit, offset)
@@ -408,6 +909,66 @@ func (_m *Client) SearchCatalogItemsInServiceNow(searchTerm string, limit string
 	return r0, r1, r2
 }
 
+// SearchConfigurationItemsInServiceNow provides a mock function with given fields: searchTerm
+func (_m *Client) SearchConfigurationItemsInServiceNow(searchTerm string) ([]*serializer.ServiceNowConfigurationItem, int, error) {
+	ret := _m.Called(searchTerm)
+
+	var r0 []*serializer.ServiceNowConfigurationItem
+	if rf, ok := ret.Get(0).(func(string) []*serializer.ServiceNowConfigurationItem); ok {
+		r0 = rf(searchTerm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowConfigurationItem)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(searchTerm)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(searchTerm)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SearchKnowledgeArticlesInServiceNow provides a mock function with given fields: query
+func (_m *Client) SearchKnowledgeArticlesInServiceNow(query string) ([]*serializer.ServiceNowKnowledgeArticle, int, error) {
+	ret := _m.Called(query)
+
+	var r0 []*serializer.ServiceNowKnowledgeArticle
+	if rf, ok := ret.Get(0).(func(string) []*serializer.ServiceNowKnowledgeArticle); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.ServiceNowKnowledgeArticle)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(string) int); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SearchRecordsInServiceNow provides a mock function with given fields: tableName, searchTerm, limit, offset
 func (_m *Client) SearchRecordsInServiceNow(tableName string, searchTerm string, limit string, offset string) ([]*serializer.ServiceNowPartialRecord, int, error) {
 	ret := _m.Called(tableName, searchTerm, limit, offset)
@@ -438,6 +999,57 @@ func (_m *Client) SearchRecordsInServiceNow(tableName string, searchTerm string,
 	return r0, r1, r2
 }
 
+// SubmitCartOrderInServiceNow provides a mock function with given fields:
+func (_m *Client) SubmitCartOrderInServiceNow() (*serializer.ServiceNowCatalogOrder, int, error) {
+	ret := _m.Called()
+
+	var r0 *serializer.ServiceNowCatalogOrder
+	if rf, ok := ret.Get(0).(func() *serializer.ServiceNowCatalogOrder); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceNowCatalogOrder)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateApprovalInServiceNow provides a mock function with given fields: approvalID, payload
+func (_m *Client) UpdateApprovalInServiceNow(approvalID string, payload *serializer.ServiceNowApprovalPayload) (int, error) {
+	ret := _m.Called(approvalID, payload)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, *serializer.ServiceNowApprovalPayload) int); ok {
+		r0 = rf(approvalID, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *serializer.ServiceNowApprovalPayload) error); ok {
+		r1 = rf(approvalID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateStateOfRecordInServiceNow provides a mock function with given fields: recordType, recordID, payload
 func (_m *Client) UpdateStateOfRecordInServiceNow(recordType string, recordID string, payload *serializer.ServiceNowUpdateStatePayload) (int, error) {
 	ret := _m.Called(recordType, recordID, payload)
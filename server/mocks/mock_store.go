@@ -17,6 +17,20 @@ type Store struct {
 	mock.Mock
 }
 
+// AddMutedEvent provides a mock function with given fields: key, event
+func (_m *Store) AddMutedEvent(key string, event *serializer.MutedEvent) error {
+	ret := _m.Called(key, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *serializer.MutedEvent) error); ok {
+		r0 = rf(key, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteAllUsersState provides a mock function with given fields:
 func (_m *Store) DeleteAllUsersState() bool {
 	ret := _m.Called()
@@ -31,6 +45,90 @@ func (_m *Store) DeleteAllUsersState() bool {
 	return r0
 }
 
+// DeleteApprovalPost provides a mock function with given fields: approvalID
+func (_m *Store) DeleteApprovalPost(approvalID string) error {
+	ret := _m.Called(approvalID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(approvalID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteInstanceUser provides a mock function with given fields: instanceID, mattermostUserID
+func (_m *Store) DeleteInstanceUser(instanceID string, mattermostUserID string) error {
+	ret := _m.Called(instanceID, mattermostUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(instanceID, mattermostUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteMute provides a mock function with given fields: key
+func (_m *Store) DeleteMute(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersonalSubscription provides a mock function with given fields: mattermostUserID
+func (_m *Store) DeletePersonalSubscription(mattermostUserID string) error {
+	ret := _m.Called(mattermostUserID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(mattermostUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteServiceAccount provides a mock function with given fields:
+func (_m *Store) DeleteServiceAccount() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSubscriptionSettings provides a mock function with given fields: instanceID, subscriptionID
+func (_m *Store) DeleteSubscriptionSettings(instanceID string, subscriptionID string) error {
+	ret := _m.Called(instanceID, subscriptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(instanceID, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteUser provides a mock function with given fields: mattermostUserID
 func (_m *Store) DeleteUser(mattermostUserID string) error {
 	ret := _m.Called(mattermostUserID)
@@ -73,6 +171,274 @@ func (_m *Store) GetAllUsers() ([]*serializer.IncidentCaller, error) {
 	return r0, r1
 }
 
+// GetMutesWithSummary provides a mock function with given fields:
+func (_m *Store) GetMutesWithSummary() ([]*serializer.SubscriptionMute, error) {
+	ret := _m.Called()
+
+	var r0 []*serializer.SubscriptionMute
+	if rf, ok := ret.Get(0).(func() []*serializer.SubscriptionMute); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*serializer.SubscriptionMute)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadApprovalPost provides a mock function with given fields: approvalID
+func (_m *Store) LoadApprovalPost(approvalID string) (*serializer.ApprovalPost, error) {
+	ret := _m.Called(approvalID)
+
+	var r0 *serializer.ApprovalPost
+	if rf, ok := ret.Get(0).(func(string) *serializer.ApprovalPost); ok {
+		r0 = rf(approvalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ApprovalPost)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(approvalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadChannelInstance provides a mock function with given fields: channelID
+func (_m *Store) LoadChannelInstance(channelID string) (string, error) {
+	ret := _m.Called(channelID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(channelID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(channelID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadInstanceMattermostUserIDFromServiceNowUserID provides a mock function with given fields: instanceID, serviceNowUserID
+func (_m *Store) LoadInstanceMattermostUserIDFromServiceNowUserID(instanceID string, serviceNowUserID string) (string, error) {
+	ret := _m.Called(instanceID, serviceNowUserID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(instanceID, serviceNowUserID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(instanceID, serviceNowUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadInstanceUser provides a mock function with given fields: instanceID, mattermostUserID
+func (_m *Store) LoadInstanceUser(instanceID string, mattermostUserID string) (*serializer.User, error) {
+	ret := _m.Called(instanceID, mattermostUserID)
+
+	var r0 *serializer.User
+	if rf, ok := ret.Get(0).(func(string, string) *serializer.User); ok {
+		r0 = rf(instanceID, mattermostUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(instanceID, mattermostUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadLookup provides a mock function with given fields: key
+func (_m *Store) LoadLookup(key string) ([]byte, error) {
+	ret := _m.Called(key)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadMattermostUserIDFromServiceNowUserID provides a mock function with given fields: serviceNowUserID
+func (_m *Store) LoadMattermostUserIDFromServiceNowUserID(serviceNowUserID string) (string, error) {
+	ret := _m.Called(serviceNowUserID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(serviceNowUserID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceNowUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadMattermostUserIDFromServiceNowUsername provides a mock function with given fields: serviceNowUsername
+func (_m *Store) LoadMattermostUserIDFromServiceNowUsername(serviceNowUsername string) (string, error) {
+	ret := _m.Called(serviceNowUsername)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(serviceNowUsername)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceNowUsername)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadMute provides a mock function with given fields: key
+func (_m *Store) LoadMute(key string) (*serializer.SubscriptionMute, error) {
+	ret := _m.Called(key)
+
+	var r0 *serializer.SubscriptionMute
+	if rf, ok := ret.Get(0).(func(string) *serializer.SubscriptionMute); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.SubscriptionMute)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadPersonalSubscription provides a mock function with given fields: mattermostUserID
+func (_m *Store) LoadPersonalSubscription(mattermostUserID string) (*serializer.PersonalSubscription, error) {
+	ret := _m.Called(mattermostUserID)
+
+	var r0 *serializer.PersonalSubscription
+	if rf, ok := ret.Get(0).(func(string) *serializer.PersonalSubscription); ok {
+		r0 = rf(mattermostUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.PersonalSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(mattermostUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadServiceAccount provides a mock function with given fields:
+func (_m *Store) LoadServiceAccount() (*serializer.ServiceAccount, error) {
+	ret := _m.Called()
+
+	var r0 *serializer.ServiceAccount
+	if rf, ok := ret.Get(0).(func() *serializer.ServiceAccount); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.ServiceAccount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadSubscriptionSettings provides a mock function with given fields: instanceID, subscriptionID
+func (_m *Store) LoadSubscriptionSettings(instanceID string, subscriptionID string) (*serializer.SubscriptionSettings, error) {
+	ret := _m.Called(instanceID, subscriptionID)
+
+	var r0 *serializer.SubscriptionSettings
+	if rf, ok := ret.Get(0).(func(string, string) *serializer.SubscriptionSettings); ok {
+		r0 = rf(instanceID, subscriptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.SubscriptionSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(instanceID, subscriptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LoadUser provides a mock function with given fields: mattermostUserID
 func (_m *Store) LoadUser(mattermostUserID string) (*serializer.User, error) {
 	ret := _m.Called(mattermostUserID)
@@ -96,6 +462,155 @@ func (_m *Store) LoadUser(mattermostUserID string) (*serializer.User, error) {
 	return r0, r1
 }
 
+// MarkPersonalNotificationSent provides a mock function with given fields: mattermostUserID, recordID, event
+func (_m *Store) MarkPersonalNotificationSent(mattermostUserID string, recordID string, event string) (bool, error) {
+	ret := _m.Called(mattermostUserID, recordID, event)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(mattermostUserID, recordID, event)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(mattermostUserID, recordID, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkSLAWarningSent provides a mock function with given fields: channelID, instanceID, slaID, threshold
+func (_m *Store) MarkSLAWarningSent(channelID string, instanceID string, slaID string, threshold int) (bool, error) {
+	ret := _m.Called(channelID, instanceID, slaID, threshold)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string, int) bool); ok {
+		r0 = rf(channelID, instanceID, slaID, threshold)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, int) error); ok {
+		r1 = rf(channelID, instanceID, slaID, threshold)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrateUserIndexes provides a mock function with given fields:
+func (_m *Store) MigrateUserIndexes() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PopMutedEvents provides a mock function with given fields: key
+func (_m *Store) PopMutedEvents(key string) (*serializer.MutedEvents, error) {
+	ret := _m.Called(key)
+
+	var r0 *serializer.MutedEvents
+	if rf, ok := ret.Get(0).(func(string) *serializer.MutedEvents); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*serializer.MutedEvents)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StoreApprovalPost provides a mock function with given fields: approvalPost
+func (_m *Store) StoreApprovalPost(approvalPost *serializer.ApprovalPost) error {
+	ret := _m.Called(approvalPost)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*serializer.ApprovalPost) error); ok {
+		r0 = rf(approvalPost)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreChannelInstance provides a mock function with given fields: channelID, instanceID
+func (_m *Store) StoreChannelInstance(channelID string, instanceID string) error {
+	ret := _m.Called(channelID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(channelID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreInstanceUser provides a mock function with given fields: instanceID, user
+func (_m *Store) StoreInstanceUser(instanceID string, user *serializer.User) error {
+	ret := _m.Called(instanceID, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *serializer.User) error); ok {
+		r0 = rf(instanceID, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreLookup provides a mock function with given fields: key, data, ttlSeconds
+func (_m *Store) StoreLookup(key string, data []byte, ttlSeconds int64) error {
+	ret := _m.Called(key, data, ttlSeconds)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []byte, int64) error); ok {
+		r0 = rf(key, data, ttlSeconds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreMute provides a mock function with given fields: mute
+func (_m *Store) StoreMute(mute *serializer.SubscriptionMute) error {
+	ret := _m.Called(mute)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*serializer.SubscriptionMute) error); ok {
+		r0 = rf(mute)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreOAuth2State provides a mock function with given fields: state
 func (_m *Store) StoreOAuth2State(state string) error {
 	ret := _m.Called(state)
@@ -110,6 +625,48 @@ func (_m *Store) StoreOAuth2State(state string) error {
 	return r0
 }
 
+// StorePersonalSubscription provides a mock function with given fields: subscription
+func (_m *Store) StorePersonalSubscription(subscription *serializer.PersonalSubscription) error {
+	ret := _m.Called(subscription)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*serializer.PersonalSubscription) error); ok {
+		r0 = rf(subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreServiceAccount provides a mock function with given fields: serviceAccount
+func (_m *Store) StoreServiceAccount(serviceAccount *serializer.ServiceAccount) error {
+	ret := _m.Called(serviceAccount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*serializer.ServiceAccount) error); ok {
+		r0 = rf(serviceAccount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StoreSubscriptionSettings provides a mock function with given fields: instanceID, subscriptionID, settings
+func (_m *Store) StoreSubscriptionSettings(instanceID string, subscriptionID string, settings *serializer.SubscriptionSettings) error {
+	ret := _m.Called(instanceID, subscriptionID, settings)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *serializer.SubscriptionSettings) error); ok {
+		r0 = rf(instanceID, subscriptionID, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreUser provides a mock function with given fields: user
 func (_m *Store) StoreUser(user *serializer.User) error {
 	ret := _m.Called(user)
@@ -124,6 +681,20 @@ func (_m *Store) StoreUser(user *serializer.User) error {
 	return r0
 }
 
+// UserIndexesMigrated provides a mock function with given fields:
+func (_m *Store) UserIndexesMigrated() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // VerifyOAuth2State provides a mock function with given fields: state
 func (_m *Store) VerifyOAuth2State(state string) error {
 	ret := _m.Called(state)
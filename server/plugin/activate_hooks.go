@@ -8,6 +8,7 @@ import (
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
@@ -37,12 +38,36 @@ func (p *Plugin) OnActivate() error {
 
 	p.router = p.InitAPI()
 	p.store = p.NewStore(p.API)
+	if err = p.store.MigrateUserIndexes(); err != nil {
+		p.API.LogError("Unable to migrate the user indexes", "Error", err.Error())
+	}
+
+	if p.muteSummaryJob, err = cluster.Schedule(p.API, constants.MuteSummaryJobKey, cluster.MakeWaitForRoundedInterval(constants.MuteSummaryJobInterval), p.PostExpiredMuteSummaries); err != nil {
+		return errors.Wrap(err, "failed to schedule the mute summary job")
+	}
+
+	if p.slaWarningJob, err = cluster.Schedule(p.API, constants.SLAWarningJobKey, cluster.MakeWaitForRoundedInterval(constants.SLAWarningJobInterval), p.PostSLAWarnings); err != nil {
+		return errors.Wrap(err, "failed to schedule the SLA warning job")
+	}
+
 	p.initializeTelemetry()
 
 	return nil
 }
 
 func (p *Plugin) OnDeactivate() error {
+	if p.muteSummaryJob != nil {
+		if err := p.muteSummaryJob.Close(); err != nil {
+			p.API.LogWarn("Failed to close the mute summary job", "Error", err.Error())
+		}
+	}
+
+	if p.slaWarningJob != nil {
+		if err := p.slaWarningJob.Close(); err != nil {
+			p.API.LogWarn("Failed to close the SLA warning job", "Error", err.Error())
+		}
+	}
+
 	if err := p.telemetryClient.Close(); err != nil {
 		p.API.LogWarn("Telemetry client failed to close", "error", err.Error())
 	}
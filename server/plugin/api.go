@@ -11,9 +11,9 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
 	"strings"
-	"sync"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost/server/public/model"
@@ -39,6 +39,10 @@ func (p *Plugin) InitAPI() *mux.Router {
 
 	s.HandleFunc(constants.PathCreateSubscription, p.checkAuth(p.checkOAuth(p.checkSubscriptionsConfigured(p.createSubscription)))).Methods(http.MethodPost)
 	s.HandleFunc(constants.PathGetAllSubscriptions, p.checkAuth(p.checkOAuth(p.checkSubscriptionsConfigured(p.getAllSubscriptions)))).Methods(http.MethodGet)
+	s.HandleFunc(constants.PathExportSubscriptions, p.checkAuth(p.checkSysAdmin(p.checkOAuth(p.checkSubscriptionsConfigured(p.exportSubscriptions))))).Methods(http.MethodGet)
+	s.HandleFunc(constants.PathImportSubscriptions, p.checkAuth(p.checkSysAdmin(p.checkOAuth(p.checkSubscriptionsConfigured(p.importSubscriptions))))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathCloneSubscriptions, p.checkAuth(p.checkSysAdmin(p.checkOAuth(p.checkSubscriptionsConfigured(p.cloneSubscriptions))))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathBulkSubscriptions, p.checkAuth(p.checkOAuth(p.checkSubscriptionsConfigured(p.bulkUpdateSubscriptions)))).Methods(http.MethodPost)
 	s.HandleFunc(constants.PathDeleteSubscription, p.checkAuth(p.checkOAuth(p.checkSubscriptionsConfigured(p.deleteSubscription)))).Methods(http.MethodDelete)
 	s.HandleFunc(constants.PathEditSubscription, p.checkAuth(p.checkOAuth(p.checkSubscriptionsConfigured(p.editSubscription)))).Methods(http.MethodPatch)
 	s.HandleFunc(constants.PathGetUserChannelsForTeam, p.checkAuth(p.getUserChannelsForTeam)).Methods(http.MethodGet)
@@ -52,7 +56,25 @@ func (p *Plugin) InitAPI() *mux.Router {
 	s.HandleFunc(constants.PathUpdateStateOfRecord, p.checkAuth(p.checkOAuth(p.updateStateOfRecord))).Methods(http.MethodPatch)
 	s.HandleFunc(constants.PathOpenStateModal, p.checkAuth(p.handleOpenStateModal)).Methods(http.MethodPost)
 	s.HandleFunc(constants.PathProcessNotification, p.checkAuthBySecret(p.handleNotification)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathProcessApproval, p.checkAuthBySecret(p.handleApprovalNotification)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathProcessPersonal, p.checkAuthBySecret(p.handlePersonalNotification)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathApprovalAction, p.checkAuth(p.checkOAuth(p.handleApprovalAction))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathApprovalRejectDialog, p.checkAuth(p.checkOAuth(p.handleApprovalRejectDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathCommentDialog, p.checkAuth(p.checkOAuth(p.handleCommentDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathStateDialog, p.checkAuth(p.checkOAuth(p.handleStateDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathIncidentDialog, p.checkAuth(p.checkOAuth(p.handleIncidentDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathCatalogOrderAction, p.checkAuth(p.handleCatalogOrderAction)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathCatalogOrderDialog, p.checkAuth(p.checkOAuth(p.handleCatalogOrderDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathCatalogSubmitCart, p.checkAuth(p.handleCatalogSubmitCart)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathKnowledgeArticleAction, p.checkAuth(p.handleKnowledgeArticleAction)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathKnowledgeSolvedAction, p.checkAuth(p.handleKnowledgeSolvedAction)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathKnowledgeSolvedDialog, p.checkAuth(p.checkOAuth(p.handleKnowledgeSolvedDialog))).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathConfigItemAction, p.checkAuth(p.handleConfigItemAction)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathPageOnCallAction, p.checkAuth(p.handlePageOnCallAction)).Methods(http.MethodPost)
+	s.HandleFunc(constants.PathPersonalSubscriptions, p.checkAuth(p.checkOAuth(p.getPersonalSubscription))).Methods(http.MethodGet)
+	s.HandleFunc(constants.PathPersonalSubscriptions, p.checkAuth(p.checkOAuth(p.updatePersonalSubscription))).Methods(http.MethodPut)
 	s.HandleFunc(constants.PathGetConfig, p.checkAuth(p.getConfig)).Methods(http.MethodGet)
+	s.HandleFunc(constants.PathDiagnostics, p.checkAuth(p.checkSysAdmin(p.getDiagnostics))).Methods(http.MethodGet)
 	s.HandleFunc(constants.PathGetUsers, p.checkAuth(p.checkOAuth(p.handleGetUsers))).Methods(http.MethodGet)
 	s.HandleFunc(constants.PathCreateIncident, p.checkAuth(p.checkOAuth(p.createIncident))).Methods(http.MethodPost)
 	s.HandleFunc(constants.PathSearchCatalogItems, p.checkAuth(p.checkOAuth(p.searchCatalogItemsInServiceNow))).Methods(http.MethodGet)
@@ -74,10 +96,34 @@ func (p *Plugin) checkAuth(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+func (p *Plugin) checkSysAdmin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		isSysAdmin, err := p.IsAuthorizedSysAdmin(r.Header.Get(constants.HeaderMattermostUserID))
+		if err != nil {
+			p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: constants.ErrorGeneric})
+			return
+		}
+
+		if !isSysAdmin {
+			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusForbidden, Message: constants.ErrorNotAuthorized})
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
 func (p *Plugin) checkOAuth(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID := r.Header.Get(constants.HeaderMattermostUserID)
-		user, err := p.GetUser(userID)
+		instanceID := r.URL.Query().Get(constants.QueryParamInstance)
+		if _, err := p.getConfiguration().GetInstance(instanceID); err != nil {
+			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		user, err := p.GetInstanceUser(userID, instanceID)
 		if err != nil {
 			if errors.Is(err, ErrNotFound) {
 				p.handleAPIError(w, &serializer.APIErrorResponse{ID: constants.APIErrorIDNotConnected, StatusCode: http.StatusUnauthorized, Message: constants.APIErrorNotConnected})
@@ -96,6 +142,9 @@ func (p *Plugin) checkOAuth(handler http.HandlerFunc) http.HandlerFunc {
 		}
 
 		ctx := context.WithValue(r.Context(), constants.ContextTokenKey, token)
+		if instanceID != "" {
+			ctx = context.WithValue(ctx, constants.ContextInstanceKey, instanceID)
+		}
 		r = r.Clone(ctx)
 		handler(w, r)
 	}
@@ -149,19 +198,22 @@ func (p *Plugin) getConnected(w http.ResponseWriter, r *http.Request) {
 // checkAuthBySecret verifies if provided request is performed by an authorized source.
 func (p *Plugin) checkAuthBySecret(handleFunc http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if status, err := verifyHTTPSecret(p.getConfiguration().WebhookSecret, r.FormValue("secret")); err != nil {
+		instance, status, err := p.getConfiguration().GetInstanceBySecret(r.FormValue("secret"))
+		if err != nil {
 			p.API.LogError(constants.ErrorInvalidSecret, "Error", err.Error())
 			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: status, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorInvalidSecret, err.Error())})
 			return
 		}
 
-		handleFunc(w, r)
+		// The instance which sent the request is identified by its webhook secret
+		ctx := context.WithValue(r.Context(), constants.ContextInstanceKey, instance.ID)
+		handleFunc(w, r.Clone(ctx))
 	}
 }
 
 func (p *Plugin) httpOAuth2Connect(w http.ResponseWriter, r *http.Request) {
 	mattermostUserID := r.Header.Get(constants.HeaderMattermostUserID)
-	redirectURL, err := p.InitOAuth2(mattermostUserID)
+	redirectURL, err := p.InitOAuth2(mattermostUserID, r.URL.Query().Get(constants.QueryParamInstance))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -247,6 +299,7 @@ func (p *Plugin) createSubscription(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := p.GetClientFromRequest(r)
+	subscription.InstanceID = getInstanceID(client)
 	exists, statusCode, err := client.CheckForDuplicateSubscription(subscription)
 	if err != nil {
 		_ = p.handleClientError(w, r, err, false, statusCode, "", "")
@@ -259,6 +312,7 @@ func (p *Plugin) createSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	settings := subscription.TakeSettings()
 	resp, statusCode, err := client.CreateSubscription(subscription)
 	if err != nil {
 		_ = p.handleClientError(w, r, err, false, statusCode, "", "")
@@ -266,11 +320,18 @@ func (p *Plugin) createSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if settings != nil {
+		if err = p.store.StoreSubscriptionSettings(subscription.InstanceID, resp.SysID, settings); err != nil {
+			p.API.LogError(constants.ErrorStoreSubscriptionSettings, "SubscriptionID", resp.SysID, "Error", err.Error())
+		}
+		resp.SetSettings(settings)
+	}
+
 	if subscription.RecordNumber != nil {
 		resp.Number = *subscription.RecordNumber
 	}
 
-	post := resp.CreateSubscriptionCreatedPost(p.botID, p.getConfiguration().ServiceNowBaseURL)
+	post := resp.CreateSubscriptionCreatedPost(p.botID, p.getServiceNowURL(client))
 	if _, postErr := p.API.CreatePost(post); postErr != nil {
 		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
 	}
@@ -282,6 +343,151 @@ func (p *Plugin) createSubscription(w http.ResponseWriter, r *http.Request) {
 	returnStatusOK(w)
 }
 
+func (p *Plugin) exportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get(constants.QueryParamFormat)
+	if format == "" {
+		format = constants.ExportFormatJSON
+	}
+
+	if !constants.ValidExportFormats[format] {
+		p.API.LogError(constants.ErrorInvalidQueryParam, "Query param", constants.QueryParamFormat)
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("Query param %s is not valid", constants.QueryParamFormat)})
+		return
+	}
+
+	channelID := r.URL.Query().Get(constants.QueryParamChannelID)
+	if channelID != "" && !model.IsValidId(channelID) {
+		p.API.LogError(constants.ErrorInvalidQueryParam, "Query param", constants.QueryParamChannelID)
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("Query param %s is not valid", constants.QueryParamChannelID)})
+		return
+	}
+
+	teamID := r.URL.Query().Get(constants.QueryParamTeamID)
+	if teamID != "" && !model.IsValidId(teamID) {
+		p.API.LogError(constants.ErrorInvalidQueryParam, "Query param", constants.QueryParamTeamID)
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("Query param %s is not valid", constants.QueryParamTeamID)})
+		return
+	}
+
+	client := p.GetClientFromRequest(r)
+	subscriptions, statusCode, err := p.GetSubscriptionsForExport(client, channelID, teamID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptions, "Error", err.Error())
+		_ = p.handleClientError(w, r, err, true, statusCode, "", fmt.Sprintf("%s. Error: %s", constants.ErrorGetSubscriptions, err.Error()))
+		return
+	}
+
+	data, err := EncodeSubscriptionsForExport(subscriptions, format)
+	if err != nil {
+		p.API.LogError(constants.ErrorExportSubscriptions, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorExportSubscriptions, err.Error())})
+		return
+	}
+
+	contentType := "application/json"
+	if format == constants.ExportFormatCSV {
+		contentType = "text/csv"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf(constants.SubscriptionsExportFileName, format)))
+	if _, err = w.Write(data); err != nil {
+		p.API.LogError("Failed to write the exported subscriptions", "Error", err.Error())
+	}
+}
+
+func (p *Plugin) importSubscriptions(w http.ResponseWriter, r *http.Request) {
+	request, err := serializer.SubscriptionImportRequestFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	if request.Format == "" {
+		request.Format = constants.ExportFormatJSON
+	}
+
+	subscriptions, err := DecodeSubscriptionsForImport([]byte(request.Data), request.Format)
+	if err != nil {
+		p.API.LogError(constants.ErrorValidatingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorValidatingRequestBody, err.Error())})
+		return
+	}
+
+	for oldChannelID, newChannelID := range request.ChannelMap {
+		if !model.IsValidId(oldChannelID) || !model.IsValidId(newChannelID) {
+			p.API.LogError(constants.ErrorInvalidChannelID)
+			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: constants.ErrorInvalidChannelID})
+			return
+		}
+	}
+
+	client := p.GetClientFromRequest(r)
+	result := p.ImportSubscriptions(client, subscriptions, request.ChannelMap, request.DryRun)
+	p.API.LogInfo("Subscriptions imported", "UserID", r.Header.Get(constants.HeaderMattermostUserID), "DryRun", request.DryRun, "Created", result.Count(constants.ImportStatusCreated))
+	p.writeJSON(w, 0, result)
+}
+
+func (p *Plugin) cloneSubscriptions(w http.ResponseWriter, r *http.Request) {
+	request, err := serializer.SubscriptionCloneRequestFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	if !model.IsValidId(request.FromChannelID) || !model.IsValidId(request.ToChannelID) {
+		p.API.LogError(constants.ErrorInvalidChannelID)
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: constants.ErrorInvalidChannelID})
+		return
+	}
+
+	client := p.GetClientFromRequest(r)
+	result, statusCode, err := p.CloneChannelSubscriptions(client, request.FromChannelID, request.ToChannelID, request.DryRun)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptions, "Error", err.Error())
+		_ = p.handleClientError(w, r, err, true, statusCode, "", fmt.Sprintf("%s. Error: %s", constants.ErrorGetSubscriptions, err.Error()))
+		return
+	}
+
+	p.writeJSON(w, 0, result)
+}
+
+func (p *Plugin) bulkUpdateSubscriptions(w http.ResponseWriter, r *http.Request) {
+	request, err := serializer.BulkSubscriptionRequestFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	if err = request.IsValid(); err != nil {
+		p.API.LogError(constants.ErrorValidatingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorValidatingRequestBody, err.Error())})
+		return
+	}
+
+	userID := r.Header.Get(constants.HeaderMattermostUserID)
+	if request.ChannelID != "" {
+		permissionStatusCode, permissionErr := p.HasPublicOrPrivateChannelPermissions(userID, request.ChannelID)
+		if permissionErr != nil {
+			p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: permissionStatusCode, Message: permissionErr.Error()})
+			return
+		}
+	}
+
+	client := p.GetClientFromRequest(r)
+	result, statusCode, err := p.BulkUpdateSubscriptions(client, userID, request)
+	if err != nil {
+		p.API.LogError(constants.ErrorBulkUpdateSubscriptions, "Error", err.Error())
+		_ = p.handleClientError(w, r, err, false, statusCode, "", fmt.Sprintf("%s. Error: %s", constants.ErrorBulkUpdateSubscriptions, err.Error()))
+		return
+	}
+
+	p.writeJSON(w, 0, result)
+}
+
 func (p *Plugin) getAllSubscriptions(w http.ResponseWriter, r *http.Request) {
 	channelID := r.URL.Query().Get(constants.QueryParamChannelID)
 	if channelID != "" && !model.IsValidId(channelID) {
@@ -315,7 +521,8 @@ func (p *Plugin) getAllSubscriptions(w http.ResponseWriter, r *http.Request) {
 
 	var bulkSubscriptions []*serializer.SubscriptionResponse
 	var recordSubscriptions []*serializer.SubscriptionResponse
-	wg := sync.WaitGroup{}
+	// The records are fetched using the service account, if configured, as the subscriptions can be created by other users
+	readOnlyClient := p.GetReadOnlyClient(client)
 	mattermostUserID := r.Header.Get(constants.HeaderMattermostUserID)
 	for _, subscription := range subscriptions {
 		_, permissionErr := p.HasPublicOrPrivateChannelPermissions(mattermostUserID, subscription.ChannelID)
@@ -323,16 +530,18 @@ func (p *Plugin) getAllSubscriptions(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		if settings, settingsErr := p.GetSubscriptionSettings(subscription.InstanceID, subscription.SysID); settingsErr == nil {
+			subscription.SetSettings(settings)
+		}
+
 		if subscription.Type == constants.SubscriptionTypeBulk {
 			bulkSubscriptions = append(bulkSubscriptions, subscription)
 			continue
 		}
-		wg.Add(1)
-		go p.GetRecordFromServiceNowForSubscription(subscription, client, &wg)
 		recordSubscriptions = append(recordSubscriptions, subscription)
 	}
 
-	wg.Wait()
+	p.PopulateRecordsForSubscriptions(recordSubscriptions, readOnlyClient)
 	recordSubscriptions = FilterSubscriptionsOnRecordData(recordSubscriptions)
 	bulkSubscriptions = append(bulkSubscriptions, recordSubscriptions...)
 
@@ -353,6 +562,10 @@ func (p *Plugin) deleteSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := p.store.DeleteSubscriptionSettings(getInstanceID(client), subscriptionID); err != nil {
+		p.API.LogWarn("Unable to delete the settings of the subscription", "SubscriptionID", subscriptionID, "Error", err.Error())
+	}
+
 	returnStatusOK(w)
 }
 
@@ -380,6 +593,18 @@ func (p *Plugin) editSubscription(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := p.GetClientFromRequest(r)
+	subscription.InstanceID = getInstanceID(client)
+	mentions := subscription.Mentions
+	// The configuration item of a subscription can't be changed after its creation
+	_ = subscription.TakeSettings()
+	if subscription.Type != nil && *subscription.Type == constants.SubscriptionTypeBulk {
+		if settings, settingsErr := p.GetSubscriptionSettings(subscription.InstanceID, subscriptionID); settingsErr != nil {
+			p.API.LogError(constants.ErrorGetSubscriptionSettings, "SubscriptionID", subscriptionID, "Error", settingsErr.Error())
+		} else {
+			subscription.RecordID = &settings.ConfigurationItemID
+		}
+	}
+
 	resp, statusCode, editErr := client.EditSubscription(subscriptionID, subscription)
 	if editErr != nil {
 		p.API.LogError(constants.ErrorEditingSubscription, "SubscriptionID", subscriptionID, "Error", editErr.Error())
@@ -391,11 +616,17 @@ func (p *Plugin) editSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if mentions != nil {
+		if err = p.StoreSubscriptionMentions(subscription.InstanceID, subscriptionID, *mentions); err != nil {
+			p.API.LogError(constants.ErrorStoreSubscriptionSettings, "SubscriptionID", subscriptionID, "Error", err.Error())
+		}
+	}
+
 	if subscription.RecordNumber != nil {
 		resp.Number = *subscription.RecordNumber
 	}
 
-	post := resp.CreateSubscriptionEditedPost(p.botID, p.getConfiguration().ServiceNowBaseURL)
+	post := resp.CreateSubscriptionEditedPost(p.botID, p.getServiceNowURL(client))
 	if _, postErr := p.API.CreatePost(post); postErr != nil {
 		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
 	}
@@ -490,7 +721,7 @@ func (p *Plugin) getRecordFromServiceNow(w http.ResponseWriter, r *http.Request)
 	}
 
 	record.RecordType = recordType
-	if err := record.HandleNestedFields(p.getConfiguration().ServiceNowBaseURL); err != nil {
+	if err := record.HandleNestedFields(p.getServiceNowURL(client)); err != nil {
 		p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
 		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorHandlingNestedFields, err.Error())})
 		return
@@ -507,13 +738,165 @@ func (p *Plugin) handleNotification(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post := event.CreateNotificationPost(p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+	// The diagnostic events only check that the Site URL is routed to the plugin
+	if event.EventOccurred == constants.SubscriptionEventDiagnostic {
+		returnStatusOK(w)
+		return
+	}
+
+	// The lookups are only cached and the personal subscriptions only exist for the default instance
+	if isDefaultInstanceRequest(r) {
+		p.InvalidateRecordLookups(event.RecordType, event.RecordID)
+	}
+
+	event.InstanceID, _ = r.Context().Value(constants.ContextInstanceKey).(string)
+	p.PostNotification(event, p.getServiceNowURLFromRequest(r))
+	returnStatusOK(w)
+}
+
+// PostNotification posts the notification of the event in the subscribed channel.
+// The notifications of the muted channels are stored for their summaries instead.
+func (p *Plugin) PostNotification(event *serializer.ServiceNowEvent, serviceNowURL string) {
+	if !p.MatchesConfigurationItemFilter(event) {
+		p.API.LogDebug("Skipping the notification of a record not belonging to the configuration item of the subscription", "SubscriptionID", event.SubscriptionID, "RecordID", event.RecordID)
+		return
+	}
+
+	if mute := p.GetNotificationMute(event); mute != nil {
+		p.HandleMutedNotification(mute, event)
+		return
+	}
+
+	mentions := p.GetNotificationMentions(event)
+	post := event.CreateNotificationPost(p.botID, serviceNowURL, p.GetPluginURL())
+	post.Message = serializer.GetMentionsMessage(mentions)
 	if _, postErr := p.API.CreatePost(post); postErr != nil {
 		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
 	}
+}
+
+// handlePersonalNotification receives the changes of the records from ServiceNow independently of the channel subscriptions
+func (p *Plugin) handlePersonalNotification(w http.ResponseWriter, r *http.Request) {
+	event, err := serializer.ServiceNowEventFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	// The personal subscriptions only exist for the users connected to the default instance
+	if !isDefaultInstanceRequest(r) {
+		p.rejectPersonalNotification(w, r)
+		return
+	}
+
+	p.NotifyPersonalSubscribers(event)
 	returnStatusOK(w)
 }
 
+// rejectPersonalNotification responds with an error to the personal notifications sent by the instances other than the default one
+func (p *Plugin) rejectPersonalNotification(w http.ResponseWriter, r *http.Request) {
+	instanceID, _ := r.Context().Value(constants.ContextInstanceKey).(string)
+	p.API.LogWarn(constants.ErrorPersonalNotificationInstance, "InstanceID", instanceID, "Path", r.URL.Path)
+	p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Instance: %s", constants.ErrorPersonalNotificationInstance, instanceID)})
+}
+
+func (p *Plugin) handleApprovalNotification(w http.ResponseWriter, r *http.Request) {
+	event, err := serializer.ServiceNowApprovalEventFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	// The approvers are only mapped to the Mattermost users connected to the default instance
+	if !isDefaultInstanceRequest(r) {
+		p.rejectPersonalNotification(w, r)
+		return
+	}
+
+	if event.State != constants.ApprovalStateRequested {
+		returnStatusOK(w)
+		return
+	}
+
+	mattermostUserID, err := p.GetMattermostUserIDFromServiceNowUserID(event.ApproverID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			p.API.LogDebug("Approver is not connected to Mattermost", "ApproverID", event.ApproverID)
+			returnStatusOK(w)
+			return
+		}
+
+		p.API.LogError(constants.ErrorGetMattermostUserForApprover, "ApproverID", event.ApproverID, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorGetMattermostUserForApprover, err.Error())})
+		return
+	}
+
+	subscription, err := p.GetPersonalSubscription(mattermostUserID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetPersonalSubscription, "UserID", mattermostUserID, "Error", err.Error())
+	} else if !subscription.HasEvent(constants.PersonalSubscriptionEventApprovals) {
+		returnStatusOK(w)
+		return
+	}
+
+	post := event.CreateApprovalPost(p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+	postID, err := p.DMPost(mattermostUserID, post)
+	if err != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", err.Error())
+		returnStatusOK(w)
+		return
+	}
+
+	if err := p.store.StoreApprovalPost(&serializer.ApprovalPost{
+		ApprovalID:       event.ApprovalID,
+		ApproverID:       event.ApproverID,
+		MattermostUserID: mattermostUserID,
+		PostID:           postID,
+	}); err != nil {
+		p.API.LogError(constants.ErrorStoreApprovalPost, "ApprovalID", event.ApprovalID, "Error", err.Error())
+	}
+
+	returnStatusOK(w)
+}
+
+func (p *Plugin) getPersonalSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get(constants.HeaderMattermostUserID)
+	subscription, err := p.GetPersonalSubscription(userID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetPersonalSubscription, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorGetPersonalSubscription, err.Error())})
+		return
+	}
+
+	p.writeJSON(w, http.StatusOK, subscription)
+}
+
+func (p *Plugin) updatePersonalSubscription(w http.ResponseWriter, r *http.Request) {
+	payload, err := serializer.PersonalSubscriptionPayloadFromJSON(r.Body)
+	if err != nil {
+		p.API.LogError(constants.ErrorUnmarshallingRequestBody, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorUnmarshallingRequestBody, err.Error())})
+		return
+	}
+
+	if err = payload.Validate(); err != nil {
+		p.handleAPIError(w, &serializer.APIErrorResponse{ID: constants.APIErrorIDInvalidPersonalEvent, StatusCode: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	userID := r.Header.Get(constants.HeaderMattermostUserID)
+	subscription, err := p.UpdatePersonalSubscription(userID, payload.Events)
+	if err != nil {
+		p.API.LogError(constants.ErrorStorePersonalSubscription, "Error", err.Error())
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorStorePersonalSubscription, err.Error())})
+		return
+	}
+
+	p.writeJSON(w, http.StatusOK, subscription)
+}
+
 func (p *Plugin) shareRecordInChannel(w http.ResponseWriter, r *http.Request) {
 	pathParams := mux.Vars(r)
 	channelID := pathParams[constants.QueryParamChannelID]
@@ -559,13 +942,14 @@ func (p *Plugin) shareRecordInChannel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	record.RecordType = shareRecordData.RecordType
-	if err := record.HandleNestedFields(p.getConfiguration().ServiceNowBaseURL); err != nil {
+	if err := record.HandleNestedFields(p.getServiceNowURL(client)); err != nil {
 		p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
 		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorHandlingNestedFields, err.Error())})
 		return
 	}
 
-	post := record.CreateSharingPost(channelID, p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL(), user.Username)
+	record.InstanceID = getInstanceID(client)
+	post := record.CreateSharingPost(channelID, p.botID, p.getServiceNowURL(client), p.GetPluginURL(), user.Username)
 	if _, postErr := p.API.CreatePost(post); postErr != nil {
 		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
 	}
@@ -643,6 +1027,10 @@ func (p *Plugin) getStatesForRecordType(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	for _, state := range states {
+		state.RequiredFields = constants.StateTransitionRequiredFields[recordType][state.Value]
+	}
+
 	p.writeJSONArray(w, statusCode, states)
 }
 
@@ -668,10 +1056,24 @@ func (p *Plugin) updateStateOfRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fieldErrors := payload.GetMissingRequiredFields(recordType); len(fieldErrors) > 0 {
+		p.handleAPIError(w, &serializer.APIErrorResponse{ID: constants.APIErrorIDMissingRequiredFields, StatusCode: http.StatusBadRequest, Message: constants.APIErrorMissingRequiredFields, FieldErrors: fieldErrors})
+		return
+	}
+
 	recordID := pathParams[constants.PathParamRecordID]
 	client := p.GetClientFromRequest(r)
 	statusCode, err := client.UpdateStateOfRecordInServiceNow(recordType, recordID, payload)
 	if err != nil {
+		var serviceNowErr *ServiceNowError
+		if errors.As(err, &serviceNowErr) {
+			if fieldErrors := serviceNowErr.FieldErrors(); len(fieldErrors) > 0 {
+				p.API.LogDebug("Missing required fields for updating the state", "Record ID", recordID, "Error", err.Error())
+				p.handleAPIError(w, &serializer.APIErrorResponse{ID: constants.APIErrorIDMissingRequiredFields, StatusCode: http.StatusBadRequest, Message: constants.APIErrorMissingRequiredFields, FieldErrors: fieldErrors})
+				return
+			}
+		}
+
 		p.API.LogError("Error in updating the state", "Record ID", recordID, "Error", err.Error())
 		_ = p.handleClientError(w, r, err, false, statusCode, "", fmt.Sprintf("Error in updating the state. Error: %s", err.Error()))
 		return
@@ -681,6 +1083,14 @@ func (p *Plugin) updateStateOfRecord(w http.ResponseWriter, r *http.Request) {
 }
 
 func (p *Plugin) handleOpenCommentModal(w http.ResponseWriter, r *http.Request) {
+	p.openRecordDialog(w, r, p.OpenCommentDialog)
+}
+
+func (p *Plugin) handleOpenStateModal(w http.ResponseWriter, r *http.Request) {
+	p.openRecordDialog(w, r, p.OpenStateDialog)
+}
+
+func (p *Plugin) handleApprovalAction(w http.ResponseWriter, r *http.Request) {
 	response := &model.PostActionIntegrationResponse{}
 	decoder := json.NewDecoder(r.Body)
 	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
@@ -690,34 +1100,179 @@ func (p *Plugin) handleOpenCommentModal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenCommentModal,
-		postActionIntegrationRequest.Context,
-		&model.WebsocketBroadcast{UserId: postActionIntegrationRequest.UserId},
-	)
+	approvalID, _ := postActionIntegrationRequest.Context[constants.ContextNameApprovalID].(string)
+	action, _ := postActionIntegrationRequest.Context[constants.ContextNameAction].(string)
+	number, _ := postActionIntegrationRequest.Context[constants.ContextNameNumber].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, approvalID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
 
-	p.returnPostActionIntegrationResponse(w, response)
-}
+	user, err := p.GetUser(postActionIntegrationRequest.UserId)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
 
-func (p *Plugin) handleOpenStateModal(w http.ResponseWriter, r *http.Request) {
-	response := &model.PostActionIntegrationResponse{}
-	decoder := json.NewDecoder(r.Body)
-	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
-	if err := decoder.Decode(&postActionIntegrationRequest); err != nil {
-		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+	approvalPost, errorMessage := p.getApprovalPostForApprover(approvalID, user)
+	if errorMessage == "" && approvalPost.PostID != postActionIntegrationRequest.PostId {
+		errorMessage = constants.APIErrorApprovalNotAllowed
+	}
+
+	if errorMessage != "" {
+		response.EphemeralText = errorMessage
 		p.returnPostActionIntegrationResponse(w, response)
 		return
 	}
 
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenUpdateStateModal,
-		postActionIntegrationRequest.Context,
-		&model.WebsocketBroadcast{UserId: postActionIntegrationRequest.UserId},
-	)
+	switch action {
+	case constants.ApprovalStateRejected:
+		if err := p.API.OpenInteractiveDialog(model.OpenDialogRequest{
+			TriggerId: postActionIntegrationRequest.TriggerId,
+			URL:       fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathApprovalRejectDialog),
+			Dialog: model.Dialog{
+				CallbackId:  approvalID,
+				Title:       fmt.Sprintf("Reject %s", number),
+				SubmitLabel: "Reject",
+				Elements: []model.DialogElement{
+					{
+						DisplayName: "Comment",
+						Name:        constants.DialogElementComments,
+						Type:        "textarea",
+						HelpText:    "A comment is required for rejecting the approval.",
+					},
+				},
+			},
+		}); err != nil {
+			p.API.LogError("Unable to open the dialog for rejecting the approval", "Error", err.Error())
+			response.EphemeralText = genericErrorMessage
+		}
+	case constants.ApprovalStateApproved:
+		client := p.GetClientFromRequest(r)
+		if statusCode, err := client.UpdateApprovalInServiceNow(approvalID, &serializer.ServiceNowApprovalPayload{State: constants.ApprovalStateApproved}); err != nil {
+			p.API.LogError(constants.ErrorUpdateApproval, "ApprovalID", approvalID, "Error", err.Error())
+			response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, user.MattermostUserID, "")
+			break
+		}
+
+		post, err := p.getApprovalOutcomePost(approvalPost, constants.ApprovalStateApproved, user.Username)
+		if err != nil {
+			p.API.LogError("Unable to get the approval post", "PostID", approvalPost.PostID, "Error", err.Error())
+			break
+		}
+
+		response.Update = post
+	default:
+		response.EphemeralText = genericErrorMessage
+	}
 
 	p.returnPostActionIntegrationResponse(w, response)
 }
 
+func (p *Plugin) handleApprovalRejectDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	comments, _ := submitDialogRequest.Submission[constants.DialogElementComments].(string)
+	comments = strings.TrimSpace(comments)
+	if comments == "" {
+		response.Errors = map[string]string{
+			constants.DialogElementComments: constants.ErrorEmptyComment,
+		}
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	user, err := p.GetUser(submitDialogRequest.UserId)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	approvalID := submitDialogRequest.CallbackId
+	approvalPost, errorMessage := p.getApprovalPostForApprover(approvalID, user)
+	if errorMessage != "" {
+		response.Error = errorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	client := p.GetClientFromRequest(r)
+	statusCode, err := client.UpdateApprovalInServiceNow(approvalID, &serializer.ServiceNowApprovalPayload{
+		State:    constants.ApprovalStateRejected,
+		Comments: comments,
+	})
+	if err != nil {
+		p.API.LogError(constants.ErrorUpdateApproval, "ApprovalID", approvalID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, user.MattermostUserID, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	post, err := p.getApprovalOutcomePost(approvalPost, constants.ApprovalStateRejected, user.Username)
+	if err != nil {
+		p.API.LogError("Unable to get the approval post", "PostID", approvalPost.PostID, "Error", err.Error())
+	} else if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		p.API.LogError("Unable to update the approval post", "PostID", post.Id, "Error", appErr.Error())
+	}
+
+	p.returnSubmitDialogResponse(w, response)
+}
+
+// getApprovalPostForApprover returns the DM posted for the approval along with the message to show if the user is not its approver
+func (p *Plugin) getApprovalPostForApprover(approvalID string, user *serializer.User) (*serializer.ApprovalPost, string) {
+	approvalPost, err := p.store.LoadApprovalPost(approvalID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, constants.APIErrorApprovalNotAllowed
+		}
+
+		p.API.LogError(constants.ErrorGetApprovalPost, "ApprovalID", approvalID, "Error", err.Error())
+		return nil, genericErrorMessage
+	}
+
+	if approvalPost.MattermostUserID != user.MattermostUserID || user.ServiceNowUser == nil || user.ServiceNowUser.UserID != approvalPost.ApproverID {
+		return nil, constants.APIErrorApprovalNotAllowed
+	}
+
+	return approvalPost, ""
+}
+
+// getApprovalOutcomePost returns the approval post updated with the outcome after verifying it is the bot's DM to the approver
+func (p *Plugin) getApprovalOutcomePost(approvalPost *serializer.ApprovalPost, state, username string) (*model.Post, error) {
+	if err := p.store.DeleteApprovalPost(approvalPost.ApprovalID); err != nil {
+		p.API.LogError("Unable to delete the approval post", "ApprovalID", approvalPost.ApprovalID, "Error", err.Error())
+	}
+
+	post, appErr := p.API.GetPost(approvalPost.PostID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	channel, appErr := p.API.GetDirectChannel(approvalPost.MattermostUserID, p.botID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if post.UserId != p.botID || post.ChannelId != channel.Id {
+		return nil, errors.New("the post is not a DM from the bot to the approver")
+	}
+
+	serializer.UpdateApprovalPostWithOutcome(post, state, username)
+	return post, nil
+}
+
 func (p *Plugin) handleGetUsers(w http.ResponseWriter, _ *http.Request) {
 	users, err := p.store.GetAllUsers()
 	if err != nil {
@@ -758,30 +1313,13 @@ func (p *Plugin) createIncident(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	record := serializer.ServiceNowRecord{
-		SysID:            response.SysID,
-		Number:           response.Number,
-		ShortDescription: response.ShortDescription,
-		Description:      response.Description,
-		RecordType:       constants.RecordTypeIncident,
-		State:            response.State,
-		Priority:         response.Priority,
-		AssignedTo:       response.AssignedTo,
-		AssignmentGroup:  response.AssignmentGroup,
-	}
-
-	if err := record.HandleNestedFields(p.getConfiguration().ServiceNowBaseURL); err != nil {
+	record, err := p.shareCreatedIncident(client, incident.ChannelID, response)
+	if err != nil {
 		p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
 		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusInternalServerError, Message: fmt.Sprintf("%s. Error: %s", constants.ErrorHandlingNestedFields, err.Error())})
 		return
 	}
 
-	channelID := incident.ChannelID
-	post := record.CreateSharingPost(channelID, p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL(), "")
-	if _, postErr := p.API.CreatePost(post); postErr != nil {
-		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
-	}
-
 	p.writeJSON(w, statusCode, record)
 }
 
@@ -820,6 +1358,14 @@ func (p *Plugin) returnPostActionIntegrationResponse(w http.ResponseWriter, res
 	}
 }
 
+func (p *Plugin) returnSubmitDialogResponse(w http.ResponseWriter, res *model.SubmitDialogResponse) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		p.API.LogWarn("failed to write SubmitDialogResponse", "Error", err.Error())
+	}
+}
+
 // handleStaticFiles handles the static files under the assets directory.
 func (p *Plugin) handleStaticFiles(r *mux.Router) {
 	bundlePath, err := p.API.GetBundlePath()
@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"bou.ke/monkey"
 	"github.com/mattermost/mattermost/server/public/model"
@@ -920,6 +921,7 @@ func TestUpdateStateOfRecord(t *testing.T) {
 		SetupClient          func(client *mock_plugin.Client)
 		ExpectedStatusCode   int
 		ExpectedErrorMessage string
+		ExpectedFieldErrors  []string
 	}{
 		"success": {
 			RecordType: constants.RecordTypeIncident,
@@ -979,6 +981,39 @@ func TestUpdateStateOfRecord(t *testing.T) {
 			ExpectedStatusCode:   http.StatusInternalServerError,
 			ExpectedErrorMessage: "update state error",
 		},
+		"missing required fields for resolving an incident": {
+			RecordType: constants.RecordTypeIncident,
+			RequestBody: `{
+				"state": "6",
+				"close_code": "Solution provided"
+			}`,
+			SetupAPI:             func(api *plugintest.API) {},
+			SetupClient:          func(client *mock_plugin.Client) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.APIErrorMissingRequiredFields,
+			ExpectedFieldErrors:  []string{constants.FieldCloseNotes},
+		},
+		"required fields reported by a ServiceNow data policy": {
+			RecordType: constants.RecordTypeIncident,
+			RequestBody: `{
+				"state": "mockState"
+			}`,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("UpdateStateOfRecordInServiceNow", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*serializer.ServiceNowUpdateStatePayload")).Return(
+					http.StatusForbidden, &ServiceNowError{
+						StatusCode: http.StatusForbidden,
+						Message:    "Operation Failed",
+						Detail:     "Data Policy Exception: \n\tThe following fields are mandatory: Resolution code, Resolution notes\n",
+					},
+				)
+			},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.APIErrorMissingRequiredFields,
+			ExpectedFieldErrors:  []string{constants.FieldCloseCode, constants.FieldCloseNotes},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
@@ -1006,6 +1041,13 @@ func TestUpdateStateOfRecord(t *testing.T) {
 				require.Nil(t, err)
 
 				assert.Contains(resp.Message, test.ExpectedErrorMessage)
+				fields := []string{}
+				for _, fieldError := range resp.FieldErrors {
+					fields = append(fields, fieldError.Field)
+				}
+				if test.ExpectedFieldErrors != nil {
+					assert.Equal(test.ExpectedFieldErrors, fields)
+				}
 			}
 		})
 	}
@@ -1015,7 +1057,9 @@ func TestHandleNotification(t *testing.T) {
 	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathProcessNotification)
 	for name, test := range map[string]struct {
 		RequestBody        string
+		Secret             string
 		SetupAPI           func(*plugintest.API)
+		SetupStore         func(*mock_plugin.Store)
 		ExpectedStatusCode int
 	}{
 		"success": {
@@ -1023,6 +1067,9 @@ func TestHandleNotification(t *testing.T) {
 			SetupAPI: func(api *plugintest.API) {
 				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
 			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+			},
 			ExpectedStatusCode: http.StatusOK,
 		},
 		"invalid request body": {
@@ -1030,6 +1077,7 @@ func TestHandleNotification(t *testing.T) {
 			SetupAPI: func(api *plugintest.API) {
 				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
 			},
+			SetupStore:         func(s *mock_plugin.Store) {},
 			ExpectedStatusCode: http.StatusBadRequest,
 		},
 		"failed to create post": {
@@ -1038,19 +1086,169 @@ func TestHandleNotification(t *testing.T) {
 				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, testutils.GetBadRequestAppError())
 				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
 			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"stage of a requested item changed": {
+			RequestBody: fmt.Sprintf(`{"mm_channel_id": "%s", "record_type": "%s", "record_id": "%s", "event_occurred": "%s", "stage": "Fulfillment"}`, testutils.GetChannelID(), constants.RecordTypeRequestItem, testutils.GetServiceNowSysID(), constants.SubscriptionEventStage),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID()
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"notification of an additional instance": {
+			RequestBody: fmt.Sprintf(`{"mm_channel_id": "%s", "record_type": "%s", "record_id": "%s"}`, testutils.GetChannelID(), constants.RecordTypeIncident, testutils.GetServiceNowSysID()),
+			Secret:      "mockHRWebhookSecret",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					actions := post.Attachments()[0].Actions
+					for _, action := range actions {
+						if action.Integration.Context[constants.ContextNameInstanceID] != "hr" {
+							return false
+						}
+					}
+					return len(actions) == 3
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"diagnostic event": {
+			RequestBody:        fmt.Sprintf(`{"event_occurred": "%s"}`, constants.SubscriptionEventDiagnostic),
+			SetupAPI:           func(api *plugintest.API) {},
+			SetupStore:         func(s *mock_plugin.Store) {},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"channel is muted": {
+			RequestBody: fmt.Sprintf(`{"mm_channel_id": "%s", "record_id": "%s"}`, testutils.GetChannelID(), testutils.GetServiceNowSysID()),
+			SetupAPI:    func(api *plugintest.API) {},
+			SetupStore: func(s *mock_plugin.Store) {
+				mute := &serializer.SubscriptionMute{ChannelID: testutils.GetChannelID(), MutedUntil: time.Now().Add(time.Hour).UnixMilli(), Summary: true}
+				s.On("LoadMute", serializer.GetMuteKey(testutils.GetChannelID(), "", "")).Return(mute, nil)
+				s.On("AddMutedEvent", mute.Key(), mock.MatchedBy(func(event *serializer.MutedEvent) bool {
+					return event.RecordID == testutils.GetServiceNowSysID()
+				})).Return(nil)
+			},
 			ExpectedStatusCode: http.StatusOK,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			store := mock_plugin.NewStore(t)
+			test.SetupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			secret := testutils.GetSecret()
+			if test.Secret != "" {
+				config := getMockInstancesConfiguration()
+				config.WebhookSecret = secret
+				p.setConfiguration(config)
+				secret = test.Secret
+			}
+			test.SetupAPI(api)
+			defer api.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			queryParams := url.Values{
+				"secret": {secret},
+			}
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(test.RequestBody))
+			r.URL.RawQuery = queryParams.Encode()
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandlePersonalNotification(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathProcessPersonal)
+	for name, test := range map[string]struct {
+		RequestBody        string
+		Secret             string
+		SetupAPI           func(*plugintest.API)
+		SetupStore         func(*mock_plugin.Store)
+		ExpectedStatusCode int
+	}{
+		"user the item is requested for is notified of the stage change": {
+			RequestBody: fmt.Sprintf(`{"record_type": "%s", "record_id": "%s", "event_occurred": "%s", "requested_for_sys_id": "%s"}`, constants.RecordTypeRequestItem, testutils.GetServiceNowSysID(), constants.SubscriptionEventStage, testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", testutils.GetID(), mock.AnythingOfType("string")).Return(&model.Channel{Id: testutils.GetChannelID()}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID() && post.Message == constants.FormattedPersonalSubscriptionEvents[constants.PersonalSubscriptionEventMyRequests]
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{Events: []string{constants.PersonalSubscriptionEventMyRequests}}, nil)
+				s.On("MarkPersonalNotificationSent", testutils.GetID(), testutils.GetServiceNowSysID(), constants.SubscriptionEventStage).Return(true, nil)
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"member of the assignment group is notified": {
+			RequestBody: fmt.Sprintf(`{"record_type": "%s", "record_id": "%s", "event_occurred": "%s", "assignment_group_sys_id": "mockGroupID", "assignment_group_member_sys_ids": ["%s"]}`, constants.RecordTypeIncident, testutils.GetServiceNowSysID(), constants.SubscriptionEventAssignmentGroup, testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", testutils.GetID(), mock.AnythingOfType("string")).Return(&model.Channel{Id: testutils.GetChannelID()}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID() && post.Message == constants.FormattedPersonalSubscriptionEvents[constants.PersonalSubscriptionEventAssignedToMyGroups]
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{Events: []string{constants.PersonalSubscriptionEventAssignedToMyGroups}}, nil)
+				s.On("MarkPersonalNotificationSent", testutils.GetID(), testutils.GetServiceNowSysID(), constants.SubscriptionEventAssignmentGroup).Return(true, nil)
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"invalid request body": {
+			RequestBody: "",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupStore:         func(s *mock_plugin.Store) {},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		"notification sent by another instance is rejected": {
+			RequestBody: fmt.Sprintf(`{"record_type": "%s", "record_id": "%s", "event_occurred": "%s", "requested_for_sys_id": "%s"}`, constants.RecordTypeRequestItem, testutils.GetServiceNowSysID(), constants.SubscriptionEventStage, testutils.GetServiceNowSysID()),
+			Secret:      "mockHRWebhookSecret",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogWarn", constants.ErrorPersonalNotificationInstance, "InstanceID", "hr", "Path", mock.AnythingOfType("string")).Return()
+			},
+			SetupStore:         func(s *mock_plugin.Store) {},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			store := mock_plugin.NewStore(t)
+			test.SetupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
 			test.SetupAPI(api)
 			defer api.AssertExpectations(t)
 
+			secret := testutils.GetSecret()
+			if test.Secret != "" {
+				p.setConfiguration(getMockInstancesConfiguration())
+				secret = test.Secret
+			}
+
 			w := httptest.NewRecorder()
 			queryParams := url.Values{
-				"secret": {testutils.GetSecret()},
+				"secret": {secret},
 			}
 			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(test.RequestBody))
 			r.URL.RawQuery = queryParams.Encode()
@@ -1065,6 +1263,255 @@ func TestHandleNotification(t *testing.T) {
 	}
 }
 
+func TestHandleApprovalNotification(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathProcessApproval)
+	for name, test := range map[string]struct {
+		RequestBody        string
+		Secret             string
+		SetupAPI           func(*plugintest.API)
+		SetupStore         func(*mock_plugin.Store)
+		SetupPlugin        func(p *Plugin)
+		ExpectedStatusCode int
+	}{
+		"success": {
+			RequestBody: fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "requested"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", mock.Anything, mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(testutils.GetPost(), nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("StoreApprovalPost", mock.MatchedBy(func(approvalPost *serializer.ApprovalPost) bool {
+					return approvalPost.ApprovalID == testutils.GetServiceNowSysID() && approvalPost.MattermostUserID == testutils.GetID() && approvalPost.PostID != ""
+				})).Return(nil)
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetMattermostUserIDFromServiceNowUserID", func(_ *Plugin, _ string) (string, error) {
+					return testutils.GetID(), nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetPersonalSubscription", func(_ *Plugin, _ string) (*serializer.PersonalSubscription, error) {
+					return &serializer.PersonalSubscription{Events: []string{constants.PersonalSubscriptionEventApprovals}}, nil
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"approvals are disabled in the personal subscription": {
+			RequestBody: fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "requested"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			SetupAPI:    func(api *plugintest.API) {},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetMattermostUserIDFromServiceNowUserID", func(_ *Plugin, _ string) (string, error) {
+					return testutils.GetID(), nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetPersonalSubscription", func(_ *Plugin, _ string) (*serializer.PersonalSubscription, error) {
+					return &serializer.PersonalSubscription{}, nil
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"approval is not in requested state": {
+			RequestBody:        fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "approved"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			SetupAPI:           func(api *plugintest.API) {},
+			SetupPlugin:        func(p *Plugin) {},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"approver is not connected": {
+			RequestBody: fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "requested"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetMattermostUserIDFromServiceNowUserID", func(_ *Plugin, _ string) (string, error) {
+					return "", ErrNotFound
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"failed to get the approver": {
+			RequestBody: fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "requested"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetMattermostUserIDFromServiceNowUserID", func(_ *Plugin, _ string) (string, error) {
+					return "", errors.New("mockError")
+				})
+			},
+			ExpectedStatusCode: http.StatusInternalServerError,
+		},
+		"invalid request body": {
+			RequestBody: "",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:        func(p *Plugin) {},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		"approval sent by another instance is rejected": {
+			RequestBody: fmt.Sprintf(`{"sys_id": "%s", "approver": "%s", "state": "requested"}`, testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+			Secret:      "mockHRWebhookSecret",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogWarn", constants.ErrorPersonalNotificationInstance, "InstanceID", "hr", "Path", mock.AnythingOfType("string")).Return()
+			},
+			SetupPlugin:        func(p *Plugin) {},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			store := mock_plugin.NewStore(t)
+			if test.SetupStore != nil {
+				test.SetupStore(store)
+			}
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			test.SetupAPI(api)
+			test.SetupPlugin(p)
+			defer api.AssertExpectations(t)
+
+			secret := testutils.GetSecret()
+			if test.Secret != "" {
+				p.setConfiguration(getMockInstancesConfiguration())
+				secret = test.Secret
+			}
+
+			w := httptest.NewRecorder()
+			queryParams := url.Values{
+				"secret": {secret},
+			}
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(test.RequestBody))
+			r.URL.RawQuery = queryParams.Encode()
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestHandleApprovalAction(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathApprovalAction)
+	channel := testutils.GetChannel(model.ChannelTypeDirect)
+	approvalPost := &serializer.ApprovalPost{
+		ApprovalID:       testutils.GetServiceNowSysID(),
+		ApproverID:       testutils.GetServiceNowSysID(),
+		MattermostUserID: testutils.GetID(),
+		PostID:           testutils.GetID(),
+	}
+	for name, test := range map[string]struct {
+		Action                string
+		SetupAPI              func(*plugintest.API)
+		SetupStore            func(*mock_plugin.Store)
+		SetupClient           func(client *mock_plugin.Client)
+		ExpectedEphemeralText string
+		ExpectUpdate          bool
+	}{
+		"approve: success": {
+			Action: constants.ApprovalStateApproved,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetPost", testutils.GetID()).Return(&model.Post{Id: testutils.GetID(), ChannelId: channel.Id}, nil)
+				api.On("GetDirectChannel", testutils.GetID(), "").Return(channel, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadApprovalPost", testutils.GetServiceNowSysID()).Return(approvalPost, nil)
+				s.On("DeleteApprovalPost", testutils.GetServiceNowSysID()).Return(nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("UpdateApprovalInServiceNow", testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.ServiceNowApprovalPayload")).Return(http.StatusOK, nil)
+			},
+			ExpectUpdate: true,
+		},
+		"approve: post is not a DM to the approver": {
+			Action: constants.ApprovalStateApproved,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetPost", testutils.GetID()).Return(&model.Post{Id: testutils.GetID(), ChannelId: testutils.GetChannelID()}, nil)
+				api.On("GetDirectChannel", testutils.GetID(), "").Return(channel, nil)
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadApprovalPost", testutils.GetServiceNowSysID()).Return(approvalPost, nil)
+				s.On("DeleteApprovalPost", testutils.GetServiceNowSysID()).Return(nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("UpdateApprovalInServiceNow", testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.ServiceNowApprovalPayload")).Return(http.StatusOK, nil)
+			},
+		},
+		"reject: opens the dialog": {
+			Action: constants.ApprovalStateRejected,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("OpenInteractiveDialog", mock.AnythingOfType("model.OpenDialogRequest")).Return(nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadApprovalPost", testutils.GetServiceNowSysID()).Return(approvalPost, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {},
+		},
+		"user is not the approver": {
+			Action:   constants.ApprovalStateApproved,
+			SetupAPI: func(api *plugintest.API) {},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadApprovalPost", testutils.GetServiceNowSysID()).Return(&serializer.ApprovalPost{
+					ApprovalID:       testutils.GetServiceNowSysID(),
+					ApproverID:       "mockApproverID",
+					MattermostUserID: testutils.GetID(),
+					PostID:           testutils.GetID(),
+				}, nil)
+			},
+			SetupClient:           func(client *mock_plugin.Client) {},
+			ExpectedEphemeralText: constants.APIErrorApprovalNotAllowed,
+		},
+		"approval was not posted to the user": {
+			Action:   constants.ApprovalStateApproved,
+			SetupAPI: func(api *plugintest.API) {},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadApprovalPost", testutils.GetServiceNowSysID()).Return(nil, ErrNotFound)
+			},
+			SetupClient:           func(client *mock_plugin.Client) {},
+			ExpectedEphemeralText: constants.APIErrorApprovalNotAllowed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			store := mock_plugin.NewStore(t)
+			test.SetupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId: testutils.GetID(),
+				PostId: testutils.GetID(),
+				Context: map[string]interface{}{
+					constants.ContextNameApprovalID: testutils.GetServiceNowSysID(),
+					constants.ContextNameAction:     test.Action,
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(http.StatusOK, result.StatusCode)
+			assert.Equal(test.ExpectedEphemeralText, response.EphemeralText)
+			assert.Equal(test.ExpectUpdate, response.Update != nil)
+		})
+	}
+}
+
 func TestCreateSubscription(t *testing.T) {
 	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathCreateSubscription)
 	for name, test := range map[string]struct {
@@ -1334,8 +1781,8 @@ func TestGetAllSubscriptions(t *testing.T) {
 					testutils.GetSubscriptions(4), http.StatusOK, nil,
 				)
 
-				client.On("GetRecordFromServiceNow", constants.RecordTypeProblem, "").Return(
-					testutils.GetServiceNowRecord(), http.StatusOK, nil,
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{""}).Return(
+					[]*serializer.ServiceNowPartialRecord{{Number: testutils.GetServiceNowNumber(), ShortDescription: testutils.GetServiceNowShortDescription()}}, http.StatusOK, nil,
 				)
 			},
 			SetupPlugin: func(p *Plugin) {
@@ -1428,7 +1875,9 @@ func TestGetAllSubscriptions(t *testing.T) {
 			assert := assert.New(t)
 			defer monkey.UnpatchAll()
 
-			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			store := &mock_plugin.Store{}
+			store.On("LoadSubscriptionSettings", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, ErrNotFound).Maybe()
+			p, api := setupTestPlugin(&plugintest.API{}, store)
 			client := setupPluginForSubscriptionsConfiguredMiddleware(p, t)
 			test.SetupClient(client)
 			test.SetupAPI(api)
@@ -1505,7 +1954,9 @@ func TestDeleteSubscription(t *testing.T) {
 			assert := assert.New(t)
 			defer monkey.UnpatchAll()
 
-			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			store := &mock_plugin.Store{}
+			store.On("DeleteSubscriptionSettings", constants.DefaultInstanceID, testutils.GetServiceNowSysID()).Return(nil).Maybe()
+			p, api := setupTestPlugin(&plugintest.API{}, store)
 			client := setupPluginForSubscriptionsConfiguredMiddleware(p, t)
 			test.SetupClient(client)
 			test.SetupAPI(api)
@@ -1538,6 +1989,7 @@ func TestEditSubscription(t *testing.T) {
 		RequestBody          string
 		SetupAPI             func(*plugintest.API)
 		SetupClient          func(client *mock_plugin.Client)
+		SetupStore           func(s *mock_plugin.Store)
 		SetupPlugin          func(p *Plugin)
 		ExpectedStatusCode   int
 		ExpectedErrorMessage string
@@ -1552,6 +2004,34 @@ func TestEditSubscription(t *testing.T) {
 					testutils.GetSubscription(constants.SubscriptionTypeRecord), http.StatusOK, nil,
 				)
 			},
+			SetupStore: func(s *mock_plugin.Store) {},
+			SetupPlugin: func(p *Plugin) {
+				var s *serializer.SubscriptionPayload
+				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
+					return nil
+				})
+
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusOK, nil
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"bulk subscription filtered by a configuration item keeps it as the record": {
+			RequestBody: fmt.Sprintf(`{"user_id": "%s", "channel_id": "%s", "type": "%s"}`, testutils.GetID(), testutils.GetChannelID(), constants.SubscriptionTypeBulk),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(nil, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("EditSubscription", testutils.GetServiceNowSysID(), mock.MatchedBy(func(subscription *serializer.SubscriptionPayload) bool {
+					return *subscription.RecordID == "mockItemID"
+				})).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil,
+				)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", constants.DefaultInstanceID, testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{Mentions: constants.MentionSettingOff, ConfigurationItemID: "mockItemID"}, nil)
+			},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1570,6 +2050,7 @@ func TestEditSubscription(t *testing.T) {
 				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
 			},
 			SetupClient:          func(client *mock_plugin.Client) {},
+			SetupStore:           func(s *mock_plugin.Store) {},
 			SetupPlugin:          func(p *Plugin) {},
 			ExpectedErrorMessage: constants.ErrorUnmarshallingRequestBody,
 			ExpectedStatusCode:   http.StatusBadRequest,
@@ -1580,6 +2061,7 @@ func TestEditSubscription(t *testing.T) {
 				api.On("LogError", mock.AnythingOfType("string"), "Error", "new error").Return()
 			},
 			SetupClient: func(client *mock_plugin.Client) {},
+			SetupStore:  func(s *mock_plugin.Store) {},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1593,6 +2075,7 @@ func TestEditSubscription(t *testing.T) {
 			RequestBody: testutils.GetTestUserAndChannelRequestBody(),
 			SetupAPI:    func(api *plugintest.API) {},
 			SetupClient: func(client *mock_plugin.Client) {},
+			SetupStore:  func(s *mock_plugin.Store) {},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1610,6 +2093,7 @@ func TestEditSubscription(t *testing.T) {
 			RequestBody: testutils.GetTestUserAndChannelRequestBody(),
 			SetupAPI:    func(api *plugintest.API) {},
 			SetupClient: func(client *mock_plugin.Client) {},
+			SetupStore:  func(s *mock_plugin.Store) {},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1633,6 +2117,7 @@ func TestEditSubscription(t *testing.T) {
 					nil, http.StatusForbidden, fmt.Errorf("edit subscription error"),
 				)
 			},
+			SetupStore: func(s *mock_plugin.Store) {},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1657,6 +2142,7 @@ func TestEditSubscription(t *testing.T) {
 					testutils.GetSubscription(constants.SubscriptionTypeRecord), http.StatusOK, nil,
 				)
 			},
+			SetupStore: func(s *mock_plugin.Store) {},
 			SetupPlugin: func(p *Plugin) {
 				var s *serializer.SubscriptionPayload
 				monkey.PatchInstanceMethod(reflect.TypeOf(s), "IsValidForUpdation", func(_ *serializer.SubscriptionPayload, _ string) error {
@@ -1674,7 +2160,9 @@ func TestEditSubscription(t *testing.T) {
 			assert := assert.New(t)
 			defer monkey.UnpatchAll()
 
-			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			store := mock_plugin.NewStore(t)
+			test.SetupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
 			client := setupPluginForSubscriptionsConfiguredMiddleware(p, t)
 			test.SetupClient(client)
 			test.SetupAPI(api)
@@ -1996,3 +2484,291 @@ func TestCreateIncident(t *testing.T) {
 		})
 	}
 }
+
+func TestExportSubscriptionsAPI(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathExportSubscriptions)
+	for name, test := range map[string]struct {
+		QueryParams          string
+		UserRole             string
+		SetupAPI             func(*plugintest.API)
+		SetupClient          func(client *mock_plugin.Client)
+		SetupPlugin          func(p *Plugin)
+		ExpectedStatusCode   int
+		ExpectedErrorMessage string
+		ExpectedContentType  string
+	}{
+		"success": {
+			QueryParams: "?format=csv",
+			UserRole:    model.SystemAdminRoleId,
+			SetupAPI:    func(api *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("ActivateSubscriptions").Return(0, nil)
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetSubscriptionsForExport", func(_ *Plugin, _ Client, _, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+					return testutils.GetSubscriptions(2), http.StatusOK, nil
+				})
+			},
+			ExpectedStatusCode:  http.StatusOK,
+			ExpectedContentType: "text/csv",
+		},
+		"user is not a system admin": {
+			UserRole:             model.SystemUserRoleId,
+			SetupAPI:             func(api *plugintest.API) {},
+			SetupClient:          func(client *mock_plugin.Client) {},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusForbidden,
+			ExpectedErrorMessage: constants.ErrorNotAuthorized,
+		},
+		"invalid format": {
+			QueryParams: "?format=xml",
+			UserRole:    model.SystemAdminRoleId,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", constants.ErrorInvalidQueryParam, "Query param", constants.QueryParamFormat).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("ActivateSubscriptions").Return(0, nil)
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.QueryParamFormat,
+		},
+		"failed to get the subscriptions": {
+			UserRole: model.SystemAdminRoleId,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("ActivateSubscriptions").Return(0, nil)
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetSubscriptionsForExport", func(_ *Plugin, _ Client, _, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+					return nil, http.StatusInternalServerError, fmt.Errorf("mockError")
+				})
+			},
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedErrorMessage: constants.ErrorGetSubscriptions,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			defer monkey.UnpatchAll()
+
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			api.On("GetUser", testutils.GetID()).Return(testutils.GetUser(test.UserRole), nil)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			test.SetupPlugin(p)
+			defer api.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, requestURL+test.QueryParams, nil)
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ExpectedContentType != "" {
+				assert.Equal(test.ExpectedContentType, result.Header.Get("Content-Type"))
+			}
+
+			if test.ExpectedErrorMessage != "" {
+				var resp *serializer.APIErrorResponse
+				err := json.NewDecoder(result.Body).Decode(&resp)
+				require.Nil(t, err)
+
+				assert.Contains(resp.Message, test.ExpectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestImportSubscriptionsAPI(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathImportSubscriptions)
+	for name, test := range map[string]struct {
+		RequestBody          string
+		SetupAPI             func(*plugintest.API)
+		SetupPlugin          func(p *Plugin)
+		ExpectedStatusCode   int
+		ExpectedErrorMessage string
+	}{
+		"success": {
+			RequestBody: fmt.Sprintf(`{"format": "json", "data": "[]", "channel_map": {"%s": "%s"}, "dry_run": true}`, testutils.GetID(), testutils.GetChannelID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogInfo", mock.AnythingOfType("string"), "UserID", testutils.GetID(), "DryRun", true, "Created", 0).Return()
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "ImportSubscriptions", func(_ *Plugin, _ Client, _ []*serializer.SubscriptionResponse, channelMap map[string]string, dryRun bool) *serializer.SubscriptionImportResult {
+					return &serializer.SubscriptionImportResult{DryRun: dryRun}
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"invalid request body": {
+			RequestBody: "",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorUnmarshallingRequestBody,
+		},
+		"invalid data": {
+			RequestBody: `{"format": "json", "data": "invalid"}`,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorValidatingRequestBody,
+		},
+		"invalid channel map": {
+			RequestBody: `{"format": "json", "data": "[]", "channel_map": {"invalid": "invalid"}}`,
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", constants.ErrorInvalidChannelID).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorInvalidChannelID,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			defer monkey.UnpatchAll()
+
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			setupPluginForSubscriptionsConfiguredMiddleware(p, t)
+			api.On("GetUser", testutils.GetID()).Return(testutils.GetUser(model.SystemAdminRoleId), nil)
+			test.SetupAPI(api)
+			test.SetupPlugin(p)
+			defer api.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(test.RequestBody))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ExpectedErrorMessage != "" {
+				var resp *serializer.APIErrorResponse
+				err := json.NewDecoder(result.Body).Decode(&resp)
+				require.Nil(t, err)
+
+				assert.Contains(resp.Message, test.ExpectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateSubscriptionsAPI(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathBulkSubscriptions)
+	for name, test := range map[string]struct {
+		RequestBody          string
+		SetupAPI             func(*plugintest.API)
+		SetupPlugin          func(p *Plugin)
+		ExpectedStatusCode   int
+		ExpectedErrorMessage string
+	}{
+		"success": {
+			RequestBody: fmt.Sprintf(`{"action": "delete", "subscription_ids": ["%s"]}`, testutils.GetServiceNowSysID()),
+			SetupAPI:    func(api *plugintest.API) {},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "BulkUpdateSubscriptions", func(_ *Plugin, _ Client, _ string, request *serializer.BulkSubscriptionRequest) (*serializer.BulkSubscriptionResult, int, error) {
+					return &serializer.BulkSubscriptionResult{Action: request.Action}, http.StatusOK, nil
+				})
+			},
+			ExpectedStatusCode: http.StatusOK,
+		},
+		"invalid request body": {
+			RequestBody: "",
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorUnmarshallingRequestBody,
+		},
+		"invalid action": {
+			RequestBody: fmt.Sprintf(`{"action": "invalid", "subscription_ids": ["%s"]}`, testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorValidatingRequestBody,
+		},
+		"missing subscription events": {
+			RequestBody: fmt.Sprintf(`{"action": "edit_events", "subscription_ids": ["%s"]}`, testutils.GetServiceNowSysID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin:          func(p *Plugin) {},
+			ExpectedStatusCode:   http.StatusBadRequest,
+			ExpectedErrorMessage: constants.ErrorValidatingRequestBody,
+		},
+		"insufficient permissions for the channel": {
+			RequestBody: fmt.Sprintf(`{"action": "deactivate", "channel_id": "%s"}`, testutils.GetChannelID()),
+			SetupAPI:    func(api *plugintest.API) {},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusForbidden, fmt.Errorf(constants.ErrorInsufficientPermissions)
+				})
+			},
+			ExpectedStatusCode:   http.StatusForbidden,
+			ExpectedErrorMessage: constants.ErrorInsufficientPermissions,
+		},
+		"failed to get the subscriptions of the channel": {
+			RequestBody: fmt.Sprintf(`{"action": "deactivate", "channel_id": "%s"}`, testutils.GetChannelID()),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			SetupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusOK, nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "BulkUpdateSubscriptions", func(_ *Plugin, _ Client, _ string, _ *serializer.BulkSubscriptionRequest) (*serializer.BulkSubscriptionResult, int, error) {
+					return nil, http.StatusInternalServerError, fmt.Errorf("mockError")
+				})
+			},
+			ExpectedStatusCode:   http.StatusInternalServerError,
+			ExpectedErrorMessage: constants.ErrorBulkUpdateSubscriptions,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			defer monkey.UnpatchAll()
+
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			setupPluginForSubscriptionsConfiguredMiddleware(p, t)
+			test.SetupAPI(api)
+			test.SetupPlugin(p)
+			defer api.AssertExpectations(t)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBufferString(test.RequestBody))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			assert.Equal(test.ExpectedStatusCode, result.StatusCode)
+			if test.ExpectedErrorMessage != "" {
+				var resp *serializer.APIErrorResponse
+				err := json.NewDecoder(result.Body).Decode(&resp)
+				require.Nil(t, err)
+
+				assert.Contains(resp.Message, test.ExpectedErrorMessage)
+			}
+		})
+	}
+}
@@ -39,3 +39,49 @@ func (p *Plugin) DM(mattermostUserID, format string, args ...interface{}) (strin
 	}
 	return sentPost.Id, nil
 }
+
+// DMPost creates the given post in the DM channel between the bot and the specified user
+func (p *Plugin) DMPost(mattermostUserID string, post *model.Post) (string, error) {
+	channel, err := p.API.GetDirectChannel(mattermostUserID, p.botID)
+	if err != nil {
+		p.API.LogError("Couldn't get bot's DM channel", "user_id", mattermostUserID, "error", err.Error())
+		return "", err
+	}
+
+	post.ChannelId = channel.Id
+	post.UserId = p.botID
+	sentPost, err := p.API.CreatePost(post)
+	if err != nil {
+		p.API.LogError("Error occurred while creating post", "error", err.Error())
+		return "", err
+	}
+	return sentPost.Id, nil
+}
+
+// DMFile uploads the file to the DM channel between the bot and the specified user and posts it with the given message
+func (p *Plugin) DMFile(mattermostUserID, fileName string, data []byte, message string) error {
+	channel, err := p.API.GetDirectChannel(mattermostUserID, p.botID)
+	if err != nil {
+		p.API.LogError("Couldn't get bot's DM channel", "user_id", mattermostUserID, "error", err.Error())
+		return err
+	}
+
+	fileInfo, err := p.API.UploadFile(data, channel.Id, fileName)
+	if err != nil {
+		p.API.LogError("Error occurred while uploading the file", "error", err.Error())
+		return err
+	}
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		UserId:    p.botID,
+		Message:   message,
+		FileIds:   []string{fileInfo.Id},
+	}
+	if _, err = p.API.CreatePost(post); err != nil {
+		p.API.LogError("Error occurred while creating post", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
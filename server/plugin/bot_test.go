@@ -67,6 +67,100 @@ func TestDM(t *testing.T) {
 	}
 }
 
+func TestDMPost(t *testing.T) {
+	p := Plugin{}
+	for _, testCase := range []struct {
+		description   string
+		setupAPI      func(*plugintest.API)
+		expectedError string
+	}{
+		{
+			description: "DMPost: post is successfully created",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetDirectChannel", mock.Anything, mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				a.On("CreatePost", mock.Anything).Return(testutils.GetPost(), nil)
+			},
+		},
+		{
+			description: "DMPost: channel is not found",
+			setupAPI: func(a *plugintest.API) {
+				a.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				a.On("GetDirectChannel", mock.Anything, mock.Anything).Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: "channel not found",
+		},
+		{
+			description: "DMPost: error in CreatePost method",
+			setupAPI: func(a *plugintest.API) {
+				a.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				a.On("GetDirectChannel", mock.Anything, mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				a.On("CreatePost", mock.Anything).Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: "error in creating the post",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			mockAPI := &plugintest.API{}
+			testCase.setupAPI(mockAPI)
+			p.SetAPI(mockAPI)
+
+			resp, err := p.DMPost("mockUserID", &model.Post{})
+
+			if testCase.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, resp, "")
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, resp)
+			}
+		})
+	}
+}
+
+func TestDMFile(t *testing.T) {
+	p := Plugin{}
+	for _, testCase := range []struct {
+		description   string
+		setupAPI      func(*plugintest.API)
+		expectedError bool
+	}{
+		{
+			description: "DMFile: file is successfully posted",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetDirectChannel", mock.Anything, mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				a.On("UploadFile", []byte("mockData"), mock.AnythingOfType("string"), "mockFileName").Return(&model.FileInfo{Id: "mockFileID"}, nil)
+				a.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return len(post.FileIds) == 1 && post.FileIds[0] == "mockFileID"
+				})).Return(testutils.GetPost(), nil)
+			},
+		},
+		{
+			description: "DMFile: error in uploading the file",
+			setupAPI: func(a *plugintest.API) {
+				a.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+				a.On("GetDirectChannel", mock.Anything, mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				a.On("UploadFile", []byte("mockData"), mock.AnythingOfType("string"), "mockFileName").Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: true,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			mockAPI := &plugintest.API{}
+			testCase.setupAPI(mockAPI)
+			defer mockAPI.AssertExpectations(t)
+			p.SetAPI(mockAPI)
+
+			err := p.DMFile("mockUserID", "mockFileName", []byte("mockData"), "mockMessage")
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEphemeral(t *testing.T) {
 	p := Plugin{}
 	mockAPI := &plugintest.API{}
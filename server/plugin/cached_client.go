@@ -0,0 +1,263 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+type lookupCacheEntry struct {
+	Data     json.RawMessage `json:"data,omitempty"`
+	CachedAt int64           `json:"cached_at"`
+}
+
+// cachedClient serves the lookups from the lookup caches and calls ServiceNow only when they are not cached.
+// The lookups whose results depend on the ACLs of the user are cached separately for each scope.
+type cachedClient struct {
+	Client
+	plugin *Plugin
+	scope  string
+}
+
+// NewCachedClient returns a client which caches the lookups made using the given client.
+// The scope identifies the user whose token is used by the client.
+func (p *Plugin) NewCachedClient(client Client, scope string) Client {
+	if p.memoryLookupCache == nil {
+		return client
+	}
+
+	return &cachedClient{
+		Client: client,
+		plugin: p,
+		scope:  scope,
+	}
+}
+
+func getRecordLookupKey(recordType, sysID string) string {
+	return fmt.Sprintf("%s/%s", recordType, sysID)
+}
+
+func (c *cachedClient) getPartialRecordKey(recordKey string) string {
+	return fmt.Sprintf("partial_record/%s/%s", c.scope, recordKey)
+}
+
+func getRecordInvalidationKey(recordKey string) string {
+	return fmt.Sprintf("invalidated/%s", recordKey)
+}
+
+// loadLookup loads the cached result of a lookup in v.
+// If the lookup is for a record, the result is ignored if it was cached before the record was invalidated.
+func (p *Plugin) loadLookup(key, recordKey string, v interface{}) bool {
+	for _, cache := range p.getLookupCaches() {
+		data, ok := cache.Get(key)
+		if !ok {
+			continue
+		}
+
+		entry := &lookupCacheEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			p.API.LogWarn("Unable to unmarshal the cached lookup", "Error", err.Error())
+			continue
+		}
+
+		if recordKey != "" && p.getRecordInvalidatedAt(recordKey) >= entry.CachedAt {
+			return false
+		}
+
+		if err := json.Unmarshal(entry.Data, v); err != nil {
+			p.API.LogWarn("Unable to unmarshal the cached lookup", "Error", err.Error())
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// storeLookup caches the result of a lookup which was fetched from ServiceNow at fetchedAt
+func (p *Plugin) storeLookup(key string, v interface{}, ttl time.Duration, fetchedAt int64) {
+	caches := p.getLookupCaches()
+	if len(caches) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		p.API.LogWarn("Unable to marshal the lookup", "Error", err.Error())
+		return
+	}
+
+	entry, err := json.Marshal(&lookupCacheEntry{Data: data, CachedAt: fetchedAt})
+	if err != nil {
+		p.API.LogWarn("Unable to marshal the lookup", "Error", err.Error())
+		return
+	}
+
+	for _, cache := range caches {
+		cache.Set(key, entry, ttl)
+	}
+}
+
+func (p *Plugin) getRecordInvalidatedAt(recordKey string) int64 {
+	var invalidatedAt int64
+	for _, cache := range p.getLookupCaches() {
+		data, ok := cache.Get(getRecordInvalidationKey(recordKey))
+		if !ok {
+			continue
+		}
+
+		entry := &lookupCacheEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			continue
+		}
+
+		if entry.CachedAt > invalidatedAt {
+			invalidatedAt = entry.CachedAt
+		}
+	}
+
+	return invalidatedAt
+}
+
+// InvalidateRecordLookups discards the cached lookups of the record for all the users
+func (p *Plugin) InvalidateRecordLookups(recordType, sysID string) {
+	caches := p.getLookupCaches()
+	if len(caches) == 0 || recordType == "" || sysID == "" {
+		return
+	}
+
+	data, err := json.Marshal(&lookupCacheEntry{CachedAt: time.Now().UnixNano()})
+	if err != nil {
+		return
+	}
+
+	for _, cache := range caches {
+		cache.Set(getRecordInvalidationKey(getRecordLookupKey(recordType, sysID)), data, constants.LookupCacheRecordTTL)
+	}
+}
+
+func (c *cachedClient) GetStatesFromServiceNow(recordType string) ([]*serializer.ServiceNowState, int, error) {
+	key := fmt.Sprintf("states/%s", recordType)
+	var states []*serializer.ServiceNowState
+	if c.plugin.loadLookup(key, "", &states) {
+		return states, http.StatusOK, nil
+	}
+
+	fetchedAt := time.Now().UnixNano()
+	states, statusCode, err := c.Client.GetStatesFromServiceNow(recordType)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	c.plugin.storeLookup(key, states, constants.LookupCacheStatesTTL, fetchedAt)
+	return states, statusCode, nil
+}
+
+func (c *cachedClient) SearchCatalogItemsInServiceNow(searchTerm, limit, offset string) ([]*serializer.ServiceNowCatalogItem, int, error) {
+	key := fmt.Sprintf("catalog/%s/%s/%s/%s", c.scope, searchTerm, limit, offset)
+	var items []*serializer.ServiceNowCatalogItem
+	if c.plugin.loadLookup(key, "", &items) {
+		return items, http.StatusOK, nil
+	}
+
+	fetchedAt := time.Now().UnixNano()
+	items, statusCode, err := c.Client.SearchCatalogItemsInServiceNow(searchTerm, limit, offset)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	c.plugin.storeLookup(key, items, constants.LookupCacheCatalogItemsTTL, fetchedAt)
+	return items, statusCode, nil
+}
+
+func (c *cachedClient) GetGroupMembersFromServiceNow(groupID string) ([]string, int, error) {
+	key := fmt.Sprintf("group_members/%s/%s", c.scope, groupID)
+	var members []string
+	if c.plugin.loadLookup(key, "", &members) {
+		return members, http.StatusOK, nil
+	}
+
+	fetchedAt := time.Now().UnixNano()
+	members, statusCode, err := c.Client.GetGroupMembersFromServiceNow(groupID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	c.plugin.storeLookup(key, members, constants.LookupCacheGroupsTTL, fetchedAt)
+	return members, statusCode, nil
+}
+
+func (c *cachedClient) GetRecordFromServiceNow(tableName, sysID string) (*serializer.ServiceNowRecord, int, error) {
+	recordKey := getRecordLookupKey(tableName, sysID)
+	key := fmt.Sprintf("record/%s/%s", c.scope, recordKey)
+	record := &serializer.ServiceNowRecord{}
+	if c.plugin.loadLookup(key, recordKey, record) {
+		return record, http.StatusOK, nil
+	}
+
+	fetchedAt := time.Now().UnixNano()
+	record, statusCode, err := c.Client.GetRecordFromServiceNow(tableName, sysID)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	c.plugin.storeLookup(key, record, constants.LookupCacheRecordTTL, fetchedAt)
+	return record, statusCode, nil
+}
+
+func (c *cachedClient) GetRecordsFromServiceNow(tableName string, sysIDs []string) ([]*serializer.ServiceNowPartialRecord, int, error) {
+	records := make([]*serializer.ServiceNowPartialRecord, 0, len(sysIDs))
+	uncachedSysIDs := []string{}
+	for _, sysID := range sysIDs {
+		recordKey := getRecordLookupKey(tableName, sysID)
+		record := &serializer.ServiceNowPartialRecord{}
+		if c.plugin.loadLookup(c.getPartialRecordKey(recordKey), recordKey, record) {
+			records = append(records, record)
+			continue
+		}
+
+		uncachedSysIDs = append(uncachedSysIDs, sysID)
+	}
+
+	if len(uncachedSysIDs) == 0 {
+		return records, http.StatusOK, nil
+	}
+
+	fetchedAt := time.Now().UnixNano()
+	fetchedRecords, statusCode, err := c.Client.GetRecordsFromServiceNow(tableName, uncachedSysIDs)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	for _, record := range fetchedRecords {
+		c.plugin.storeLookup(c.getPartialRecordKey(getRecordLookupKey(tableName, record.SysID)), record, constants.LookupCacheRecordTTL, fetchedAt)
+	}
+
+	return append(records, fetchedRecords...), statusCode, nil
+}
+
+func (c *cachedClient) AddComment(recordType, recordID string, payload *serializer.ServiceNowCommentPayload) (int, error) {
+	statusCode, err := c.Client.AddComment(recordType, recordID, payload)
+	if err == nil {
+		c.plugin.InvalidateRecordLookups(recordType, recordID)
+	}
+
+	return statusCode, err
+}
+
+func (c *cachedClient) UpdateStateOfRecordInServiceNow(recordType, recordID string, payload *serializer.ServiceNowUpdateStatePayload) (int, error) {
+	statusCode, err := c.Client.UpdateStateOfRecordInServiceNow(recordType, recordID, payload)
+	if err == nil {
+		c.plugin.InvalidateRecordLookups(recordType, recordID)
+	}
+
+	return statusCode, err
+}
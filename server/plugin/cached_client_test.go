@@ -0,0 +1,135 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func setupCachedClient(t *testing.T) (*Plugin, *mock_plugin.Client, Client) {
+	p, _ := setupTestPlugin(&plugintest.API{}, nil)
+	p.memoryLookupCache = newMemoryLookupCache(constants.MaxLookupCacheEntries)
+	client := mock_plugin.NewClient(t)
+	return p, client, p.NewCachedClient(client, testutils.GetID())
+}
+
+func TestNewCachedClient(t *testing.T) {
+	p, _ := setupTestPlugin(&plugintest.API{}, nil)
+	client := &mock_plugin.Client{}
+	assert.Equal(t, client, p.NewCachedClient(client, testutils.GetID()))
+
+	p.memoryLookupCache = newMemoryLookupCache(constants.MaxLookupCacheEntries)
+	assert.IsType(t, &cachedClient{}, p.NewCachedClient(client, testutils.GetID()))
+}
+
+func TestCachedClientGetStatesFromServiceNow(t *testing.T) {
+	_, client, c := setupCachedClient(t)
+	client.On("GetStatesFromServiceNow", constants.RecordTypeIncident).Return(
+		testutils.GetServiceNowStates(2), http.StatusOK, nil,
+	).Once()
+	client.On("GetStatesFromServiceNow", constants.RecordTypeProblem).Return(
+		nil, http.StatusInternalServerError, fmt.Errorf("mockError"),
+	).Twice()
+
+	for i := 0; i < 2; i++ {
+		states, statusCode, err := c.GetStatesFromServiceNow(constants.RecordTypeIncident)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, testutils.GetServiceNowStates(2), states)
+
+		_, statusCode, err = c.GetStatesFromServiceNow(constants.RecordTypeProblem)
+		assert.EqualError(t, err, "mockError")
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+	}
+}
+
+func TestCachedClientIsScopedByUser(t *testing.T) {
+	p, client, c := setupCachedClient(t)
+	client.On("GetGroupMembersFromServiceNow", "mockGroupID").Return(
+		[]string{"mockMemberID"}, http.StatusOK, nil,
+	).Twice()
+
+	otherClient := p.NewCachedClient(client, "mockOtherUserID")
+	for i := 0; i < 2; i++ {
+		members, _, err := c.GetGroupMembersFromServiceNow("mockGroupID")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mockMemberID"}, members)
+
+		members, _, err = otherClient.GetGroupMembersFromServiceNow("mockGroupID")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mockMemberID"}, members)
+	}
+}
+
+func TestCachedClientGetRecordFromServiceNow(t *testing.T) {
+	p, client, c := setupCachedClient(t)
+	client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(
+		testutils.GetServiceNowRecord(), http.StatusOK, nil,
+	).Twice()
+
+	for i := 0; i < 2; i++ {
+		record, _, err := c.GetRecordFromServiceNow(constants.RecordTypeIncident, testutils.GetServiceNowSysID())
+		assert.NoError(t, err)
+		assert.Equal(t, testutils.GetServiceNowRecord(), record)
+	}
+
+	p.InvalidateRecordLookups(constants.RecordTypeIncident, testutils.GetServiceNowSysID())
+	record, _, err := c.GetRecordFromServiceNow(constants.RecordTypeIncident, testutils.GetServiceNowSysID())
+	assert.NoError(t, err)
+	assert.Equal(t, testutils.GetServiceNowRecord(), record)
+}
+
+func TestCachedClientGetRecordsFromServiceNow(t *testing.T) {
+	p, client, c := setupCachedClient(t)
+	record1 := &serializer.ServiceNowPartialRecord{SysID: "mockSysID1", Number: "PRB1", ShortDescription: "mockDescription1"}
+	record2 := &serializer.ServiceNowPartialRecord{SysID: "mockSysID2", Number: "PRB2", ShortDescription: "mockDescription2"}
+	client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{"mockSysID1"}).Return(
+		[]*serializer.ServiceNowPartialRecord{record1}, http.StatusOK, nil,
+	).Twice()
+	client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{"mockSysID2"}).Return(
+		[]*serializer.ServiceNowPartialRecord{record2}, http.StatusOK, nil,
+	).Once()
+
+	records, _, err := c.GetRecordsFromServiceNow(constants.RecordTypeProblem, []string{"mockSysID1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*serializer.ServiceNowPartialRecord{record1}, records)
+
+	records, _, err = c.GetRecordsFromServiceNow(constants.RecordTypeProblem, []string{"mockSysID1", "mockSysID2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*serializer.ServiceNowPartialRecord{record1, record2}, records)
+
+	p.InvalidateRecordLookups(constants.RecordTypeProblem, "mockSysID1")
+	records, _, err = c.GetRecordsFromServiceNow(constants.RecordTypeProblem, []string{"mockSysID1", "mockSysID2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*serializer.ServiceNowPartialRecord{record2, record1}, records)
+}
+
+func TestCachedClientUpdateStateOfRecordInServiceNow(t *testing.T) {
+	_, client, c := setupCachedClient(t)
+	client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(
+		testutils.GetServiceNowRecord(), http.StatusOK, nil,
+	).Twice()
+	client.On("UpdateStateOfRecordInServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), &serializer.ServiceNowUpdateStatePayload{}).Return(
+		http.StatusOK, nil,
+	).Once()
+
+	_, _, err := c.GetRecordFromServiceNow(constants.RecordTypeIncident, testutils.GetServiceNowSysID())
+	assert.NoError(t, err)
+
+	_, err = c.UpdateStateOfRecordInServiceNow(constants.RecordTypeIncident, testutils.GetServiceNowSysID(), &serializer.ServiceNowUpdateStatePayload{})
+	assert.NoError(t, err)
+
+	_, _, err = c.GetRecordFromServiceNow(constants.RecordTypeIncident, testutils.GetServiceNowSysID())
+	assert.NoError(t, err)
+}
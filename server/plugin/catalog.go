@@ -0,0 +1,427 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+func (p *Plugin) handleCatalog(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, isSysAdmin bool) string {
+	searchTerm := strings.TrimSpace(strings.Trim(strings.Join(parameters, " "), `"`))
+	if len(searchTerm) < constants.CharacterThresholdForSearchingCatalogItems {
+		return fmt.Sprintf(constants.ErrorSearchTermThreshold, constants.CharacterThresholdForSearchingCatalogItems)
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		items, statusCode, err := client.SearchCatalogItemsInServiceNow(searchTerm, fmt.Sprint(constants.MaxCatalogItemsInSearch), "0")
+		if err != nil {
+			p.API.LogError(constants.APIErrorSearchingCatalogItems, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if len(items) == 0 {
+			p.postCommandResponse(args, fmt.Sprintf(constants.CatalogItemNotFoundMessage, searchTerm))
+			return
+		}
+
+		post := serializer.CreateCatalogSearchPost(items, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+		post.UserId = p.botID
+		post.ChannelId = args.ChannelId
+		post.RootId = args.RootId
+		_ = p.API.SendEphemeralPost(args.UserId, post)
+	}()
+
+	return genericWaitMessage
+}
+
+// handleCatalogOrderAction opens the dialog for ordering a catalog item, with its variables as the elements of the dialog
+func (p *Plugin) handleCatalogOrderAction(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	itemID, _ := postActionIntegrationRequest.Context[constants.ContextNameItemID].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, itemID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	client, err := p.GetClientFromMattermostUserID(userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			response.EphemeralText = fmt.Sprintf(notConnectedMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
+		} else {
+			p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+			response.EphemeralText = genericErrorMessage
+		}
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	item, statusCode, err := client.GetCatalogItemFromServiceNow(itemID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetCatalogItem, "Item ID", itemID, "Error", err.Error())
+		response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	elements, supported := getCatalogDialogElements(item.Variables)
+	if !supported {
+		response.EphemeralText = fmt.Sprintf(constants.CatalogUnsupportedItemMessage, item.Name, fmt.Sprintf(constants.PathCatalogItem, p.getConfiguration().ServiceNowBaseURL, itemID))
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	if err := p.openDialog(model.OpenDialogRequest{
+		TriggerId: postActionIntegrationRequest.TriggerId,
+		URL:       fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathCatalogOrderDialog),
+		Dialog: model.Dialog{
+			CallbackId:       itemID,
+			Title:            fmt.Sprintf("Order %s", item.Name),
+			IntroductionText: item.ShortDescription,
+			SubmitLabel:      "Order",
+			State:            item.Name,
+			Elements:         elements,
+		},
+	}); err != nil {
+		p.API.LogError(constants.ErrorOpenDialog, "Item ID", itemID, "Error", err.Error())
+		response.EphemeralText = genericErrorMessage
+	}
+
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+// getCatalogDialogElements maps the variables of a catalog item to the elements of an interactive dialog.
+// It returns false if any of the mandatory variables can't be filled in the dialog.
+func getCatalogDialogElements(variables []*serializer.ServiceNowCatalogVariable) ([]model.DialogElement, bool) {
+	elements := make([]model.DialogElement, 0, len(variables)+1)
+	for _, variable := range variables {
+		if variable.IsLayout() || variable.ReadOnly {
+			continue
+		}
+
+		if !variable.IsSupported() {
+			if variable.Mandatory {
+				return nil, false
+			}
+			continue
+		}
+
+		element := model.DialogElement{
+			DisplayName: variable.Label,
+			Name:        variable.Name,
+			Optional:    !variable.Mandatory,
+			HelpText:    variable.HelpText,
+			Default:     variable.GetDefaultValue(),
+		}
+
+		switch variable.Type {
+		case constants.CatalogVariableTypeSingleLineText, constants.CatalogVariableTypeWideSingleLineText:
+			element.Type = "text"
+			element.MaxLength = constants.CatalogMaxTextLength
+		case constants.CatalogVariableTypeEmail:
+			element.Type = "text"
+			element.SubType = "email"
+		case constants.CatalogVariableTypeMultiLineText:
+			element.Type = "textarea"
+			element.MaxLength = constants.CatalogMaxTextAreaLength
+		case constants.CatalogVariableTypeCheckBox:
+			element.Type = "bool"
+			element.Optional = true
+		case constants.CatalogVariableTypeYesNo:
+			element.Type = "select"
+			element.Options = []*model.PostActionOptions{
+				{Text: constants.CatalogDialogElementOptionYes, Value: constants.CatalogDialogElementOptionYes},
+				{Text: constants.CatalogDialogElementOptionNo, Value: constants.CatalogDialogElementOptionNo},
+			}
+		case constants.CatalogVariableTypeMultipleChoice, constants.CatalogVariableTypeSelectBox, constants.CatalogVariableTypeLookupSelectBox:
+			element.Type = "select"
+			element.Options = getCatalogDialogOptions(variable.Choices)
+		case constants.CatalogVariableTypeReference:
+			// The default values of the references are sys_ids, which can't be shown in the dialog
+			element.Default = ""
+			if variable.IsUserReference() {
+				element.Type = "select"
+				element.DataSource = "users"
+				element.HelpText = joinHelpText(variable.HelpText, constants.CatalogUserReferenceHelpText)
+			} else {
+				element.Type = "text"
+				element.MaxLength = constants.CatalogMaxTextLength
+				element.HelpText = joinHelpText(variable.HelpText, constants.CatalogReferenceHelpText)
+			}
+		}
+
+		if element.Type == "select" && element.DataSource == "" && !hasDialogOption(element.Options, element.Default) {
+			element.Default = ""
+		}
+		elements = append(elements, element)
+	}
+
+	elements = append(elements, model.DialogElement{
+		DisplayName: "Add to cart",
+		Name:        constants.DialogElementAddToCart,
+		Type:        "bool",
+		Optional:    true,
+		HelpText:    constants.CatalogDialogAddToCartHelpText,
+	})
+
+	return elements, true
+}
+
+func joinHelpText(helpText, note string) string {
+	if helpText == "" {
+		return note
+	}
+
+	return fmt.Sprintf("%s %s", helpText, note)
+}
+
+func getCatalogDialogOptions(choices []*serializer.ServiceNowCatalogChoice) []*model.PostActionOptions {
+	options := make([]*model.PostActionOptions, 0, len(choices))
+	for _, choice := range choices {
+		if choice.Value == "" {
+			continue
+		}
+		options = append(options, &model.PostActionOptions{Text: choice.Label, Value: choice.Value})
+	}
+
+	return options
+}
+
+func hasDialogOption(options []*model.PostActionOptions, value string) bool {
+	for _, option := range options {
+		if option.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getCatalogOrderVariables converts the submission of the order dialog to the variables of the order
+func getCatalogOrderVariables(submission map[string]interface{}) map[string]string {
+	variables := make(map[string]string, len(submission))
+	for name, value := range submission {
+		if name == constants.DialogElementAddToCart {
+			continue
+		}
+
+		switch value := value.(type) {
+		case string:
+			variables[name] = value
+		case bool:
+			variables[name] = constants.CatalogDialogElementNo
+			if value {
+				variables[name] = constants.CatalogDialogElementYes
+			}
+		}
+	}
+
+	return variables
+}
+
+// resolveCatalogReferences replaces the values of the reference variables submitted in the order dialog with the sys_ids of their records.
+// The users are selected from the Mattermost users and mapped to their ServiceNow users, the other records are looked up by their display value.
+// The errors of the variables whose records can't be found are returned keyed by the name of the variable.
+func (p *Plugin) resolveCatalogReferences(client Client, variables []*serializer.ServiceNowCatalogVariable, values map[string]string) (map[string]string, int, error) {
+	fieldErrors := map[string]string{}
+	for _, variable := range variables {
+		value := strings.TrimSpace(values[variable.Name])
+		if variable.Type != constants.CatalogVariableTypeReference || value == "" {
+			continue
+		}
+
+		if variable.IsUserReference() {
+			user, err := p.GetUser(value)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, 0, err
+			}
+
+			if user == nil || user.ServiceNowUser == nil {
+				fieldErrors[variable.Name] = constants.CatalogUserNotConnectedMessage
+				continue
+			}
+
+			values[variable.Name] = user.ServiceNowUser.UserID
+			continue
+		}
+
+		records, statusCode, err := client.GetReferenceRecordsFromServiceNow(variable.Reference, variable.GetReferenceQualifier(), value)
+		if err != nil {
+			return nil, statusCode, err
+		}
+
+		switch len(records) {
+		case 0:
+			fieldErrors[variable.Name] = fmt.Sprintf(constants.CatalogReferenceNotFoundMessage, value)
+		case 1:
+			values[variable.Name] = records[0].SysID
+		default:
+			fieldErrors[variable.Name] = fmt.Sprintf(constants.CatalogReferenceAmbiguousMessage, value)
+		}
+	}
+
+	return fieldErrors, 0, nil
+}
+
+func (p *Plugin) handleCatalogOrderDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	itemID := submitDialogRequest.CallbackId
+	itemName := submitDialogRequest.State
+	userID := submitDialogRequest.UserId
+	payload := &serializer.ServiceNowCatalogOrderPayload{
+		Quantity:  constants.CatalogOrderQuantity,
+		Variables: getCatalogOrderVariables(submitDialogRequest.Submission),
+	}
+
+	client := p.GetClientFromRequest(r)
+	item, statusCode, err := client.GetCatalogItemFromServiceNow(itemID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetCatalogItem, "Item ID", itemID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	fieldErrors, statusCode, err := p.resolveCatalogReferences(client, item.Variables, payload.Variables)
+	if err != nil {
+		p.API.LogError("Error in looking up the references of the catalog item", "Item ID", itemID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if len(fieldErrors) > 0 {
+		response.Errors = fieldErrors
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if addToCart, _ := submitDialogRequest.Submission[constants.DialogElementAddToCart].(bool); addToCart {
+		if _, statusCode, err := client.AddCatalogItemToCartInServiceNow(itemID, payload); err != nil {
+			p.API.LogError("Error in adding the catalog item to the cart", "Item ID", itemID, "Error", err.Error())
+			response.Error = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+			p.returnSubmitDialogResponse(w, response)
+			return
+		}
+
+		if _, err := p.DMPost(userID, p.getCatalogCartPost(itemName)); err != nil {
+			p.API.LogError(constants.ErrorCreatePost, "Error", err.Error())
+		}
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	order, statusCode, err := client.OrderCatalogItemInServiceNow(itemID, payload)
+	if err != nil {
+		p.API.LogError(constants.ErrorOrderCatalogItem, "Item ID", itemID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	p.sendCatalogOrder(client, userID, itemName, order)
+	p.returnSubmitDialogResponse(w, response)
+}
+
+// handleCatalogSubmitCart orders all the items present in the cart of the user
+func (p *Plugin) handleCatalogSubmitCart(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	client, err := p.GetClientFromMattermostUserID(userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			response.EphemeralText = fmt.Sprintf(notConnectedMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
+		} else {
+			p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+			response.EphemeralText = genericErrorMessage
+		}
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	order, statusCode, err := client.SubmitCartOrderInServiceNow()
+	if err != nil {
+		p.API.LogError(constants.ErrorOrderCatalogItem, "Error", err.Error())
+		response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	p.sendCatalogOrder(client, userID, constants.CatalogCartName, order)
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+// sendCatalogOrder sends the request created for an order along with its items to the user as a DM
+func (p *Plugin) sendCatalogOrder(client Client, userID, itemName string, order *serializer.ServiceNowCatalogOrder) {
+	requestItems, _, err := client.GetRequestItemsFromServiceNow(order.GetOrderID())
+	if err != nil {
+		p.API.LogWarn("Unable to get the items of the request", "Request ID", order.GetOrderID(), "Error", err.Error())
+	}
+
+	post := serializer.CreateCatalogOrderPost(itemName, p.getConfiguration().ServiceNowBaseURL, order, requestItems)
+	if _, err := p.DMPost(userID, post); err != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", err.Error())
+	}
+}
+
+func (p *Plugin) getCatalogCartPost(itemName string) *model.Post {
+	post := &model.Post{}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{
+		{
+			Text: fmt.Sprintf(constants.CatalogAddedToCartMessage, itemName),
+			Actions: []*model.PostAction{
+				{
+					Type: model.PostActionTypeButton,
+					Name: "Submit cart",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathCatalogSubmitCart),
+					},
+				},
+			},
+		},
+	})
+
+	return post
+}
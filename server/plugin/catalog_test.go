@@ -0,0 +1,197 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetCatalogDialogElements(t *testing.T) {
+	for name, test := range map[string]struct {
+		Variables         []*serializer.ServiceNowCatalogVariable
+		ExpectedSupported bool
+		ExpectedElements  []model.DialogElement
+	}{
+		"variables are mapped to the dialog elements": {
+			Variables: []*serializer.ServiceNowCatalogVariable{
+				{Name: "reason", Label: "Reason", Type: constants.CatalogVariableTypeSingleLineText, Mandatory: true},
+				{Name: "layout", Type: constants.CatalogVariableTypeContainerStart},
+				{Name: "details", Label: "Details", Type: constants.CatalogVariableTypeMultiLineText},
+				{Name: "urgent", Label: "Urgent", Type: constants.CatalogVariableTypeCheckBox, Value: "false"},
+				{Name: "size", Label: "Size", Type: constants.CatalogVariableTypeSelectBox, Value: "unknown", Choices: []*serializer.ServiceNowCatalogChoice{{Label: "Large", Value: "large"}}},
+				{Name: "manager", Label: "Manager", Type: constants.CatalogVariableTypeReference, Reference: constants.RecordTypeUser, Value: testutils.GetServiceNowSysID()},
+				{Name: "location", Label: "Location", Type: constants.CatalogVariableTypeReference, Reference: "cmn_location", HelpText: "Where you work"},
+			},
+			ExpectedSupported: true,
+			ExpectedElements: []model.DialogElement{
+				{DisplayName: "Reason", Name: "reason", Type: "text", MaxLength: constants.CatalogMaxTextLength},
+				{DisplayName: "Details", Name: "details", Type: "textarea", Optional: true, MaxLength: constants.CatalogMaxTextAreaLength},
+				{DisplayName: "Urgent", Name: "urgent", Type: "bool", Optional: true, Default: "false"},
+				{DisplayName: "Size", Name: "size", Type: "select", Optional: true, Options: []*model.PostActionOptions{{Text: "Large", Value: "large"}}},
+				{DisplayName: "Manager", Name: "manager", Type: "select", DataSource: "users", Optional: true, HelpText: constants.CatalogUserReferenceHelpText},
+				{DisplayName: "Location", Name: "location", Type: "text", Optional: true, MaxLength: constants.CatalogMaxTextLength, HelpText: "Where you work " + constants.CatalogReferenceHelpText},
+				{DisplayName: "Add to cart", Name: constants.DialogElementAddToCart, Type: "bool", Optional: true, HelpText: constants.CatalogDialogAddToCartHelpText},
+			},
+		},
+		"mandatory variable is not supported": {
+			Variables: []*serializer.ServiceNowCatalogVariable{
+				{Name: "attachment", Label: "Attachment", Type: 33, Mandatory: true},
+			},
+		},
+		"mandatory reference variable is supported": {
+			Variables: []*serializer.ServiceNowCatalogVariable{
+				{Name: "requested_for", Label: "Requested for", Type: constants.CatalogVariableTypeReference, Reference: constants.RecordTypeUser, Mandatory: true},
+			},
+			ExpectedSupported: true,
+			ExpectedElements: []model.DialogElement{
+				{DisplayName: "Requested for", Name: "requested_for", Type: "select", DataSource: "users", HelpText: constants.CatalogUserReferenceHelpText},
+				{DisplayName: "Add to cart", Name: constants.DialogElementAddToCart, Type: "bool", Optional: true, HelpText: constants.CatalogDialogAddToCartHelpText},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			elements, supported := getCatalogDialogElements(test.Variables)
+			assert.Equal(t, test.ExpectedSupported, supported)
+			assert.Equal(t, test.ExpectedElements, elements)
+		})
+	}
+}
+
+func TestHandleCatalogOrderDialog(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathCatalogOrderDialog)
+	for name, test := range map[string]struct {
+		Submission          map[string]any
+		SetupAPI            func(*plugintest.API)
+		SetupClient         func(*mock_plugin.Client)
+		ExpectedError       string
+		ExpectedFieldErrors map[string]string
+	}{
+		"item is ordered and the request is sent as a DM": {
+			Submission: map[string]any{"reason": "mockReason", "urgent": true},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", testutils.GetID(), "mockBotID").Return(&model.Channel{Id: testutils.GetChannelID()}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID()
+				})).Return(&model.Post{}, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetCatalogItemFromServiceNow", testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowCatalogItemDetails{}, http.StatusOK, nil)
+				client.On("OrderCatalogItemInServiceNow", testutils.GetServiceNowSysID(), &serializer.ServiceNowCatalogOrderPayload{
+					Quantity:  constants.CatalogOrderQuantity,
+					Variables: map[string]string{"reason": "mockReason", "urgent": "true"},
+				}).Return(&serializer.ServiceNowCatalogOrder{RequestID: "mockRequestID", RequestNumber: "REQ0010001"}, http.StatusOK, nil)
+				client.On("GetRequestItemsFromServiceNow", "mockRequestID").Return([]*serializer.ServiceNowRequestItem{{Number: "RITM0010001"}}, http.StatusOK, nil)
+			},
+		},
+		"item is added to the cart": {
+			Submission: map[string]any{"reason": "mockReason", constants.DialogElementAddToCart: true},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", testutils.GetID(), "mockBotID").Return(&model.Channel{Id: testutils.GetChannelID()}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetCatalogItemFromServiceNow", testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowCatalogItemDetails{}, http.StatusOK, nil)
+				client.On("AddCatalogItemToCartInServiceNow", testutils.GetServiceNowSysID(), &serializer.ServiceNowCatalogOrderPayload{
+					Quantity:  constants.CatalogOrderQuantity,
+					Variables: map[string]string{"reason": "mockReason"},
+				}).Return(&serializer.ServiceNowCart{CartID: "mockCartID"}, http.StatusOK, nil)
+			},
+		},
+		"references are replaced with the sys_ids of their records": {
+			Submission: map[string]any{"requested_for": testutils.GetID(), "location": "Paris"},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetDirectChannel", testutils.GetID(), "mockBotID").Return(&model.Channel{Id: testutils.GetChannelID()}, nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetCatalogItemFromServiceNow", testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowCatalogItemDetails{
+					Variables: []*serializer.ServiceNowCatalogVariable{
+						{Name: "requested_for", Type: constants.CatalogVariableTypeReference, Reference: constants.RecordTypeUser},
+						{Name: "location", Type: constants.CatalogVariableTypeReference, Reference: "cmn_location", ReferenceQualifier: "javascript:getLocations()"},
+					},
+				}, http.StatusOK, nil)
+				client.On("GetReferenceRecordsFromServiceNow", "cmn_location", "", "Paris").Return([]*serializer.ServiceNowReferenceRecord{{SysID: "mockLocationID"}}, http.StatusOK, nil)
+				client.On("OrderCatalogItemInServiceNow", testutils.GetServiceNowSysID(), &serializer.ServiceNowCatalogOrderPayload{
+					Quantity:  constants.CatalogOrderQuantity,
+					Variables: map[string]string{"requested_for": testutils.GetServiceNowSysID(), "location": "mockLocationID"},
+				}).Return(&serializer.ServiceNowCatalogOrder{RequestID: "mockRequestID"}, http.StatusOK, nil)
+				client.On("GetRequestItemsFromServiceNow", "mockRequestID").Return(nil, http.StatusOK, nil)
+			},
+		},
+		"record of a reference is not found": {
+			Submission: map[string]any{"location": "Atlantis"},
+			SetupAPI:   func(_ *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetCatalogItemFromServiceNow", testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowCatalogItemDetails{
+					Variables: []*serializer.ServiceNowCatalogVariable{
+						{Name: "location", Type: constants.CatalogVariableTypeReference, Reference: "cmn_location", ReferenceQualifier: "active=true"},
+					},
+				}, http.StatusOK, nil)
+				client.On("GetReferenceRecordsFromServiceNow", "cmn_location", "active=true", "Atlantis").Return(nil, http.StatusOK, nil)
+			},
+			ExpectedFieldErrors: map[string]string{"location": fmt.Sprintf(constants.CatalogReferenceNotFoundMessage, "Atlantis")},
+		},
+		"failed to order the item": {
+			Submission: map[string]any{"reason": "mockReason"},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetCatalogItemFromServiceNow", testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowCatalogItemDetails{}, http.StatusOK, nil)
+				client.On("OrderCatalogItemInServiceNow", testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.ServiceNowCatalogOrderPayload")).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedError: genericErrorMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			p.botID = "mockBotID"
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.SubmitDialogRequest{
+				UserId:     testutils.GetID(),
+				CallbackId: testutils.GetServiceNowSysID(),
+				State:      "mockItem",
+				Submission: test.Submission,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.SubmitDialogResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedError, response.Error)
+			assert.Equal(t, test.ExpectedFieldErrors, response.Errors)
+		})
+	}
+}
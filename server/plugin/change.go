@@ -0,0 +1,117 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+type changeArgs struct {
+	configurationItem string
+	window            time.Duration
+	windowText        string
+}
+
+func (p *Plugin) handleChanges(_ *plugin.Context, args *model.CommandArgs, parameters []string, client Client, isSysAdmin bool) string {
+	if len(parameters) == 0 {
+		return fmt.Sprintf("Invalid changes command. Available commands are '%s' and '%s'.", constants.SubCommandUpcoming, constants.SubCommandConflicts)
+	}
+
+	command := parameters[0]
+	parsed, message := parseChangeArgs(parameters[1:])
+	if message != "" {
+		return message
+	}
+
+	switch command {
+	case constants.SubCommandUpcoming:
+	case constants.SubCommandConflicts:
+		if parsed.configurationItem == "" {
+			return fmt.Sprintf("The configuration item is required for checking the conflicts. Pass it using %s.", constants.FlagCI)
+		}
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", command)
+	}
+
+	client, err := p.getCommandClient(args.UserId, client)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		from := time.Now()
+		to := from.Add(parsed.window)
+		changes, statusCode, err := client.GetScheduledChangesFromServiceNow(from, to, parsed.configurationItem)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetScheduledChanges, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		serviceNowURL := p.getServiceNowURL(client)
+		var message string
+		switch {
+		case command == constants.SubCommandConflicts && len(changes) == 0:
+			p.postCommandResponse(args, fmt.Sprintf(constants.NoConflictingChangesMessage, parsed.configurationItem, parsed.windowText))
+			return
+		case len(changes) == 0:
+			p.postCommandResponse(args, fmt.Sprintf(constants.NoScheduledChangesMessage, parsed.windowText))
+			return
+		case command == constants.SubCommandConflicts:
+			message = serializer.GetFormattedChangeConflicts(parsed.configurationItem, parsed.windowText, changes, serviceNowURL)
+		default:
+			message = serializer.GetFormattedScheduledChanges(changes, from, to, serviceNowURL)
+		}
+
+		if len(changes) == constants.MaxScheduledChanges {
+			message += fmt.Sprintf(constants.ScheduledChangesLimitNote, constants.MaxScheduledChanges)
+		}
+
+		p.postCommandResponse(args, message)
+	}()
+
+	return genericWaitMessage
+}
+
+// parseChangeArgs parses the flags of the changes command
+func parseChangeArgs(params []string) (*changeArgs, string) {
+	parsed := &changeArgs{
+		window:     constants.DefaultChangeWindow,
+		windowText: "7d",
+	}
+
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if i+1 >= len(params) {
+			return nil, fmt.Sprintf("Missing value for %s", param)
+		}
+
+		i++
+		value := strings.TrimSpace(strings.Trim(params[i], `"`))
+		switch param {
+		case constants.FlagCI:
+			parsed.configurationItem = value
+		case constants.FlagWindow:
+			window, err := parseDurationWithDays(value)
+			if err != nil || window <= 0 || window > constants.MaxChangeWindow {
+				return nil, fmt.Sprintf("Unable to get the changes. Error: %s", constants.ErrorInvalidChangeWindow)
+			}
+			parsed.window = window
+			parsed.windowText = value
+		default:
+			return nil, fmt.Sprintf("Unknown flag %s", param)
+		}
+	}
+
+	return parsed, ""
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleChanges(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	scheduledChanges := []*serializer.ServiceNowChangeRequest{
+		{SysID: "mockSysID1", Number: "CHG0010001", ShortDescription: "Upgrade the database", State: constants.ChangeStateScheduled, StartDate: "2036-10-20 10:00:00", EndDate: "2036-10-20 12:00:00", ConfigurationItem: "web01"},
+		{SysID: "mockSysID2", Number: "CHG0010002", ShortDescription: "Patch the kernel", State: constants.ChangeStateImplement, StartDate: "2036-10-20 11:00:00", EndDate: "2036-10-20 13:00:00", ConfigurationItem: "web01"},
+		{SysID: "mockSysID3", Number: "CHG0010003", ShortDescription: "Rotate the certificates", State: constants.ChangeStateScheduled, StartDate: "2036-10-22 09:00:00", EndDate: "2036-10-22 10:00:00", ConfigurationItem: "web01"},
+	}
+	for _, testCase := range []struct {
+		description       string
+		params            []string
+		setupClient       func(*mock_plugin.Client)
+		expectedMessage   string
+		expectedPostCheck func(string) bool
+	}{
+		{
+			description:     "HandleChanges: subcommand is missing",
+			params:          []string{},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "Invalid changes command. Available commands are 'upcoming' and 'conflicts'.",
+		},
+		{
+			description:     "HandleChanges: unknown subcommand",
+			params:          []string{"past"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "Unknown subcommand past",
+		},
+		{
+			description:     "HandleChanges: invalid window",
+			params:          []string{"upcoming", "--window", "1y"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: fmt.Sprintf("Unable to get the changes. Error: %s", constants.ErrorInvalidChangeWindow),
+		},
+		{
+			description:     "HandleChanges: window is too long",
+			params:          []string{"upcoming", "--window", "91d"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: fmt.Sprintf("Unable to get the changes. Error: %s", constants.ErrorInvalidChangeWindow),
+		},
+		{
+			description:     "HandleChanges: missing value of a flag",
+			params:          []string{"upcoming", "--ci"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "Missing value for --ci",
+		},
+		{
+			description:     "HandleChanges: unknown flag",
+			params:          []string{"upcoming", "--team", "mockTeam"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "Unknown flag --team",
+		},
+		{
+			description:     "HandleChanges: configuration item is missing for checking the conflicts",
+			params:          []string{"conflicts"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "The configuration item is required for checking the conflicts. Pass it using --ci.",
+		},
+		{
+			description: "HandleChanges: upcoming changes are listed by day",
+			params:      []string{"upcoming", "--ci", `"web01"`, "--window", "3d"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetScheduledChangesFromServiceNow", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(to time.Time) bool {
+					return time.Until(to) > 71*time.Hour && time.Until(to) <= 72*time.Hour
+				}), "web01").Return(scheduledChanges, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return strings.Count(message, "| Start | End |") == 2 && strings.Contains(message, "##### Monday, Oct 20\n") && strings.Contains(message, "|11:00|13:00 Oct 20|[CHG0010002]") && strings.Contains(message, "|web01|Implement|")
+			},
+		},
+		{
+			description: "HandleChanges: no upcoming changes",
+			params:      []string{"upcoming"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetScheduledChangesFromServiceNow", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "").Return([]*serializer.ServiceNowChangeRequest{}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return message == "No changes are scheduled in the next 7d."
+			},
+		},
+		{
+			description: "HandleChanges: conflicting changes are listed along with the ones overlapping each other",
+			params:      []string{"conflicts", "--ci", "web01"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetScheduledChangesFromServiceNow", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "web01").Return(scheduledChanges, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return strings.HasPrefix(message, "#### Changes of web01 conflicting with the next 7d") && strings.Count(message, "\n|[CHG") == 4 &&
+					strings.Contains(message, "##### Changes overlapping each other") && strings.Contains(message, "|10:00 Oct 20 - 12:00 Oct 20|[CHG0010002]")
+			},
+		},
+		{
+			description: "HandleChanges: a single change conflicts with the window",
+			params:      []string{"conflicts", "--ci", "web01", "--window", "12h"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetScheduledChangesFromServiceNow", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "web01").Return(scheduledChanges[2:], http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return strings.HasPrefix(message, "#### Changes of web01 conflicting with the next 12h") && strings.Contains(message, "|[CHG0010003]") &&
+					strings.Contains(message, "|09:00 Oct 22 - 10:00 Oct 22|Scheduled|") && !strings.Contains(message, "overlapping each other")
+			},
+		},
+		{
+			description: "HandleChanges: no conflicting changes",
+			params:      []string{"conflicts", "--ci", "web01", "--window", "12h"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetScheduledChangesFromServiceNow", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), "web01").Return([]*serializer.ServiceNowChangeRequest{}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return message == "No changes of web01 are scheduled in the next 12h."
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			defer mockAPI.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			if testCase.expectedPostCheck != nil {
+				mockAPI.On("SendEphemeralPost", testutils.GetID(), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					message := args.Get(1).(*model.Post).Message
+					assert.True(t, testCase.expectedPostCheck(message), message)
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleChanges(&plugin.Context{}, args, testCase.params, nil, false)
+			assert.Equal(t, testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
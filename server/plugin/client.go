@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
@@ -22,39 +23,119 @@ type Client interface {
 	CreateSubscription(*serializer.SubscriptionPayload) (*serializer.SubscriptionResponse, int, error)
 	GetSubscription(subscriptionID string) (*serializer.SubscriptionResponse, int, error)
 	GetAllSubscriptions(channelID, userID, subscriptionType, limit, offset string) ([]*serializer.SubscriptionResponse, int, error)
+	GetInactiveSubscriptions(channelID, limit, offset string) ([]*serializer.SubscriptionResponse, int, error)
 	DeleteSubscription(subscriptionID string) (int, error)
 	EditSubscription(subscriptionID string, subscription *serializer.SubscriptionPayload) (*serializer.SubscriptionResponse, int, error)
 	CheckForDuplicateSubscription(*serializer.SubscriptionPayload) (bool, int, error)
 	SearchRecordsInServiceNow(tableName, searchTerm, limit, offset string) ([]*serializer.ServiceNowPartialRecord, int, error)
 	GetRecordFromServiceNow(tableName, sysID string) (*serializer.ServiceNowRecord, int, error)
+	GetRecordsFromServiceNow(tableName string, sysIDs []string) ([]*serializer.ServiceNowPartialRecord, int, error)
+	GetActiveSLAsFromServiceNow(recordIDs []string) ([]*serializer.ServiceNowTaskSLA, int, error)
 	GetAllComments(recordType, recordID string) (*serializer.ServiceNowComment, int, error)
 	AddComment(recordType, recordID string, payload *serializer.ServiceNowCommentPayload) (int, error)
+	AddWorkNote(recordType, recordID string, payload *serializer.ServiceNowWorkNotePayload) (int, error)
 	GetStatesFromServiceNow(recordType string) ([]*serializer.ServiceNowState, int, error)
 	UpdateStateOfRecordInServiceNow(recordType, recordID string, payload *serializer.ServiceNowUpdateStatePayload) (int, error)
 	GetMe(userEmail string) (*serializer.ServiceNowUser, int, error)
 	CreateIncident(*serializer.IncidentPayload) (*serializer.IncidentResponse, int, error)
 	SearchCatalogItemsInServiceNow(searchTerm, limit, offset string) ([]*serializer.ServiceNowCatalogItem, int, error)
+	UpdateApprovalInServiceNow(approvalID string, payload *serializer.ServiceNowApprovalPayload) (int, error)
+	GetGroupMembersFromServiceNow(groupID string) ([]string, int, error)
+	GetGroupByNameFromServiceNow(name string) (*serializer.ServiceNowGroup, int, error)
+	GetCatalogItemFromServiceNow(itemID string) (*serializer.ServiceNowCatalogItemDetails, int, error)
+	GetReferenceRecordsFromServiceNow(tableName, qualifier, value string) ([]*serializer.ServiceNowReferenceRecord, int, error)
+	OrderCatalogItemInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCatalogOrder, int, error)
+	AddCatalogItemToCartInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCart, int, error)
+	SubmitCartOrderInServiceNow() (*serializer.ServiceNowCatalogOrder, int, error)
+	GetRequestItemsFromServiceNow(requestID string) ([]*serializer.ServiceNowRequestItem, int, error)
+	GetOpenRequestItemsFromServiceNow(requestedFor string) ([]*serializer.ServiceNowRequestItem, int, error)
+	SearchConfigurationItemsInServiceNow(searchTerm string) ([]*serializer.ServiceNowConfigurationItem, int, error)
+	GetOpenRecordsOfConfigurationItemFromServiceNow(recordType, itemID string) ([]*serializer.ServiceNowRecord, int, error)
+	SearchKnowledgeArticlesInServiceNow(query string) ([]*serializer.ServiceNowKnowledgeArticle, int, error)
+	GetScheduledChangesFromServiceNow(from, to time.Time, configurationItem string) ([]*serializer.ServiceNowChangeRequest, int, error)
+	GetOnCallUsersFromServiceNow(groupID string, at time.Time) ([]*serializer.ServiceNowOnCallUser, int, error)
+	GetSubscriptionAuthDetails() ([]*serializer.SubscriptionAuthPayload, int, error)
+	GetNotificationsAppVersion() (string, int, error)
 }
 
 type client struct {
 	ctx        context.Context
 	httpClient *http.Client
 	plugin     *Plugin
+
+	// instance is set for the clients of the instances other than the default one
+	instance *serviceNowInstance
+
+	// mattermostUserID is the ID of the user whose token is used by the client. Each call is logged with it.
+	mattermostUserID string
+
+	// identity is set for the clients which are not using the token of the user making the request.
+	// Such clients can only be used for read-only calls and each of their calls is logged with the identity.
+	identity string
 }
 
-func (p *Plugin) NewClient(ctx context.Context, token *oauth2.Token) Client {
+func (p *Plugin) NewClient(ctx context.Context, token *oauth2.Token, mattermostUserID string) Client {
 	httpClient := p.NewOAuth2Config().Client(ctx, token)
+	return &client{
+		ctx:              ctx,
+		httpClient:       httpClient,
+		plugin:           p,
+		mattermostUserID: mattermostUserID,
+	}
+}
+
+// NewInstanceClient returns a client for an instance other than the default one
+func (p *Plugin) NewInstanceClient(ctx context.Context, instance *serviceNowInstance, token *oauth2.Token, mattermostUserID string) Client {
+	return &client{
+		ctx:              ctx,
+		httpClient:       p.newInstanceOAuth2Config(instance).Client(ctx, token),
+		plugin:           p,
+		instance:         instance,
+		mattermostUserID: mattermostUserID,
+	}
+}
+
+// NewServiceAccountClient returns a read-only client authenticated as the service account
+func (p *Plugin) NewServiceAccountClient(ctx context.Context, httpClient *http.Client, identity string) Client {
 	return &client{
 		ctx:        ctx,
 		httpClient: httpClient,
 		plugin:     p,
+		identity:   identity,
+	}
+}
+
+// GetInstanceID returns the ID of the instance called by the client
+func (c *client) GetInstanceID() string {
+	if c.instance == nil {
+		return constants.DefaultInstanceID
+	}
+
+	return c.instance.ID
+}
+
+// GetBaseURL returns the base URL of the instance called by the client
+func (c *client) GetBaseURL() string {
+	if c.instance == nil {
+		return c.plugin.getConfiguration().ServiceNowBaseURL
+	}
+
+	return c.instance.BaseURL
+}
+
+func (c *client) getWebhookSecret() string {
+	if c.instance == nil {
+		return c.plugin.getConfiguration().WebhookSecret
 	}
+
+	return c.instance.WebhookSecret
 }
 
 func (c *client) ActivateSubscriptions() (int, error) {
 	pluginConfig := c.plugin.getConfiguration()
+	webhookSecret := c.getWebhookSecret()
 	subscriptionAuthDetails := &serializer.SubscriptionAuthDetails{}
-	query := fmt.Sprintf("server_url=%s^api_secret=%s", pluginConfig.MattermostSiteURL, pluginConfig.WebhookSecret)
+	query := fmt.Sprintf("server_url=%s^api_secret=%s", pluginConfig.MattermostSiteURL, webhookSecret)
 	queryParams := url.Values{
 		constants.SysQueryParam: {query},
 	}
@@ -77,7 +158,7 @@ func (c *client) ActivateSubscriptions() (int, error) {
 
 	payload := serializer.SubscriptionAuthPayload{
 		ServerURL: pluginConfig.MattermostSiteURL,
-		APISecret: pluginConfig.WebhookSecret,
+		APISecret: webhookSecret,
 	}
 
 	if _, statusCode, err := c.CallJSON(http.MethodPost, constants.PathActivateSubscriptions, payload, nil, nil); err != nil {
@@ -94,6 +175,7 @@ func (c *client) CreateSubscription(subscription *serializer.SubscriptionPayload
 		return nil, statusCode, errors.Wrap(err, "failed to create subscription in ServiceNow")
 	}
 
+	c.setSubscriptionsInstance(subscriptionResult.Result)
 	return subscriptionResult.Result, statusCode, nil
 }
 
@@ -127,6 +209,26 @@ func (c *client) GetAllSubscriptions(channelID, userID, subscriptionType, limit,
 		return nil, statusCode, errors.Wrap(err, "failed to get subscriptions from ServiceNow")
 	}
 
+	c.setSubscriptionsInstance(subscriptions.Result...)
+	return subscriptions.Result, statusCode, nil
+}
+
+// GetInactiveSubscriptions returns the deactivated subscriptions of the channel
+func (c *client) GetInactiveSubscriptions(channelID, limit, offset string) ([]*serializer.SubscriptionResponse, int, error) {
+	query := fmt.Sprintf("is_active=false^server_url=%s^channel_id=%s^ORDERBYDESC%s", c.plugin.getConfiguration().MattermostSiteURL, channelID, constants.FieldSysUpdatedOn)
+	queryParams := url.Values{
+		constants.SysQueryParam:       {query},
+		constants.SysQueryParamLimit:  {limit},
+		constants.SysQueryParamOffset: {offset},
+	}
+
+	subscriptions := &serializer.SubscriptionsResult{}
+	_, statusCode, err := c.CallJSON(http.MethodGet, constants.PathSubscriptionCRUD, nil, subscriptions, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get inactive subscriptions from ServiceNow")
+	}
+
+	c.setSubscriptionsInstance(subscriptions.Result...)
 	return subscriptions.Result, statusCode, nil
 }
 
@@ -137,6 +239,7 @@ func (c *client) GetSubscription(subscriptionID string) (*serializer.Subscriptio
 		return nil, statusCode, errors.Wrap(err, "failed to get subscription from ServiceNow")
 	}
 
+	c.setSubscriptionsInstance(subscription.Result)
 	return subscription.Result, statusCode, nil
 }
 
@@ -155,9 +258,19 @@ func (c *client) EditSubscription(subscriptionID string, subscription *serialize
 		return nil, statusCode, errors.Wrap(err, "failed to update subscription from ServiceNow")
 	}
 
+	c.setSubscriptionsInstance(subscriptionResult.Result)
 	return subscriptionResult.Result, statusCode, nil
 }
 
+// setSubscriptionsInstance sets the instance of the client in the subscriptions returned by ServiceNow
+func (c *client) setSubscriptionsInstance(subscriptions ...*serializer.SubscriptionResponse) {
+	for _, subscription := range subscriptions {
+		if subscription != nil {
+			subscription.InstanceID = c.GetInstanceID()
+		}
+	}
+}
+
 // CheckForDuplicateSubscription returns true and an error if a duplicate subscription exists in ServiceNow
 // The boolean return type value should be checked only if the error being returned is nil
 func (c *client) CheckForDuplicateSubscription(subscription *serializer.SubscriptionPayload) (bool, int, error) {
@@ -178,11 +291,17 @@ func (c *client) CheckForDuplicateSubscription(subscription *serializer.Subscrip
 
 func (c *client) SearchRecordsInServiceNow(tableName, searchTerm, limit, offset string) ([]*serializer.ServiceNowPartialRecord, int, error) {
 	query := fmt.Sprintf("%s LIKE%s ^OR %s STARTSWITH%s", constants.FieldShortDescription, searchTerm, constants.FieldNumber, searchTerm)
+	fields := fmt.Sprintf("%s,%s,%s", constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription)
+	if tableName == constants.RecordTypeConfigurationItem {
+		query = fmt.Sprintf("%s LIKE%s", constants.FieldName, searchTerm)
+		fields = fmt.Sprintf("%s,%s,%s", constants.FieldSysID, constants.FieldName, constants.FieldShortDescription)
+	}
+
 	queryParams := url.Values{
 		constants.SysQueryParam:       {query},
 		constants.SysQueryParamLimit:  {limit},
 		constants.SysQueryParamOffset: {offset},
-		constants.SysQueryParamFields: {fmt.Sprintf("%s,%s,%s", constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription)},
+		constants.SysQueryParamFields: {fields},
 	}
 
 	records := &serializer.ServiceNowPartialRecordsResult{}
@@ -192,6 +311,13 @@ func (c *client) SearchRecordsInServiceNow(tableName, searchTerm, limit, offset
 		return nil, statusCode, err
 	}
 
+	// The configuration items don't have a number, so their name is shown in its place
+	if tableName == constants.RecordTypeConfigurationItem {
+		for _, record := range records.Result {
+			record.Number = record.Name
+		}
+	}
+
 	return records.Result, statusCode, nil
 }
 
@@ -210,6 +336,50 @@ func (c *client) GetRecordFromServiceNow(tableName, sysID string) (*serializer.S
 	return record.Result, statusCode, nil
 }
 
+// GetRecordsFromServiceNow returns the number and short description of the records in a single call
+func (c *client) GetRecordsFromServiceNow(tableName string, sysIDs []string) ([]*serializer.ServiceNowPartialRecord, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%sIN%s", constants.FieldSysID, strings.Join(sysIDs, ","))},
+		constants.SysQueryParamLimit:  {fmt.Sprint(len(sysIDs))},
+		constants.SysQueryParamFields: {fmt.Sprintf("%s,%s,%s", constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription)},
+	}
+
+	records := &serializer.ServiceNowPartialRecordsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", tableName, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, records, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the records from ServiceNow")
+	}
+
+	return records.Result, statusCode, nil
+}
+
+// GetActiveSLAsFromServiceNow returns the active SLAs of the records along with the fields of the records, the most elapsed first
+func (c *client) GetActiveSLAsFromServiceNow(recordIDs []string) ([]*serializer.ServiceNowTaskSLA, int, error) {
+	fields := []string{constants.FieldSysID, constants.FieldSLA, constants.FieldBusinessPercentage, constants.FieldBusinessTimeLeft, constants.FieldHasBreached}
+	// The fields of the records are dot-walked from the task of the SLA
+	for _, field := range []string{constants.FieldSysID, constants.FieldClassName, constants.FieldNumber, constants.FieldShortDescription, constants.FieldState, constants.FieldPriority, constants.FieldAssignedTo, constants.FieldAssignmentGroup} {
+		fields = append(fields, fmt.Sprintf("%s.%s", constants.FieldTask, field))
+	}
+
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {fmt.Sprintf("%s=true^%sIN%s^ORDERBYDESC%s", constants.FieldActive, constants.FieldTask, strings.Join(recordIDs, ","), constants.FieldBusinessPercentage)},
+		constants.SysQueryParamFields:               {strings.Join(fields, ",")},
+		constants.SysQueryParamLimit:                {fmt.Sprint(constants.MaxSLAsPerBatch)},
+		constants.SysQueryParamDisplayValue:         {"true"},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+	}
+
+	slas := &serializer.ServiceNowTaskSLAsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeTaskSLA, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, slas, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the SLAs from ServiceNow")
+	}
+
+	return slas.Result, statusCode, nil
+}
+
 func (c *client) GetAllComments(recordType, recordID string) (*serializer.ServiceNowComment, int, error) {
 	queryParams := url.Values{
 		constants.SysQueryParamDisplayValue: {"true"},
@@ -232,6 +402,16 @@ func (c *client) AddComment(recordType, recordID string, payload *serializer.Ser
 	return statusCode, err
 }
 
+func (c *client) AddWorkNote(recordType, recordID string, payload *serializer.ServiceNowWorkNotePayload) (int, error) {
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", recordType, 1)
+	_, statusCode, err := c.CallJSON(http.MethodPatch, fmt.Sprintf("%s/%s", url, recordID), payload, nil, nil)
+	if err != nil {
+		return statusCode, errors.Wrap(err, "failed to add the work note in ServiceNow")
+	}
+
+	return statusCode, nil
+}
+
 func (c *client) GetStatesFromServiceNow(recordType string) ([]*serializer.ServiceNowState, int, error) {
 	states := &serializer.ServiceNowStatesResult{}
 	url := strings.Replace(constants.PathGetStatesFromServiceNow, "{record_type}", recordType, 1)
@@ -255,7 +435,7 @@ func (c *client) UpdateStateOfRecordInServiceNow(recordType, recordID string, pa
 
 func (c *client) GetMe(userEmail string) (*serializer.ServiceNowUser, int, error) {
 	userList := &serializer.UserList{}
-	path := fmt.Sprintf("%s%s", c.plugin.getConfiguration().ServiceNowBaseURL, constants.PathGetUserFromServiceNow)
+	path := fmt.Sprintf("%s%s", c.GetBaseURL(), constants.PathGetUserFromServiceNow)
 	params := url.Values{}
 	params.Add(constants.SysQueryParam, fmt.Sprintf("email=%s", userEmail))
 
@@ -269,7 +449,7 @@ func (c *client) GetMe(userEmail string) (*serializer.ServiceNowUser, int, error
 	}
 
 	if len(userList.UserDetails) > 1 {
-		c.plugin.API.LogWarn("Multiple users with the same email address exist on ServiceNow instance", "Email", userEmail, "Instance", c.plugin.getConfiguration().ServiceNowBaseURL)
+		c.plugin.API.LogWarn("Multiple users with the same email address exist on ServiceNow instance", "Email", userEmail, "Instance", c.GetBaseURL())
 	}
 
 	return userList.UserDetails[0], statusCode, nil
@@ -305,3 +485,380 @@ func (c *client) SearchCatalogItemsInServiceNow(searchTerm, limit, offset string
 
 	return items.Result, statusCode, nil
 }
+
+func (c *client) UpdateApprovalInServiceNow(approvalID string, payload *serializer.ServiceNowApprovalPayload) (int, error) {
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeApproval, 1)
+	_, statusCode, err := c.CallJSON(http.MethodPatch, fmt.Sprintf("%s/%s", url, approvalID), payload, nil, nil)
+	if err != nil {
+		return statusCode, errors.Wrap(err, "failed to update the approval in ServiceNow")
+	}
+
+	return statusCode, nil
+}
+
+func (c *client) GetGroupMembersFromServiceNow(groupID string) ([]string, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {fmt.Sprintf("%s=%s", constants.FieldGroup, groupID)},
+		constants.SysQueryParamFields:               {constants.FieldUser},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+		// One more member than can be mentioned is fetched to know if the group is too large to be mentioned
+		constants.SysQueryParamLimit: {fmt.Sprint(constants.MaxGroupMentions + 1)},
+	}
+
+	memberships := &serializer.ServiceNowGroupMembershipsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeGroupMember, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, memberships, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the members of the group from ServiceNow")
+	}
+
+	userIDs := make([]string, 0, len(memberships.Result))
+	for _, membership := range memberships.Result {
+		userIDs = append(userIDs, membership.UserID)
+	}
+
+	return userIDs, statusCode, nil
+}
+
+// GetGroupByNameFromServiceNow returns the group with the given name, or nil if there is no such group
+func (c *client) GetGroupByNameFromServiceNow(name string) (*serializer.ServiceNowGroup, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%s=%s", constants.FieldName, strings.ReplaceAll(name, "^", " "))},
+		constants.SysQueryParamFields: {"sys_id,name"},
+		constants.SysQueryParamLimit:  {"1"},
+	}
+
+	groups := &serializer.ServiceNowGroupsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeGroup, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, groups, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the group from ServiceNow")
+	}
+
+	if len(groups.Result) == 0 {
+		return nil, statusCode, nil
+	}
+
+	return groups.Result[0], statusCode, nil
+}
+
+// GetReferenceRecordsFromServiceNow returns the records of a table whose display value is the given value.
+// Only the first records are returned, as more than one match can't be used for a reference.
+func (c *client) GetReferenceRecordsFromServiceNow(tableName, qualifier, value string) ([]*serializer.ServiceNowReferenceRecord, int, error) {
+	displayField, statusCode, err := c.getDisplayFieldFromServiceNow(tableName)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	query := fmt.Sprintf("%s=%s", displayField, strings.ReplaceAll(value, "^", " "))
+	if qualifier != "" {
+		query = fmt.Sprintf("%s^%s", qualifier, query)
+	}
+
+	queryParams := url.Values{
+		constants.SysQueryParam:       {query},
+		constants.SysQueryParamFields: {constants.FieldSysID},
+		constants.SysQueryParamLimit:  {fmt.Sprint(constants.MaxCatalogReferenceMatches)},
+	}
+
+	records := &serializer.ServiceNowReferenceRecordsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", tableName, 1)
+	_, statusCode, err = c.CallJSON(http.MethodGet, url, nil, records, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the reference records from ServiceNow")
+	}
+
+	return records.Result, statusCode, nil
+}
+
+// getDisplayFieldFromServiceNow returns the field holding the display value of the records of a table.
+// The display field is usually defined on a parent table, like for the classes of the CMDB, which are displayed by their name.
+func (c *client) getDisplayFieldFromServiceNow(tableName string) (string, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%s=%s^%s=true", constants.FieldName, tableName, constants.FieldDisplay)},
+		constants.SysQueryParamFields: {constants.FieldElement},
+		constants.SysQueryParamLimit:  {"1"},
+	}
+
+	entries := &serializer.ServiceNowDictionaryEntriesResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeDictionary, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, entries, queryParams)
+	if err != nil {
+		return "", statusCode, errors.Wrap(err, "failed to get the display field from ServiceNow")
+	}
+
+	if len(entries.Result) == 0 || entries.Result[0].Element == "" {
+		return constants.FieldName, statusCode, nil
+	}
+
+	return entries.Result[0].Element, statusCode, nil
+}
+
+// GetCatalogItemFromServiceNow returns the catalog item along with the variables to be filled for ordering it
+func (c *client) GetCatalogItemFromServiceNow(itemID string) (*serializer.ServiceNowCatalogItemDetails, int, error) {
+	item := &serializer.ServiceNowCatalogItemDetailsResult{}
+	path := strings.Replace(constants.PathGetCatalogItemFromServiceNow, "{sys_id}", itemID, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, path, nil, item, nil)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the catalog item from ServiceNow")
+	}
+
+	return item.Result, statusCode, nil
+}
+
+func (c *client) OrderCatalogItemInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCatalogOrder, int, error) {
+	order := &serializer.ServiceNowCatalogOrderResult{}
+	path := strings.Replace(constants.PathOrderNowInServiceNow, "{sys_id}", itemID, 1)
+	_, statusCode, err := c.CallJSON(http.MethodPost, path, payload, order, nil)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to order the catalog item in ServiceNow")
+	}
+
+	return order.Result, statusCode, nil
+}
+
+func (c *client) AddCatalogItemToCartInServiceNow(itemID string, payload *serializer.ServiceNowCatalogOrderPayload) (*serializer.ServiceNowCart, int, error) {
+	cart := &serializer.ServiceNowCartResult{}
+	path := strings.Replace(constants.PathAddToCartInServiceNow, "{sys_id}", itemID, 1)
+	_, statusCode, err := c.CallJSON(http.MethodPost, path, payload, cart, nil)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to add the catalog item to the cart in ServiceNow")
+	}
+
+	return cart.Result, statusCode, nil
+}
+
+// SubmitCartOrderInServiceNow orders all the items in the cart of the user
+func (c *client) SubmitCartOrderInServiceNow() (*serializer.ServiceNowCatalogOrder, int, error) {
+	order := &serializer.ServiceNowCatalogOrderResult{}
+	_, statusCode, err := c.CallJSON(http.MethodPost, constants.PathSubmitOrderInServiceNow, nil, order, nil)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to submit the order in ServiceNow")
+	}
+
+	return order.Result, statusCode, nil
+}
+
+func (c *client) GetRequestItemsFromServiceNow(requestID string) ([]*serializer.ServiceNowRequestItem, int, error) {
+	requestItems, statusCode, err := c.getRequestItems(fmt.Sprintf("%s=%s", constants.FieldRequest, requestID), "")
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the items of the request from ServiceNow")
+	}
+
+	return requestItems, statusCode, nil
+}
+
+// GetOpenRequestItemsFromServiceNow returns the active items requested for the given ServiceNow user, the latest first
+func (c *client) GetOpenRequestItemsFromServiceNow(requestedFor string) ([]*serializer.ServiceNowRequestItem, int, error) {
+	query := fmt.Sprintf("%s=%s^%s=true^ORDERBYDESC%s", constants.FieldRequestedFor, requestedFor, constants.FieldActive, constants.FieldOpenedAt)
+	requestItems, statusCode, err := c.getRequestItems(query, fmt.Sprint(constants.MaxRequestItemsInList))
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the requested items from ServiceNow")
+	}
+
+	return requestItems, statusCode, nil
+}
+
+func (c *client) getRequestItems(query, limit string) ([]*serializer.ServiceNowRequestItem, int, error) {
+	fields := []string{constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription, constants.FieldStage, constants.FieldState, constants.FieldApproval, constants.FieldDueDate, constants.FieldRequest}
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {query},
+		constants.SysQueryParamFields:               {strings.Join(fields, ",")},
+		constants.SysQueryParamDisplayValue:         {"true"},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+	}
+	if limit != "" {
+		queryParams.Set(constants.SysQueryParamLimit, limit)
+	}
+
+	requestItems := &serializer.ServiceNowRequestItemsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeRequestItem, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, requestItems, queryParams)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return requestItems.Result, statusCode, nil
+}
+
+// SearchConfigurationItemsInServiceNow returns the configuration items whose name contains the search term.
+// The items of all the subclasses of cmdb_ci are returned, with their class in the sys_class_name field.
+func (c *client) SearchConfigurationItemsInServiceNow(searchTerm string) ([]*serializer.ServiceNowConfigurationItem, int, error) {
+	fields := []string{constants.FieldSysID, constants.FieldName, constants.FieldClassName, constants.FieldOwnedBy, constants.FieldSupportGroup, constants.FieldOperationalStatus, constants.FieldEnvironment}
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {fmt.Sprintf("%sLIKE%s^ORDERBY%s", constants.FieldName, strings.ReplaceAll(searchTerm, "^", " "), constants.FieldName)},
+		constants.SysQueryParamFields:               {strings.Join(fields, ",")},
+		constants.SysQueryParamLimit:                {fmt.Sprint(constants.MaxConfigurationItemsInSearch)},
+		constants.SysQueryParamDisplayValue:         {"true"},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+	}
+
+	items := &serializer.ServiceNowConfigurationItemsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeConfigurationItem, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, items, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to search for the configuration items in ServiceNow")
+	}
+
+	return items.Result, statusCode, nil
+}
+
+// GetOpenRecordsOfConfigurationItemFromServiceNow returns the active records of the given type raised against a configuration item, the latest first
+func (c *client) GetOpenRecordsOfConfigurationItemFromServiceNow(recordType, itemID string) ([]*serializer.ServiceNowRecord, int, error) {
+	fields := []string{constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription, constants.FieldState, constants.FieldPriority}
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {fmt.Sprintf("%s=%s^%s=true^ORDERBYDESC%s", constants.FieldConfigurationItem, itemID, constants.FieldActive, constants.FieldOpenedAt)},
+		constants.SysQueryParamFields:               {strings.Join(fields, ",")},
+		constants.SysQueryParamLimit:                {fmt.Sprint(constants.MaxConfigurationItemRecords)},
+		constants.SysQueryParamDisplayValue:         {"true"},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+	}
+
+	records := &serializer.ServiceNowRecordsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", recordType, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, records, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the records of the configuration item from ServiceNow")
+	}
+
+	return records.Result, statusCode, nil
+}
+
+// GetScheduledChangesFromServiceNow returns the scheduled or implementing changes whose schedule overlaps the given time range, the earliest first.
+// The changes can be filtered by the sys_id or the name of a configuration item.
+func (c *client) GetScheduledChangesFromServiceNow(from, to time.Time, configurationItem string) ([]*serializer.ServiceNowChangeRequest, int, error) {
+	query := fmt.Sprintf("%sIN%s,%s^%s<%s^%s>%s", constants.FieldState, constants.ChangeStateScheduled, constants.ChangeStateImplement,
+		constants.FieldStartDate, to.UTC().Format(constants.ServiceNowDateTimeLayout), constants.FieldEndDate, from.UTC().Format(constants.ServiceNowDateTimeLayout))
+	if configurationItem = strings.ReplaceAll(configurationItem, "^", ""); configurationItem != "" {
+		query += fmt.Sprintf("^%s=%s^OR%s=%s", constants.FieldConfigurationItem, configurationItem, constants.FieldConfigurationItemRef, configurationItem)
+	}
+
+	fields := []string{constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription, constants.FieldState, constants.FieldStartDate, constants.FieldEndDate, constants.FieldConfigurationItemRef}
+	queryParams := url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%s^ORDERBY%s", query, constants.FieldStartDate)},
+		constants.SysQueryParamFields: {strings.Join(fields, ",")},
+		constants.SysQueryParamLimit:  {fmt.Sprint(constants.MaxScheduledChanges)},
+	}
+
+	changes := &serializer.ServiceNowChangeRequestsResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeChangeRequest, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, changes, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the scheduled changes from ServiceNow")
+	}
+
+	return changes.Result, statusCode, nil
+}
+
+// GetOnCallUsersFromServiceNow returns the users on call for a group at the given time along with their names and emails
+func (c *client) GetOnCallUsersFromServiceNow(groupID string, at time.Time) ([]*serializer.ServiceNowOnCallUser, int, error) {
+	queryParams := url.Values{
+		constants.OnCallQueryParamGroupIDs: {groupID},
+		constants.OnCallQueryParamDateTime: {at.UTC().Format(constants.ServiceNowDateTimeLayout)},
+	}
+
+	onCall := &serializer.ServiceNowOnCallResult{}
+	_, statusCode, err := c.CallJSON(http.MethodGet, constants.PathGetOnCallFromServiceNow, nil, onCall, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the on-call users from ServiceNow")
+	}
+
+	if len(onCall.Result) == 0 {
+		return onCall.Result, statusCode, nil
+	}
+
+	userIDs := make([]string, 0, len(onCall.Result))
+	for _, user := range onCall.Result {
+		userIDs = append(userIDs, user.UserID)
+	}
+
+	userIDs = getUniqueValues(userIDs)
+	queryParams = url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%sIN%s", constants.FieldSysID, strings.Join(userIDs, ","))},
+		constants.SysQueryParamFields: {strings.Join([]string{constants.FieldSysID, constants.FieldName, constants.FieldEmail}, ",")},
+		constants.SysQueryParamLimit:  {fmt.Sprint(len(userIDs))},
+	}
+
+	users := &serializer.ServiceNowUserDetailsResult{}
+	_, statusCode, err = c.CallJSON(http.MethodGet, constants.PathGetUserFromServiceNow, nil, users, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to get the details of the on-call users from ServiceNow")
+	}
+
+	details := map[string]*serializer.ServiceNowUserDetails{}
+	for _, user := range users.Result {
+		details[user.SysID] = user
+	}
+
+	for _, user := range onCall.Result {
+		if detail, ok := details[user.UserID]; ok {
+			user.Name = detail.Name
+			user.Email = detail.Email
+		}
+	}
+
+	return onCall.Result, statusCode, nil
+}
+
+// SearchKnowledgeArticlesInServiceNow returns the published articles matching the query, ordered by their relevance.
+// Only the articles of the knowledge bases visible to the user are returned by ServiceNow.
+func (c *client) SearchKnowledgeArticlesInServiceNow(query string) ([]*serializer.ServiceNowKnowledgeArticle, int, error) {
+	fields := []string{constants.FieldSysID, constants.FieldNumber, constants.FieldShortDescription, constants.FieldText, constants.FieldKnowledgeBaseRef, constants.FieldKnowledgeCategoryRef}
+	queryParams := url.Values{
+		constants.SysQueryParam:                     {fmt.Sprintf("%s=%s^%s=%s^%s=true", constants.TextQueryOperator, strings.ReplaceAll(query, "^", " "), constants.FieldWorkflowState, constants.KnowledgeWorkflowStatePublished, constants.FieldActive)},
+		constants.SysQueryParamFields:               {strings.Join(fields, ",")},
+		constants.SysQueryParamLimit:                {fmt.Sprint(constants.MaxKnowledgeArticlesInSearch)},
+		constants.SysQueryParamDisplayValue:         {"true"},
+		constants.SysQueryParamExcludeReferenceLink: {"true"},
+	}
+
+	articles := &serializer.ServiceNowKnowledgeArticlesResult{}
+	url := strings.Replace(constants.PathGetRecordsFromServiceNow, "{tableName}", constants.RecordTypeKnowledge, 1)
+	_, statusCode, err := c.CallJSON(http.MethodGet, url, nil, articles, queryParams)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "failed to search the knowledge articles in ServiceNow")
+	}
+
+	return articles.Result, statusCode, nil
+}
+
+// GetSubscriptionAuthDetails returns the Mattermost servers registered in the auth table of the update set
+func (c *client) GetSubscriptionAuthDetails() ([]*serializer.SubscriptionAuthPayload, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParamLimit: {fmt.Sprint(constants.DefaultPerPage)},
+	}
+
+	subscriptionAuthDetails := &serializer.SubscriptionAuthDetails{}
+	_, statusCode, err := c.CallJSON(http.MethodGet, constants.PathActivateSubscriptions, nil, subscriptionAuthDetails, queryParams)
+	if err != nil {
+		if strings.Contains(err.Error(), "Invalid table") {
+			return nil, statusCode, errors.New(constants.APIErrorIDSubscriptionsNotConfigured)
+		}
+
+		return nil, statusCode, errors.Wrap(err, "failed to get the subscription auth details from ServiceNow")
+	}
+
+	return subscriptionAuthDetails.Result, statusCode, nil
+}
+
+// GetNotificationsAppVersion returns the version of the application installed by the update set.
+// An empty version is returned if the application is not installed.
+func (c *client) GetNotificationsAppVersion() (string, int, error) {
+	queryParams := url.Values{
+		constants.SysQueryParam:       {fmt.Sprintf("%s=%s", constants.FieldScope, constants.ServiceNowForMattermostNotificationsAppID)},
+		constants.SysQueryParamFields: {constants.FieldVersion},
+		constants.SysQueryParamLimit:  {"1"},
+	}
+
+	apps := &serializer.ServiceNowAppResult{}
+	_, statusCode, err := c.CallJSON(http.MethodGet, constants.PathGetAppFromServiceNow, nil, apps, queryParams)
+	if err != nil {
+		return "", statusCode, errors.Wrap(err, "failed to get the application of the update set from ServiceNow")
+	}
+
+	if len(apps.Result) == 0 {
+		return "", statusCode, nil
+	}
+
+	return apps.Result[0].Version, statusCode, nil
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	"bou.ke/monkey"
 	"github.com/pkg/errors"
@@ -151,9 +152,48 @@ func TestGetAllSubscriptionsClient(t *testing.T) {
 	}
 }
 
-func TestGetSubscription(t *testing.T) {
+func TestGetInactiveSubscriptionsClient(t *testing.T) {
 	defer monkey.UnpatchAll()
 	c := new(client)
+	c.plugin = &Plugin{}
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetInactiveSubscriptions: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "GetInactiveSubscriptions: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to get inactive subscriptions from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, _ interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, params.Get(constants.SysQueryParam), "is_active=false")
+				assert.Contains(t, params.Get(constants.SysQueryParam), "channel_id=mockChannelID")
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			_, statusCode, err := c.GetInactiveSubscriptions("mockChannelID", "mockLimit", "mockOffset")
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
+func TestGetSubscription(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := &client{instance: &serviceNowInstance{ID: "mockInstance"}}
 	for _, testCase := range []struct {
 		description        string
 		statusCode         int
@@ -175,14 +215,18 @@ func TestGetSubscription(t *testing.T) {
 		},
 	} {
 		t.Run(testCase.description, func(t *testing.T) {
-			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, _ interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, out interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+				if testCase.errorMessage == nil {
+					out.(*serializer.SubscriptionResult).Result = &serializer.SubscriptionResponse{SysID: "mockSubscriptionID"}
+				}
 				return nil, testCase.statusCode, testCase.errorMessage
 			})
-			_, statusCode, err := c.GetSubscription("mockSubscriptionID")
+			subscription, statusCode, err := c.GetSubscription("mockSubscriptionID")
 			if testCase.expectedErr != "" {
 				assert.EqualError(t, err, testCase.expectedErr)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, "mockInstance", subscription.InstanceID)
 			}
 
 			assert.Equal(t, testCase.statusCode, statusCode)
@@ -356,6 +400,18 @@ func TestSearchRecordsInServiceNow(t *testing.T) {
 			assert.Equal(t, testCase.statusCode, statusCode)
 		})
 	}
+
+	t.Run("SearchRecordsInServiceNow: configuration items are searched by name", func(t *testing.T) {
+		monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+			assert.Equal(t, "name LIKEweb", params.Get(constants.SysQueryParam))
+			out.(*serializer.ServiceNowPartialRecordsResult).Result = []*serializer.ServiceNowPartialRecord{{Name: "web server 01"}}
+			return nil, http.StatusOK, nil
+		})
+
+		records, _, err := c.SearchRecordsInServiceNow(constants.RecordTypeConfigurationItem, "web", "mockLimit", "mockOffset")
+		assert.NoError(t, err)
+		assert.Equal(t, "web server 01", records[0].Number)
+	})
 }
 
 func TestGetRecordFromServiceNowClient(t *testing.T) {
@@ -397,6 +453,145 @@ func TestGetRecordFromServiceNowClient(t *testing.T) {
 	}
 }
 
+func TestGetRecordsFromServiceNowClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetRecordsFromServiceNow: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "GetRecordsFromServiceNow: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("error in getting the records"),
+			expectedErr:  "failed to get the records from ServiceNow: error in getting the records",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, _ interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Equal(t, "sys_idINmockSysID1,mockSysID2", params.Get(constants.SysQueryParam))
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			_, statusCode, err := c.GetRecordsFromServiceNow("mockTable", []string{"mockSysID1", "mockSysID2"})
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
+func TestGetActiveSLAsFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetActiveSLAsFromServiceNow: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "GetActiveSLAsFromServiceNow: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("error in getting the SLAs"),
+			expectedErr:  "failed to get the SLAs from ServiceNow: error in getting the SLAs",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, _ interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Equal(t, "api/now/table/task_sla", path)
+				assert.Equal(t, "active=true^taskINmockSysID1,mockSysID2^ORDERBYDESCbusiness_percentage", params.Get(constants.SysQueryParam))
+				assert.Contains(t, params.Get(constants.SysQueryParamFields), "task.number")
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			_, statusCode, err := c.GetActiveSLAsFromServiceNow([]string{"mockSysID1", "mockSysID2"})
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
+func TestGetReferenceRecordsFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description   string
+		displayField  string
+		qualifier     string
+		value         string
+		expectedQuery string
+		errorMessage  error
+		expectedErr   string
+	}{
+		{
+			description:   "GetReferenceRecordsFromServiceNow: display field of the table",
+			displayField:  "full_name",
+			value:         "Paris",
+			expectedQuery: "full_name=Paris",
+		},
+		{
+			description:   "GetReferenceRecordsFromServiceNow: name is used when the table has no display field",
+			qualifier:     "active=true",
+			value:         "Paris^ORnameISNOTEMPTY",
+			expectedQuery: "active=true^name=Paris ORnameISNOTEMPTY",
+		},
+		{
+			description:  "GetReferenceRecordsFromServiceNow: with error",
+			value:        "Paris",
+			errorMessage: errors.New("error in getting the records"),
+			expectedErr:  "failed to get the display field from ServiceNow: error in getting the records",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				if testCase.errorMessage != nil {
+					return nil, http.StatusInternalServerError, testCase.errorMessage
+				}
+
+				if path == "api/now/table/sys_dictionary" {
+					assert.Equal(t, "name=cmn_location^display=true", params.Get(constants.SysQueryParam))
+					if testCase.displayField != "" {
+						out.(*serializer.ServiceNowDictionaryEntriesResult).Result = []*serializer.ServiceNowDictionaryEntry{{Element: testCase.displayField}}
+					}
+					return nil, http.StatusOK, nil
+				}
+
+				assert.Equal(t, "api/now/table/cmn_location", path)
+				assert.Equal(t, testCase.expectedQuery, params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowReferenceRecordsResult).Result = []*serializer.ServiceNowReferenceRecord{{SysID: "mockSysID"}}
+				return nil, http.StatusOK, nil
+			})
+
+			records, _, err := c.GetReferenceRecordsFromServiceNow("cmn_location", testCase.qualifier, testCase.value)
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []*serializer.ServiceNowReferenceRecord{{SysID: "mockSysID"}}, records)
+		})
+	}
+}
+
 func TestGetAllCommentsClient(t *testing.T) {
 	defer monkey.UnpatchAll()
 	c := new(client)
@@ -478,6 +673,47 @@ func TestAddCommentClient(t *testing.T) {
 	}
 }
 
+func TestAddWorkNoteClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "AddWorkNote: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "AddWorkNote: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("error in adding the work note"),
+			expectedErr:  "failed to add the work note in ServiceNow: error in adding the work note",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, method, path string, _, _ interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+				assert.Equal(t, http.MethodPatch, method)
+				assert.Equal(t, "api/now/table/incident/mockRecordID", path)
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			statusCode, err := c.AddWorkNote(constants.RecordTypeIncident, "mockRecordID", &serializer.ServiceNowWorkNotePayload{
+				WorkNotes: "mockWorkNote",
+			})
+
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
 func TestGetStatesFromServiceNowClient(t *testing.T) {
 	defer monkey.UnpatchAll()
 	c := new(client)
@@ -566,3 +802,439 @@ func TestUpdateStateOfRecordInServiceNowClient(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateApprovalInServiceNowClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "UpdateApprovalInServiceNow: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "UpdateApprovalInServiceNow: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("error in updating the approval"),
+			expectedErr:  "failed to update the approval in ServiceNow: error in updating the approval",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, _ interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			statusCode, err := c.UpdateApprovalInServiceNow("mockApprovalID", &serializer.ServiceNowApprovalPayload{
+				State: constants.ApprovalStateApproved,
+			})
+
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.EqualValues(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
+func TestGetGroupMembersFromServiceNowClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		statusCode   int
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetGroupMembersFromServiceNow: valid",
+			statusCode:  http.StatusOK,
+		},
+		{
+			description:  "GetGroupMembersFromServiceNow: with error",
+			statusCode:   http.StatusInternalServerError,
+			errorMessage: errors.New("error in getting the members"),
+			expectedErr:  "failed to get the members of the group from ServiceNow: error in getting the members",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, _ string, _, _ interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+				return nil, testCase.statusCode, testCase.errorMessage
+			})
+			userIDs, statusCode, err := c.GetGroupMembersFromServiceNow("mockGroupID")
+
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				assert.Nil(t, userIDs)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, userIDs)
+			}
+
+			assert.EqualValues(t, testCase.statusCode, statusCode)
+		})
+	}
+}
+
+func TestGetGroupByNameFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description   string
+		name          string
+		expectedQuery string
+		groups        []*serializer.ServiceNowGroup
+		errorMessage  error
+		expectedGroup *serializer.ServiceNowGroup
+		expectedErr   string
+	}{
+		{
+			description:   "GetGroupByNameFromServiceNow: valid",
+			name:          "mockGroup",
+			expectedQuery: "name=mockGroup",
+			groups:        []*serializer.ServiceNowGroup{{SysID: "mockSysID", Name: "mockGroup"}},
+			expectedGroup: &serializer.ServiceNowGroup{SysID: "mockSysID", Name: "mockGroup"},
+		},
+		{
+			description:   "GetGroupByNameFromServiceNow: group doesn't exist",
+			name:          "mockGroup",
+			expectedQuery: "name=mockGroup",
+		},
+		{
+			description:   "GetGroupByNameFromServiceNow: encoded query clauses are not added from the name",
+			name:          "mockGroup^ORnameISNOTEMPTY",
+			expectedQuery: "name=mockGroup ORnameISNOTEMPTY",
+		},
+		{
+			description:   "GetGroupByNameFromServiceNow: with error",
+			name:          "mockGroup",
+			expectedQuery: "name=mockGroup",
+			errorMessage:  errors.New("mockError"),
+			expectedErr:   "failed to get the group from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeGroup)
+				assert.Equal(t, testCase.expectedQuery, params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowGroupsResult).Result = testCase.groups
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			group, _, err := c.GetGroupByNameFromServiceNow(testCase.name)
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedGroup, group)
+		})
+	}
+}
+
+func TestOrderCatalogItemInServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description   string
+		order         *serializer.ServiceNowCatalogOrder
+		errorMessage  error
+		expectedOrder *serializer.ServiceNowCatalogOrder
+		expectedErr   string
+	}{
+		{
+			description:   "OrderCatalogItemInServiceNow: valid",
+			order:         &serializer.ServiceNowCatalogOrder{RequestID: "mockRequestID", RequestNumber: "REQ0010001"},
+			expectedOrder: &serializer.ServiceNowCatalogOrder{RequestID: "mockRequestID", RequestNumber: "REQ0010001"},
+		},
+		{
+			description:  "OrderCatalogItemInServiceNow: with error",
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to order the catalog item in ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, method, path string, _, out interface{}, _ url.Values) (_ []byte, _ int, _ error) {
+				assert.Equal(t, http.MethodPost, method)
+				assert.Equal(t, "api/sn_sc/servicecatalog/items/mockItemID/order_now", path)
+				out.(*serializer.ServiceNowCatalogOrderResult).Result = testCase.order
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			order, _, err := c.OrderCatalogItemInServiceNow("mockItemID", &serializer.ServiceNowCatalogOrderPayload{Quantity: "1"})
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOrder, order)
+		})
+	}
+}
+
+func TestGetOpenRequestItemsFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetOpenRequestItemsFromServiceNow: valid",
+		},
+		{
+			description:  "GetOpenRequestItemsFromServiceNow: with error",
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to get the requested items from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeRequestItem)
+				assert.Equal(t, "requested_for=mockUserID^active=true^ORDERBYDESCopened_at", params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowRequestItemsResult).Result = []*serializer.ServiceNowRequestItem{{Number: "RITM0010001"}}
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			requestItems, _, err := c.GetOpenRequestItemsFromServiceNow("mockUserID")
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, requestItems, 1)
+		})
+	}
+}
+
+func TestSearchKnowledgeArticlesInServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "SearchKnowledgeArticlesInServiceNow: valid",
+		},
+		{
+			description:  "SearchKnowledgeArticlesInServiceNow: with error",
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to search the knowledge articles in ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeKnowledge)
+				assert.Equal(t, "123TEXTQUERY321=reset password ^workflow_state=published^active=true", params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowKnowledgeArticlesResult).Result = []*serializer.ServiceNowKnowledgeArticle{{Number: "KB0010001"}}
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			articles, _, err := c.SearchKnowledgeArticlesInServiceNow("reset password^")
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, articles, 1)
+		})
+	}
+}
+
+func TestSearchConfigurationItemsInServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "SearchConfigurationItemsInServiceNow: valid",
+		},
+		{
+			description:  "SearchConfigurationItemsInServiceNow: with error",
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to search for the configuration items in ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeConfigurationItem)
+				assert.Equal(t, "nameLIKEweb server^ORDERBYname", params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowConfigurationItemsResult).Result = []*serializer.ServiceNowConfigurationItem{{Name: "web server 01"}}
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			items, _, err := c.SearchConfigurationItemsInServiceNow("web server")
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, items, 1)
+		})
+	}
+}
+
+func TestGetOpenRecordsOfConfigurationItemFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	for _, testCase := range []struct {
+		description  string
+		errorMessage error
+		expectedErr  string
+	}{
+		{
+			description: "GetOpenRecordsOfConfigurationItemFromServiceNow: valid",
+		},
+		{
+			description:  "GetOpenRecordsOfConfigurationItemFromServiceNow: with error",
+			errorMessage: errors.New("mockError"),
+			expectedErr:  "failed to get the records of the configuration item from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeChangeRequest)
+				assert.Equal(t, "cmdb_ci=mockItemID^active=true^ORDERBYDESCopened_at", params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowRecordsResult).Result = []*serializer.ServiceNowRecord{{Number: "CHG0010001"}}
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			records, _, err := c.GetOpenRecordsOfConfigurationItemFromServiceNow(constants.RecordTypeChangeRequest, "mockItemID")
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, records, 1)
+		})
+	}
+}
+
+func TestGetScheduledChangesFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	from := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	for _, testCase := range []struct {
+		description       string
+		configurationItem string
+		expectedQuery     string
+		errorMessage      error
+		expectedErr       string
+	}{
+		{
+			description:   "GetScheduledChangesFromServiceNow: valid",
+			expectedQuery: "stateIN-2,-1^start_date<2026-10-26 10:00:00^end_date>2026-10-19 10:00:00^ORDERBYstart_date",
+		},
+		{
+			description:       "GetScheduledChangesFromServiceNow: filtered by configuration item",
+			configurationItem: "web^01",
+			expectedQuery:     "stateIN-2,-1^start_date<2026-10-26 10:00:00^end_date>2026-10-19 10:00:00^cmdb_ci=web01^ORcmdb_ci.name=web01^ORDERBYstart_date",
+		},
+		{
+			description:   "GetScheduledChangesFromServiceNow: with error",
+			expectedQuery: "stateIN-2,-1^start_date<2026-10-26 10:00:00^end_date>2026-10-19 10:00:00^ORDERBYstart_date",
+			errorMessage:  errors.New("mockError"),
+			expectedErr:   "failed to get the scheduled changes from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				assert.Contains(t, path, constants.RecordTypeChangeRequest)
+				assert.Equal(t, testCase.expectedQuery, params.Get(constants.SysQueryParam))
+				out.(*serializer.ServiceNowChangeRequestsResult).Result = []*serializer.ServiceNowChangeRequest{{Number: "CHG0010001"}}
+				return nil, http.StatusOK, testCase.errorMessage
+			})
+
+			changes, _, err := c.GetScheduledChangesFromServiceNow(from, to, testCase.configurationItem)
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, changes, 1)
+		})
+	}
+}
+
+func TestGetOnCallUsersFromServiceNow(t *testing.T) {
+	defer monkey.UnpatchAll()
+	c := new(client)
+	at := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	for _, testCase := range []struct {
+		description   string
+		onCallErr     error
+		usersErr      error
+		onCallUsers   []*serializer.ServiceNowOnCallUser
+		expectedUsers []*serializer.ServiceNowOnCallUser
+		expectedErr   string
+	}{
+		{
+			description: "GetOnCallUsersFromServiceNow: valid",
+			onCallUsers: []*serializer.ServiceNowOnCallUser{{UserID: "mockUserID1", EscalationLevel: 1}, {UserID: "mockUserID2", EscalationLevel: 2}},
+			expectedUsers: []*serializer.ServiceNowOnCallUser{
+				{UserID: "mockUserID1", EscalationLevel: 1, Name: "mockName1", Email: "mockEmail1"},
+				{UserID: "mockUserID2", EscalationLevel: 2},
+			},
+		},
+		{
+			description:   "GetOnCallUsersFromServiceNow: no one is on call",
+			onCallUsers:   []*serializer.ServiceNowOnCallUser{},
+			expectedUsers: []*serializer.ServiceNowOnCallUser{},
+		},
+		{
+			description: "GetOnCallUsersFromServiceNow: failed to get the on-call users",
+			onCallErr:   errors.New("mockError"),
+			expectedErr: "failed to get the on-call users from ServiceNow: mockError",
+		},
+		{
+			description: "GetOnCallUsersFromServiceNow: failed to get the details of the users",
+			onCallUsers: []*serializer.ServiceNowOnCallUser{{UserID: "mockUserID1", EscalationLevel: 1}},
+			usersErr:    errors.New("mockError"),
+			expectedErr: "failed to get the details of the on-call users from ServiceNow: mockError",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			monkey.PatchInstanceMethod(reflect.TypeOf(c), "CallJSON", func(_ *client, _, path string, _, out interface{}, params url.Values) (_ []byte, _ int, _ error) {
+				if path == constants.PathGetOnCallFromServiceNow {
+					assert.Equal(t, "mockGroupID", params.Get(constants.OnCallQueryParamGroupIDs))
+					assert.Equal(t, "2026-10-19 10:00:00", params.Get(constants.OnCallQueryParamDateTime))
+					out.(*serializer.ServiceNowOnCallResult).Result = testCase.onCallUsers
+					return nil, http.StatusOK, testCase.onCallErr
+				}
+
+				assert.Equal(t, constants.PathGetUserFromServiceNow, path)
+				assert.Contains(t, params.Get(constants.SysQueryParam), "sys_idINmockUserID1")
+				out.(*serializer.ServiceNowUserDetailsResult).Result = []*serializer.ServiceNowUserDetails{{SysID: "mockUserID1", Name: "mockName1", Email: "mockEmail1"}}
+				return nil, http.StatusOK, testCase.usersErr
+			})
+
+			users, _, err := c.GetOnCallUsersFromServiceNow("mockGroupID", at)
+			if testCase.expectedErr != "" {
+				assert.EqualError(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedUsers, users)
+		})
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
-	"sync"
+	"strconv"
+	"strings"
+	"time"
 	"unicode"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -26,10 +28,25 @@ const (
 * |/servicenow disconnect| - Disconnect your Mattermost account from your ServiceNow account
 * |/servicenow subscriptions| - Manage your subscriptions to the record changes in ServiceNow
 * |/servicenow share| - Search a record in ServiceNow and share it in a channel
+* |/servicenow incident create ["short description"] [--urgency 1|2|3] [--group "group"] [--caller @username] [--description "description"]| - Create an incident and share it in the channel. A dialog is opened when no short description is given
+* |/servicenow catalog [search term]| - Search the service catalog and order an item
+* |/servicenow requests| - List your open requested items along with their stage and approval
+* |/servicenow kb [query]| - Search the published knowledge articles and view or share them
+* |/servicenow ci [name]| - Search the configuration items by name and view their open incidents and changes
+* |/servicenow changes upcoming [--ci name] [--window 7d]| - View the calendar of the scheduled changes, optionally of a configuration item
+* |/servicenow changes conflicts --ci name [--window 7d]| - List the changes of a configuration item scheduled during the window and the ones overlapping each other
+* |/servicenow oncall [assignment group]| - See who is on call now and next for an assignment group
+* |/servicenow instance [list|default instance]| - List the ServiceNow instances or set the default instance of the channel. The connect, disconnect, subscriptions, requests, changes and oncall commands can be run on another instance by passing |--instance id|
 * |/servicenow help| - Know about the features of this plugin
 `
 
-	commandHelpForAdmin = commandHelp + "\n\n" + `##### Configure/Enable subscriptions
+	commandHelpForAdmin = commandHelp + `* |/servicenow admin service-account| - Check, set or unset the service account used for the read-only calls to ServiceNow
+* |/servicenow admin reassign @from @to| - Reassign all the subscriptions owned by a user to another user
+* |/servicenow admin diagnose [instance]| - Check the connection with ServiceNow and the configuration of the plugin and the update set
+* |/servicenow admin subscriptions export [json|csv] [--channel ~channel] [--team team]| - Export the subscriptions as a file sent to you as a DM
+* |/servicenow admin subscriptions import [post ID or link] [--map old_channel:new_channel] [--apply]| - Import the subscriptions from the file attached to a post. Only a dry run is done unless |--apply| is passed
+* |/servicenow admin subscriptions clone ~from ~to [--apply]| - Copy all the subscriptions of a channel to another channel. Only a dry run is done unless |--apply| is passed
+` + "\n\n" + `##### Configure/Enable subscriptions
 * Download the update set XML file from **System Console > Plugins > ServiceNow Plugin > Download ServiceNow Update Set**.
 * Go to ServiceNow and search for Update sets. Then go to "Retrieved Update Sets" under "System Update Sets".
 * Click on "Import Update Set from XML" link.
@@ -58,6 +75,7 @@ After that, this user will have the permission to add or manage subscriptions fr
 	deleteSubscriptionSuccessMessage        = "Subscription successfully deleted."
 	genericErrorMessage                     = "Something went wrong."
 	invalidSubscriptionIDMessage            = "Invalid subscription ID."
+	adminOnlyMessage                        = "Only system admins can run this command."
 	notConnectedMessage                     = "You are not connected to ServiceNow.\n[Click here to link your ServiceNow account.](%s%s)"
 	tokenExpiredReconnectMessage            = constants.APIErrorRefreshTokenExpired + "\n[Click here to link your ServiceNow account.](%s%s)"
 	subscriptionsNotConfiguredError         = "It seems that subscriptions for ServiceNow have not been configured properly."
@@ -112,35 +130,52 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 		return &model.CommandResponse{}, nil
 	}
 
-	if action == constants.CommandConnect {
-		message := ""
-		if _, userErr := p.GetUser(args.UserId); userErr == nil {
-			message = constants.UserAlreadyConnectedMessage
-		} else {
-			message = fmt.Sprintf("[%s](%s%s)", constants.UserConnectMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
-		}
+	if action == "" || action == constants.CommandHelp {
+		p.handleHelp(args, isSysAdmin)
+		return &model.CommandResponse{}, nil
+	}
+
+	if action == constants.CommandInstance {
+		p.postCommandResponse(args, p.handleInstance(args, parameters))
+		return &model.CommandResponse{}, nil
+	}
 
+	instanceID, parameters, message := p.getCommandInstance(args, action, parameters)
+	if message != "" {
 		p.postCommandResponse(args, message)
 		return &model.CommandResponse{}, nil
 	}
 
-	if action == "" || action == constants.CommandHelp {
-		p.handleHelp(args, isSysAdmin)
+	if action == constants.CommandConnect {
+		p.postCommandResponse(args, p.getInstanceConnectMessage(args.UserId, instanceID))
 		return &model.CommandResponse{}, nil
 	}
 
-	if f, ok := p.CommandHandlers[action]; ok {
-		user := p.checkConnected(args)
-		if user == nil {
-			return &model.CommandResponse{}, nil
-		}
+	if action == constants.CommandDisconnect && instanceID != constants.DefaultInstanceID {
+		p.postCommandResponse(args, p.disconnectInstance(args.UserId, instanceID))
+		return &model.CommandResponse{}, nil
+	}
 
+	if f, ok := p.CommandHandlers[action]; ok {
 		var client Client
-		if action == constants.CommandSubscriptions || action == constants.CommandUnsubscribe {
-			if client = p.GetClientFromUser(args, user); client == nil {
+		if instanceID != constants.DefaultInstanceID {
+			if client = p.getInstanceClientForCommand(args, instanceID); client == nil {
+				return &model.CommandResponse{}, nil
+			}
+		} else {
+			user := p.checkConnected(args)
+			if user == nil {
 				return &model.CommandResponse{}, nil
 			}
 
+			if action == constants.CommandSubscriptions || action == constants.CommandUnsubscribe {
+				if client = p.GetClientFromUser(args, user); client == nil {
+					return &model.CommandResponse{}, nil
+				}
+			}
+		}
+
+		if action == constants.CommandSubscriptions || action == constants.CommandUnsubscribe {
 			if _, err := client.ActivateSubscriptions(); err != nil {
 				p.API.LogError("Unable to check or activate subscriptions in ServiceNow.", "Error", err.Error())
 				p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, ""))
@@ -182,7 +217,7 @@ func (p *Plugin) GetClientFromUser(args *model.CommandArgs, user *serializer.Use
 		return nil
 	}
 
-	return p.NewClient(context.Background(), token)
+	return p.NewCachedClient(p.NewClient(context.Background(), token, user.MattermostUserID), user.MattermostUserID)
 }
 
 func (p *Plugin) handleHelp(args *model.CommandArgs, isSysAdmin bool) {
@@ -205,7 +240,7 @@ func (p *Plugin) handleDisconnect(_ *plugin.Context, args *model.CommandArgs, _
 
 func (p *Plugin) handleSubscriptions(c *plugin.Context, args *model.CommandArgs, parameters []string, client Client, isSysAdmin bool) string {
 	if len(parameters) == 0 {
-		return "Invalid subscribe command. Available commands are 'list', 'add', 'edit' and 'delete'."
+		return "Invalid subscribe command. Available commands are 'list', 'add', 'edit', 'delete', 'bulk', 'transfer', 'mentions', 'mute', 'unmute' and 'personal'."
 	}
 
 	command := parameters[0]
@@ -220,261 +255,1327 @@ func (p *Plugin) handleSubscriptions(c *plugin.Context, args *model.CommandArgs,
 		return p.handleEditSubscription(c, args, parameters, client, isSysAdmin)
 	case constants.SubCommandDelete:
 		return p.handleDeleteSubscription(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandBulk:
+		return p.handleBulkSubscriptions(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandTransfer:
+		return p.handleTransferSubscription(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandMentions:
+		return p.handleSubscriptionMentions(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandMute:
+		return p.handleMuteSubscriptions(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandUnmute:
+		return p.handleUnmuteSubscriptions(c, args, parameters, client, isSysAdmin)
+	case constants.SubCommandPersonal:
+		return p.handlePersonalSubscription(c, args, parameters, client, isSysAdmin)
 	default:
 		return fmt.Sprintf("Unknown subcommand %v", command)
 	}
 }
 
-func (p *Plugin) handleIncident(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, _ bool) string {
+func (p *Plugin) handleAdmin(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, isSysAdmin bool) string {
+	if !isSysAdmin {
+		return adminOnlyMessage
+	}
+
 	if len(parameters) == 0 {
-		return "Invalid incident command. Available command is 'create'."
+		return "Invalid admin command. Available commands are 'service-account', 'reassign', 'subscriptions' and 'diagnose'."
 	}
 
 	command := parameters[0]
+	parameters = parameters[1:]
 
 	switch command {
-	case constants.SubCommandCreate:
-		return p.HandleCreateIncident(args)
+	case constants.SubCommandService:
+		return p.handleServiceAccount(args, parameters)
+	case constants.SubCommandReassign:
+		return p.handleReassignSubscriptions(args, parameters, isSysAdmin)
+	case constants.CommandSubscriptions:
+		return p.handleAdminSubscriptions(args, parameters, isSysAdmin)
+	case constants.SubCommandDiagnose:
+		return p.handleDiagnose(args, parameters)
 	default:
 		return fmt.Sprintf("Unknown subcommand %v", command)
 	}
 }
 
-func (p *Plugin) HandleCreateIncident(args *model.CommandArgs) string {
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenCreateIncidentModal,
-		nil,
-		&model.WebsocketBroadcast{UserId: args.UserId},
-	)
+func (p *Plugin) handleServiceAccount(args *model.CommandArgs, parameters []string) string {
+	if len(parameters) == 0 || parameters[0] == constants.SubCommandStatus {
+		return p.GetServiceAccountStatus()
+	}
 
-	return ""
-}
+	switch parameters[0] {
+	case constants.SubCommandSet:
+		if p.getConfiguration().ServiceAccountMode != constants.ServiceAccountModeAdminToken {
+			return "The service account mode must be set to use an admin token in the plugin settings before storing your token."
+		}
 
-func (p *Plugin) handleSubscribe(_ *plugin.Context, args *model.CommandArgs, _ []string, _ Client, _ bool) string {
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenAddSubscriptionModal,
-		nil,
-		&model.WebsocketBroadcast{UserId: args.UserId},
-	)
+		user, err := p.GetUser(args.UserId)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+			return genericErrorMessage
+		}
 
-	return ""
-}
+		if _, err = p.SetServiceAccountFromUser(user); err != nil {
+			p.API.LogError(constants.ErrorStoreServiceAccount, "Error", err.Error())
+			return genericErrorMessage
+		}
 
-func (p *Plugin) handleSearchAndShare(_ *plugin.Context, args *model.CommandArgs, _ []string, _ Client, _ bool) string {
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenSearchAndShareRecordsModal,
-		nil,
-		&model.WebsocketBroadcast{UserId: args.UserId},
-	)
+		p.API.LogInfo("Service account token updated", "UserID", args.UserId)
+		return "Your ServiceNow token is now used by the service account for the read-only calls."
+	case constants.SubCommandUnset:
+		if err := p.store.DeleteServiceAccount(); err != nil {
+			p.API.LogError("Unable to delete the service account", "Error", err.Error())
+			return genericErrorMessage
+		}
 
-	return ""
+		p.API.LogInfo("Service account token removed", "UserID", args.UserId)
+		return "The token of the service account has been removed."
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", parameters[0])
+	}
 }
 
-func (p *Plugin) handleListSubscriptions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
-	userID := args.UserId
-	channelID := args.ChannelId
-	if len(params) >= 1 {
-		if params[0] != constants.FilterCreatedByMe && params[0] != constants.FilterCreatedByAnyone {
-			return fmt.Sprintf("Unknown filter %s", params[0])
-		}
+func (p *Plugin) handleReassignSubscriptions(args *model.CommandArgs, parameters []string, isSysAdmin bool) string {
+	if len(parameters) < 2 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
 
-		if params[0] == constants.FilterCreatedByAnyone {
-			userID = ""
-		}
+	oldOwner, appErr := p.API.GetUserByUsername(strings.TrimPrefix(parameters[0], "@"))
+	if appErr != nil {
+		return fmt.Sprintf("User %s doesn't exist.", parameters[0])
 	}
 
-	if len(params) == 2 {
-		if params[1] != constants.FilterAllChannels {
-			return fmt.Sprintf("Unknown filter %s", params[1])
-		}
-		channelID = ""
+	newOwner, err := p.GetNewSubscriptionOwner(parameters[1], "")
+	if err != nil {
+		return fmt.Sprintf("Unable to reassign the subscriptions. Error: %s", err.Error())
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
 	}
 
-	var subscriptionList []*serializer.SubscriptionResponse
 	go func() {
-		subscriptions, _, err := client.GetAllSubscriptions(channelID, userID, "", fmt.Sprint(constants.DefaultPerPage), fmt.Sprint(constants.DefaultPage))
+		subscriptions, statusCode, err := p.GetAllSubscriptionsOfUser(client, oldOwner.Id)
 		if err != nil {
-			p.API.LogError("Unable to get subscriptions", "Error", err.Error())
-			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, 0, userID, ""))
-			return
-		}
-
-		if len(subscriptions) == 0 {
-			p.postCommandResponse(args, constants.ErrorNoActiveSubscriptions)
+			p.API.LogError(constants.ErrorGetSubscriptions, "UserID", oldOwner.Id, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
 			return
 		}
 
+		reassigned := 0
+		var skipped []string
 		for _, subscription := range subscriptions {
-			_, permissionErr := p.HasPublicOrPrivateChannelPermissions(args.UserId, subscription.ChannelID)
-			if permissionErr == nil {
-				subscriptionList = append(subscriptionList, subscription)
+			if _, permissionErr := p.HasChannelPermissions(newOwner.Id, subscription.ChannelID); permissionErr != nil {
+				skipped = append(skipped, subscription.SysID)
+				continue
+			}
+
+			if _, _, err := p.TransferSubscription(client, subscription.SysID, newOwner.Id); err != nil {
+				p.API.LogError(constants.ErrorTransferSubscription, "SubscriptionID", subscription.SysID, "Error", err.Error())
+				skipped = append(skipped, subscription.SysID)
+				continue
 			}
+
+			reassigned++
 		}
 
-		if len(subscriptionList) == 0 {
-			p.postCommandResponse(args, constants.ErrorNoActiveSubscriptions)
-			return
+		message := fmt.Sprintf("Reassigned %d subscription(s) from @%s to @%s.", reassigned, oldOwner.Username, newOwner.Username)
+		if len(skipped) > 0 {
+			message = fmt.Sprintf("%s The following subscriptions could not be reassigned: %s", message, strings.Join(skipped, ", "))
 		}
 
-		wg := sync.WaitGroup{}
-		for _, subscription := range subscriptionList {
-			wg.Add(1)
-			go func(subscription *serializer.SubscriptionResponse) {
-				defer wg.Done()
-				user, err := p.API.GetUser(subscription.UserID)
-				if err != nil {
-					p.API.LogError("Error in getting user", "UserID", subscription.UserID)
-					subscription.UserName = constants.NotAvailableText
-				} else {
-					subscription.UserName = user.Username
-				}
+		p.postCommandResponse(args, message)
+	}()
 
-				channel, err := p.API.GetChannel(subscription.ChannelID)
-				if err != nil {
-					p.API.LogError("Error in getting channel", "ChannelID", subscription.ChannelID)
-					subscription.ChannelName = constants.NotAvailableText
-				} else {
-					subscription.ChannelName = channel.DisplayName
-				}
-			}(subscription)
+	return genericWaitMessage
+}
 
-			if subscription.Type == constants.SubscriptionTypeBulk {
-				continue
-			}
-			wg.Add(1)
-			go p.GetRecordFromServiceNowForSubscription(subscription, client, &wg)
+func (p *Plugin) handleAdminSubscriptions(args *model.CommandArgs, parameters []string, isSysAdmin bool) string {
+	if len(parameters) == 0 {
+		return "Invalid subscriptions command. Available commands are 'export', 'import' and 'clone'."
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "You are not connected to ServiceNow."
 		}
 
-		wg.Wait()
-		p.postCommandResponse(args, ParseSubscriptionsToCommandResponse(subscriptionList))
-	}()
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
 
-	return listSubscriptionsWaitMessage
+	switch parameters[0] {
+	case constants.SubCommandExport:
+		return p.handleExportSubscriptions(args, parameters[1:], client, isSysAdmin)
+	case constants.SubCommandImport:
+		return p.handleImportSubscriptions(args, parameters[1:], client)
+	case constants.SubCommandClone:
+		return p.handleCloneSubscriptions(args, parameters[1:], client, isSysAdmin)
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", parameters[0])
+	}
 }
 
-func (p *Plugin) handleDeleteSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
-	if len(params) < 1 {
-		return constants.ErrorCommandInvalidNumberOfParams
+// getChannelIDFromReference returns the ID of the channel referred by its ID or by its name in the current team
+func (p *Plugin) getChannelIDFromReference(args *model.CommandArgs, reference string) (string, error) {
+	if model.IsValidId(reference) {
+		return reference, nil
+	}
+
+	channel, appErr := p.API.GetChannelByName(args.TeamId, strings.TrimPrefix(reference, "~"), false)
+	if appErr != nil {
+		return "", fmt.Errorf("channel %s doesn't exist", reference)
+	}
+
+	return channel.Id, nil
+}
+
+func (p *Plugin) handleExportSubscriptions(args *model.CommandArgs, parameters []string, client Client, isSysAdmin bool) string {
+	format := constants.ExportFormatJSON
+	channelID, teamID := "", ""
+	for i := 0; i < len(parameters); i++ {
+		switch parameter := parameters[i]; {
+		case constants.ValidExportFormats[parameter]:
+			format = parameter
+		case (parameter == constants.FlagChannel || parameter == constants.FlagTeam) && i+1 < len(parameters):
+			i++
+			if parameter == constants.FlagTeam {
+				team, appErr := p.API.GetTeamByName(strings.TrimPrefix(parameters[i], "~"))
+				if appErr != nil {
+					return fmt.Sprintf("Team %s doesn't exist.", parameters[i])
+				}
+
+				teamID = team.Id
+				continue
+			}
+
+			id, err := p.getChannelIDFromReference(args, parameters[i])
+			if err != nil {
+				return fmt.Sprintf("Unable to export the subscriptions. Error: %s", err.Error())
+			}
+
+			channelID = id
+		default:
+			return fmt.Sprintf("Invalid argument %s.", parameter)
+		}
 	}
 
 	go func() {
-		subscriptionID := params[0]
-		valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+		subscriptions, statusCode, err := p.GetSubscriptionsForExport(client, channelID, teamID)
 		if err != nil {
-			p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
-			p.postCommandResponse(args, deleteSubscriptionErrorMessage)
+			p.API.LogError(constants.ErrorGetSubscriptions, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
 			return
 		}
 
-		if !valid {
-			p.postCommandResponse(args, invalidSubscriptionIDMessage)
+		data, err := EncodeSubscriptionsForExport(subscriptions, format)
+		if err != nil {
+			p.API.LogError(constants.ErrorExportSubscriptions, "Error", err.Error())
+			p.postCommandResponse(args, genericErrorMessage)
 			return
 		}
 
-		if statusCode, err := client.DeleteSubscription(subscriptionID); err != nil {
-			p.API.LogError("Unable to delete subscription", "Error", err.Error())
-			if statusCode == http.StatusNotFound {
-				p.postCommandResponse(args, fmt.Sprintf("Subscription with ID %s doesn't exist.", subscriptionID))
-			} else {
-				p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, ""))
-			}
+		message := fmt.Sprintf("Exported %d subscription(s).", len(subscriptions))
+		if err = p.DMFile(args.UserId, fmt.Sprintf(constants.SubscriptionsExportFileName, format), data, message); err != nil {
+			p.postCommandResponse(args, genericErrorMessage)
 			return
 		}
 
-		p.API.PublishWebSocketEvent(
-			constants.WSEventSubscriptionDeleted,
-			nil,
-			&model.WebsocketBroadcast{UserId: args.UserId},
-		)
-
-		p.postCommandResponse(args, deleteSubscriptionSuccessMessage)
+		p.postCommandResponse(args, "The exported subscriptions have been sent to you as a DM.")
 	}()
 
 	return genericWaitMessage
 }
 
-func (p *Plugin) handleEditSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
-	if len(params) < 1 {
-		return constants.ErrorCommandInvalidNumberOfParams
+// getExportedSubscriptionsFromPost returns the subscriptions from the export file attached to a post
+func (p *Plugin) getExportedSubscriptionsFromPost(args *model.CommandArgs, postReference string) ([]*serializer.SubscriptionResponse, error) {
+	postID := postReference[strings.LastIndex(postReference, "/")+1:]
+	if !model.IsValidId(postID) {
+		return nil, fmt.Errorf("%s is not a valid post ID or link", postReference)
 	}
-	subscriptionID := params[0]
-	valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
-	if err != nil {
-		p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
-		return genericErrorMessage
+
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil || !p.API.HasPermissionToChannel(args.UserId, post.ChannelId, model.PermissionReadChannel) {
+		return nil, fmt.Errorf("post %s doesn't exist", postReference)
 	}
 
-	if !valid {
-		return invalidSubscriptionIDMessage
+	if len(post.FileIds) == 0 {
+		return nil, fmt.Errorf("post %s doesn't have a file attached", postReference)
 	}
 
-	subscription, _, err := client.GetSubscription(subscriptionID)
+	fileInfo, appErr := p.API.GetFileInfo(post.FileIds[0])
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get the file info")
+	}
+
+	format := strings.ToLower(fileInfo.Extension)
+	if !constants.ValidExportFormats[format] {
+		return nil, fmt.Errorf("the file must be a JSON or CSV file")
+	}
+
+	data, appErr := p.API.GetFile(fileInfo.Id)
+	if appErr != nil {
+		return nil, errors.Wrap(appErr, "failed to get the file")
+	}
+
+	return DecodeSubscriptionsForImport(data, format)
+}
+
+func (p *Plugin) handleImportSubscriptions(args *model.CommandArgs, parameters []string, client Client) string {
+	if len(parameters) < 1 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	dryRun := true
+	channelMap := map[string]string{}
+	for i := 1; i < len(parameters); i++ {
+		switch parameter := parameters[i]; {
+		case parameter == constants.FlagApply:
+			dryRun = false
+		case parameter == constants.FlagMap && i+1 < len(parameters):
+			i++
+			channels := strings.Split(parameters[i], ":")
+			if len(channels) != 2 {
+				return fmt.Sprintf("Invalid channel mapping %s. Use the format old_channel:new_channel.", parameters[i])
+			}
+
+			oldChannelID, err := p.getChannelIDFromReference(args, channels[0])
+			if err != nil {
+				return fmt.Sprintf("Unable to import the subscriptions. Error: %s", err.Error())
+			}
+
+			newChannelID, err := p.getChannelIDFromReference(args, channels[1])
+			if err != nil {
+				return fmt.Sprintf("Unable to import the subscriptions. Error: %s", err.Error())
+			}
+
+			channelMap[oldChannelID] = newChannelID
+		default:
+			return fmt.Sprintf("Invalid argument %s.", parameter)
+		}
+	}
+
+	subscriptions, err := p.getExportedSubscriptionsFromPost(args, parameters[0])
 	if err != nil {
-		p.API.LogError("Unable to get subscription", "Error", err.Error())
-		return p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, "")
+		return fmt.Sprintf("Unable to import the subscriptions. Error: %s", err.Error())
 	}
 
-	if subscription.Type == constants.SubscriptionTypeRecord {
-		p.GetRecordFromServiceNowForSubscription(subscription, client, nil)
+	go func() {
+		result := p.ImportSubscriptions(client, subscriptions, channelMap, dryRun)
+		p.API.LogInfo("Subscriptions imported", "UserID", args.UserId, "DryRun", dryRun, "Created", result.Count(constants.ImportStatusCreated))
+		p.postCommandResponse(args, getFormattedImportResult(result))
+	}()
+
+	return genericWaitMessage
+}
+
+func (p *Plugin) handleCloneSubscriptions(args *model.CommandArgs, parameters []string, client Client, isSysAdmin bool) string {
+	if len(parameters) < 2 {
+		return constants.ErrorCommandInvalidNumberOfParams
 	}
 
-	subscriptionMap, err := ConvertSubscriptionToMap(subscription)
+	fromChannelID, err := p.getChannelIDFromReference(args, parameters[0])
 	if err != nil {
-		p.API.LogError("Unable to convert subscription to map", "Error", err.Error())
-		return genericErrorMessage
+		return fmt.Sprintf("Unable to clone the subscriptions. Error: %s", err.Error())
 	}
 
-	p.API.PublishWebSocketEvent(
-		constants.WSEventOpenEditSubscriptionModal,
-		subscriptionMap,
-		&model.WebsocketBroadcast{UserId: args.UserId},
-	)
+	toChannelID, err := p.getChannelIDFromReference(args, parameters[1])
+	if err != nil {
+		return fmt.Sprintf("Unable to clone the subscriptions. Error: %s", err.Error())
+	}
 
-	return ""
+	dryRun := len(parameters) < 3 || parameters[2] != constants.FlagApply
+	go func() {
+		result, statusCode, err := p.CloneChannelSubscriptions(client, fromChannelID, toChannelID, dryRun)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetSubscriptions, "ChannelID", fromChannelID, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		p.postCommandResponse(args, getFormattedImportResult(result))
+	}()
+
+	return genericWaitMessage
 }
 
-func getAutocompleteData() *model.AutocompleteData {
-	serviceNow := model.NewAutocompleteData(constants.CommandTrigger, "[command]", fmt.Sprintf("Available commands: %s, %s, %s, %s, %s, %s", constants.CommandConnect, constants.CommandDisconnect, constants.CommandSubscriptions, constants.CommandSearchAndShare, constants.CommandIncident, constants.CommandHelp))
+func getFormattedImportResult(result *serializer.SubscriptionImportResult) string {
+	message := result.GetFormattedResult()
+	if result.DryRun && result.Count(constants.ImportStatusReady) > 0 {
+		message = fmt.Sprintf("%s\nRun the command again with `%s` to import them.", message, constants.FlagApply)
+	}
 
-	connect := model.NewAutocompleteData(constants.CommandConnect, "", "Connect your Mattermost account to your ServiceNow account")
-	serviceNow.AddCommand(connect)
+	return message
+}
 
-	disconnect := model.NewAutocompleteData(constants.CommandDisconnect, "", "Disconnect your Mattermost account from your ServiceNow account")
-	serviceNow.AddCommand(disconnect)
+func (p *Plugin) handleIncident(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, isSysAdmin bool) string {
+	if len(parameters) == 0 {
+		return "Invalid incident command. Available command is 'create'."
+	}
 
-	subscriptions := model.NewAutocompleteData(constants.CommandSubscriptions, "[command]", fmt.Sprintf("Available commands: %s, %s, %s, %s", constants.SubCommandList, constants.SubCommandAdd, constants.SubCommandEdit, constants.SubCommandDelete))
+	command := parameters[0]
 
-	subscribeList := model.NewAutocompleteData("list", "", "List the current channel subscriptions")
-	subscriptionCreatedByMe := model.NewAutocompleteData("me", "", "Created By Me")
-	subscriptionShowForAllChannels := model.NewAutocompleteData("all_channels", "", "Show for all channels or You can leave this argument to show for the current channel only")
-	subscriptionCreatedByMe.AddCommand(subscriptionShowForAllChannels)
-	subscribeList.AddCommand(subscriptionCreatedByMe)
-	subscriptionCreatedByAnyone := model.NewAutocompleteData("anyone", "", "Created By Anyone")
-	subscriptionCreatedByAnyone.AddCommand(subscriptionShowForAllChannels)
-	subscribeList.AddCommand(subscriptionCreatedByAnyone)
-	subscriptions.AddCommand(subscribeList)
+	switch command {
+	case constants.SubCommandCreate:
+		if len(parameters) > 1 {
+			return p.handleCreateIncidentFromArgs(args, parameters[1:], isSysAdmin)
+		}
 
-	subscriptionsAdd := model.NewAutocompleteData(constants.SubCommandAdd, "", "Subscribe to the record changes in ServiceNow")
-	subscriptions.AddCommand(subscriptionsAdd)
+		return p.HandleCreateIncident(args)
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", command)
+	}
+}
 
-	subscriptionsEdit := model.NewAutocompleteData(constants.SubCommandEdit, "[subscription_id]", "Edit the subscriptions created to the record changes in ServiceNow")
-	subscriptionsEdit.AddTextArgument("ID of the subscription", "[subscription_id]", "")
-	subscriptions.AddCommand(subscriptionsEdit)
+func (p *Plugin) HandleCreateIncident(args *model.CommandArgs) string {
+	if err := p.OpenIncidentDialog(args.TriggerId, args.ChannelId); err != nil {
+		p.API.LogError(constants.ErrorOpenDialog, "Error", err.Error())
+		return genericErrorMessage
+	}
 
-	subscriptionsDelete := model.NewAutocompleteData(constants.SubCommandDelete, "[subscription_id]", "Unsubscribe to the record changes in ServiceNow")
-	subscriptionsDelete.AddTextArgument("ID of the subscription", "[subscription_id]", "")
-	subscriptions.AddCommand(subscriptionsDelete)
+	return ""
+}
 
-	serviceNow.AddCommand(subscriptions)
+type incidentArgs struct {
+	shortDescription string
+	description      string
+	urgency          string
+	group            string
+	caller           string
+}
 
-	searchRecords := model.NewAutocompleteData(constants.CommandSearchAndShare, "", "Search and share a ServiceNow record")
-	serviceNow.AddCommand(searchRecords)
+// parseIncidentArgs parses the arguments of the command for creating an incident without the dialog.
+// The words which are not flags form the short description.
+func parseIncidentArgs(params []string) (*incidentArgs, string) {
+	parsed := &incidentArgs{}
+	var shortDescription []string
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if !strings.HasPrefix(param, "--") {
+			shortDescription = append(shortDescription, strings.Trim(param, `"`))
+			continue
+		}
+
+		if i+1 >= len(params) {
+			return nil, fmt.Sprintf("Missing value for %s", param)
+		}
+
+		i++
+		value := strings.TrimSpace(strings.Trim(params[i], `"`))
+		switch param {
+		case constants.FlagUrgency:
+			if _, ok := constants.ValidIncidentUrgencies[value]; !ok {
+				return nil, fmt.Sprintf("Invalid urgency %s. Available urgencies are: %s", value, strings.Join(getSortedKeys(constants.ValidIncidentUrgencies), ", "))
+			}
+			parsed.urgency = value
+		case constants.FlagGroup:
+			parsed.group = value
+		case constants.FlagCaller:
+			parsed.caller = strings.TrimPrefix(value, "@")
+		case constants.FlagDescription:
+			parsed.description = value
+		default:
+			return nil, fmt.Sprintf("Unknown flag %s", param)
+		}
+	}
+
+	parsed.shortDescription = strings.TrimSpace(strings.Join(shortDescription, " "))
+	if parsed.shortDescription == "" {
+		return nil, "Short description of the incident is required."
+	}
+
+	return parsed, ""
+}
+
+// handleCreateIncidentFromArgs creates the incident from the arguments of the command and shares it in the channel
+func (p *Plugin) handleCreateIncidentFromArgs(args *model.CommandArgs, parameters []string, isSysAdmin bool) string {
+	parsed, message := parseIncidentArgs(parameters)
+	if message != "" {
+		return message
+	}
+
+	if _, err := p.HasChannelPermissions(args.UserId, args.ChannelId); err != nil {
+		return err.Error()
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		incident := &serializer.IncidentPayload{
+			ShortDescription: parsed.shortDescription,
+			Description:      parsed.description,
+			Urgency:          parsed.urgency,
+			ChannelID:        args.ChannelId,
+		}
+
+		if parsed.caller != "" {
+			callerID, message := p.getIncidentCallerID(client, parsed.caller)
+			if message != "" {
+				p.postCommandResponse(args, message)
+				return
+			}
+			incident.Caller = callerID
+		}
+
+		if parsed.group != "" {
+			group, statusCode, err := client.GetGroupByNameFromServiceNow(parsed.group)
+			if err != nil {
+				p.API.LogError("Unable to get the group", "Group", parsed.group, "Error", err.Error())
+				p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+				return
+			}
+
+			if group == nil {
+				p.postCommandResponse(args, fmt.Sprintf("Group %s doesn't exist in ServiceNow.", parsed.group))
+				return
+			}
+			incident.AssignmentGroup = group.SysID
+		}
+
+		response, statusCode, err := client.CreateIncident(incident)
+		if err != nil {
+			p.API.LogError(constants.APIErrorCreateIncident, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if _, err := p.shareCreatedIncident(client, args.ChannelId, response); err != nil {
+			p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
+			p.postCommandResponse(args, fmt.Sprintf("Incident %s has been created, but it could not be shared in the channel.", response.Number))
+		}
+	}()
+
+	return genericWaitMessage
+}
+
+// getIncidentCallerID returns the ServiceNow user ID of the Mattermost user with the given username
+func (p *Plugin) getIncidentCallerID(client Client, username string) (string, string) {
+	mmUser, appErr := p.API.GetUserByUsername(username)
+	if appErr != nil {
+		return "", fmt.Sprintf("User @%s doesn't exist.", username)
+	}
+
+	if user, err := p.GetUser(mmUser.Id); err == nil && user.ServiceNowUser != nil {
+		return user.ServiceNowUser.UserID, ""
+	}
+
+	serviceNowUser, _, err := client.GetMe(mmUser.Email)
+	if err != nil {
+		p.API.LogDebug("Unable to get the ServiceNow user", "Username", username, "Error", err.Error())
+		return "", fmt.Sprintf("Unable to find the ServiceNow user of @%s.", username)
+	}
+
+	return serviceNowUser.UserID, ""
+}
+
+func (p *Plugin) handleSubscribe(_ *plugin.Context, args *model.CommandArgs, _ []string, client Client, _ bool) string {
+	if getInstanceID(client) != constants.DefaultInstanceID {
+		return constants.InstanceModalNotSupportedMessage
+	}
+
+	p.API.PublishWebSocketEvent(
+		constants.WSEventOpenAddSubscriptionModal,
+		nil,
+		&model.WebsocketBroadcast{UserId: args.UserId},
+	)
+
+	return ""
+}
+
+func (p *Plugin) handleSearchAndShare(_ *plugin.Context, args *model.CommandArgs, _ []string, _ Client, _ bool) string {
+	p.API.PublishWebSocketEvent(
+		constants.WSEventOpenSearchAndShareRecordsModal,
+		nil,
+		&model.WebsocketBroadcast{UserId: args.UserId},
+	)
+
+	return ""
+}
+
+type subscriptionListFilters struct {
+	userID           string
+	channelID        string
+	subscriptionType string
+	recordType       string
+	event            string
+	page             int
+	exportCSV        bool
+}
+
+func parseSubscriptionListFilters(args *model.CommandArgs, params []string) (*subscriptionListFilters, string) {
+	filters := &subscriptionListFilters{
+		userID:    args.UserId,
+		channelID: args.ChannelId,
+		page:      1,
+	}
+
+	var positional []string
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if !strings.HasPrefix(param, "--") {
+			positional = append(positional, param)
+			continue
+		}
+
+		if param == constants.FlagCSV {
+			filters.exportCSV = true
+			continue
+		}
+
+		if i+1 >= len(params) {
+			return nil, fmt.Sprintf("Missing value for %s", param)
+		}
+
+		i++
+		value := params[i]
+		switch param {
+		case constants.FlagPage:
+			page, err := strconv.Atoi(value)
+			if err != nil || page < 1 {
+				return nil, fmt.Sprintf("Invalid page %s", value)
+			}
+			filters.page = page
+		case constants.FlagRecordType:
+			if !constants.ValidSubscriptionRecordTypes[value] {
+				return nil, fmt.Sprintf("Unknown record type %s. Available record types are: %s", value, strings.Join(getSortedKeys(constants.ValidSubscriptionRecordTypes), ", "))
+			}
+			filters.recordType = value
+		case constants.FlagType:
+			subscriptionType, ok := constants.SubscriptionTypesForFilters[value]
+			if !ok {
+				return nil, fmt.Sprintf("Unknown subscription type %s. Available types are: %s, %s", value, constants.FilterTypeBulk, constants.FilterTypeRecord)
+			}
+			filters.subscriptionType = subscriptionType
+		case constants.FlagEvent:
+			if !constants.ValidSubscriptionEvents[value] {
+				return nil, fmt.Sprintf("Unknown event %s. Available events are: %s", value, strings.Join(getSortedKeys(constants.ValidSubscriptionEvents), ", "))
+			}
+			filters.event = value
+		default:
+			return nil, fmt.Sprintf("Unknown filter %s", param)
+		}
+	}
+
+	if len(positional) >= 1 {
+		if positional[0] != constants.FilterCreatedByMe && positional[0] != constants.FilterCreatedByAnyone {
+			return nil, fmt.Sprintf("Unknown filter %s", positional[0])
+		}
+
+		if positional[0] == constants.FilterCreatedByAnyone {
+			filters.userID = ""
+		}
+	}
+
+	if len(positional) >= 2 {
+		if positional[1] != constants.FilterAllChannels {
+			return nil, fmt.Sprintf("Unknown filter %s", positional[1])
+		}
+		filters.channelID = ""
+	}
+
+	return filters, ""
+}
+
+func (f *subscriptionListFilters) matches(subscription *serializer.SubscriptionResponse) bool {
+	if f.recordType != "" && subscription.RecordType != f.recordType {
+		return false
+	}
+
+	if f.event == "" {
+		return true
+	}
+
+	for _, event := range strings.Split(subscription.SubscriptionEvents, ",") {
+		if strings.TrimSpace(event) == f.event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Plugin) handleListSubscriptions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	filters, errMessage := parseSubscriptionListFilters(args, params)
+	if errMessage != "" {
+		return errMessage
+	}
+
+	go func() {
+		subscriptions, _, err := p.GetAllSubscriptionPages(client, filters.channelID, filters.userID, filters.subscriptionType)
+		if err != nil {
+			p.API.LogError("Unable to get subscriptions", "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, 0, filters.userID, ""))
+			return
+		}
+
+		var subscriptionList []*serializer.SubscriptionResponse
+		for _, subscription := range subscriptions {
+			if !filters.matches(subscription) {
+				continue
+			}
+
+			_, permissionErr := p.HasPublicOrPrivateChannelPermissions(args.UserId, subscription.ChannelID)
+			if permissionErr == nil {
+				subscriptionList = append(subscriptionList, subscription)
+			}
+		}
+
+		if len(subscriptionList) == 0 {
+			p.postCommandResponse(args, constants.ErrorNoActiveSubscriptions)
+			return
+		}
+
+		// The records are fetched using the service account, if configured, as the subscriptions can be created by other users
+		readOnlyClient := p.GetReadOnlyClient(client)
+		if filters.exportCSV {
+			p.exportSubscriptionsToCSV(args, subscriptionList, readOnlyClient)
+			return
+		}
+
+		totalPages := (len(subscriptionList) + constants.DefaultPerPage - 1) / constants.DefaultPerPage
+		if filters.page > totalPages {
+			p.postCommandResponse(args, fmt.Sprintf("Page %d doesn't exist. There are %d page(s) of subscriptions.", filters.page, totalPages))
+			return
+		}
+
+		start := (filters.page - 1) * constants.DefaultPerPage
+		end := start + constants.DefaultPerPage
+		if end > len(subscriptionList) {
+			end = len(subscriptionList)
+		}
+
+		pageSubscriptions := subscriptionList[start:end]
+		p.populateSubscriptionDetails(pageSubscriptions, readOnlyClient)
+
+		message := ParseSubscriptionsToCommandResponse(pageSubscriptions)
+		if totalPages > 1 {
+			message = fmt.Sprintf("%s\n\nShowing page %d of %d (%d subscriptions). Use `%s` to see another page or `%s` to export all the subscriptions.", message, filters.page, totalPages, len(subscriptionList), constants.FlagPage, constants.FlagCSV)
+		}
+
+		p.postCommandResponse(args, message)
+	}()
+
+	return listSubscriptionsWaitMessage
+}
+
+// populateSubscriptionDetails fetches the creator and channel of the subscriptions using a bounded number of goroutines and then fetches their records
+func (p *Plugin) populateSubscriptionDetails(subscriptions []*serializer.SubscriptionResponse, client Client) {
+	runWithWorkerPool(len(subscriptions), constants.SubscriptionsWorkerPoolSize, func(i int) {
+		subscription := subscriptions[i]
+		user, err := p.API.GetUser(subscription.UserID)
+		if err != nil {
+			p.API.LogError("Error in getting user", "UserID", subscription.UserID)
+			subscription.UserName = constants.NotAvailableText
+		} else if user.DeleteAt != 0 {
+			subscription.UserName = fmt.Sprintf("%s (deactivated)", user.Username)
+		} else {
+			subscription.UserName = user.Username
+		}
+
+		channel, err := p.API.GetChannel(subscription.ChannelID)
+		if err != nil {
+			p.API.LogError("Error in getting channel", "ChannelID", subscription.ChannelID)
+			subscription.ChannelName = constants.NotAvailableText
+		} else {
+			subscription.ChannelName = channel.DisplayName
+		}
+	})
+
+	p.PopulateRecordsForSubscriptions(subscriptions, client)
+}
+
+func (p *Plugin) exportSubscriptionsToCSV(args *model.CommandArgs, subscriptions []*serializer.SubscriptionResponse, client Client) {
+	p.populateSubscriptionDetails(subscriptions, client)
+	data, err := ParseSubscriptionsToCSV(subscriptions)
+	if err != nil {
+		p.API.LogError("Unable to create the CSV file of the subscriptions", "Error", err.Error())
+		p.postCommandResponse(args, genericErrorMessage)
+		return
+	}
+
+	message := fmt.Sprintf("Exported %d subscription(s).", len(subscriptions))
+	if err = p.DMFile(args.UserId, constants.SubscriptionsCSVFileName, data, message); err != nil {
+		p.postCommandResponse(args, genericErrorMessage)
+		return
+	}
+
+	p.postCommandResponse(args, "The CSV file of the subscriptions has been sent to you as a DM.")
+}
+
+func (p *Plugin) handleDeleteSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 1 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	go func() {
+		subscriptionID := params[0]
+		valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+		if err != nil {
+			p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
+			p.postCommandResponse(args, deleteSubscriptionErrorMessage)
+			return
+		}
+
+		if !valid {
+			p.postCommandResponse(args, invalidSubscriptionIDMessage)
+			return
+		}
+
+		if statusCode, err := client.DeleteSubscription(subscriptionID); err != nil {
+			p.API.LogError("Unable to delete subscription", "Error", err.Error())
+			if statusCode == http.StatusNotFound {
+				p.postCommandResponse(args, fmt.Sprintf("Subscription with ID %s doesn't exist.", subscriptionID))
+			} else {
+				p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, ""))
+			}
+			return
+		}
+
+		if err := p.store.DeleteSubscriptionSettings(getInstanceID(client), subscriptionID); err != nil {
+			p.API.LogWarn("Unable to delete the settings of the subscription", "SubscriptionID", subscriptionID, "Error", err.Error())
+		}
+
+		p.API.PublishWebSocketEvent(
+			constants.WSEventSubscriptionDeleted,
+			nil,
+			&model.WebsocketBroadcast{UserId: args.UserId},
+		)
+
+		p.postCommandResponse(args, deleteSubscriptionSuccessMessage)
+	}()
+
+	return genericWaitMessage
+}
+
+func (p *Plugin) handleBulkSubscriptions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 2 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	request := &serializer.BulkSubscriptionRequest{}
+	switch params[0] {
+	case constants.SubCommandDelete:
+		request.Action = constants.BulkActionDelete
+	case constants.SubCommandDeactivate:
+		request.Action = constants.BulkActionDeactivate
+	case constants.SubCommandReactivate:
+		request.Action = constants.BulkActionReactivate
+	case constants.SubCommandEvents:
+		request.Action = constants.BulkActionEditEvents
+		request.SubscriptionEvents = params[1]
+		params = params[1:]
+	default:
+		return fmt.Sprintf("Unknown bulk action %s. Available actions are 'delete', 'deactivate', 'reactivate' and 'events'.", params[0])
+	}
+
+	params = params[1:]
+	if len(params) == 0 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	if params[0] == constants.FlagAll {
+		request.ChannelID = args.ChannelId
+	} else {
+		request.SubscriptionIDs = params
+	}
+
+	if err := request.IsValid(); err != nil {
+		return fmt.Sprintf("Unable to update the subscriptions. Error: %s", err.Error())
+	}
+
+	if request.ChannelID != "" {
+		if _, err := p.HasPublicOrPrivateChannelPermissions(args.UserId, request.ChannelID); err != nil {
+			return err.Error()
+		}
+	}
+
+	go func() {
+		result, statusCode, err := p.BulkUpdateSubscriptions(client, args.UserId, request)
+		if err != nil {
+			p.API.LogError(constants.ErrorBulkUpdateSubscriptions, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if (request.Action == constants.BulkActionDelete || request.Action == constants.BulkActionDeactivate) && result.Count(constants.BulkStatusSucceeded) > 0 {
+			p.API.PublishWebSocketEvent(
+				constants.WSEventSubscriptionDeleted,
+				nil,
+				&model.WebsocketBroadcast{UserId: args.UserId},
+			)
+		}
+
+		p.postCommandResponse(args, result.GetFormattedResult())
+	}()
+
+	return genericWaitMessage
+}
+
+func (p *Plugin) handleEditSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 1 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+	subscriptionID := params[0]
+	valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	if !valid {
+		return invalidSubscriptionIDMessage
+	}
+
+	if getInstanceID(client) != constants.DefaultInstanceID {
+		return constants.InstanceModalNotSupportedMessage
+	}
+
+	subscription, _, err := client.GetSubscription(subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to get subscription", "Error", err.Error())
+		return p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, "")
+	}
+
+	if subscription.Type == constants.SubscriptionTypeRecord {
+		p.GetRecordFromServiceNowForSubscription(subscription, client, nil)
+	}
+
+	subscriptionMap, err := ConvertSubscriptionToMap(subscription)
+	if err != nil {
+		p.API.LogError("Unable to convert subscription to map", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	p.API.PublishWebSocketEvent(
+		constants.WSEventOpenEditSubscriptionModal,
+		subscriptionMap,
+		&model.WebsocketBroadcast{UserId: args.UserId},
+	)
+
+	return ""
+}
+
+func (p *Plugin) handleTransferSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 2 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	subscriptionID := params[0]
+	valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	if !valid {
+		return invalidSubscriptionIDMessage
+	}
+
+	subscription, statusCode, err := client.GetSubscription(subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to get subscription", "Error", err.Error())
+		if statusCode == http.StatusNotFound {
+			return fmt.Sprintf("Subscription with ID %s doesn't exist.", subscriptionID)
+		}
+		return p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, "")
+	}
+
+	if !p.CanManageSubscriptionOwner(args.UserId, subscription, isSysAdmin) {
+		return "Only the owner of the subscription, the channel admins and the system admins can transfer the subscription."
+	}
+
+	newOwner, err := p.GetNewSubscriptionOwner(params[1], subscription.ChannelID)
+	if err != nil {
+		return fmt.Sprintf("Unable to transfer the subscription. Error: %s", err.Error())
+	}
+
+	if _, statusCode, err = p.TransferSubscription(client, subscriptionID, newOwner.Id); err != nil {
+		p.API.LogError(constants.ErrorTransferSubscription, "SubscriptionID", subscriptionID, "Error", err.Error())
+		return p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, "")
+	}
+
+	return fmt.Sprintf("Subscription with ID %s has been transferred to @%s.", subscriptionID, newOwner.Username)
+}
+
+func (p *Plugin) handleSubscriptionMentions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 1 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	subscriptionID := params[0]
+	valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	if !valid {
+		return invalidSubscriptionIDMessage
+	}
+
+	mentions := ""
+	if len(params) > 1 {
+		mentions = params[1]
+		if !constants.ValidMentionSettings[mentions] {
+			return fmt.Sprintf("Unknown mention setting %s. Available settings are: %s", mentions, strings.Join(getSortedKeys(constants.ValidMentionSettings), ", "))
+		}
+	}
+
+	subscription, statusCode, err := client.GetSubscription(subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to get subscription", "Error", err.Error())
+		if statusCode == http.StatusNotFound {
+			return fmt.Sprintf("Subscription with ID %s doesn't exist.", subscriptionID)
+		}
+		return p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, "")
+	}
+
+	if _, err = p.HasChannelPermissions(args.UserId, subscription.ChannelID); err != nil {
+		return err.Error()
+	}
+
+	if mentions == "" {
+		settings, err := p.GetSubscriptionSettings(subscription.InstanceID, subscriptionID)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetSubscriptionSettings, "SubscriptionID", subscriptionID, "Error", err.Error())
+			return genericErrorMessage
+		}
+
+		return fmt.Sprintf("Mentions for the subscription with ID %s are set to `%s`.", subscriptionID, settings.Mentions)
+	}
+
+	if err = p.StoreSubscriptionMentions(subscription.InstanceID, subscriptionID, mentions); err != nil {
+		p.API.LogError(constants.ErrorStoreSubscriptionSettings, "SubscriptionID", subscriptionID, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	return fmt.Sprintf("Mentions for the subscription with ID %s are set to `%s`.", subscriptionID, mentions)
+}
+
+func (p *Plugin) handleMuteSubscriptions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	if len(params) < 1 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	duration, err := ParseMuteDuration(params[0])
+	if err != nil {
+		return fmt.Sprintf("Unable to mute the notifications. Error: %s", err.Error())
+	}
+
+	summary := false
+	var targetParams []string
+	for _, param := range params[1:] {
+		if param == constants.FlagSummary {
+			summary = true
+			continue
+		}
+
+		targetParams = append(targetParams, param)
+	}
+
+	channelID, subscriptionID, message := p.getMuteTarget(args, targetParams, client, isSysAdmin)
+	if message != "" {
+		return message
+	}
+
+	mute := &serializer.SubscriptionMute{
+		ChannelID:      channelID,
+		SubscriptionID: subscriptionID,
+		MutedBy:        args.UserId,
+		MutedUntil:     time.Now().Add(duration).UnixMilli(),
+		Summary:        summary,
+	}
+	// The mutes of the channels apply to the subscriptions of all the instances
+	if subscriptionID != "" {
+		mute.InstanceID = getInstanceID(client)
+	}
+	if err = p.store.StoreMute(mute); err != nil {
+		p.API.LogError(constants.ErrorStoreMute, "Key", mute.Key(), "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	response := fmt.Sprintf("Notifications of the subscriptions in this channel are muted for %s.", params[0])
+	if subscriptionID != "" {
+		response = fmt.Sprintf("Notifications of the subscription with ID %s are muted for %s.", subscriptionID, params[0])
+	}
+
+	if summary {
+		response += " A summary of the muted notifications will be posted when the mute expires."
+	}
+
+	return response
+}
+
+func (p *Plugin) handleUnmuteSubscriptions(_ *plugin.Context, args *model.CommandArgs, params []string, client Client, isSysAdmin bool) string {
+	channelID, subscriptionID, message := p.getMuteTarget(args, params, client, isSysAdmin)
+	if message != "" {
+		return message
+	}
+
+	target := "the subscriptions in this channel"
+	if subscriptionID != "" {
+		target = fmt.Sprintf("the subscription with ID %s", subscriptionID)
+	}
+
+	if _, err := p.UnmuteNotifications(channelID, getInstanceID(client), subscriptionID); err != nil {
+		if err == ErrNotFound {
+			return fmt.Sprintf("Notifications of %s are not muted.", target)
+		}
+
+		p.API.LogError("Unable to unmute the notifications", "ChannelID", channelID, "SubscriptionID", subscriptionID, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	return fmt.Sprintf("Notifications of %s are unmuted.", target)
+}
+
+// getMuteTarget returns the channel and the subscription to be muted or unmuted, or a message for the user if they can't be muted.
+// The current channel is muted when no subscription ID is given.
+func (p *Plugin) getMuteTarget(args *model.CommandArgs, params []string, client Client, isSysAdmin bool) (channelID, subscriptionID, message string) {
+	if len(params) == 0 {
+		if _, err := p.HasPublicOrPrivateChannelPermissions(args.UserId, args.ChannelId); err != nil {
+			return "", "", err.Error()
+		}
+
+		return args.ChannelId, "", ""
+	}
+
+	subscriptionID = params[0]
+	valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to validate the subscription ID", "Error", err.Error())
+		return "", "", genericErrorMessage
+	}
+
+	if !valid {
+		return "", "", invalidSubscriptionIDMessage
+	}
+
+	subscription, statusCode, err := client.GetSubscription(subscriptionID)
+	if err != nil {
+		p.API.LogError("Unable to get subscription", "Error", err.Error())
+		if statusCode == http.StatusNotFound {
+			return "", "", fmt.Sprintf("Subscription with ID %s doesn't exist.", subscriptionID)
+		}
+		return "", "", p.handleClientError(nil, nil, err, isSysAdmin, 0, args.UserId, "")
+	}
+
+	if _, err = p.HasPublicOrPrivateChannelPermissions(args.UserId, subscription.ChannelID); err != nil {
+		return "", "", err.Error()
+	}
+
+	return subscription.ChannelID, subscriptionID, ""
+}
+
+func (p *Plugin) handlePersonalSubscription(_ *plugin.Context, args *model.CommandArgs, params []string, _ Client, _ bool) string {
+	subscription, err := p.GetPersonalSubscription(args.UserId)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetPersonalSubscription, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	if len(params) == 0 || params[0] == constants.SubCommandList {
+		return subscription.GetFormattedPersonalSubscription()
+	}
+
+	if len(params) < 2 {
+		return constants.ErrorCommandInvalidNumberOfParams
+	}
+
+	command, event := params[0], params[1]
+	if !constants.ValidPersonalSubscriptionEvents[event] {
+		return fmt.Sprintf("Unknown event %s. Available events are: %s", event, strings.Join(getSortedKeys(constants.ValidPersonalSubscriptionEvents), ", "))
+	}
+
+	events := []string{}
+	for _, e := range subscription.Events {
+		if e != event {
+			events = append(events, e)
+		}
+	}
+
+	switch command {
+	case constants.SubCommandAdd:
+		events = append(events, event)
+	case constants.SubCommandDelete:
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", command)
+	}
+
+	subscription, err = p.UpdatePersonalSubscription(args.UserId, events)
+	if err != nil {
+		p.API.LogError(constants.ErrorStorePersonalSubscription, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	return subscription.GetFormattedPersonalSubscription()
+}
+
+func getAutocompleteData() *model.AutocompleteData {
+	serviceNow := model.NewAutocompleteData(constants.CommandTrigger, "[command]", fmt.Sprintf("Available commands: %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s", constants.CommandConnect, constants.CommandDisconnect, constants.CommandSubscriptions, constants.CommandSearchAndShare, constants.CommandIncident, constants.CommandCatalog, constants.CommandRequests, constants.CommandKnowledge, constants.CommandCI, constants.CommandChanges, constants.CommandOnCall, constants.CommandInstance, constants.CommandHelp))
+
+	connect := model.NewAutocompleteData(constants.CommandConnect, "", "Connect your Mattermost account to your ServiceNow account")
+	serviceNow.AddCommand(connect)
+
+	disconnect := model.NewAutocompleteData(constants.CommandDisconnect, "", "Disconnect your Mattermost account from your ServiceNow account")
+	serviceNow.AddCommand(disconnect)
+
+	subscriptions := model.NewAutocompleteData(constants.CommandSubscriptions, "[command]", fmt.Sprintf("Available commands: %s, %s, %s, %s", constants.SubCommandList, constants.SubCommandAdd, constants.SubCommandEdit, constants.SubCommandDelete))
+
+	subscribeList := model.NewAutocompleteData("list", "", fmt.Sprintf("List the current channel subscriptions. Optional flags: %s <number>, %s <record_type>, %s <record|bulk>, %s <event>, %s", constants.FlagPage, constants.FlagRecordType, constants.FlagType, constants.FlagEvent, constants.FlagCSV))
+	subscriptionCreatedByMe := model.NewAutocompleteData("me", "", "Created By Me")
+	subscriptionShowForAllChannels := model.NewAutocompleteData("all_channels", "", "Show for all channels or You can leave this argument to show for the current channel only")
+	subscriptionCreatedByMe.AddCommand(subscriptionShowForAllChannels)
+	subscribeList.AddCommand(subscriptionCreatedByMe)
+	subscriptionCreatedByAnyone := model.NewAutocompleteData("anyone", "", "Created By Anyone")
+	subscriptionCreatedByAnyone.AddCommand(subscriptionShowForAllChannels)
+	subscribeList.AddCommand(subscriptionCreatedByAnyone)
+	subscriptions.AddCommand(subscribeList)
+
+	subscriptionsAdd := model.NewAutocompleteData(constants.SubCommandAdd, "", "Subscribe to the record changes in ServiceNow")
+	subscriptions.AddCommand(subscriptionsAdd)
+
+	subscriptionsEdit := model.NewAutocompleteData(constants.SubCommandEdit, "[subscription_id]", "Edit the subscriptions created to the record changes in ServiceNow")
+	subscriptionsEdit.AddTextArgument("ID of the subscription", "[subscription_id]", "")
+	subscriptions.AddCommand(subscriptionsEdit)
+
+	subscriptionsDelete := model.NewAutocompleteData(constants.SubCommandDelete, "[subscription_id]", "Unsubscribe to the record changes in ServiceNow")
+	subscriptionsDelete.AddTextArgument("ID of the subscription", "[subscription_id]", "")
+	subscriptions.AddCommand(subscriptionsDelete)
+
+	subscriptionsBulk := model.NewAutocompleteData(constants.SubCommandBulk, "[command]", "Update many subscriptions at once")
+	subscriptionsBulkDelete := model.NewAutocompleteData(constants.SubCommandDelete, "[subscription_ids]", fmt.Sprintf("Delete the given subscriptions or all the subscriptions of the channel using %s", constants.FlagAll))
+	subscriptionsBulkDelete.AddTextArgument("IDs of the subscriptions separated by spaces", "[subscription_ids]", "")
+	subscriptionsBulk.AddCommand(subscriptionsBulkDelete)
+	subscriptionsBulkDeactivate := model.NewAutocompleteData(constants.SubCommandDeactivate, "[subscription_ids]", fmt.Sprintf("Deactivate the given subscriptions or all the subscriptions of the channel using %s", constants.FlagAll))
+	subscriptionsBulkDeactivate.AddTextArgument("IDs of the subscriptions separated by spaces", "[subscription_ids]", "")
+	subscriptionsBulk.AddCommand(subscriptionsBulkDeactivate)
+	subscriptionsBulkReactivate := model.NewAutocompleteData(constants.SubCommandReactivate, "[subscription_ids]", fmt.Sprintf("Reactivate the given subscriptions or all the deactivated subscriptions of the channel using %s", constants.FlagAll))
+	subscriptionsBulkReactivate.AddTextArgument("IDs of the subscriptions separated by spaces", "[subscription_ids]", "")
+	subscriptionsBulk.AddCommand(subscriptionsBulkReactivate)
+	subscriptionsBulkEvents := model.NewAutocompleteData(constants.SubCommandEvents, "[events] [subscription_ids]", fmt.Sprintf("Change the events of the given subscriptions or all the subscriptions of the channel using %s", constants.FlagAll))
+	subscriptionsBulkEvents.AddTextArgument(fmt.Sprintf("Comma separated events from: %s", strings.Join(getSortedKeys(constants.ValidSubscriptionEvents), ", ")), "[events]", "")
+	subscriptionsBulkEvents.AddTextArgument("IDs of the subscriptions separated by spaces", "[subscription_ids]", "")
+	subscriptionsBulk.AddCommand(subscriptionsBulkEvents)
+	subscriptions.AddCommand(subscriptionsBulk)
+
+	subscriptionsTransfer := model.NewAutocompleteData(constants.SubCommandTransfer, "[subscription_id] [@user]", "Transfer the ownership of a subscription to another user")
+	subscriptionsTransfer.AddTextArgument("ID of the subscription", "[subscription_id]", "")
+	subscriptionsTransfer.AddTextArgument("User who will own the subscription", "[@user]", "")
+	subscriptions.AddCommand(subscriptionsTransfer)
+
+	subscriptionsMentions := model.NewAutocompleteData(constants.SubCommandMentions, "[subscription_id] [setting]", "Mention the assignees in the notifications of a subscription")
+	subscriptionsMentions.AddTextArgument("ID of the subscription", "[subscription_id]", "")
+	subscriptionsMentions.AddStaticListArgument("Setting", false, []model.AutocompleteListItem{
+		{Item: constants.MentionSettingOff, HelpText: "Don't mention anyone"},
+		{Item: constants.MentionSettingAssignee, HelpText: "Mention the assignee of the record"},
+		{Item: constants.MentionSettingAssigneeAndGroup, HelpText: "Mention the assignee and the members of the assignment group of the record"},
+	})
+	subscriptions.AddCommand(subscriptionsMentions)
+
+	subscriptionsMute := model.NewAutocompleteData(constants.SubCommandMute, "[duration] [subscription_id]", fmt.Sprintf("Mute the notifications of a subscription or of all the subscriptions in the channel for a duration like 30m, 2h or 1d. Optional flag: %s", constants.FlagSummary))
+	subscriptionsMute.AddTextArgument("Duration like 30m, 2h or 1d", "[duration]", "")
+	subscriptionsMute.AddTextArgument("ID of the subscription. Leave empty to mute the whole channel", "[subscription_id]", "")
+	subscriptions.AddCommand(subscriptionsMute)
+
+	subscriptionsUnmute := model.NewAutocompleteData(constants.SubCommandUnmute, "[subscription_id]", "Unmute the notifications of a subscription or of the channel")
+	subscriptionsUnmute.AddTextArgument("ID of the subscription. Leave empty to unmute the whole channel", "[subscription_id]", "")
+	subscriptions.AddCommand(subscriptionsUnmute)
+
+	personalEvents := []model.AutocompleteListItem{}
+	for _, event := range getSortedKeys(constants.ValidPersonalSubscriptionEvents) {
+		personalEvents = append(personalEvents, model.AutocompleteListItem{
+			Item:     event,
+			HelpText: constants.FormattedPersonalSubscriptionEvents[event],
+		})
+	}
+
+	subscriptionsPersonal := model.NewAutocompleteData(constants.SubCommandPersonal, "[command]", "Manage the notifications sent to you as DMs")
+	subscriptionsPersonal.AddCommand(model.NewAutocompleteData(constants.SubCommandList, "", "List your personal subscriptions"))
+	subscriptionsPersonalAdd := model.NewAutocompleteData(constants.SubCommandAdd, "[event]", "Get a DM for an event")
+	subscriptionsPersonalAdd.AddStaticListArgument("Event", true, personalEvents)
+	subscriptionsPersonal.AddCommand(subscriptionsPersonalAdd)
+	subscriptionsPersonalDelete := model.NewAutocompleteData(constants.SubCommandDelete, "[event]", "Stop getting a DM for an event")
+	subscriptionsPersonalDelete.AddStaticListArgument("Event", true, personalEvents)
+	subscriptionsPersonal.AddCommand(subscriptionsPersonalDelete)
+	subscriptions.AddCommand(subscriptionsPersonal)
+
+	serviceNow.AddCommand(subscriptions)
+
+	admin := model.NewAutocompleteData(constants.CommandAdmin, "[command]", fmt.Sprintf("Available commands: %s, %s, %s, %s", constants.SubCommandService, constants.SubCommandReassign, constants.CommandSubscriptions, constants.SubCommandDiagnose))
+	admin.RoleID = model.SystemAdminRoleId
+	serviceAccount := model.NewAutocompleteData(constants.SubCommandService, "[command]", "Manage the service account used for the read-only calls to ServiceNow")
+	serviceAccount.AddCommand(model.NewAutocompleteData(constants.SubCommandStatus, "", "Check the status of the service account"))
+	serviceAccount.AddCommand(model.NewAutocompleteData(constants.SubCommandSet, "", "Use your ServiceNow token for the service account"))
+	serviceAccount.AddCommand(model.NewAutocompleteData(constants.SubCommandUnset, "", "Remove the token of the service account"))
+	admin.AddCommand(serviceAccount)
+	reassign := model.NewAutocompleteData(constants.SubCommandReassign, "[@from] [@to]", "Reassign all the subscriptions owned by a user to another user")
+	reassign.AddTextArgument("User owning the subscriptions", "[@from]", "")
+	reassign.AddTextArgument("User who will own the subscriptions", "[@to]", "")
+	admin.AddCommand(reassign)
+	adminSubscriptions := model.NewAutocompleteData(constants.CommandSubscriptions, "[command]", "Export, import or clone the subscriptions")
+	adminSubscriptionsExport := model.NewAutocompleteData(constants.SubCommandExport, "[format]", fmt.Sprintf("Export the subscriptions as a file sent to you as a DM. Optional flags: %s <~channel>, %s <team>", constants.FlagChannel, constants.FlagTeam))
+	adminSubscriptionsExport.AddStaticListArgument("Format", false, []model.AutocompleteListItem{
+		{Item: constants.ExportFormatJSON, HelpText: "Export the subscriptions as a JSON file"},
+		{Item: constants.ExportFormatCSV, HelpText: "Export the subscriptions as a CSV file"},
+	})
+	adminSubscriptions.AddCommand(adminSubscriptionsExport)
+	adminSubscriptionsImport := model.NewAutocompleteData(constants.SubCommandImport, "[post ID or link]", fmt.Sprintf("Import the subscriptions from the file attached to a post. Optional flags: %s <old_channel:new_channel>, %s", constants.FlagMap, constants.FlagApply))
+	adminSubscriptionsImport.AddTextArgument("ID or link of the post having the exported file", "[post ID or link]", "")
+	adminSubscriptions.AddCommand(adminSubscriptionsImport)
+	adminSubscriptionsClone := model.NewAutocompleteData(constants.SubCommandClone, "[~from] [~to]", fmt.Sprintf("Copy all the subscriptions of a channel to another channel. Optional flag: %s", constants.FlagApply))
+	adminSubscriptionsClone.AddTextArgument("Channel whose subscriptions are copied", "[~from]", "")
+	adminSubscriptionsClone.AddTextArgument("Channel to which the subscriptions are copied", "[~to]", "")
+	adminSubscriptions.AddCommand(adminSubscriptionsClone)
+	admin.AddCommand(adminSubscriptions)
+	serviceNow.AddCommand(admin)
+
+	searchRecords := model.NewAutocompleteData(constants.CommandSearchAndShare, "", "Search and share a ServiceNow record")
+	serviceNow.AddCommand(searchRecords)
 
 	incident := model.NewAutocompleteData(constants.CommandIncident, "[command]", fmt.Sprintf("Available command: %s", constants.SubCommandCreate))
-	incidentCreate := model.NewAutocompleteData(constants.SubCommandCreate, "", "Create an incident")
+	incidentCreate := model.NewAutocompleteData(constants.SubCommandCreate, "[short description] [--urgency 1|2|3] [--group group] [--caller @username] [--description description]", "Create an incident. The dialog is opened when no short description is given.")
 	incident.AddCommand(incidentCreate)
 	serviceNow.AddCommand(incident)
 
+	catalog := model.NewAutocompleteData(constants.CommandCatalog, "[search term]", "Search the service catalog and order an item")
+	serviceNow.AddCommand(catalog)
+
+	requests := model.NewAutocompleteData(constants.CommandRequests, "", "List your open requested items along with their stage and approval")
+	serviceNow.AddCommand(requests)
+
+	knowledge := model.NewAutocompleteData(constants.CommandKnowledge, "[query]", "Search the published knowledge articles and view or share them")
+	serviceNow.AddCommand(knowledge)
+
+	ci := model.NewAutocompleteData(constants.CommandCI, "[name]", "Search the configuration items by name and view their open incidents and changes")
+	serviceNow.AddCommand(ci)
+
+	changes := model.NewAutocompleteData(constants.CommandChanges, "[command]", fmt.Sprintf("Available commands: %s, %s", constants.SubCommandUpcoming, constants.SubCommandConflicts))
+	changesUpcoming := model.NewAutocompleteData(constants.SubCommandUpcoming, "[--ci name] [--window 7d]", "View the calendar of the scheduled changes, optionally of a configuration item")
+	changes.AddCommand(changesUpcoming)
+	changesConflicts := model.NewAutocompleteData(constants.SubCommandConflicts, "--ci name [--window 7d]", "List the changes of a configuration item scheduled during the window and the ones overlapping each other")
+	changes.AddCommand(changesConflicts)
+	serviceNow.AddCommand(changes)
+
+	onCall := model.NewAutocompleteData(constants.CommandOnCall, "[assignment group]", "See who is on call now and next for an assignment group")
+	onCall.AddTextArgument("Name of the assignment group", "[assignment group]", "")
+	serviceNow.AddCommand(onCall)
+
+	instance := model.NewAutocompleteData(constants.CommandInstance, "[command]", fmt.Sprintf("Available commands: %s, %s", constants.SubCommandList, constants.SubCommandDefault))
+	instanceList := model.NewAutocompleteData(constants.SubCommandList, "", "List the ServiceNow instances and the ones you are connected to")
+	instance.AddCommand(instanceList)
+	instanceDefault := model.NewAutocompleteData(constants.SubCommandDefault, "[instance]", "Set the ServiceNow instance used by the commands run in this channel")
+	instanceDefault.AddTextArgument("ID of the instance", "[instance]", "")
+	instance.AddCommand(instanceDefault)
+	serviceNow.AddCommand(instance)
+
 	help := model.NewAutocompleteData(constants.CommandHelp, "", "Display slash command help text")
 	serviceNow.AddCommand(help)
 
@@ -403,7 +403,7 @@ func TestHandleSubscriptions(t *testing.T) {
 	}{
 		{
 			description:      "HandleSubscriptions: Invalid number of params",
-			expectedResponse: "Invalid subscribe command. Available commands are 'list', 'add', 'edit' and 'delete'.",
+			expectedResponse: "Invalid subscribe command. Available commands are 'list', 'add', 'edit', 'delete', 'bulk', 'transfer', 'mentions', 'mute', 'unmute' and 'personal'.",
 		},
 		{
 			description:      "HandleSubscriptions: Unknown command",
@@ -562,8 +562,8 @@ func TestHandleListSubscriptions(t *testing.T) {
 				client.On("GetAllSubscriptions", testutils.GetMockArgumentsWithType("string", 5)...).Return(
 					testutils.GetSubscriptions(2), 0, nil,
 				)
-				client.On("GetRecordFromServiceNow", testutils.GetMockArgumentsWithType("string", 2)...).Return(
-					testutils.GetServiceNowRecord(), 0, nil,
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{""}).Return(
+					[]*serializer.ServiceNowPartialRecord{{Number: testutils.GetServiceNowNumber(), ShortDescription: testutils.GetServiceNowShortDescription()}}, http.StatusOK, nil,
 				)
 			},
 			setupPlugin: func(p *Plugin) {
@@ -626,6 +626,66 @@ func TestHandleListSubscriptions(t *testing.T) {
 			expectedResponse: constants.ErrorNoActiveSubscriptions,
 			expectedError:    listSubscriptionsWaitMessage,
 		},
+		{
+			description: "HandleListSubscriptions: Page doesn't exist",
+			params:      []string{constants.FilterCreatedByMe, constants.FilterAllChannels, constants.FlagPage, "3"},
+			setupAPI:    func(a *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetAllSubscriptions", testutils.GetMockArgumentsWithType("string", 5)...).Return(
+					testutils.GetSubscriptions(2), 0, nil,
+				)
+			},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusOK, nil
+				})
+			},
+			isResponse:       true,
+			expectedResponse: "Page 3 doesn't exist. There are 1 page(s) of subscriptions.",
+			expectedError:    listSubscriptionsWaitMessage,
+		},
+		{
+			description: "HandleListSubscriptions: No subscriptions match the filters",
+			params:      []string{constants.FilterCreatedByMe, constants.FilterAllChannels, constants.FlagRecordType, constants.RecordTypeIncident},
+			setupAPI:    func(a *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetAllSubscriptions", testutils.GetMockArgumentsWithType("string", 5)...).Return(
+					testutils.GetSubscriptions(2), 0, nil,
+				)
+			},
+			setupPlugin:      func(p *Plugin) {},
+			isResponse:       true,
+			expectedResponse: constants.ErrorNoActiveSubscriptions,
+			expectedError:    listSubscriptionsWaitMessage,
+		},
+		{
+			description: "HandleListSubscriptions: Export to CSV",
+			params:      []string{constants.FilterCreatedByMe, constants.FilterAllChannels, constants.FlagType, constants.FilterTypeBulk, constants.FlagCSV},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", mock.AnythingOfType("string")).Return(
+					testutils.GetUser(model.SystemAdminRoleId), nil,
+				)
+				a.On("GetChannel", mock.AnythingOfType("string")).Return(
+					testutils.GetChannel(model.ChannelTypePrivate), nil,
+				)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetAllSubscriptions", "", testutils.GetID(), constants.SubscriptionTypeBulk, fmt.Sprint(constants.MaxPerPage), "0").Return(
+					[]*serializer.SubscriptionResponse{testutils.GetSubscription(constants.SubscriptionTypeBulk)}, 0, nil,
+				)
+			},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusOK, nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "DMFile", func(_ *Plugin, _, _ string, _ []byte, _ string) error {
+					return nil
+				})
+			},
+			isResponse:       true,
+			expectedResponse: "The CSV file of the subscriptions has been sent to you as a DM.",
+			expectedError:    listSubscriptionsWaitMessage,
+		},
 		{
 			description: "HandleListSubscriptions: Success",
 			params:      []string{constants.FilterCreatedByMe, constants.FilterAllChannels},
@@ -641,8 +701,8 @@ func TestHandleListSubscriptions(t *testing.T) {
 				client.On("GetAllSubscriptions", testutils.GetMockArgumentsWithType("string", 5)...).Return(
 					testutils.GetSubscriptions(2), 0, nil,
 				)
-				client.On("GetRecordFromServiceNow", testutils.GetMockArgumentsWithType("string", 2)...).Return(
-					testutils.GetServiceNowRecord(), 0, nil,
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{""}).Return(
+					[]*serializer.ServiceNowPartialRecord{{Number: testutils.GetServiceNowNumber(), ShortDescription: testutils.GetServiceNowShortDescription()}}, http.StatusOK, nil,
 				)
 			},
 			setupPlugin: func(p *Plugin) {
@@ -680,8 +740,70 @@ func TestHandleListSubscriptions(t *testing.T) {
 	}
 }
 
+func TestParseSubscriptionListFilters(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description     string
+		params          []string
+		expectedFilters *subscriptionListFilters
+		expectedError   string
+	}{
+		{
+			description: "ParseSubscriptionListFilters: Default filters",
+			expectedFilters: &subscriptionListFilters{
+				userID:    testutils.GetID(),
+				channelID: testutils.GetChannelID(),
+				page:      1,
+			},
+		},
+		{
+			description: "ParseSubscriptionListFilters: All filters",
+			params:      []string{constants.FilterCreatedByAnyone, constants.FilterAllChannels, constants.FlagPage, "2", constants.FlagRecordType, constants.RecordTypeIncident, constants.FlagType, constants.FilterTypeBulk, constants.FlagEvent, constants.SubscriptionEventState, constants.FlagCSV},
+			expectedFilters: &subscriptionListFilters{
+				subscriptionType: constants.SubscriptionTypeBulk,
+				recordType:       constants.RecordTypeIncident,
+				event:            constants.SubscriptionEventState,
+				page:             2,
+				exportCSV:        true,
+			},
+		},
+		{
+			description:   "ParseSubscriptionListFilters: Invalid page",
+			params:        []string{constants.FlagPage, "0"},
+			expectedError: "Invalid page 0",
+		},
+		{
+			description:   "ParseSubscriptionListFilters: Missing value",
+			params:        []string{constants.FlagEvent},
+			expectedError: "Missing value for --event",
+		},
+		{
+			description:   "ParseSubscriptionListFilters: Invalid type",
+			params:        []string{constants.FlagType, "invalid"},
+			expectedError: "Unknown subscription type invalid. Available types are: bulk, record",
+		},
+		{
+			description:   "ParseSubscriptionListFilters: Unknown flag",
+			params:        []string{"--invalid", "value"},
+			expectedError: "Unknown filter --invalid",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			filters, errMessage := parseSubscriptionListFilters(args, testCase.params)
+
+			assert.Equal(t, testCase.expectedError, errMessage)
+			assert.Equal(t, testCase.expectedFilters, filters)
+		})
+	}
+}
+
 func TestHandleDeleteSubscription(t *testing.T) {
-	p := Plugin{}
+	store := &mock_plugin.Store{}
+	store.On("DeleteSubscriptionSettings", constants.DefaultInstanceID, testutils.GetServiceNowSysID()).Return(nil).Maybe()
+	p := Plugin{store: store}
 	mockAPI := &plugintest.API{}
 	args := &model.CommandArgs{
 		UserId: testutils.GetID(),
@@ -763,6 +885,119 @@ func TestHandleDeleteSubscription(t *testing.T) {
 	}
 }
 
+func TestHandleBulkSubscriptions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	successResult := &serializer.BulkSubscriptionResult{
+		Action: constants.BulkActionDelete,
+		Items: []*serializer.BulkSubscriptionItem{
+			{SubscriptionID: testutils.GetServiceNowSysID(), Status: constants.BulkStatusSucceeded},
+		},
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		setupAPI         func(*plugintest.API)
+		setupPlugin      func(*Plugin)
+		isResponse       bool
+		expectedResponse string
+		expectedMessage  string
+	}{
+		{
+			description: "HandleBulkSubscriptions: Delete the given subscriptions",
+			params:      []string{constants.SubCommandDelete, testutils.GetServiceNowSysID()},
+			setupAPI: func(a *plugintest.API) {
+				a.On("PublishWebSocketEvent", constants.WSEventSubscriptionDeleted, mock.Anything, mock.AnythingOfType("*model.WebsocketBroadcast")).Return().Once()
+			},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "BulkUpdateSubscriptions", func(_ *Plugin, _ Client, _ string, request *serializer.BulkSubscriptionRequest) (*serializer.BulkSubscriptionResult, int, error) {
+					assert.Equal(t, []string{testutils.GetServiceNowSysID()}, request.SubscriptionIDs)
+					return successResult, http.StatusOK, nil
+				})
+			},
+			isResponse:       true,
+			expectedResponse: successResult.GetFormattedResult(),
+			expectedMessage:  genericWaitMessage,
+		},
+		{
+			description: "HandleBulkSubscriptions: Edit the events of all the subscriptions of the channel",
+			params:      []string{constants.SubCommandEvents, constants.SubscriptionEventState, constants.FlagAll},
+			setupAPI:    func(a *plugintest.API) {},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusOK, nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "BulkUpdateSubscriptions", func(_ *Plugin, _ Client, _ string, request *serializer.BulkSubscriptionRequest) (*serializer.BulkSubscriptionResult, int, error) {
+					assert.Equal(t, constants.BulkActionEditEvents, request.Action)
+					assert.Equal(t, constants.SubscriptionEventState, request.SubscriptionEvents)
+					assert.Equal(t, testutils.GetChannelID(), request.ChannelID)
+					return &serializer.BulkSubscriptionResult{Action: request.Action}, http.StatusOK, nil
+				})
+			},
+			isResponse:       true,
+			expectedResponse: "No subscriptions found.",
+			expectedMessage:  genericWaitMessage,
+		},
+		{
+			description:     "HandleBulkSubscriptions: Invalid number of params",
+			params:          []string{constants.SubCommandDelete},
+			setupAPI:        func(a *plugintest.API) {},
+			setupPlugin:     func(p *Plugin) {},
+			expectedMessage: constants.ErrorCommandInvalidNumberOfParams,
+		},
+		{
+			description:     "HandleBulkSubscriptions: Unknown action",
+			params:          []string{"invalid", constants.FlagAll},
+			setupAPI:        func(a *plugintest.API) {},
+			setupPlugin:     func(p *Plugin) {},
+			expectedMessage: "Unknown bulk action invalid. Available actions are 'delete', 'deactivate', 'reactivate' and 'events'.",
+		},
+		{
+			description:     "HandleBulkSubscriptions: Invalid subscription ID",
+			params:          []string{constants.SubCommandDeactivate, "invalidID"},
+			setupAPI:        func(a *plugintest.API) {},
+			setupPlugin:     func(p *Plugin) {},
+			expectedMessage: "Unable to update the subscriptions. Error: subscription ID invalidID is not valid",
+		},
+		{
+			description: "HandleBulkSubscriptions: Insufficient permissions for the channel",
+			params:      []string{constants.SubCommandReactivate, constants.FlagAll},
+			setupAPI:    func(a *plugintest.API) {},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+					return http.StatusForbidden, fmt.Errorf(constants.ErrorInsufficientPermissions)
+				})
+			},
+			expectedMessage: constants.ErrorInsufficientPermissions,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			assert := assert.New(t)
+			mockAPI := &plugintest.API{}
+			defer mockAPI.AssertExpectations(t)
+			p := &Plugin{}
+			p.SetAPI(mockAPI)
+			testCase.setupAPI(mockAPI)
+			testCase.setupPlugin(p)
+
+			if testCase.isResponse {
+				mockAPI.On("SendEphemeralPost", mock.AnythingOfType("string"), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					post := args.Get(1).(*model.Post)
+					assert.Equal(testCase.expectedResponse, post.Message)
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleBulkSubscriptions(&plugin.Context{}, args, testCase.params, mock_plugin.NewClient(t), false)
+			assert.EqualValues(testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
+
 func TestHandleEditSubscription(t *testing.T) {
 	p := Plugin{}
 	mockAPI := &plugintest.API{}
@@ -830,6 +1065,440 @@ func TestHandleEditSubscription(t *testing.T) {
 	}
 }
 
+func TestHandleSubscriptionMentions(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId: testutils.GetID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		setupAPI         func(*plugintest.API)
+		setupClient      func(client *mock_plugin.Client)
+		setupStore       func(store *mock_plugin.Store)
+		expectedResponse string
+	}{
+		{
+			description: "HandleSubscriptionMentions: Success",
+			params:      []string{testutils.GetServiceNowSysID(), constants.MentionSettingAssignee},
+			setupAPI: func(a *plugintest.API) {
+				a.On("HasPermissionToChannel", testutils.GetID(), mock.AnythingOfType("string"), model.PermissionCreatePost).Return(true)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			setupStore: func(store *mock_plugin.Store) {
+				store.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{Mentions: constants.MentionSettingOff, ConfigurationItemID: "mockItemID"}, nil)
+				store.On("StoreSubscriptionSettings", "", testutils.GetServiceNowSysID(), &serializer.SubscriptionSettings{Mentions: constants.MentionSettingAssignee, ConfigurationItemID: "mockItemID"}).Return(nil)
+			},
+			expectedResponse: fmt.Sprintf("Mentions for the subscription with ID %s are set to `%s`.", testutils.GetServiceNowSysID(), constants.MentionSettingAssignee),
+		},
+		{
+			description: "HandleSubscriptionMentions: Current setting",
+			params:      []string{testutils.GetServiceNowSysID()},
+			setupAPI: func(a *plugintest.API) {
+				a.On("HasPermissionToChannel", testutils.GetID(), mock.AnythingOfType("string"), model.PermissionCreatePost).Return(true)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			setupStore: func(store *mock_plugin.Store) {
+				store.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(nil, ErrNotFound)
+			},
+			expectedResponse: fmt.Sprintf("Mentions for the subscription with ID %s are set to `%s`.", testutils.GetServiceNowSysID(), constants.MentionSettingOff),
+		},
+		{
+			description: "HandleSubscriptionMentions: User does not have permission to view the current setting",
+			params:      []string{testutils.GetServiceNowSysID()},
+			setupAPI: func(a *plugintest.API) {
+				a.On("HasPermissionToChannel", testutils.GetID(), mock.AnythingOfType("string"), model.PermissionCreatePost).Return(false)
+				a.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			setupStore:       func(store *mock_plugin.Store) {},
+			expectedResponse: constants.ErrorInsufficientPermissions,
+		},
+		{
+			description:      "HandleSubscriptionMentions: Invalid number of params",
+			setupAPI:         func(a *plugintest.API) {},
+			setupClient:      func(client *mock_plugin.Client) {},
+			setupStore:       func(store *mock_plugin.Store) {},
+			expectedResponse: constants.ErrorCommandInvalidNumberOfParams,
+		},
+		{
+			description:      "HandleSubscriptionMentions: Invalid subscription ID",
+			params:           []string{"invalidID", constants.MentionSettingAssignee},
+			setupAPI:         func(a *plugintest.API) {},
+			setupClient:      func(client *mock_plugin.Client) {},
+			setupStore:       func(store *mock_plugin.Store) {},
+			expectedResponse: invalidSubscriptionIDMessage,
+		},
+		{
+			description:      "HandleSubscriptionMentions: Invalid setting",
+			params:           []string{testutils.GetServiceNowSysID(), "everyone"},
+			setupAPI:         func(a *plugintest.API) {},
+			setupClient:      func(client *mock_plugin.Client) {},
+			setupStore:       func(store *mock_plugin.Store) {},
+			expectedResponse: "Unknown mention setting everyone. Available settings are: assignee, assignee_and_group, off",
+		},
+		{
+			description: "HandleSubscriptionMentions: User does not have permission for the subscription channel",
+			params:      []string{testutils.GetServiceNowSysID(), constants.MentionSettingAssignee},
+			setupAPI: func(a *plugintest.API) {
+				a.On("HasPermissionToChannel", testutils.GetID(), mock.AnythingOfType("string"), model.PermissionCreatePost).Return(false)
+				a.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			setupStore:       func(store *mock_plugin.Store) {},
+			expectedResponse: constants.ErrorInsufficientPermissions,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert := assert.New(t)
+			mockAPI := &plugintest.API{}
+			defer mockAPI.AssertExpectations(t)
+			c := mock_plugin.NewClient(t)
+			store := mock_plugin.NewStore(t)
+			testCase.setupAPI(mockAPI)
+			testCase.setupClient(c)
+			testCase.setupStore(store)
+			p := Plugin{store: store}
+			p.SetAPI(mockAPI)
+
+			resp := p.handleSubscriptionMentions(&plugin.Context{}, args, testCase.params, c, true)
+
+			assert.EqualValues(testCase.expectedResponse, resp)
+		})
+	}
+}
+
+func TestHandleAdmin(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId: testutils.GetID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		isSysAdmin       bool
+		mode             string
+		setupAPI         func(*plugintest.API)
+		setupStore       func(*mock_plugin.Store)
+		setupPlugin      func(*Plugin)
+		expectedResponse string
+	}{
+		{
+			description:      "HandleAdmin: User is not a system admin",
+			params:           []string{constants.SubCommandService},
+			setupAPI:         func(a *plugintest.API) {},
+			setupStore:       func(s *mock_plugin.Store) {},
+			setupPlugin:      func(p *Plugin) {},
+			expectedResponse: adminOnlyMessage,
+		},
+		{
+			description:      "HandleAdmin: Unknown subcommand",
+			params:           []string{"mockCommand"},
+			isSysAdmin:       true,
+			setupAPI:         func(a *plugintest.API) {},
+			setupStore:       func(s *mock_plugin.Store) {},
+			setupPlugin:      func(p *Plugin) {},
+			expectedResponse: "Unknown subcommand mockCommand",
+		},
+		{
+			description:      "HandleAdmin: Service account status",
+			params:           []string{constants.SubCommandService},
+			isSysAdmin:       true,
+			mode:             constants.ServiceAccountModeClientCredentials,
+			setupAPI:         func(a *plugintest.API) {},
+			setupStore:       func(s *mock_plugin.Store) {},
+			setupPlugin:      func(p *Plugin) {},
+			expectedResponse: "The service account is using the OAuth client credentials of the client `mockServiceNowOAuthClientID`.",
+		},
+		{
+			description:      "HandleAdmin: Service account token can't be set when the mode is not admin token",
+			params:           []string{constants.SubCommandService, constants.SubCommandSet},
+			isSysAdmin:       true,
+			mode:             constants.ServiceAccountModeDisabled,
+			setupAPI:         func(a *plugintest.API) {},
+			setupStore:       func(s *mock_plugin.Store) {},
+			setupPlugin:      func(p *Plugin) {},
+			expectedResponse: "The service account mode must be set to use an admin token in the plugin settings before storing your token.",
+		},
+		{
+			description: "HandleAdmin: Service account token is set",
+			params:      []string{constants.SubCommandService, constants.SubCommandSet},
+			isSysAdmin:  true,
+			mode:        constants.ServiceAccountModeAdminToken,
+			setupAPI: func(a *plugintest.API) {
+				a.On("LogInfo", "Service account token updated", "UserID", testutils.GetID()).Return()
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreServiceAccount", mock.AnythingOfType("*serializer.ServiceAccount")).Return(nil)
+			},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetUser", func(_ *Plugin, _ string) (*serializer.User, error) {
+					return testutils.GetSerializerUser(), nil
+				})
+			},
+			expectedResponse: "Your ServiceNow token is now used by the service account for the read-only calls.",
+		},
+		{
+			description: "HandleAdmin: Service account token is removed",
+			params:      []string{constants.SubCommandService, constants.SubCommandUnset},
+			isSysAdmin:  true,
+			mode:        constants.ServiceAccountModeAdminToken,
+			setupAPI: func(a *plugintest.API) {
+				a.On("LogInfo", "Service account token removed", "UserID", testutils.GetID()).Return()
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("DeleteServiceAccount").Return(nil)
+			},
+			setupPlugin:      func(p *Plugin) {},
+			expectedResponse: "The token of the service account has been removed.",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert := assert.New(t)
+			mockAPI := &plugintest.API{}
+			defer mockAPI.AssertExpectations(t)
+			store := mock_plugin.NewStore(t)
+			testCase.setupAPI(mockAPI)
+			testCase.setupStore(store)
+			p := &Plugin{store: store}
+			p.SetAPI(mockAPI)
+			p.setConfiguration(&configuration{
+				ServiceNowOAuthClientID: "mockServiceNowOAuthClientID",
+				ServiceAccountMode:      testCase.mode,
+			})
+			testCase.setupPlugin(p)
+
+			resp := p.handleAdmin(&plugin.Context{}, args, testCase.params, nil, testCase.isSysAdmin)
+
+			assert.EqualValues(testCase.expectedResponse, resp)
+		})
+	}
+}
+
+func TestHandleTransferSubscription(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId: "mockCallerID",
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		isSysAdmin       bool
+		setupAPI         func(*plugintest.API)
+		setupClient      func(client *mock_plugin.Client)
+		expectedResponse string
+	}{
+		{
+			description: "HandleTransferSubscription: Success",
+			params:      []string{testutils.GetServiceNowSysID(), "@mockUsername"},
+			isSysAdmin:  true,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: "mockNewOwnerID", Username: "mockUsername"}, nil)
+				a.On("HasPermissionToChannel", "mockNewOwnerID", testutils.GetID(), model.PermissionCreatePost).Return(true)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+				client.On("EditSubscription", testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(nil, 0, nil)
+			},
+			expectedResponse: fmt.Sprintf("Subscription with ID %s has been transferred to @mockUsername.", testutils.GetServiceNowSysID()),
+		},
+		{
+			description:      "HandleTransferSubscription: Invalid number of params",
+			params:           []string{testutils.GetServiceNowSysID()},
+			setupAPI:         func(a *plugintest.API) {},
+			setupClient:      func(client *mock_plugin.Client) {},
+			expectedResponse: constants.ErrorCommandInvalidNumberOfParams,
+		},
+		{
+			description:      "HandleTransferSubscription: Invalid subscription ID",
+			params:           []string{"invalidID", "@mockUsername"},
+			setupAPI:         func(a *plugintest.API) {},
+			setupClient:      func(client *mock_plugin.Client) {},
+			expectedResponse: invalidSubscriptionIDMessage,
+		},
+		{
+			description: "HandleTransferSubscription: User is not allowed to transfer the subscription",
+			params:      []string{testutils.GetServiceNowSysID(), "@mockUsername"},
+			setupAPI: func(a *plugintest.API) {
+				a.On("HasPermissionToChannel", "mockCallerID", testutils.GetID(), model.PermissionManageChannelRoles).Return(false)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			expectedResponse: "Only the owner of the subscription, the channel admins and the system admins can transfer the subscription.",
+		},
+		{
+			description: "HandleTransferSubscription: New owner is deactivated",
+			params:      []string{testutils.GetServiceNowSysID(), "@mockUsername"},
+			isSysAdmin:  true,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: "mockNewOwnerID", DeleteAt: 1}, nil)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(
+					testutils.GetSubscription(constants.SubscriptionTypeBulk), 0, nil,
+				)
+			},
+			expectedResponse: "Unable to transfer the subscription. Error: user @mockUsername can't own subscriptions",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert := assert.New(t)
+			mockAPI := &plugintest.API{}
+			defer mockAPI.AssertExpectations(t)
+			c := mock_plugin.NewClient(t)
+			testCase.setupAPI(mockAPI)
+			testCase.setupClient(c)
+			p := &Plugin{}
+			p.SetAPI(mockAPI)
+
+			resp := p.handleTransferSubscription(&plugin.Context{}, args, testCase.params, c, testCase.isSysAdmin)
+
+			assert.EqualValues(testCase.expectedResponse, resp)
+		})
+	}
+}
+
+func TestHandleMuteSubscriptions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		hasPermission    bool
+		setupClient      func(*mock_plugin.Client)
+		setupStore       func(*mock_plugin.Store)
+		expectedResponse string
+	}{
+		{
+			description:   "HandleMuteSubscriptions: Mute the channel with a summary",
+			params:        []string{"2h", constants.FlagSummary},
+			hasPermission: true,
+			setupClient:   func(_ *mock_plugin.Client) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreMute", mock.MatchedBy(func(mute *serializer.SubscriptionMute) bool {
+					return mute.ChannelID == testutils.GetChannelID() && mute.SubscriptionID == "" && mute.Summary && mute.MutedBy == testutils.GetID()
+				})).Return(nil)
+			},
+			expectedResponse: "Notifications of the subscriptions in this channel are muted for 2h. A summary of the muted notifications will be posted when the mute expires.",
+		},
+		{
+			description:   "HandleMuteSubscriptions: Mute a subscription",
+			params:        []string{"1d", testutils.GetServiceNowSysID()},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil)
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreMute", mock.MatchedBy(func(mute *serializer.SubscriptionMute) bool {
+					return mute.ChannelID == testutils.GetID() && mute.SubscriptionID == testutils.GetServiceNowSysID() && !mute.Summary
+				})).Return(nil)
+			},
+			expectedResponse: fmt.Sprintf("Notifications of the subscription with ID %s are muted for 1d.", testutils.GetServiceNowSysID()),
+		},
+		{
+			description:      "HandleMuteSubscriptions: Invalid number of params",
+			setupClient:      func(_ *mock_plugin.Client) {},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedResponse: constants.ErrorCommandInvalidNumberOfParams,
+		},
+		{
+			description:      "HandleMuteSubscriptions: Invalid duration",
+			params:           []string{"10d"},
+			setupClient:      func(_ *mock_plugin.Client) {},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedResponse: fmt.Sprintf("Unable to mute the notifications. Error: %s", constants.ErrorInvalidMuteDuration),
+		},
+		{
+			description:      "HandleMuteSubscriptions: Insufficient permissions for the channel",
+			params:           []string{"2h"},
+			setupClient:      func(_ *mock_plugin.Client) {},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedResponse: constants.ErrorInsufficientPermissions,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			p := &Plugin{store: store}
+			p.SetAPI(&plugintest.API{})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+				if testCase.hasPermission {
+					return http.StatusOK, nil
+				}
+
+				return http.StatusForbidden, fmt.Errorf(constants.ErrorInsufficientPermissions)
+			})
+
+			resp := p.handleMuteSubscriptions(&plugin.Context{}, args, testCase.params, client, false)
+			assert.Equal(t, testCase.expectedResponse, resp)
+		})
+	}
+}
+
+func TestHandleUnmuteSubscriptions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		unmuteErr        error
+		expectedResponse string
+	}{
+		{
+			description:      "HandleUnmuteSubscriptions: Success",
+			expectedResponse: "Notifications of the subscriptions in this channel are unmuted.",
+		},
+		{
+			description:      "HandleUnmuteSubscriptions: Channel is not muted",
+			unmuteErr:        ErrNotFound,
+			expectedResponse: "Notifications of the subscriptions in this channel are not muted.",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			p := &Plugin{}
+			p.SetAPI(&plugintest.API{})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+				return http.StatusOK, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "UnmuteNotifications", func(_ *Plugin, channelID, _, subscriptionID string) (*serializer.SubscriptionMute, error) {
+				assert.Equal(t, testutils.GetChannelID(), channelID)
+				assert.Empty(t, subscriptionID)
+				return &serializer.SubscriptionMute{}, testCase.unmuteErr
+			})
+
+			resp := p.handleUnmuteSubscriptions(&plugin.Context{}, args, nil, mock_plugin.NewClient(t), false)
+			assert.Equal(t, testCase.expectedResponse, resp)
+		})
+	}
+}
+
 func TestGetAutocompleteData(t *testing.T) {
 	t.Run("GetAutocompleteData", func(t *testing.T) {
 		assert := assert.New(t)
@@ -838,6 +1507,139 @@ func TestGetAutocompleteData(t *testing.T) {
 	})
 }
 
+func TestParseIncidentArgs(t *testing.T) {
+	for _, testCase := range []struct {
+		description   string
+		params        []string
+		expectedArgs  *incidentArgs
+		expectedError string
+	}{
+		{
+			description: "ParseIncidentArgs: All flags",
+			params:      []string{`"Server`, `is down"`, constants.FlagUrgency, "2", constants.FlagGroup, `"Network Team"`, constants.FlagCaller, "@mockUsername", constants.FlagDescription, `"mock description"`},
+			expectedArgs: &incidentArgs{
+				shortDescription: "Server is down",
+				description:      "mock description",
+				urgency:          "2",
+				group:            "Network Team",
+				caller:           "mockUsername",
+			},
+		},
+		{
+			description:   "ParseIncidentArgs: Missing short description",
+			params:        []string{constants.FlagUrgency, "1"},
+			expectedError: "Short description of the incident is required.",
+		},
+		{
+			description:   "ParseIncidentArgs: Invalid urgency",
+			params:        []string{"mockShortDescription", constants.FlagUrgency, "4"},
+			expectedError: "Invalid urgency 4. Available urgencies are: 1, 2, 3",
+		},
+		{
+			description:   "ParseIncidentArgs: Missing value",
+			params:        []string{"mockShortDescription", constants.FlagGroup},
+			expectedError: "Missing value for --group",
+		},
+		{
+			description:   "ParseIncidentArgs: Unknown flag",
+			params:        []string{"mockShortDescription", "--invalid", "value"},
+			expectedError: "Unknown flag --invalid",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			parsed, errMessage := parseIncidentArgs(testCase.params)
+
+			assert.Equal(t, testCase.expectedError, errMessage)
+			assert.Equal(t, testCase.expectedArgs, parsed)
+		})
+	}
+}
+
+func TestHandleCreateIncidentFromArgs(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	groupID := "abcdef0123456789abcdef0123456789"
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		setupAPI         func(*plugintest.API)
+		setupClient      func(*mock_plugin.Client)
+		expectedMessage  string
+		expectedResponse string
+	}{
+		{
+			description: "HandleCreateIncidentFromArgs: Incident is created and shared in the channel",
+			params:      []string{`"Server down"`, constants.FlagUrgency, "1", constants.FlagGroup, `"Network"`, constants.FlagCaller, "@mockUsername"},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: testutils.GetID(), Username: "mockUsername"}, nil)
+				a.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID()
+				})).Return(&model.Post{}, nil).Once()
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetGroupByNameFromServiceNow", "Network").Return(&serializer.ServiceNowGroup{SysID: groupID, Name: "Network"}, http.StatusOK, nil)
+				client.On("CreateIncident", &serializer.IncidentPayload{
+					ShortDescription: "Server down",
+					Caller:           testutils.GetServiceNowSysID(),
+					ChannelID:        testutils.GetChannelID(),
+					Urgency:          "1",
+					AssignmentGroup:  groupID,
+				}).Return(&serializer.IncidentResponse{SysID: testutils.GetServiceNowSysID()}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+		},
+		{
+			description: "HandleCreateIncidentFromArgs: Group doesn't exist",
+			params:      []string{"mockShortDescription", constants.FlagGroup, "invalid"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetGroupByNameFromServiceNow", "invalid").Return(nil, http.StatusOK, nil)
+			},
+			expectedMessage:  genericWaitMessage,
+			expectedResponse: "Group invalid doesn't exist in ServiceNow.",
+		},
+		{
+			description:     "HandleCreateIncidentFromArgs: Invalid arguments",
+			params:          []string{constants.FlagUrgency, "1"},
+			setupAPI:        func(_ *plugintest.API) {},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: "Short description of the incident is required.",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			defer mockAPI.AssertExpectations(t)
+			testCase.setupAPI(mockAPI)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			mockAPI.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true).Maybe()
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetUser", func(_ *Plugin, _ string) (*serializer.User, error) {
+				return testutils.GetSerializerUser(), nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(&serializer.ServiceNowRecord{}), "HandleNestedFields", func(_ *serializer.ServiceNowRecord, _ string) error {
+				return nil
+			})
+
+			if testCase.expectedResponse != "" {
+				mockAPI.On("SendEphemeralPost", mock.AnythingOfType("string"), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					post := args.Get(1).(*model.Post)
+					assert.Equal(t, testCase.expectedResponse, post.Message)
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleIncident(&plugin.Context{}, args, append([]string{constants.SubCommandCreate}, testCase.params...), nil, false)
+			assert.Equal(t, testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
+
 func TestParseCommand(t *testing.T) {
 	expectedCommand := "/servicenow"
 	for _, testCase := range []struct {
@@ -896,3 +1698,92 @@ func TestParseCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleAdminSubscriptions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	args := &model.CommandArgs{
+		UserId: testutils.GetID(),
+		TeamId: testutils.GetID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		clientErr        error
+		setupAPI         func(*plugintest.API)
+		expectedResponse string
+	}{
+		{
+			description:      "HandleAdminSubscriptions: Missing subcommand",
+			params:           []string{},
+			setupAPI:         func(a *plugintest.API) {},
+			expectedResponse: "Invalid subscriptions command. Available commands are 'export', 'import' and 'clone'.",
+		},
+		{
+			description:      "HandleAdminSubscriptions: User is not connected",
+			params:           []string{constants.SubCommandExport},
+			clientErr:        ErrNotFound,
+			setupAPI:         func(a *plugintest.API) {},
+			expectedResponse: "You are not connected to ServiceNow.",
+		},
+		{
+			description:      "HandleAdminSubscriptions: Invalid export argument",
+			params:           []string{constants.SubCommandExport, "xml"},
+			setupAPI:         func(a *plugintest.API) {},
+			expectedResponse: "Invalid argument xml.",
+		},
+		{
+			description: "HandleAdminSubscriptions: Export team doesn't exist",
+			params:      []string{constants.SubCommandExport, constants.FlagTeam, "mockTeam"},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetTeamByName", "mockTeam").Return(nil, testutils.GetBadRequestAppError())
+			},
+			expectedResponse: "Team mockTeam doesn't exist.",
+		},
+		{
+			description:      "HandleAdminSubscriptions: Import post is not valid",
+			params:           []string{constants.SubCommandImport, "mockPost"},
+			setupAPI:         func(a *plugintest.API) {},
+			expectedResponse: "Unable to import the subscriptions. Error: mockPost is not a valid post ID or link",
+		},
+		{
+			description: "HandleAdminSubscriptions: Import post doesn't have a file",
+			params:      []string{constants.SubCommandImport, "https://mockSiteURL/mockTeam/pl/" + testutils.GetID()},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetPost", testutils.GetID()).Return(&model.Post{Id: testutils.GetID(), ChannelId: testutils.GetChannelID()}, nil)
+				a.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionReadChannel).Return(true)
+			},
+			expectedResponse: fmt.Sprintf("Unable to import the subscriptions. Error: post https://mockSiteURL/mockTeam/pl/%s doesn't have a file attached", testutils.GetID()),
+		},
+		{
+			description:      "HandleAdminSubscriptions: Invalid channel mapping",
+			params:           []string{constants.SubCommandImport, testutils.GetID(), constants.FlagMap, "mockChannel"},
+			setupAPI:         func(a *plugintest.API) {},
+			expectedResponse: "Invalid channel mapping mockChannel. Use the format old_channel:new_channel.",
+		},
+		{
+			description: "HandleAdminSubscriptions: Clone channel doesn't exist",
+			params:      []string{constants.SubCommandClone, "~mockChannel", testutils.GetChannelID()},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetChannelByName", testutils.GetID(), "mockChannel", false).Return(nil, testutils.GetBadRequestAppError())
+			},
+			expectedResponse: "Unable to clone the subscriptions. Error: channel ~mockChannel doesn't exist",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			testCase.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				if testCase.clientErr != nil {
+					return nil, testCase.clientErr
+				}
+
+				return &mock_plugin.Client{}, nil
+			})
+
+			response := p.handleAdmin(nil, args, append([]string{constants.CommandSubscriptions}, testCase.params...), nil, true)
+			assert.Equal(t, testCase.expectedResponse, response)
+		})
+	}
+}
@@ -5,6 +5,8 @@ package plugin
 
 import (
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -25,16 +27,22 @@ import (
 // If you add non-reference types to your configuration struct, be sure to rewrite Clone as a deep
 // copy appropriate for your types.
 type configuration struct {
-	ServiceNowBaseURL           string `json:"ServiceNowBaseURL"`
-	ServiceNowOAuthClientID     string `json:"ServiceNowOAuthClientID"`
-	ServiceNowOAuthClientSecret string `json:"ServiceNowOAuthClientSecret"`
-	EncryptionSecret            string `json:"EncryptionSecret"`
-	WebhookSecret               string `json:"WebhookSecret"`
-	UpdateSetDownload           string `json:"ServiceNowUpdateSetDownload"`
-	MattermostSiteURL           string `json:"-"`
-	PluginID                    string `json:"-"`
-	PluginURL                   string `json:"-"`
-	PluginURLPath               string `json:"-"`
+	ServiceNowBaseURL           string                `json:"ServiceNowBaseURL"`
+	ServiceNowOAuthClientID     string                `json:"ServiceNowOAuthClientID"`
+	ServiceNowOAuthClientSecret string                `json:"ServiceNowOAuthClientSecret"`
+	EncryptionSecret            string                `json:"EncryptionSecret"`
+	WebhookSecret               string                `json:"WebhookSecret"`
+	UpdateSetDownload           string                `json:"ServiceNowUpdateSetDownload"`
+	ServiceAccountMode          string                `json:"ServiceAccountMode"`
+	EnableSharedLookupCache     bool                  `json:"EnableSharedLookupCache"`
+	SLAWarningThresholds        string                `json:"SLAWarningThresholds"`
+	SLAThresholds               []int                 `json:"-"`
+	AdditionalInstances         string                `json:"AdditionalInstances"`
+	Instances                   []*serviceNowInstance `json:"-"`
+	MattermostSiteURL           string                `json:"-"`
+	PluginID                    string                `json:"-"`
+	PluginURL                   string                `json:"-"`
+	PluginURLPath               string                `json:"-"`
 }
 
 // Clone shallow copies the configuration. Your implementation may require a deep copy if
@@ -51,6 +59,14 @@ func (c *configuration) ProcessConfiguration() error {
 	c.ServiceNowOAuthClientID = strings.TrimSpace(c.ServiceNowOAuthClientID)
 	c.ServiceNowOAuthClientSecret = strings.TrimSpace(c.ServiceNowOAuthClientSecret)
 	c.EncryptionSecret = strings.TrimSpace(c.EncryptionSecret)
+	c.ServiceAccountMode = strings.TrimSpace(c.ServiceAccountMode)
+	if c.ServiceAccountMode == "" {
+		c.ServiceAccountMode = constants.ServiceAccountModeDisabled
+	}
+
+	// The invalid thresholds and instances are reported by IsValid
+	c.SLAThresholds, _ = parseSLAThresholds(c.SLAWarningThresholds)
+	c.Instances, _ = parseInstances(c.AdditionalInstances)
 
 	return nil
 }
@@ -72,10 +88,39 @@ func (c *configuration) IsValid() error {
 	if c.EncryptionSecret == "" {
 		return errors.New(constants.ErrorEmptyEncryptionSecret)
 	}
+	if c.ServiceAccountMode != "" && !constants.ValidServiceAccountModes[c.ServiceAccountMode] {
+		return errors.New(constants.ErrorInvalidServiceAccountMode)
+	}
+	if _, err := parseSLAThresholds(c.SLAWarningThresholds); err != nil {
+		return err
+	}
+	if _, err := parseInstances(c.AdditionalInstances); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// parseSLAThresholds parses the comma separated percentages of the SLA warnings in ascending order.
+// No warnings are posted when the thresholds are empty.
+func parseSLAThresholds(value string) ([]int, error) {
+	thresholds := []int{}
+	for _, threshold := range strings.Split(value, ",") {
+		if threshold = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(threshold), "%")); threshold == "" {
+			continue
+		}
+
+		percentage, err := strconv.Atoi(threshold)
+		if err != nil || percentage < 1 || percentage > 100 {
+			return nil, errors.New(constants.ErrorInvalidSLAThresholds)
+		}
+		thresholds = append(thresholds, percentage)
+	}
+
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
 // getConfiguration retrieves the active configuration under lock, making it safe to use
 // concurrently. The active configuration may change underneath the client of this method, but
 // the struct returned by this API call is considered immutable.
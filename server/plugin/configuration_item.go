@@ -0,0 +1,215 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+func (p *Plugin) handleCI(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, isSysAdmin bool) string {
+	searchTerm := strings.TrimSpace(strings.Trim(strings.Join(parameters, " "), `"`))
+	if len(searchTerm) < constants.CharacterThresholdForSearchingRecords {
+		return fmt.Sprintf(constants.ErrorSearchTermThreshold, constants.CharacterThresholdForSearchingRecords)
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		items, statusCode, err := client.SearchConfigurationItemsInServiceNow(searchTerm)
+		if err != nil {
+			p.API.LogError(constants.ErrorSearchingConfigurationItems, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if len(items) == 0 {
+			p.postCommandResponse(args, fmt.Sprintf(constants.ConfigurationItemNotFoundMessage, searchTerm))
+			return
+		}
+
+		post := serializer.CreateConfigurationItemSearchPost(items, searchTerm, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+		post.UserId = p.botID
+		post.ChannelId = args.ChannelId
+		post.RootId = args.RootId
+		_ = p.API.SendEphemeralPost(args.UserId, post)
+	}()
+
+	return genericWaitMessage
+}
+
+// handleConfigItemAction shares a configuration item in the channel, lists its open incidents and changes
+// or subscribes the channel to its incidents
+func (p *Plugin) handleConfigItemAction(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	itemID, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordID].(string)
+	action, _ := postActionIntegrationRequest.Context[constants.ContextNameAction].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, itemID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	channelID := postActionIntegrationRequest.ChannelId
+	switch action {
+	case constants.ConfigurationItemActionShare:
+		if _, err := p.HasChannelPermissions(userID, channelID); err != nil {
+			response.EphemeralText = err.Error()
+			p.returnPostActionIntegrationResponse(w, response)
+			return
+		}
+	case constants.ConfigurationItemActionSubscribe:
+		if _, err := p.HasPublicOrPrivateChannelPermissions(userID, channelID); err != nil {
+			response.EphemeralText = err.Error()
+			p.returnPostActionIntegrationResponse(w, response)
+			return
+		}
+	}
+
+	client, errorMessage := p.getPostActionClient(postActionIntegrationRequest)
+	if client == nil {
+		response.EphemeralText = errorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	item, statusCode, err := client.GetRecordFromServiceNow(constants.RecordTypeConfigurationItem, itemID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetRecord, "Configuration item ID", itemID, "Error", err.Error())
+		response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	item.RecordType = constants.RecordTypeConfigurationItem
+	switch action {
+	case constants.ConfigurationItemActionShare:
+		response.EphemeralText = p.shareConfigurationItem(client, item, userID, channelID)
+	case constants.ConfigurationItemActionRecords:
+		response.EphemeralText = p.getConfigurationItemRecords(client, item, userID)
+	case constants.ConfigurationItemActionSubscribe:
+		response.EphemeralText = p.subscribeToConfigurationItemIncidents(client, item, userID, channelID)
+	default:
+		response.EphemeralText = genericErrorMessage
+	}
+
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+func (p *Plugin) shareConfigurationItem(client Client, item *serializer.ServiceNowRecord, userID, channelID string) string {
+	user, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		p.API.LogError(constants.ErrorGetUser, "UserID", userID, "Error", appErr.Error())
+		return genericErrorMessage
+	}
+
+	serviceNowURL := p.getServiceNowURL(client)
+	if err := item.HandleNestedFields(serviceNowURL); err != nil {
+		p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	item.InstanceID = getInstanceID(client)
+	post := item.CreateSharingPost(channelID, p.botID, serviceNowURL, p.GetPluginURL(), user.Username)
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+		return genericErrorMessage
+	}
+
+	return constants.ConfigurationItemSharedMessage
+}
+
+func (p *Plugin) getConfigurationItemRecords(client Client, item *serializer.ServiceNowRecord, userID string) string {
+	incidents, statusCode, err := client.GetOpenRecordsOfConfigurationItemFromServiceNow(constants.RecordTypeIncident, item.SysID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetConfigurationItemRecords, "Configuration item ID", item.SysID, "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	changes, statusCode, err := client.GetOpenRecordsOfConfigurationItemFromServiceNow(constants.RecordTypeChangeRequest, item.SysID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetConfigurationItemRecords, "Configuration item ID", item.SysID, "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	return serializer.GetFormattedConfigurationItemRecords(item.Name, incidents, changes, p.getServiceNowURL(client))
+}
+
+// subscribeToConfigurationItemIncidents creates a bulk subscription for the incidents in the channel which is filtered by the plugin
+// to the incidents of the configuration item
+func (p *Plugin) subscribeToConfigurationItemIncidents(client Client, item *serializer.ServiceNowRecord, userID, channelID string) string {
+	subscriptionType := constants.SubscriptionTypeBulk
+	recordType := constants.RecordTypeIncident
+	recordID := ""
+	isActive := true
+	events := strings.Join(getSortedKeys(constants.ValidSubscriptionEvents), ",")
+	serverURL := p.getConfiguration().MattermostSiteURL
+	subscription := &serializer.SubscriptionPayload{
+		ChannelID:             &channelID,
+		UserID:                &userID,
+		Type:                  &subscriptionType,
+		RecordType:            &recordType,
+		RecordID:              &recordID,
+		IsActive:              &isActive,
+		SubscriptionEvents:    &events,
+		ServerURL:             &serverURL,
+		ConfigurationItemID:   &item.SysID,
+		ConfigurationItemName: &item.Name,
+		InstanceID:            getInstanceID(client),
+	}
+	if err := subscription.IsValidForCreation(serverURL); err != nil {
+		p.API.LogError(constants.ErrorValidatingRequestBody, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	exists, statusCode, err := client.CheckForDuplicateSubscription(subscription)
+	if err != nil {
+		p.API.LogError("Error in checking for duplicate subscription", "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	if exists {
+		return constants.ConfigurationItemDuplicateMessage
+	}
+
+	settings := subscription.TakeSettings()
+	resp, statusCode, err := client.CreateSubscription(subscription)
+	if err != nil {
+		p.API.LogError("Error in creating subscription", "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	if err = p.store.StoreSubscriptionSettings(subscription.InstanceID, resp.SysID, settings); err != nil {
+		p.API.LogError(constants.ErrorStoreSubscriptionSettings, "SubscriptionID", resp.SysID, "Error", err.Error())
+	}
+	resp.SetSettings(settings)
+
+	post := resp.CreateSubscriptionCreatedPost(p.botID, p.getServiceNowURL(client))
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+	}
+
+	return ""
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleCI(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		setupClient      func(*mock_plugin.Client)
+		expectedMessage  string
+		expectedResponse func(*model.Post) bool
+	}{
+		{
+			description: "HandleCI: configuration items are listed with their details",
+			params:      []string{"web", "server"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchConfigurationItemsInServiceNow", "web server").Return([]*serializer.ServiceNowConfigurationItem{
+					{SysID: testutils.GetServiceNowSysID(), Name: "web server 01", ClassName: "Linux Server", SupportGroup: "Hardware"},
+				}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedResponse: func(post *model.Post) bool {
+				attachments := post.Attachments()
+				return len(attachments) == 1 && attachments[0].Title == "web server 01" && attachments[0].Fields[0].Value == "Linux Server" && len(attachments[0].Actions) == 3
+			},
+		},
+		{
+			description: "HandleCI: no configuration items found",
+			params:      []string{"unknown"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchConfigurationItemsInServiceNow", "unknown").Return([]*serializer.ServiceNowConfigurationItem{}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedResponse: func(post *model.Post) bool {
+				return post.Message == fmt.Sprintf(constants.ConfigurationItemNotFoundMessage, "unknown")
+			},
+		},
+		{
+			description:     "HandleCI: search term is too short",
+			params:          []string{"ab"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: fmt.Sprintf(constants.ErrorSearchTermThreshold, constants.CharacterThresholdForSearchingRecords),
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			defer mockAPI.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			if testCase.expectedResponse != nil {
+				mockAPI.On("SendEphemeralPost", testutils.GetID(), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					assert.True(t, testCase.expectedResponse(args.Get(1).(*model.Post)))
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleCI(&plugin.Context{}, args, testCase.params, nil, false)
+			assert.Equal(t, testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
+
+func TestHandleConfigItemAction(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathConfigItemAction)
+	item := &serializer.ServiceNowRecord{
+		SysID:     testutils.GetServiceNowSysID(),
+		Name:      "web server 01",
+		ClassName: "Linux Server",
+	}
+	for name, test := range map[string]struct {
+		Action               string
+		ItemID               string
+		SetupAPI             func(*plugintest.API)
+		SetupStore           func(*mock_plugin.Store)
+		SetupClient          func(*mock_plugin.Client)
+		ExpectedEphemeralMsg func(string) bool
+	}{
+		"configuration item is shared in the channel": {
+			Action: constants.ConfigurationItemActionShare,
+			ItemID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("GetUser", testutils.GetID()).Return(&model.User{Username: "mockUser"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					attachments := post.Attachments()
+					return post.ChannelId == testutils.GetChannelID() && len(attachments) == 1 && strings.Contains(attachments[0].Title, "[web server 01]") && len(attachments[0].Actions) == 2
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeConfigurationItem, testutils.GetServiceNowSysID()).Return(item, http.StatusOK, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == constants.ConfigurationItemSharedMessage
+			},
+		},
+		"open incidents and changes are listed": {
+			Action:     constants.ConfigurationItemActionRecords,
+			ItemID:     testutils.GetServiceNowSysID(),
+			SetupAPI:   func(_ *plugintest.API) {},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeConfigurationItem, testutils.GetServiceNowSysID()).Return(item, http.StatusOK, nil)
+				client.On("GetOpenRecordsOfConfigurationItemFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return([]*serializer.ServiceNowRecord{
+					{SysID: testutils.GetServiceNowSysID(), Number: "INC0010001", ShortDescription: "Disk full", State: "New", Priority: "1 - Critical"},
+				}, http.StatusOK, nil)
+				client.On("GetOpenRecordsOfConfigurationItemFromServiceNow", constants.RecordTypeChangeRequest, testutils.GetServiceNowSysID()).Return([]*serializer.ServiceNowRecord{}, http.StatusOK, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return strings.Contains(message, "|Disk full|New|1 - Critical|") && strings.HasSuffix(message, "#### Open changes of web server 01\nNone")
+			},
+		},
+		"channel is subscribed to the incidents of the configuration item": {
+			Action: constants.ConfigurationItemActionSubscribe,
+			ItemID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetChannel", testutils.GetChannelID()).Return(&model.Channel{Type: model.ChannelTypeOpen}, nil)
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					fields := post.Attachments()[0].Fields
+					return len(fields) == 3 && fields[2].Title == "Configuration item"
+				})).Return(&model.Post{}, nil)
+			},
+			SetupStore: func(store *mock_plugin.Store) {
+				store.On("StoreSubscriptionSettings", constants.DefaultInstanceID, "mockSubscriptionID", &serializer.SubscriptionSettings{
+					Mentions:              constants.MentionSettingOff,
+					ConfigurationItemID:   testutils.GetServiceNowSysID(),
+					ConfigurationItemName: "web server 01",
+				}).Return(nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeConfigurationItem, testutils.GetServiceNowSysID()).Return(item, http.StatusOK, nil)
+				client.On("CheckForDuplicateSubscription", mock.MatchedBy(func(subscription *serializer.SubscriptionPayload) bool {
+					return *subscription.RecordID == testutils.GetServiceNowSysID()
+				})).Return(false, http.StatusOK, nil)
+				client.On("CreateSubscription", mock.MatchedBy(func(subscription *serializer.SubscriptionPayload) bool {
+					return *subscription.Type == constants.SubscriptionTypeBulk && *subscription.RecordType == constants.RecordTypeIncident && subscription.ConfigurationItemID == nil
+				})).Return(&serializer.SubscriptionResponse{SysID: "mockSubscriptionID", ChannelID: testutils.GetChannelID()}, http.StatusCreated, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == ""
+			},
+		},
+		"subscription for the incidents of the configuration item already exists in the channel": {
+			Action: constants.ConfigurationItemActionSubscribe,
+			ItemID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("GetChannel", testutils.GetChannelID()).Return(&model.Channel{Type: model.ChannelTypeOpen}, nil)
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeConfigurationItem, testutils.GetServiceNowSysID()).Return(item, http.StatusOK, nil)
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(true, http.StatusOK, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == constants.ConfigurationItemDuplicateMessage
+			},
+		},
+		"failed to get the configuration item": {
+			Action: constants.ConfigurationItemActionRecords,
+			ItemID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeConfigurationItem, testutils.GetServiceNowSysID()).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == genericErrorMessage
+			},
+		},
+		"invalid configuration item ID": {
+			Action:      constants.ConfigurationItemActionRecords,
+			ItemID:      "invalid",
+			SetupAPI:    func(_ *plugintest.API) {},
+			SetupStore:  func(_ *mock_plugin.Store) {},
+			SetupClient: func(_ *mock_plugin.Client) {},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == genericErrorMessage
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{ServiceNowBaseURL: "https://example.service-now.com", MattermostSiteURL: "https://example.mattermost.com"})
+			client := mock_plugin.NewClient(t)
+			test.SetupAPI(api)
+			test.SetupStore(store)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(&serializer.ServiceNowRecord{}), "HandleNestedFields", func(_ *serializer.ServiceNowRecord, _ string) error {
+				return nil
+			})
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId:    testutils.GetID(),
+				ChannelId: testutils.GetChannelID(),
+				Context: map[string]interface{}{
+					constants.ContextNameRecordID: test.ItemID,
+					constants.ContextNameAction:   test.Action,
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.True(t, test.ExpectedEphemeralMsg(response.EphemeralText), response.EphemeralText)
+		})
+	}
+}
@@ -83,6 +83,42 @@ func TestIsValid(t *testing.T) {
 			},
 			errMsg: constants.ErrorEmptyWebhookSecret,
 		},
+		{
+			description: "invalid configuration: ServiceAccountMode invalid",
+			config: &configuration{
+				ServiceNowBaseURL:           "mockServiceNowBaseURL",
+				ServiceNowOAuthClientID:     "mockServiceNowOAuthClientID",
+				ServiceNowOAuthClientSecret: "mockServiceNowOAuthClientSecret",
+				EncryptionSecret:            "mockEncryptionSecret",
+				WebhookSecret:               "mockWebhookSecret",
+				ServiceAccountMode:          "mockServiceAccountMode",
+			},
+			errMsg: constants.ErrorInvalidServiceAccountMode,
+		},
+		{
+			description: "invalid configuration: SLAWarningThresholds not percentages",
+			config: &configuration{
+				ServiceNowBaseURL:           "mockServiceNowBaseURL",
+				ServiceNowOAuthClientID:     "mockServiceNowOAuthClientID",
+				ServiceNowOAuthClientSecret: "mockServiceNowOAuthClientSecret",
+				EncryptionSecret:            "mockEncryptionSecret",
+				WebhookSecret:               "mockWebhookSecret",
+				SLAWarningThresholds:        "75,120",
+			},
+			errMsg: constants.ErrorInvalidSLAThresholds,
+		},
+		{
+			description: "invalid configuration: AdditionalInstances not a JSON array",
+			config: &configuration{
+				ServiceNowBaseURL:           "mockServiceNowBaseURL",
+				ServiceNowOAuthClientID:     "mockServiceNowOAuthClientID",
+				ServiceNowOAuthClientSecret: "mockServiceNowOAuthClientSecret",
+				EncryptionSecret:            "mockEncryptionSecret",
+				WebhookSecret:               "mockWebhookSecret",
+				AdditionalInstances:         "mockAdditionalInstances",
+			},
+			errMsg: constants.ErrorInvalidInstances,
+		},
 	} {
 		t.Run(testCase.description, func(t *testing.T) {
 			err := testCase.config.IsValid()
@@ -95,3 +131,39 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSLAThresholds(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		value       string
+		expected    []int
+		errMsg      string
+	}{
+		{
+			description: "thresholds are sorted",
+			value:       "90, 75%",
+			expected:    []int{75, 90},
+		},
+		{
+			description: "no thresholds",
+			value:       "",
+			expected:    []int{},
+		},
+		{
+			description: "invalid threshold",
+			value:       "75,high",
+			errMsg:      constants.ErrorInvalidSLAThresholds,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			thresholds, err := parseSLAThresholds(testCase.value)
+			if testCase.errMsg != "" {
+				require.EqualError(t, err, testCase.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, thresholds)
+		})
+	}
+}
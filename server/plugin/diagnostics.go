@@ -0,0 +1,327 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+const (
+	diagnosticsTimeout = 10 * time.Second
+
+	diagnosticCheckBaseURL          = "Base URL"
+	diagnosticCheckOAuthEndpoints   = "OAuth endpoints"
+	diagnosticCheckOAuthClient      = "OAuth client ID"
+	diagnosticCheckUpdateSetTables  = "Update set tables"
+	diagnosticCheckUpdateSetVersion = "Update set version"
+	diagnosticCheckWebhookRouting   = "Webhook routing"
+	diagnosticCheckSiteURL          = "Site URL"
+
+	diagnosticBaseURLNotReachableMessage = "Skipped as the base URL is not reachable."
+	diagnosticNotConnectedMessage        = "Skipped as you are not connected to this ServiceNow instance."
+	diagnosticTablesNotReadMessage       = "Skipped as the tables of the update set couldn't be read."
+	diagnosticUploadUpdateSetMessage     = "Upload and commit the update set shipped with the plugin."
+
+	// A code which is never issued by ServiceNow, so the token endpoint always rejects it
+	diagnosticAuthorizationCode = "mattermost_diagnostics"
+)
+
+// getDiagnosticsHTTPClient returns the HTTP client used for the checks which are made without the token of the admin
+func getDiagnosticsHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: diagnosticsTimeout,
+		// ServiceNow redirects the unauthenticated requests to its login page
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// RunDiagnostics checks the configuration of the plugin for the given instance.
+// The checks which call the APIs of ServiceNow are only run if the client of the admin is given.
+func (p *Plugin) RunDiagnostics(instance *serviceNowInstance, client Client) *serializer.DiagnosticReport {
+	report := &serializer.DiagnosticReport{
+		Instance: instance.ID,
+		BaseURL:  instance.BaseURL,
+	}
+	httpClient := getDiagnosticsHTTPClient()
+
+	baseURLCheck := checkBaseURL(httpClient, instance.BaseURL)
+	report.AddCheck(baseURLCheck)
+	if baseURLCheck.Passed {
+		oauthConfig := p.newInstanceOAuth2Config(instance)
+		report.AddCheck(checkOAuthEndpoints(httpClient, oauthConfig))
+		report.AddCheck(checkOAuthClient(httpClient, oauthConfig))
+	} else {
+		report.AddCheck(getFailedCheck(diagnosticCheckOAuthEndpoints, diagnosticBaseURLNotReachableMessage))
+		report.AddCheck(getFailedCheck(diagnosticCheckOAuthClient, diagnosticBaseURLNotReachableMessage))
+	}
+
+	var authDetails []*serializer.SubscriptionAuthPayload
+	var authErr error
+	if client == nil {
+		report.AddCheck(getFailedCheck(diagnosticCheckUpdateSetTables, diagnosticNotConnectedMessage))
+		report.AddCheck(getFailedCheck(diagnosticCheckUpdateSetVersion, diagnosticNotConnectedMessage))
+	} else {
+		authDetails, _, authErr = client.GetSubscriptionAuthDetails()
+		report.AddCheck(getUpdateSetTablesCheck(authErr))
+		report.AddCheck(checkUpdateSetVersion(client))
+	}
+
+	report.AddCheck(p.checkWebhookRouting(httpClient, instance))
+
+	switch {
+	case client == nil:
+		report.AddCheck(getFailedCheck(diagnosticCheckSiteURL, diagnosticNotConnectedMessage))
+	case authErr != nil:
+		report.AddCheck(getFailedCheck(diagnosticCheckSiteURL, diagnosticTablesNotReadMessage))
+	default:
+		report.AddCheck(p.getSiteURLCheck(instance, authDetails))
+	}
+
+	return report
+}
+
+// checkBaseURL checks that ServiceNow is reachable over HTTPS with a valid certificate
+func checkBaseURL(httpClient *http.Client, baseURL string) *serializer.DiagnosticCheck {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return getFailedCheck(diagnosticCheckBaseURL, fmt.Sprintf("%s is not a valid URL.", baseURL))
+	}
+
+	if u.Scheme != "https" {
+		return getFailedCheck(diagnosticCheckBaseURL, "The base URL must use HTTPS.")
+	}
+
+	resp, err := httpClient.Get(baseURL)
+	if err != nil {
+		if isTLSError(err) {
+			return getFailedCheck(diagnosticCheckBaseURL, fmt.Sprintf("The TLS certificate of ServiceNow couldn't be verified. Error: %s", getRequestError(err)))
+		}
+
+		return getFailedCheck(diagnosticCheckBaseURL, fmt.Sprintf("ServiceNow is not reachable. Error: %s", getRequestError(err)))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return getFailedCheck(diagnosticCheckBaseURL, fmt.Sprintf("ServiceNow responded with the status %d.", resp.StatusCode))
+	}
+
+	return getPassedCheck(diagnosticCheckBaseURL, "ServiceNow is reachable over HTTPS with a valid TLS certificate.")
+}
+
+// checkOAuthEndpoints checks that the authorization and token endpoints exist in ServiceNow
+func checkOAuthEndpoints(httpClient *http.Client, oauthConfig *oauth2.Config) *serializer.DiagnosticCheck {
+	for _, endpoint := range []string{oauthConfig.Endpoint.AuthURL, oauthConfig.Endpoint.TokenURL} {
+		resp, err := httpClient.Get(endpoint)
+		if err != nil {
+			return getFailedCheck(diagnosticCheckOAuthEndpoints, fmt.Sprintf("%s is not reachable. Error: %s", endpoint, getRequestError(err)))
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode >= http.StatusInternalServerError {
+			return getFailedCheck(diagnosticCheckOAuthEndpoints, fmt.Sprintf("%s responded with the status %d.", endpoint, resp.StatusCode))
+		}
+	}
+
+	return getPassedCheck(diagnosticCheckOAuthEndpoints, "The authorization and token endpoints exist.")
+}
+
+// checkOAuthClient checks that the OAuth client is valid by exchanging an invalid code for a token.
+// ServiceNow only complains about the code if it has accepted the client ID and secret.
+func checkOAuthClient(httpClient *http.Client, oauthConfig *oauth2.Config) *serializer.DiagnosticCheck {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {diagnosticAuthorizationCode},
+		"redirect_uri":  {oauthConfig.RedirectURL},
+		"client_id":     {oauthConfig.ClientID},
+		"client_secret": {oauthConfig.ClientSecret},
+	}
+
+	resp, err := httpClient.PostForm(oauthConfig.Endpoint.TokenURL, form)
+	if err != nil {
+		return getFailedCheck(diagnosticCheckOAuthClient, fmt.Sprintf("The token endpoint is not reachable. Error: %s", getRequestError(err)))
+	}
+	defer resp.Body.Close()
+
+	oauthErr := &serializer.OAuthErrorResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(oauthErr); err != nil || oauthErr.Error == "" {
+		return getFailedCheck(diagnosticCheckOAuthClient, fmt.Sprintf("The token endpoint responded with an unexpected response with the status %d.", resp.StatusCode))
+	}
+
+	if oauthErr.Error == "invalid_client" || oauthErr.ErrorDescription == "access_denied" {
+		return getFailedCheck(diagnosticCheckOAuthClient, "ServiceNow rejected the OAuth client ID or secret.")
+	}
+
+	return getPassedCheck(diagnosticCheckOAuthClient, "ServiceNow accepted the OAuth client ID and secret.")
+}
+
+// getUpdateSetTablesCheck checks the error returned while reading the auth table of the update set
+func getUpdateSetTablesCheck(err error) *serializer.DiagnosticCheck {
+	if err == nil {
+		return getPassedCheck(diagnosticCheckUpdateSetTables, "The tables of the update set exist.")
+	}
+
+	if err.Error() == constants.APIErrorIDSubscriptionsNotConfigured {
+		return getFailedCheck(diagnosticCheckUpdateSetTables, fmt.Sprintf("The tables of the update set don't exist. %s", diagnosticUploadUpdateSetMessage))
+	}
+
+	return getFailedCheck(diagnosticCheckUpdateSetTables, fmt.Sprintf("Unable to read the tables of the update set. Error: %s", err.Error()))
+}
+
+// checkUpdateSetVersion checks that the update set installed in ServiceNow is the one shipped with the plugin
+func checkUpdateSetVersion(client Client) *serializer.DiagnosticCheck {
+	version, _, err := client.GetNotificationsAppVersion()
+	if err != nil {
+		return getFailedCheck(diagnosticCheckUpdateSetVersion, fmt.Sprintf("Unable to get the version of the update set. Error: %s", err.Error()))
+	}
+
+	if version == "" {
+		return getFailedCheck(diagnosticCheckUpdateSetVersion, fmt.Sprintf("The update set is not installed. %s", diagnosticUploadUpdateSetMessage))
+	}
+
+	if version != constants.ServiceNowForMattermostNotificationsAppVersion {
+		return getFailedCheck(diagnosticCheckUpdateSetVersion, fmt.Sprintf("Version %s of the update set is installed but the plugin ships version %s. %s", version, constants.ServiceNowForMattermostNotificationsAppVersion, diagnosticUploadUpdateSetMessage))
+	}
+
+	return getPassedCheck(diagnosticCheckUpdateSetVersion, fmt.Sprintf("Version %s of the update set is installed.", version))
+}
+
+// checkWebhookRouting checks that the Site URL is routed to the plugin and that the webhook secret of the instance is accepted.
+// The notification is sent by the Mattermost server itself, so the delivery from ServiceNow is not covered by this check.
+func (p *Plugin) checkWebhookRouting(httpClient *http.Client, instance *serviceNowInstance) *serializer.DiagnosticCheck {
+	if p.GetSiteURL() == "" {
+		return getFailedCheck(diagnosticCheckWebhookRouting, "The Site URL of Mattermost is not set.")
+	}
+
+	event, err := json.Marshal(&serializer.ServiceNowEvent{EventOccurred: constants.SubscriptionEventDiagnostic})
+	if err != nil {
+		return getFailedCheck(diagnosticCheckWebhookRouting, fmt.Sprintf("Unable to create the notification. Error: %s", err.Error()))
+	}
+
+	notificationURL := fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathProcessNotification)
+	resp, err := httpClient.Post(fmt.Sprintf("%s?secret=%s", notificationURL, url.QueryEscape(instance.WebhookSecret)), "application/json", bytes.NewReader(event))
+	if err != nil {
+		// The URL is removed from the error as it contains the webhook secret
+		return getFailedCheck(diagnosticCheckWebhookRouting, fmt.Sprintf("%s is not reachable from Mattermost. Error: %s", notificationURL, getRequestError(err)))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return getFailedCheck(diagnosticCheckWebhookRouting, fmt.Sprintf("%s responded with the status %d.", notificationURL, resp.StatusCode))
+	}
+
+	return getPassedCheck(diagnosticCheckWebhookRouting, fmt.Sprintf("A notification sent by Mattermost to %s reached the plugin. The delivery of the notifications from ServiceNow is not checked.", notificationURL))
+}
+
+// getSiteURLCheck checks that the Site URL is registered in ServiceNow with the webhook secret of the instance
+func (p *Plugin) getSiteURLCheck(instance *serviceNowInstance, authDetails []*serializer.SubscriptionAuthPayload) *serializer.DiagnosticCheck {
+	siteURL := strings.TrimRight(p.GetSiteURL(), "/")
+	if len(authDetails) == 0 {
+		return getFailedCheck(diagnosticCheckSiteURL, "No Mattermost server is registered in ServiceNow. It is registered when the subscriptions are managed for the first time.")
+	}
+
+	serverURLs := make([]string, 0, len(authDetails))
+	for _, authDetail := range authDetails {
+		serverURL := strings.TrimRight(authDetail.ServerURL, "/")
+		if serverURL != siteURL {
+			serverURLs = append(serverURLs, serverURL)
+			continue
+		}
+
+		if authDetail.APISecret != instance.WebhookSecret {
+			return getFailedCheck(diagnosticCheckSiteURL, fmt.Sprintf("%s is registered in ServiceNow with a different webhook secret.", siteURL))
+		}
+
+		return getPassedCheck(diagnosticCheckSiteURL, fmt.Sprintf("%s is registered in ServiceNow.", siteURL))
+	}
+
+	return getFailedCheck(diagnosticCheckSiteURL, fmt.Sprintf("%s doesn't match the server URLs registered in ServiceNow: %s.", siteURL, strings.Join(serverURLs, ", ")))
+}
+
+// handleDiagnose runs the diagnostics for the default instance or for the instance given as the parameter
+func (p *Plugin) handleDiagnose(args *model.CommandArgs, parameters []string) string {
+	instanceID := constants.DefaultInstanceID
+	if len(parameters) > 0 {
+		instanceID = parameters[0]
+	}
+
+	instance, err := p.getConfiguration().GetInstance(instanceID)
+	if err != nil {
+		return fmt.Sprintf(constants.UnknownInstanceMessage, instanceID)
+	}
+
+	return p.RunDiagnostics(instance, p.getDiagnosticsClient(args.UserId, instance.ID)).Format()
+}
+
+// getDiagnostics returns the diagnostics of the instance passed in the query params, or of the default instance
+func (p *Plugin) getDiagnostics(w http.ResponseWriter, r *http.Request) {
+	instance, err := p.getConfiguration().GetInstance(r.URL.Query().Get(constants.QueryParamInstance))
+	if err != nil {
+		p.handleAPIError(w, &serializer.APIErrorResponse{StatusCode: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	userID := r.Header.Get(constants.HeaderMattermostUserID)
+	p.writeJSON(w, 0, p.RunDiagnostics(instance, p.getDiagnosticsClient(userID, instance.ID)))
+}
+
+// getDiagnosticsClient returns the client of the admin for the instance, or nil if the admin is not connected to it
+func (p *Plugin) getDiagnosticsClient(mattermostUserID, instanceID string) Client {
+	client, err := p.GetInstanceClient(mattermostUserID, instanceID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			p.API.LogError("Unable to get the client for running the diagnostics", "Instance", instanceID, "Error", err.Error())
+		}
+		return nil
+	}
+
+	return client
+}
+
+// isTLSError checks if the request failed because the TLS connection with the server couldn't be established
+func isTLSError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	return errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr)
+}
+
+// getRequestError returns the error of a failed request without its URL
+func getRequestError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+func getPassedCheck(name, message string) *serializer.DiagnosticCheck {
+	return &serializer.DiagnosticCheck{
+		Name:    name,
+		Passed:  true,
+		Message: message,
+	}
+}
+
+func getFailedCheck(name, message string) *serializer.DiagnosticCheck {
+	return &serializer.DiagnosticCheck{
+		Name:    name,
+		Message: message,
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+// getMockDiagnosticsServer returns a server acting as both ServiceNow and Mattermost for the diagnostics
+func getMockDiagnosticsServer(webhookSecret string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/oauth_auth.do", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login.do", http.StatusFound)
+	})
+	mux.HandleFunc("/oauth_token.do", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+		if r.FormValue("client_id") != "mockClientID" {
+			_, _ = w.Write([]byte(`{"error_description": "access_denied", "error": "server_error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"error_description": "Invalid authorization code", "error": "invalid_grant"}`))
+	})
+	mux.HandleFunc("/plugins/"+manifest.Id+constants.PathPrefix+constants.PathProcessNotification, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("secret") != webhookSecret {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return httptest.NewTLSServer(mux)
+}
+
+func TestRunDiagnostics(t *testing.T) {
+	server := getMockDiagnosticsServer("mockWebhookSecret")
+	defer server.Close()
+
+	for _, testCase := range []struct {
+		description          string
+		clientID             string
+		webhookSecret        string
+		untrustedCertificate bool
+		setupClient          func(*mock_plugin.Client)
+		expectedFailedChecks []string
+	}{
+		{
+			description: "all the checks pass",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: server.URL + "/", APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return(constants.ServiceNowForMattermostNotificationsAppVersion, http.StatusOK, nil)
+			},
+		},
+		{
+			description: "OAuth client is rejected",
+			clientID:    "mockInvalidClientID",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: server.URL, APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return(constants.ServiceNowForMattermostNotificationsAppVersion, http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckOAuthClient},
+		},
+		{
+			description:          "admin is not connected",
+			expectedFailedChecks: []string{diagnosticCheckUpdateSetTables, diagnosticCheckUpdateSetVersion, diagnosticCheckSiteURL},
+		},
+		{
+			description: "update set is not uploaded",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return(nil, http.StatusBadRequest, errors.New(constants.APIErrorIDSubscriptionsNotConfigured))
+				client.On("GetNotificationsAppVersion").Return("", http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckUpdateSetTables, diagnosticCheckUpdateSetVersion, diagnosticCheckSiteURL},
+		},
+		{
+			description: "update set is outdated",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: server.URL, APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return("0.9.0", http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckUpdateSetVersion},
+		},
+		{
+			description: "Site URL is not registered",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: "https://old.mattermost.com", APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return(constants.ServiceNowForMattermostNotificationsAppVersion, http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckSiteURL},
+		},
+		{
+			description:   "webhook secret doesn't match",
+			webhookSecret: "mockOtherWebhookSecret",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: server.URL, APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return(constants.ServiceNowForMattermostNotificationsAppVersion, http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckWebhookRouting, diagnosticCheckSiteURL},
+		},
+		{
+			description:          "TLS certificate is not trusted",
+			untrustedCertificate: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscriptionAuthDetails").Return([]*serializer.SubscriptionAuthPayload{{ServerURL: server.URL, APISecret: "mockWebhookSecret"}}, http.StatusOK, nil)
+				client.On("GetNotificationsAppVersion").Return(constants.ServiceNowForMattermostNotificationsAppVersion, http.StatusOK, nil)
+			},
+			expectedFailedChecks: []string{diagnosticCheckBaseURL, diagnosticCheckOAuthEndpoints, diagnosticCheckOAuthClient, diagnosticCheckWebhookRouting},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			if !testCase.untrustedCertificate {
+				monkey.Patch(getDiagnosticsHTTPClient, func() *http.Client {
+					httpClient := server.Client()
+					httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+						return http.ErrUseLastResponse
+					}
+					return httpClient
+				})
+			}
+
+			p, _ := setupTestPlugin(&plugintest.API{}, nil)
+			instance := &serviceNowInstance{
+				ID:                constants.DefaultInstanceID,
+				BaseURL:           server.URL,
+				OAuthClientID:     "mockClientID",
+				OAuthClientSecret: "mockClientSecret",
+				WebhookSecret:     "mockWebhookSecret",
+			}
+			if testCase.clientID != "" {
+				instance.OAuthClientID = testCase.clientID
+			}
+			if testCase.webhookSecret != "" {
+				instance.WebhookSecret = testCase.webhookSecret
+			}
+			p.setConfiguration(&configuration{MattermostSiteURL: server.URL})
+
+			var client Client
+			if testCase.setupClient != nil {
+				mockClient := mock_plugin.NewClient(t)
+				testCase.setupClient(mockClient)
+				client = mockClient
+			}
+
+			report := p.RunDiagnostics(instance, client)
+			require.Len(t, report.Checks, 7)
+			assert.Equal(t, len(testCase.expectedFailedChecks) == 0, report.Passed)
+			if testCase.untrustedCertificate {
+				assert.Contains(t, report.Checks[0].Message, "The TLS certificate of ServiceNow couldn't be verified.")
+			}
+			for _, check := range report.Checks {
+				assert.Equal(t, !slices.Contains(testCase.expectedFailedChecks, check.Name), check.Passed, fmt.Sprintf("%s: %s", check.Name, check.Message))
+				assert.NotContains(t, check.Message, instance.WebhookSecret)
+			}
+		})
+	}
+}
+
+func TestHandleDiagnose(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId: testutils.GetID(),
+	}
+	for _, testCase := range []struct {
+		description     string
+		parameters      []string
+		setupStore      func(*mock_plugin.Store)
+		expectedMessage func(string) bool
+	}{
+		{
+			description: "instance doesn't exist",
+			parameters:  []string{"finance"},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			expectedMessage: func(message string) bool {
+				return message == fmt.Sprintf(constants.UnknownInstanceMessage, "finance")
+			},
+		},
+		{
+			description: "diagnostics of the default instance",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadUser", testutils.GetID()).Return(nil, ErrNotFound)
+			},
+			expectedMessage: func(message string) bool {
+				return strings.HasPrefix(message, "#### Diagnostics of the ServiceNow instance `default`\nhttp://prod.service-now.com Some checks failed.") &&
+					strings.Contains(message, "\n|Base URL|:x: Fail|The base URL must use HTTPS.|") &&
+					strings.Contains(message, fmt.Sprintf("\n|Site URL|:x: Fail|%s|", diagnosticNotConnectedMessage))
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, _ := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{ServiceNowBaseURL: "http://prod.service-now.com"})
+
+			message := p.handleDiagnose(args, testCase.parameters)
+			assert.True(t, testCase.expectedMessage(message), message)
+		})
+	}
+}
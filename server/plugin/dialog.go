@@ -0,0 +1,409 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// OpenCommentDialog opens the interactive dialog for adding a comment on a record. The existing comments are shown in the dialog.
+func (p *Plugin) OpenCommentDialog(client Client, triggerID, recordType, recordID string) (int, error) {
+	introduction := ""
+	response, _, err := client.GetAllComments(recordType, recordID)
+	if err != nil {
+		p.API.LogWarn(constants.ErrorGetComments, "Record ID", recordID, "Error", err.Error())
+	} else if comments := strings.TrimSpace(response.CommentsAndWorkNotes); comments != "" {
+		if runes := []rune(comments); len(runes) > constants.MaxCommentsLengthInDialog {
+			comments = string(runes[:constants.MaxCommentsLengthInDialog]) + "..."
+		}
+		introduction = fmt.Sprintf("**Comments**\n```\n%s\n```", comments)
+	}
+
+	return http.StatusOK, p.openDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       p.getInstanceDialogURL(client, constants.PathCommentDialog),
+		Dialog: model.Dialog{
+			CallbackId:       recordID,
+			Title:            "Add a comment",
+			IntroductionText: introduction,
+			SubmitLabel:      "Add",
+			State:            recordType,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Comment",
+					Name:        constants.DialogElementComments,
+					Type:        "textarea",
+				},
+			},
+		},
+	})
+}
+
+// OpenStateDialog opens the interactive dialog for updating the state of a record, with the states of its record type as the options
+func (p *Plugin) OpenStateDialog(client Client, triggerID, recordType, recordID string) (int, error) {
+	statesRecordType := recordType
+	if statesRecordType == constants.RecordTypeFollowOnTask {
+		statesRecordType = constants.RecordTypeTask
+	}
+
+	states, statusCode, err := client.GetStatesFromServiceNow(statesRecordType)
+	if err != nil {
+		return statusCode, errors.Wrap(err, constants.ErrorGetStates)
+	}
+
+	options := make([]*model.PostActionOptions, 0, len(states))
+	for _, state := range states {
+		options = append(options, &model.PostActionOptions{Text: state.Label, Value: state.Value})
+	}
+
+	elements := []model.DialogElement{
+		{
+			DisplayName: "State",
+			Name:        constants.DialogElementState,
+			Type:        "select",
+			Options:     options,
+		},
+	}
+
+	// The fields required for some of the state transitions are shown as optional fields and are validated on submission
+	if len(constants.StateTransitionRequiredFields[recordType]) > 0 {
+		elements = append(elements,
+			model.DialogElement{
+				DisplayName: constants.FormattedFieldLabels[constants.FieldCloseCode],
+				Name:        constants.FieldCloseCode,
+				Type:        "text",
+				Optional:    true,
+				HelpText:    "Required for resolving or closing the record.",
+			},
+			model.DialogElement{
+				DisplayName: constants.FormattedFieldLabels[constants.FieldCloseNotes],
+				Name:        constants.FieldCloseNotes,
+				Type:        "textarea",
+				Optional:    true,
+				HelpText:    "Required for resolving or closing the record.",
+			},
+		)
+	}
+
+	return http.StatusOK, p.openDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       p.getInstanceDialogURL(client, constants.PathStateDialog),
+		Dialog: model.Dialog{
+			CallbackId:  recordID,
+			Title:       "Update the state",
+			SubmitLabel: "Update",
+			State:       recordType,
+			Elements:    elements,
+		},
+	})
+}
+
+// OpenIncidentDialog opens the interactive dialog for creating an incident in the given channel.
+// The caller is selected from the Mattermost users and mapped to their ServiceNow user when the dialog is submitted.
+func (p *Plugin) OpenIncidentDialog(triggerID, channelID string) error {
+	return p.openDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathIncidentDialog),
+		Dialog: model.Dialog{
+			Title:       "Create an incident",
+			SubmitLabel: "Create",
+			State:       channelID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Short description",
+					Name:        constants.DialogElementShortDescription,
+					Type:        "text",
+				},
+				{
+					DisplayName: "Description",
+					Name:        constants.DialogElementDescription,
+					Type:        "textarea",
+					Optional:    true,
+				},
+				{
+					DisplayName: "Caller",
+					Name:        constants.DialogElementCaller,
+					Type:        "select",
+					DataSource:  "users",
+					Optional:    true,
+					HelpText:    "Only the users who have connected their ServiceNow account can be selected.",
+				},
+			},
+		},
+	})
+}
+
+// getInstanceDialogURL returns the URL of a dialog whose submission is handled with the client of the given instance.
+// The instance is passed in the query as the dialogs are submitted to the routes checking the connection of the user.
+func (p *Plugin) getInstanceDialogURL(client Client, path string) string {
+	if instanceID := getInstanceID(client); instanceID != constants.DefaultInstanceID {
+		return fmt.Sprintf("%s%s?%s=%s", p.GetPluginURL(), path, constants.QueryParamInstance, instanceID)
+	}
+
+	return fmt.Sprintf("%s%s", p.GetPluginURL(), path)
+}
+
+func (p *Plugin) openDialog(request model.OpenDialogRequest) error {
+	if appErr := p.API.OpenInteractiveDialog(request); appErr != nil {
+		return appErr
+	}
+
+	return nil
+}
+
+// openRecordDialog opens the comment or the state dialog of the record in the context of a post action
+func (p *Plugin) openRecordDialog(w http.ResponseWriter, r *http.Request, openDialog func(client Client, triggerID, recordType, recordID string) (int, error)) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	recordType, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordType].(string)
+	recordID, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordID].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, recordID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	client, errorMessage := p.getPostActionClient(postActionIntegrationRequest)
+	if client == nil {
+		response.EphemeralText = errorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	if statusCode, err := openDialog(client, postActionIntegrationRequest.TriggerId, recordType, recordID); err != nil {
+		p.API.LogError(constants.ErrorOpenDialog, "Record ID", recordID, "Error", err.Error())
+		response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+func (p *Plugin) handleCommentDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	recordType := submitDialogRequest.State
+	recordID := submitDialogRequest.CallbackId
+	if !constants.RecordTypesSupportingComments[recordType] {
+		response.Error = constants.ErrorInvalidRecordType
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	comments, _ := submitDialogRequest.Submission[constants.DialogElementComments].(string)
+	payload := &serializer.ServiceNowCommentPayload{Comments: comments}
+	if err := payload.Validate(); err != nil {
+		response.Errors = map[string]string{
+			constants.DialogElementComments: err.Error(),
+		}
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	client := p.GetClientFromRequest(r)
+	if statusCode, err := client.AddComment(recordType, recordID, payload); err != nil {
+		p.API.LogError(constants.ErrorCreateComment, "Record ID", recordID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, submitDialogRequest.UserId, "")
+	}
+
+	p.returnSubmitDialogResponse(w, response)
+}
+
+func (p *Plugin) handleStateDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	recordType := submitDialogRequest.State
+	recordID := submitDialogRequest.CallbackId
+	if !constants.RecordTypesSupportingStateUpdation[recordType] {
+		response.Error = constants.ErrorInvalidRecordType
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	state, _ := submitDialogRequest.Submission[constants.DialogElementState].(string)
+	closeCode, _ := submitDialogRequest.Submission[constants.FieldCloseCode].(string)
+	closeNotes, _ := submitDialogRequest.Submission[constants.FieldCloseNotes].(string)
+	payload := &serializer.ServiceNowUpdateStatePayload{
+		State:      state,
+		CloseCode:  closeCode,
+		CloseNotes: closeNotes,
+	}
+	if err := payload.Validate(); err != nil {
+		response.Errors = map[string]string{
+			constants.DialogElementState: err.Error(),
+		}
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if fieldErrors := payload.GetMissingRequiredFields(recordType); len(fieldErrors) > 0 {
+		response.Errors = getDialogFieldErrors(fieldErrors)
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	client := p.GetClientFromRequest(r)
+	statusCode, err := client.UpdateStateOfRecordInServiceNow(recordType, recordID, payload)
+	if err != nil {
+		var serviceNowErr *ServiceNowError
+		if errors.As(err, &serviceNowErr) {
+			if fieldErrors := serviceNowErr.FieldErrors(); len(fieldErrors) > 0 {
+				p.API.LogDebug("Missing required fields for updating the state", "Record ID", recordID, "Error", err.Error())
+				response.Error = constants.APIErrorMissingRequiredFields
+				response.Errors = getDialogFieldErrors(fieldErrors)
+				p.returnSubmitDialogResponse(w, response)
+				return
+			}
+		}
+
+		p.API.LogError("Error in updating the state", "Record ID", recordID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, submitDialogRequest.UserId, "")
+	}
+
+	p.returnSubmitDialogResponse(w, response)
+}
+
+func (p *Plugin) handleIncidentDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	shortDescription, _ := submitDialogRequest.Submission[constants.DialogElementShortDescription].(string)
+	description, _ := submitDialogRequest.Submission[constants.DialogElementDescription].(string)
+	callerID, _ := submitDialogRequest.Submission[constants.DialogElementCaller].(string)
+	incident := &serializer.IncidentPayload{
+		ShortDescription: shortDescription,
+		Description:      strings.TrimSpace(description),
+		ChannelID:        submitDialogRequest.State,
+	}
+	if err := incident.IsValid(); err != nil {
+		response.Errors = map[string]string{
+			constants.DialogElementShortDescription: err.Error(),
+		}
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if _, err := p.HasChannelPermissions(submitDialogRequest.UserId, incident.ChannelID); err != nil {
+		response.Error = err.Error()
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if callerID != "" {
+		caller, err := p.GetUser(callerID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			p.API.LogError(constants.ErrorGetUser, "UserID", callerID, "Error", err.Error())
+			response.Error = genericErrorMessage
+			p.returnSubmitDialogResponse(w, response)
+			return
+		}
+
+		if caller == nil || caller.ServiceNowUser == nil {
+			response.Errors = map[string]string{
+				constants.DialogElementCaller: constants.ErrorCallerNotConnected,
+			}
+			p.returnSubmitDialogResponse(w, response)
+			return
+		}
+
+		incident.Caller = caller.ServiceNowUser.UserID
+	}
+
+	client := p.GetClientFromRequest(r)
+	incidentResponse, statusCode, err := client.CreateIncident(incident)
+	if err != nil {
+		p.API.LogError(constants.APIErrorCreateIncident, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, submitDialogRequest.UserId, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if _, err := p.shareCreatedIncident(client, incident.ChannelID, incidentResponse); err != nil {
+		p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
+		response.Error = genericErrorMessage
+	}
+
+	p.returnSubmitDialogResponse(w, response)
+}
+
+// shareCreatedIncident posts the incident created from Mattermost in the channel it was created from,
+// along with the knowledge articles which might solve it as a reply
+func (p *Plugin) shareCreatedIncident(client Client, channelID string, response *serializer.IncidentResponse) (*serializer.IncidentCreatedResponse, error) {
+	record := &serializer.ServiceNowRecord{
+		SysID:            response.SysID,
+		Number:           response.Number,
+		ShortDescription: response.ShortDescription,
+		Description:      response.Description,
+		RecordType:       constants.RecordTypeIncident,
+		State:            response.State,
+		Priority:         response.Priority,
+		AssignedTo:       response.AssignedTo,
+		AssignmentGroup:  response.AssignmentGroup,
+	}
+
+	if err := record.HandleNestedFields(p.getConfiguration().ServiceNowBaseURL); err != nil {
+		return nil, err
+	}
+
+	post := record.CreateSharingPost(channelID, p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL(), "")
+	createdPost, postErr := p.API.CreatePost(post)
+	if postErr != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", postErr.Error())
+	}
+
+	rootID := ""
+	if createdPost != nil {
+		rootID = createdPost.Id
+	}
+
+	return &serializer.IncidentCreatedResponse{
+		ServiceNowRecord:  record,
+		SuggestedArticles: p.suggestKnowledgeArticles(client, channelID, rootID, record),
+	}, nil
+}
+
+func getDialogFieldErrors(fieldErrors []*serializer.FieldError) map[string]string {
+	errs := make(map[string]string, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		errs[fieldError.Field] = fieldError.Message
+	}
+
+	return errs
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleOpenStateModal(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathOpenStateModal)
+	for name, test := range map[string]struct {
+		RecordID              string
+		SetupAPI              func(*plugintest.API)
+		SetupClient           func(*mock_plugin.Client)
+		ExpectedEphemeralText string
+	}{
+		"dialog is opened with the states of the record type": {
+			RecordID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("OpenInteractiveDialog", mock.MatchedBy(func(request model.OpenDialogRequest) bool {
+					elements := request.Dialog.Elements
+					return request.TriggerId == "mockTriggerID" && request.Dialog.CallbackId == testutils.GetServiceNowSysID() && request.Dialog.State == constants.RecordTypeIncident &&
+						len(elements) == 3 && len(elements[0].Options) == 1 && elements[0].Options[0].Value == constants.IncidentStateResolved
+				})).Return(nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetStatesFromServiceNow", constants.RecordTypeIncident).Return([]*serializer.ServiceNowState{{Label: "Resolved", Value: constants.IncidentStateResolved}}, http.StatusOK, nil)
+			},
+		},
+		"failed to get the states": {
+			RecordID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetStatesFromServiceNow", constants.RecordTypeIncident).Return(nil, http.StatusInternalServerError, fmt.Errorf("mockError"))
+			},
+			ExpectedEphemeralText: genericErrorMessage,
+		},
+		"invalid record ID": {
+			RecordID:              "mockRecordID",
+			SetupAPI:              func(_ *plugintest.API) {},
+			SetupClient:           func(_ *mock_plugin.Client) {},
+			ExpectedEphemeralText: genericErrorMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := mock_plugin.NewClient(t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId:    testutils.GetID(),
+				TriggerId: "mockTriggerID",
+				Context: map[string]interface{}{
+					constants.ContextNameRecordType: constants.RecordTypeIncident,
+					constants.ContextNameRecordID:   test.RecordID,
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedEphemeralText, response.EphemeralText)
+		})
+	}
+}
+
+func TestHandleStateDialog(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathStateDialog)
+	for name, test := range map[string]struct {
+		RecordType     string
+		Submission     map[string]any
+		SetupAPI       func(*plugintest.API)
+		SetupClient    func(*mock_plugin.Client)
+		ExpectedError  string
+		ExpectedErrors map[string]string
+	}{
+		"state is updated": {
+			RecordType: constants.RecordTypeIncident,
+			Submission: map[string]any{
+				constants.DialogElementState: constants.IncidentStateResolved,
+				constants.FieldCloseCode:     "Solved (Permanently)",
+				constants.FieldCloseNotes:    "mockNotes",
+			},
+			SetupAPI: func(_ *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("UpdateStateOfRecordInServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), &serializer.ServiceNowUpdateStatePayload{
+					State:      constants.IncidentStateResolved,
+					CloseCode:  "Solved (Permanently)",
+					CloseNotes: "mockNotes",
+				}).Return(http.StatusOK, nil)
+			},
+		},
+		"state is missing": {
+			RecordType:     constants.RecordTypeIncident,
+			Submission:     map[string]any{},
+			SetupAPI:       func(_ *plugintest.API) {},
+			SetupClient:    func(_ *mock_plugin.Client) {},
+			ExpectedErrors: map[string]string{constants.DialogElementState: "state value cannot be empty"},
+		},
+		"required fields are missing": {
+			RecordType: constants.RecordTypeIncident,
+			Submission: map[string]any{
+				constants.DialogElementState: constants.IncidentStateClosed,
+				constants.FieldCloseNotes:    "mockNotes",
+			},
+			SetupAPI:       func(_ *plugintest.API) {},
+			SetupClient:    func(_ *mock_plugin.Client) {},
+			ExpectedErrors: map[string]string{constants.FieldCloseCode: "Resolution code is required"},
+		},
+		"failed to update the state": {
+			RecordType: constants.RecordTypeIncident,
+			Submission: map[string]any{
+				constants.DialogElementState: "2",
+			},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("UpdateStateOfRecordInServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.ServiceNowUpdateStatePayload")).Return(http.StatusInternalServerError, fmt.Errorf("mockError"))
+			},
+			ExpectedError: genericErrorMessage,
+		},
+		"record type does not support updating the state": {
+			RecordType:    "mockRecordType",
+			Submission:    map[string]any{},
+			SetupAPI:      func(_ *plugintest.API) {},
+			SetupClient:   func(_ *mock_plugin.Client) {},
+			ExpectedError: constants.ErrorInvalidRecordType,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.SubmitDialogRequest{
+				UserId:     testutils.GetID(),
+				CallbackId: testutils.GetServiceNowSysID(),
+				State:      test.RecordType,
+				Submission: test.Submission,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.SubmitDialogResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedError, response.Error)
+			assert.Equal(t, test.ExpectedErrors, response.Errors)
+		})
+	}
+}
+
+func TestHandleIncidentDialog(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathIncidentDialog)
+	for name, test := range map[string]struct {
+		Submission     map[string]any
+		SetupAPI       func(*plugintest.API)
+		SetupClient    func(*mock_plugin.Client)
+		ExpectedError  string
+		ExpectedErrors map[string]string
+	}{
+		"incident is created and shared in the channel": {
+			Submission: map[string]any{
+				constants.DialogElementShortDescription: "mockShortDescription",
+				constants.DialogElementCaller:           testutils.GetID(),
+			},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID()
+				})).Return(&model.Post{}, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("CreateIncident", &serializer.IncidentPayload{
+					ShortDescription: "mockShortDescription",
+					Caller:           testutils.GetServiceNowSysID(),
+					ChannelID:        testutils.GetChannelID(),
+				}).Return(&serializer.IncidentResponse{SysID: testutils.GetServiceNowSysID()}, http.StatusOK, nil)
+			},
+		},
+		"caller has not connected their ServiceNow account": {
+			Submission: map[string]any{
+				constants.DialogElementShortDescription: "mockShortDescription",
+				constants.DialogElementCaller:           "mockUnconnectedUserID",
+			},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+			SetupClient:    func(_ *mock_plugin.Client) {},
+			ExpectedErrors: map[string]string{constants.DialogElementCaller: constants.ErrorCallerNotConnected},
+		},
+		"short description is missing": {
+			Submission:     map[string]any{},
+			SetupAPI:       func(_ *plugintest.API) {},
+			SetupClient:    func(_ *mock_plugin.Client) {},
+			ExpectedErrors: map[string]string{constants.DialogElementShortDescription: constants.ErrorEmptyShortDescription},
+		},
+		"does not have permission to post in the channel": {
+			Submission: map[string]any{
+				constants.DialogElementShortDescription: "mockShortDescription",
+			},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(false)
+			},
+			SetupClient:   func(_ *mock_plugin.Client) {},
+			ExpectedError: constants.ErrorInsufficientPermissions,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetUser", func(_ *Plugin, mattermostUserID string) (*serializer.User, error) {
+				if mattermostUserID == "mockUnconnectedUserID" {
+					return nil, ErrNotFound
+				}
+				return testutils.GetSerializerUser(), nil
+			})
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.SubmitDialogRequest{
+				UserId:     testutils.GetID(),
+				State:      testutils.GetChannelID(),
+				Submission: test.Submission,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.SubmitDialogResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedError, response.Error)
+			assert.Equal(t, test.ExpectedErrors, response.Errors)
+		})
+	}
+}
+
+func TestOpenIncidentDialog(t *testing.T) {
+	p, api := setupTestPlugin(&plugintest.API{}, nil)
+	defer api.AssertExpectations(t)
+
+	api.On("OpenInteractiveDialog", mock.MatchedBy(func(request model.OpenDialogRequest) bool {
+		caller := request.Dialog.Elements[2]
+		return request.Dialog.State == testutils.GetChannelID() && caller.DataSource == "users" && len(caller.Options) == 0
+	})).Return(nil)
+
+	require.NoError(t, p.OpenIncidentDialog("mockTriggerID", testutils.GetChannelID()))
+}
@@ -10,8 +10,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
 )
 
 type ErrorResponse struct {
@@ -24,8 +28,55 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// ServiceNowError is returned by Call when ServiceNow responds with an error body
+type ServiceNowError struct {
+	StatusCode int
+	Message    string
+	Detail     string
+}
+
+const dataPolicyMandatoryFieldsPrefix = "The following fields are mandatory:"
+
 var ErrorConnectionRefused = fmt.Errorf("unable to make connection to the specified ServiceNow instance")
 
+func (e *ServiceNowError) Error() string {
+	return fmt.Sprintf("errorMessage %s. errorDetail: %s", e.Message, e.Detail)
+}
+
+// FieldErrors parses the mandatory fields reported by a ServiceNow data policy exception.
+// ServiceNow reports the fields using their labels, so the labels are mapped back to the field names wherever they are known.
+func (e *ServiceNowError) FieldErrors() []*serializer.FieldError {
+	index := strings.Index(e.Detail, dataPolicyMandatoryFieldsPrefix)
+	if index == -1 {
+		return nil
+	}
+
+	labels := strings.SplitN(e.Detail[index+len(dataPolicyMandatoryFieldsPrefix):], "\n", 2)[0]
+	var fieldErrors []*serializer.FieldError
+	for _, label := range strings.Split(labels, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+
+		field := label
+		for name, formattedLabel := range constants.FormattedFieldLabels {
+			if strings.EqualFold(formattedLabel, label) {
+				field = name
+				break
+			}
+		}
+
+		fieldErrors = append(fieldErrors, &serializer.FieldError{
+			Field:   field,
+			Label:   label,
+			Message: fmt.Sprintf("%s is required", label),
+		})
+	}
+
+	return fieldErrors
+}
+
 func (c *client) CallJSON(method, path string, in, out interface{}, params url.Values) (responseData []byte, statusCode int, err error) {
 	contentType := "application/json"
 	buf := &bytes.Buffer{}
@@ -37,6 +88,16 @@ func (c *client) CallJSON(method, path string, in, out interface{}, params url.V
 
 func (c *client) Call(method, path, contentType string, inBody io.Reader, out interface{}, params url.Values) (responseData []byte, statusCode int, err error) {
 	errContext := fmt.Sprintf("serviceNow: Call failed: method:%s, path:%s", method, path)
+	if c.identity != "" {
+		if method != http.MethodGet {
+			return nil, http.StatusForbidden, errors.New(constants.ErrorServiceAccountReadOnly)
+		}
+
+		c.plugin.API.LogInfo("Calling ServiceNow using the service account", "Identity", c.identity, "Method", method, "Path", path)
+	} else {
+		c.plugin.API.LogDebug("Calling ServiceNow using the token of the user", "UserID", c.mattermostUserID, "Method", method, "Path", path)
+	}
+
 	pathURL, err := url.Parse(path)
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.WithMessage(err, errContext)
@@ -44,7 +105,7 @@ func (c *client) Call(method, path, contentType string, inBody io.Reader, out in
 
 	if pathURL.Scheme == "" || pathURL.Host == "" {
 		var baseURL *url.URL
-		baseURL, err = url.Parse(c.plugin.getConfiguration().ServiceNowBaseURL)
+		baseURL, err = url.Parse(c.GetBaseURL())
 		if err != nil {
 			return nil, http.StatusInternalServerError, errors.WithMessage(err, errContext)
 		}
@@ -99,5 +160,9 @@ func (c *client) Call(method, path, contentType string, inBody io.Reader, out in
 	if err = json.Unmarshal(responseData, &errResp); err != nil {
 		return responseData, resp.StatusCode, errors.WithMessagef(err, "status: %s", resp.Status)
 	}
-	return responseData, resp.StatusCode, fmt.Errorf("errorMessage %s. errorDetail: %s", errResp.Error.Message, errResp.Error.Detail)
+	return responseData, resp.StatusCode, &ServiceNowError{
+		StatusCode: resp.StatusCode,
+		Message:    errResp.Error.Message,
+		Detail:     errResp.Error.Detail,
+	}
 }
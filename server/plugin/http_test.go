@@ -17,6 +17,8 @@ import (
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
 )
 
 func TestCallJSON(t *testing.T) {
@@ -53,7 +55,8 @@ func TestCall(t *testing.T) {
 	p, api := setupTestPlugin(&plugintest.API{}, nil)
 	c := new(client)
 	mockClient := &client{
-		plugin: p,
+		plugin:           p,
+		mattermostUserID: "mockUserID",
 	}
 
 	for _, testCase := range []struct {
@@ -101,6 +104,20 @@ func TestCall(t *testing.T) {
 			setupAPI:           func(api *plugintest.API) {},
 			expectedStatusCode: http.StatusNoContent,
 		},
+		{
+			description: "Call: error response from ServiceNow",
+			setupClient: func(c *client) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(c.httpClient), "Do", func(*http.Client, *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusForbidden,
+						Body:       io.NopCloser(bytes.NewBufferString(`{"error":{"message":"Operation Failed","detail":"Data Policy Exception"},"status":"failure"}`)),
+					}, nil
+				})
+			},
+			setupAPI:             func(api *plugintest.API) {},
+			expectedErrorMessage: "errorMessage Operation Failed. errorDetail: Data Policy Exception",
+			expectedStatusCode:   http.StatusForbidden,
+		},
 		{
 			description: "Call: response body with status StatusOK",
 			setupClient: func(c *client) {
@@ -118,6 +135,7 @@ func TestCall(t *testing.T) {
 		t.Run(testCase.description, func(t *testing.T) {
 			testCase.setupClient(c)
 			testCase.setupAPI(api)
+			api.On("LogDebug", mock.AnythingOfType("string"), "UserID", "mockUserID", "Method", "mockMethod", "Path", mock.AnythingOfType("string")).Once().Return()
 			_, statusCode, err := mockClient.Call("mockMethod", "mockPath", "mockContentType", nil, nil, url.Values{})
 			if testCase.expectedErrorMessage != "" {
 				assert.EqualError(t, err, testCase.expectedErrorMessage)
@@ -129,3 +147,75 @@ func TestCall(t *testing.T) {
 		})
 	}
 }
+
+func TestCallWithServiceAccount(t *testing.T) {
+	defer monkey.UnpatchAll()
+	p, api := setupTestPlugin(&plugintest.API{}, nil)
+	mockClient := &client{
+		plugin:   p,
+		identity: "mockIdentity",
+	}
+
+	monkey.PatchInstanceMethod(reflect.TypeOf(mockClient.httpClient), "Do", func(*http.Client, *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil
+	})
+
+	t.Run("Call: read-only call is logged with the identity", func(t *testing.T) {
+		api.On("LogInfo", mock.AnythingOfType("string"), "Identity", "mockIdentity", "Method", http.MethodGet, "Path", mock.AnythingOfType("string")).Once().Return()
+		_, statusCode, err := mockClient.Call(http.MethodGet, "mockPath", "mockContentType", nil, nil, url.Values{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+		api.AssertExpectations(t)
+	})
+
+	t.Run("Call: write call is not allowed", func(t *testing.T) {
+		_, statusCode, err := mockClient.Call(http.MethodPatch, "mockPath", "mockContentType", nil, nil, url.Values{})
+
+		assert.EqualError(t, err, constants.ErrorServiceAccountReadOnly)
+		assert.Equal(t, http.StatusForbidden, statusCode)
+	})
+}
+
+func TestServiceNowErrorFieldErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		description    string
+		detail         string
+		expectedFields []string
+		expectedLabels []string
+	}{
+		{
+			description: "ServiceNowError: error is not a data policy exception",
+			detail:      "ACL restricts the record retrieval",
+		},
+		{
+			description:    "ServiceNowError: known mandatory fields",
+			detail:         "Data Policy Exception: \n\tThe following fields are mandatory: Resolution code, Resolution notes\n",
+			expectedFields: []string{"close_code", "close_notes"},
+			expectedLabels: []string{"Resolution code", "Resolution notes"},
+		},
+		{
+			description:    "ServiceNowError: unknown mandatory field",
+			detail:         "Data Policy Exception: The following fields are mandatory: Cause code",
+			expectedFields: []string{"Cause code"},
+			expectedLabels: []string{"Cause code"},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			serviceNowErr := &ServiceNowError{
+				StatusCode: http.StatusForbidden,
+				Message:    "Operation Failed",
+				Detail:     testCase.detail,
+			}
+
+			fieldErrors := serviceNowErr.FieldErrors()
+			require.Equal(t, len(testCase.expectedFields), len(fieldErrors))
+			for i, fieldError := range fieldErrors {
+				assert.Equal(t, testCase.expectedFields[i], fieldError.Field)
+				assert.Equal(t, testCase.expectedLabels[i], fieldError.Label)
+			}
+		})
+	}
+}
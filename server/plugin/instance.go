@@ -0,0 +1,394 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// serviceNowInstance is a ServiceNow instance which the users can connect to and which can send notifications
+type serviceNowInstance struct {
+	ID                string `json:"id"`
+	BaseURL           string `json:"base_url"`
+	OAuthClientID     string `json:"oauth_client_id"`
+	OAuthClientSecret string `json:"oauth_client_secret"`
+	WebhookSecret     string `json:"webhook_secret"`
+}
+
+// instanceClient is implemented by the clients which are not wrapped by the lookup cache.
+// The clients of the instances other than the default one are never wrapped.
+type instanceClient interface {
+	GetInstanceID() string
+	GetBaseURL() string
+}
+
+// parseInstances parses the JSON array of the additional ServiceNow instances
+func parseInstances(value string) ([]*serviceNowInstance, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	instances := []*serviceNowInstance{}
+	if err := json.Unmarshal([]byte(value), &instances); err != nil {
+		return nil, errors.New(constants.ErrorInvalidInstances)
+	}
+
+	ids := map[string]bool{constants.DefaultInstanceID: true}
+	for _, instance := range instances {
+		if instance == nil {
+			return nil, errors.New(constants.ErrorInvalidInstances)
+		}
+
+		instance.ID = strings.TrimSpace(instance.ID)
+		instance.BaseURL = strings.TrimRight(strings.TrimSpace(instance.BaseURL), "/")
+		instance.OAuthClientID = strings.TrimSpace(instance.OAuthClientID)
+		instance.OAuthClientSecret = strings.TrimSpace(instance.OAuthClientSecret)
+		instance.WebhookSecret = strings.TrimSpace(instance.WebhookSecret)
+
+		valid, _ := regexp.MatchString(constants.InstanceIDRegex, instance.ID)
+		if !valid || ids[instance.ID] || instance.BaseURL == "" || instance.OAuthClientID == "" || instance.OAuthClientSecret == "" || instance.WebhookSecret == "" {
+			return nil, fmt.Errorf(constants.ErrorInvalidInstance, instance.ID)
+		}
+		ids[instance.ID] = true
+	}
+
+	return instances, nil
+}
+
+// GetInstances returns the default instance followed by the additional instances
+func (c *configuration) GetInstances() []*serviceNowInstance {
+	return append([]*serviceNowInstance{{
+		ID:                constants.DefaultInstanceID,
+		BaseURL:           c.ServiceNowBaseURL,
+		OAuthClientID:     c.ServiceNowOAuthClientID,
+		OAuthClientSecret: c.ServiceNowOAuthClientSecret,
+		WebhookSecret:     c.WebhookSecret,
+	}}, c.Instances...)
+}
+
+// GetInstance returns the instance with the given ID. The default instance is returned for an empty ID.
+func (c *configuration) GetInstance(instanceID string) (*serviceNowInstance, error) {
+	if instanceID == "" {
+		instanceID = constants.DefaultInstanceID
+	}
+
+	for _, instance := range c.GetInstances() {
+		if instance.ID == instanceID {
+			return instance, nil
+		}
+	}
+
+	return nil, errors.New(constants.ErrorUnknownInstance)
+}
+
+// GetInstanceBySecret returns the instance whose webhook secret matches the given secret
+func (c *configuration) GetInstanceBySecret(secret string) (*serviceNowInstance, int, error) {
+	status, err := http.StatusForbidden, errors.New(constants.ErrorInvalidSecret)
+	for _, instance := range c.GetInstances() {
+		if status, err = verifyHTTPSecret(instance.WebhookSecret, secret); err == nil {
+			return instance, 0, nil
+		}
+	}
+
+	return nil, status, err
+}
+
+func (p *Plugin) newInstanceOAuth2Config(instance *serviceNowInstance) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     instance.OAuthClientID,
+		ClientSecret: instance.OAuthClientSecret,
+		RedirectURL:  fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathOAuth2Complete),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  fmt.Sprintf("%s/oauth_auth.do", instance.BaseURL),
+			TokenURL: fmt.Sprintf("%s/oauth_token.do", instance.BaseURL),
+		},
+	}
+}
+
+// GetInstanceUser returns the connection of the Mattermost user to the given instance
+func (p *Plugin) GetInstanceUser(mattermostUserID, instanceID string) (*serializer.User, error) {
+	if instanceID == "" || instanceID == constants.DefaultInstanceID {
+		return p.GetUser(mattermostUserID)
+	}
+
+	return p.store.LoadInstanceUser(instanceID, mattermostUserID)
+}
+
+// GetInstanceClient returns the client for the connected Mattermost user of the given instance.
+// The lookups are only cached for the default instance as the cache keys don't identify the instances.
+func (p *Plugin) GetInstanceClient(mattermostUserID, instanceID string) (Client, error) {
+	if instanceID == "" || instanceID == constants.DefaultInstanceID {
+		return p.GetClientFromMattermostUserID(mattermostUserID)
+	}
+
+	instance, err := p.getConfiguration().GetInstance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := p.store.LoadInstanceUser(instanceID, mattermostUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.ParseAuthToken(user.OAuth2Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NewInstanceClient(context.Background(), instance, token, mattermostUserID), nil
+}
+
+// GetInstanceClientForSubscriptionOwner returns the client for the owner of a subscription of the given instance.
+// The service account is only used for the default instance as it can't be configured for the other instances.
+func (p *Plugin) GetInstanceClientForSubscriptionOwner(mattermostUserID, instanceID string) (Client, error) {
+	if instanceID == "" || instanceID == constants.DefaultInstanceID {
+		return p.GetClientForSubscriptionOwner(mattermostUserID)
+	}
+
+	return p.GetInstanceClient(mattermostUserID, instanceID)
+}
+
+// GetInstanceMattermostUserIDFromServiceNowUserID returns the Mattermost user connected to the ServiceNow user of the given instance
+func (p *Plugin) GetInstanceMattermostUserIDFromServiceNowUserID(instanceID, serviceNowUserID string) (string, error) {
+	if instanceID == "" || instanceID == constants.DefaultInstanceID {
+		return p.GetMattermostUserIDFromServiceNowUserID(serviceNowUserID)
+	}
+
+	return p.store.LoadInstanceMattermostUserIDFromServiceNowUserID(instanceID, serviceNowUserID)
+}
+
+// getPostActionClient returns the client of the user for the instance of the post on which the action was triggered.
+// The message for the user is returned if the client can't be created.
+func (p *Plugin) getPostActionClient(request *model.PostActionIntegrationRequest) (Client, string) {
+	instanceID, _ := request.Context[constants.ContextNameInstanceID].(string)
+	client, err := p.GetInstanceClient(request.UserId, instanceID)
+	if err != nil {
+		switch {
+		case !errors.Is(err, ErrNotFound):
+			p.API.LogError(constants.ErrorGetUser, "Instance", instanceID, "Error", err.Error())
+			return nil, genericErrorMessage
+		case instanceID == "" || instanceID == constants.DefaultInstanceID:
+			return nil, fmt.Sprintf(notConnectedMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
+		default:
+			return nil, fmt.Sprintf(constants.InstanceNotConnectedMessage, instanceID, p.GetPluginURL(), constants.PathOAuth2Connect, constants.QueryParamInstance, instanceID)
+		}
+	}
+
+	return client, ""
+}
+
+// getCommandClient returns the client of the instance selected for the command, or the client of the default instance
+func (p *Plugin) getCommandClient(mattermostUserID string, client Client) (Client, error) {
+	if _, ok := client.(instanceClient); ok {
+		return client, nil
+	}
+
+	return p.GetClientFromMattermostUserID(mattermostUserID)
+}
+
+// getInstanceID returns the ID of the instance used by the client
+func getInstanceID(client Client) string {
+	if c, ok := client.(instanceClient); ok {
+		return c.GetInstanceID()
+	}
+
+	return constants.DefaultInstanceID
+}
+
+// getServiceNowURL returns the base URL of the instance used by the client
+func (p *Plugin) getServiceNowURL(client Client) string {
+	if c, ok := client.(instanceClient); ok {
+		return c.GetBaseURL()
+	}
+
+	return p.getConfiguration().ServiceNowBaseURL
+}
+
+// getServiceNowURLFromRequest returns the base URL of the instance which sent the webhook request
+func (p *Plugin) getServiceNowURLFromRequest(r *http.Request) string {
+	instanceID, _ := r.Context().Value(constants.ContextInstanceKey).(string)
+	return p.getInstanceBaseURL(instanceID)
+}
+
+// getInstanceBaseURL returns the base URL of the given instance, or of the default instance if the instance is not configured
+func (p *Plugin) getInstanceBaseURL(instanceID string) string {
+	if instance, err := p.getConfiguration().GetInstance(instanceID); err == nil {
+		return instance.BaseURL
+	}
+
+	return p.getConfiguration().ServiceNowBaseURL
+}
+
+// isDefaultInstanceRequest checks if the webhook request was sent by the default instance
+func isDefaultInstanceRequest(r *http.Request) bool {
+	instanceID, _ := r.Context().Value(constants.ContextInstanceKey).(string)
+	return instanceID == "" || instanceID == constants.DefaultInstanceID
+}
+
+// getCommandInstance removes the instance flag from the parameters and returns the instance selected for the command.
+// The default instance of the channel is used when the flag is not passed.
+func (p *Plugin) getCommandInstance(args *model.CommandArgs, action string, parameters []string) (string, []string, string) {
+	instanceID := ""
+	remaining := make([]string, 0, len(parameters))
+	for i := 0; i < len(parameters); i++ {
+		if parameters[i] == constants.FlagInstance {
+			if i+1 == len(parameters) {
+				return "", nil, constants.MissingInstanceMessage
+			}
+
+			instanceID = parameters[i+1]
+			i++
+			continue
+		}
+		remaining = append(remaining, parameters[i])
+	}
+
+	config := p.getConfiguration()
+	if instanceID == "" {
+		if len(config.Instances) == 0 || !constants.InstanceCommands[action] {
+			return constants.DefaultInstanceID, remaining, ""
+		}
+
+		channelInstanceID, err := p.store.LoadChannelInstance(args.ChannelId)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			p.API.LogWarn(constants.ErrorGetChannelInstance, "ChannelID", args.ChannelId, "Error", err.Error())
+		}
+
+		// The instance may have been removed from the configuration after it was set as the default of the channel
+		if _, err = config.GetInstance(channelInstanceID); channelInstanceID == "" || err != nil {
+			return constants.DefaultInstanceID, remaining, ""
+		}
+
+		return channelInstanceID, remaining, ""
+	}
+
+	if _, err := config.GetInstance(instanceID); err != nil {
+		return "", nil, fmt.Sprintf(constants.UnknownInstanceMessage, instanceID)
+	}
+
+	if instanceID != constants.DefaultInstanceID && !constants.InstanceCommands[action] {
+		return "", nil, fmt.Sprintf(constants.InstanceNotSupportedMessage, action)
+	}
+
+	return instanceID, remaining, ""
+}
+
+// handleInstance lists the instances or sets the default instance of the channel
+func (p *Plugin) handleInstance(args *model.CommandArgs, parameters []string) string {
+	if len(parameters) == 0 || parameters[0] == constants.SubCommandList {
+		return p.getFormattedInstances(args.UserId, args.ChannelId)
+	}
+
+	switch parameters[0] {
+	case constants.SubCommandDefault:
+		if len(parameters) < 2 {
+			return constants.ErrorCommandInvalidNumberOfParams
+		}
+
+		instanceID := parameters[1]
+		if _, err := p.getConfiguration().GetInstance(instanceID); err != nil {
+			return fmt.Sprintf(constants.UnknownInstanceMessage, instanceID)
+		}
+
+		if _, err := p.HasChannelPermissions(args.UserId, args.ChannelId); err != nil {
+			return err.Error()
+		}
+
+		if err := p.store.StoreChannelInstance(args.ChannelId, instanceID); err != nil {
+			p.API.LogError("Unable to store the default ServiceNow instance of the channel", "ChannelID", args.ChannelId, "Error", err.Error())
+			return genericErrorMessage
+		}
+
+		return fmt.Sprintf(constants.InstanceDefaultSetMessage, instanceID)
+	default:
+		return fmt.Sprintf("Unknown subcommand %v", parameters[0])
+	}
+}
+
+func (p *Plugin) getFormattedInstances(mattermostUserID, channelID string) string {
+	instances := p.getConfiguration().GetInstances()
+	channelInstanceID, err := p.store.LoadChannelInstance(channelID)
+	if err != nil || channelInstanceID == "" {
+		channelInstanceID = constants.DefaultInstanceID
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("#### ServiceNow instances\n| ID | URL | Connected | Default of this channel |\n| :--- | :--- | :--- | :--- |")
+	for _, instance := range instances {
+		connected := "No"
+		if _, userErr := p.GetInstanceUser(mattermostUserID, instance.ID); userErr == nil {
+			connected = "Yes"
+		}
+
+		isDefault := ""
+		if instance.ID == channelInstanceID {
+			isDefault = "Yes"
+		}
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|%s|", instance.ID, instance.BaseURL, connected, isDefault))
+	}
+
+	return sb.String()
+}
+
+// getInstanceConnectMessage returns the link for connecting to the instance, or a message if the user is already connected to it
+func (p *Plugin) getInstanceConnectMessage(mattermostUserID, instanceID string) string {
+	if _, err := p.GetInstanceUser(mattermostUserID, instanceID); err == nil {
+		return constants.UserAlreadyConnectedMessage
+	}
+
+	if instanceID == constants.DefaultInstanceID {
+		return fmt.Sprintf("[%s](%s%s)", constants.UserConnectMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
+	}
+
+	return fmt.Sprintf("[%s](%s%s?%s=%s)", constants.UserConnectMessage, p.GetPluginURL(), constants.PathOAuth2Connect, constants.QueryParamInstance, instanceID)
+}
+
+// getInstanceClientForCommand returns the client of the user for an instance other than the default one.
+// A message is posted for the user and nil is returned if the user is not connected to the instance.
+func (p *Plugin) getInstanceClientForCommand(args *model.CommandArgs, instanceID string) Client {
+	client, err := p.GetInstanceClient(args.UserId, instanceID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			p.postCommandResponse(args, fmt.Sprintf(constants.InstanceNotConnectedMessage, instanceID, p.GetPluginURL(), constants.PathOAuth2Connect, constants.QueryParamInstance, instanceID))
+		} else {
+			p.API.LogError("Unable to get the client for the instance", "Instance", instanceID, "Error", err.Error())
+			p.postCommandResponse(args, genericErrorMessage)
+		}
+		return nil
+	}
+
+	return client
+}
+
+// disconnectInstance disconnects the user from an instance other than the default one
+func (p *Plugin) disconnectInstance(mattermostUserID, instanceID string) string {
+	if _, err := p.store.LoadInstanceUser(instanceID, mattermostUserID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return fmt.Sprintf(constants.InstanceNotConnectedMessage, instanceID, p.GetPluginURL(), constants.PathOAuth2Connect, constants.QueryParamInstance, instanceID)
+		}
+
+		p.API.LogError("Unable to get the connection of the user to the instance", "Instance", instanceID, "Error", err.Error())
+		return disconnectErrorMessage
+	}
+
+	if err := p.store.DeleteInstanceUser(instanceID, mattermostUserID); err != nil {
+		p.API.LogError("Unable to disconnect user from the instance", "Instance", instanceID, "Error", err.Error())
+		return disconnectErrorMessage
+	}
+
+	return fmt.Sprintf(constants.InstanceDisconnectedMessage, instanceID)
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func getMockInstancesConfiguration() *configuration {
+	return &configuration{
+		ServiceNowBaseURL: "https://prod.service-now.com",
+		WebhookSecret:     "mockWebhookSecret",
+		Instances: []*serviceNowInstance{
+			{
+				ID:                "hr",
+				BaseURL:           "https://hr.service-now.com",
+				OAuthClientID:     "mockOAuthClientID",
+				OAuthClientSecret: "mockOAuthClientSecret",
+				WebhookSecret:     "mockHRWebhookSecret",
+			},
+		},
+	}
+}
+
+func TestParseInstances(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		value       string
+		expected    []*serviceNowInstance
+		errMsg      string
+	}{
+		{
+			description: "instances are parsed",
+			value:       `[{"id": "hr", "base_url": "https://hr.service-now.com/ ", "oauth_client_id": "mockID", "oauth_client_secret": "mockSecret", "webhook_secret": "mockWebhookSecret"}]`,
+			expected: []*serviceNowInstance{
+				{ID: "hr", BaseURL: "https://hr.service-now.com", OAuthClientID: "mockID", OAuthClientSecret: "mockSecret", WebhookSecret: "mockWebhookSecret"},
+			},
+		},
+		{
+			description: "no instances",
+			value:       " ",
+		},
+		{
+			description: "value is not a JSON array",
+			value:       `{"id": "hr"}`,
+			errMsg:      constants.ErrorInvalidInstances,
+		},
+		{
+			description: "ID of the default instance is used",
+			value:       `[{"id": "default", "base_url": "https://hr.service-now.com", "oauth_client_id": "mockID", "oauth_client_secret": "mockSecret", "webhook_secret": "mockWebhookSecret"}]`,
+			errMsg:      fmt.Sprintf(constants.ErrorInvalidInstance, "default"),
+		},
+		{
+			description: "ID is not valid",
+			value:       `[{"id": "HR team", "base_url": "https://hr.service-now.com", "oauth_client_id": "mockID", "oauth_client_secret": "mockSecret", "webhook_secret": "mockWebhookSecret"}]`,
+			errMsg:      fmt.Sprintf(constants.ErrorInvalidInstance, "HR team"),
+		},
+		{
+			description: "webhook secret is missing",
+			value:       `[{"id": "hr", "base_url": "https://hr.service-now.com", "oauth_client_id": "mockID", "oauth_client_secret": "mockSecret"}]`,
+			errMsg:      fmt.Sprintf(constants.ErrorInvalidInstance, "hr"),
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			instances, err := parseInstances(testCase.value)
+			if testCase.errMsg != "" {
+				require.EqualError(t, err, testCase.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, instances)
+		})
+	}
+}
+
+func TestGetInstanceBySecret(t *testing.T) {
+	config := getMockInstancesConfiguration()
+	for _, testCase := range []struct {
+		description      string
+		secret           string
+		expectedInstance string
+		expectedStatus   int
+	}{
+		{
+			description:      "secret of the default instance",
+			secret:           "mockWebhookSecret",
+			expectedInstance: constants.DefaultInstanceID,
+		},
+		{
+			description:      "secret of an additional instance",
+			secret:           "mockHRWebhookSecret",
+			expectedInstance: "hr",
+		},
+		{
+			description:    "secret doesn't match any instance",
+			secret:         "mockSecret",
+			expectedStatus: http.StatusForbidden,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			instance, status, err := config.GetInstanceBySecret(testCase.secret)
+			assert.Equal(t, testCase.expectedStatus, status)
+			if testCase.expectedInstance == "" {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedInstance, instance.ID)
+		})
+	}
+}
+
+func TestGetCommandInstance(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description        string
+		config             *configuration
+		action             string
+		parameters         []string
+		setupStore         func(*mock_plugin.Store)
+		expectedInstance   string
+		expectedParameters []string
+		expectedMessage    string
+	}{
+		{
+			description:        "no additional instances are configured",
+			config:             &configuration{},
+			action:             constants.CommandChanges,
+			parameters:         []string{constants.SubCommandUpcoming},
+			setupStore:         func(_ *mock_plugin.Store) {},
+			expectedInstance:   constants.DefaultInstanceID,
+			expectedParameters: []string{constants.SubCommandUpcoming},
+		},
+		{
+			description:        "instance is passed using the flag",
+			config:             getMockInstancesConfiguration(),
+			action:             constants.CommandChanges,
+			parameters:         []string{constants.SubCommandUpcoming, constants.FlagInstance, "hr", constants.FlagWindow, "2d"},
+			setupStore:         func(_ *mock_plugin.Store) {},
+			expectedInstance:   "hr",
+			expectedParameters: []string{constants.SubCommandUpcoming, constants.FlagWindow, "2d"},
+		},
+		{
+			description:     "instance passed using the flag doesn't exist",
+			config:          getMockInstancesConfiguration(),
+			action:          constants.CommandChanges,
+			parameters:      []string{constants.FlagInstance, "finance"},
+			setupStore:      func(_ *mock_plugin.Store) {},
+			expectedMessage: fmt.Sprintf(constants.UnknownInstanceMessage, "finance"),
+		},
+		{
+			description:     "value of the instance flag is missing",
+			config:          getMockInstancesConfiguration(),
+			action:          constants.CommandChanges,
+			parameters:      []string{constants.SubCommandUpcoming, constants.FlagInstance},
+			setupStore:      func(_ *mock_plugin.Store) {},
+			expectedMessage: constants.MissingInstanceMessage,
+		},
+		{
+			description:     "command doesn't support the instances",
+			config:          getMockInstancesConfiguration(),
+			action:          constants.CommandKnowledge,
+			parameters:      []string{"vpn", constants.FlagInstance, "hr"},
+			setupStore:      func(_ *mock_plugin.Store) {},
+			expectedMessage: fmt.Sprintf(constants.InstanceNotSupportedMessage, constants.CommandKnowledge),
+		},
+		{
+			description: "default instance of the channel is used",
+			config:      getMockInstancesConfiguration(),
+			action:      constants.CommandOnCall,
+			parameters:  []string{"Network"},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadChannelInstance", testutils.GetChannelID()).Return("hr", nil)
+			},
+			expectedInstance:   "hr",
+			expectedParameters: []string{"Network"},
+		},
+		{
+			description: "default instance of the channel doesn't exist anymore",
+			config:      getMockInstancesConfiguration(),
+			action:      constants.CommandOnCall,
+			parameters:  []string{"Network"},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadChannelInstance", testutils.GetChannelID()).Return("finance", nil)
+			},
+			expectedInstance:   constants.DefaultInstanceID,
+			expectedParameters: []string{"Network"},
+		},
+		{
+			description:        "default instance of the channel is not used by the commands which don't support the instances",
+			config:             getMockInstancesConfiguration(),
+			action:             constants.CommandKnowledge,
+			parameters:         []string{"vpn"},
+			setupStore:         func(_ *mock_plugin.Store) {},
+			expectedInstance:   constants.DefaultInstanceID,
+			expectedParameters: []string{"vpn"},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, _ := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(testCase.config)
+
+			instanceID, parameters, message := p.getCommandInstance(args, testCase.action, testCase.parameters)
+			assert.Equal(t, testCase.expectedMessage, message)
+			assert.Equal(t, testCase.expectedInstance, instanceID)
+			if testCase.expectedMessage == "" {
+				assert.Equal(t, testCase.expectedParameters, parameters)
+			}
+		})
+	}
+}
+
+func TestHandleInstance(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description     string
+		parameters      []string
+		setupAPI        func(*plugintest.API)
+		setupStore      func(*mock_plugin.Store)
+		expectedMessage func(string) bool
+	}{
+		{
+			description: "instances are listed",
+			parameters:  []string{constants.SubCommandList},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadChannelInstance", testutils.GetChannelID()).Return("hr", nil)
+				s.On("LoadUser", testutils.GetID()).Return(&serializer.User{}, nil)
+				s.On("LoadInstanceUser", "hr", testutils.GetID()).Return(nil, ErrNotFound)
+			},
+			expectedMessage: func(message string) bool {
+				return strings.Contains(message, "\n|default|https://prod.service-now.com|Yes||") &&
+					strings.Contains(message, "\n|hr|https://hr.service-now.com|No|Yes|")
+			},
+		},
+		{
+			description: "default instance of the channel is set",
+			parameters:  []string{constants.SubCommandDefault, "hr"},
+			setupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreChannelInstance", testutils.GetChannelID(), "hr").Return(nil)
+			},
+			expectedMessage: func(message string) bool {
+				return message == fmt.Sprintf(constants.InstanceDefaultSetMessage, "hr")
+			},
+		},
+		{
+			description: "instance doesn't exist",
+			parameters:  []string{constants.SubCommandDefault, "finance"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			expectedMessage: func(message string) bool {
+				return message == fmt.Sprintf(constants.UnknownInstanceMessage, "finance")
+			},
+		},
+		{
+			description: "instance is missing",
+			parameters:  []string{constants.SubCommandDefault},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			expectedMessage: func(message string) bool {
+				return message == constants.ErrorCommandInvalidNumberOfParams
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(getMockInstancesConfiguration())
+			testCase.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			message := p.handleInstance(args, testCase.parameters)
+			assert.True(t, testCase.expectedMessage(message), message)
+		})
+	}
+}
+
+func TestGetPostActionClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	for _, testCase := range []struct {
+		description        string
+		context            map[string]interface{}
+		setupAPI           func(*plugintest.API)
+		setupStore         func(*mock_plugin.Store)
+		expectedInstanceID string
+		expectedMessage    func(*Plugin) string
+	}{
+		{
+			description: "client of the instance of the post is returned",
+			context:     map[string]interface{}{constants.ContextNameInstanceID: "hr"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadInstanceUser", "hr", testutils.GetID()).Return(&serializer.User{OAuth2Token: "mockToken"}, nil)
+			},
+			expectedInstanceID: "hr",
+		},
+		{
+			description: "client of the default instance is returned for the posts without an instance",
+			context:     map[string]interface{}{},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadUser", testutils.GetID()).Return(&serializer.User{OAuth2Token: "mockToken"}, nil)
+			},
+			expectedInstanceID: constants.DefaultInstanceID,
+		},
+		{
+			description: "user is not connected to the instance of the post",
+			context:     map[string]interface{}{constants.ContextNameInstanceID: "hr"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadInstanceUser", "hr", testutils.GetID()).Return(nil, ErrNotFound)
+			},
+			expectedMessage: func(p *Plugin) string {
+				return fmt.Sprintf(constants.InstanceNotConnectedMessage, "hr", p.GetPluginURL(), constants.PathOAuth2Connect, constants.QueryParamInstance, "hr")
+			},
+		},
+		{
+			description: "instance of the post has been removed",
+			context:     map[string]interface{}{constants.ContextNameInstanceID: "finance"},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupStore: func(_ *mock_plugin.Store) {},
+			expectedMessage: func(_ *Plugin) string {
+				return genericErrorMessage
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(getMockInstancesConfiguration())
+			testCase.setupAPI(api)
+			defer api.AssertExpectations(t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "ParseAuthToken", func(_ *Plugin, _ string) (*oauth2.Token, error) {
+				return &oauth2.Token{}, nil
+			})
+
+			client, message := p.getPostActionClient(&model.PostActionIntegrationRequest{
+				UserId:  testutils.GetID(),
+				Context: testCase.context,
+			})
+			if testCase.expectedMessage != nil {
+				assert.Equal(t, testCase.expectedMessage(p), message)
+				assert.Nil(t, client)
+				return
+			}
+
+			assert.Empty(t, message)
+			require.NotNil(t, client)
+			assert.Equal(t, testCase.expectedInstanceID, getInstanceID(client))
+		})
+	}
+}
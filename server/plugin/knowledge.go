@@ -0,0 +1,304 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+func (p *Plugin) handleKnowledge(_ *plugin.Context, args *model.CommandArgs, parameters []string, _ Client, isSysAdmin bool) string {
+	query := strings.TrimSpace(strings.Trim(strings.Join(parameters, " "), `"`))
+	if len(query) < constants.CharacterThresholdForSearchingRecords {
+		return fmt.Sprintf(constants.ErrorSearchTermThreshold, constants.CharacterThresholdForSearchingRecords)
+	}
+
+	client, err := p.GetClientFromMattermostUserID(args.UserId)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		articles, statusCode, err := client.SearchKnowledgeArticlesInServiceNow(query)
+		if err != nil {
+			p.API.LogError(constants.ErrorSearchingKnowledgeArticles, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if len(articles) == 0 {
+			p.postCommandResponse(args, fmt.Sprintf(constants.KnowledgeArticleNotFoundMessage, query))
+			return
+		}
+
+		post := serializer.CreateKnowledgeSearchPost(articles, query, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+		post.UserId = p.botID
+		post.ChannelId = args.ChannelId
+		post.RootId = args.RootId
+		_ = p.API.SendEphemeralPost(args.UserId, post)
+	}()
+
+	return genericWaitMessage
+}
+
+// handleKnowledgeArticleAction shows the body of an article to the user or shares the article in the channel
+func (p *Plugin) handleKnowledgeArticleAction(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	articleID, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordID].(string)
+	action, _ := postActionIntegrationRequest.Context[constants.ContextNameAction].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, articleID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	channelID := postActionIntegrationRequest.ChannelId
+	if action == constants.KnowledgeActionShare {
+		if _, err := p.HasChannelPermissions(userID, channelID); err != nil {
+			response.EphemeralText = err.Error()
+			p.returnPostActionIntegrationResponse(w, response)
+			return
+		}
+	}
+
+	client, err := p.GetClientFromMattermostUserID(userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			response.EphemeralText = fmt.Sprintf(notConnectedMessage, p.GetPluginURL(), constants.PathOAuth2Connect)
+		} else {
+			p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+			response.EphemeralText = genericErrorMessage
+		}
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	article, statusCode, err := client.GetRecordFromServiceNow(constants.RecordTypeKnowledge, articleID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetRecord, "Article ID", articleID, "Error", err.Error())
+		response.EphemeralText = p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	serviceNowURL := p.getConfiguration().ServiceNowBaseURL
+	article.RecordType = constants.RecordTypeKnowledge
+	switch action {
+	case constants.KnowledgeActionView:
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeKnowledge, articleID, constants.RecordTypeKnowledge)
+		response.EphemeralText = fmt.Sprintf("#### [%s](%s): %s\n%s", article.Number, link, article.ShortDescription, serializer.GetKnowledgeArticleMarkdown(article.Text, serviceNowURL, link))
+	case constants.KnowledgeActionShare:
+		user, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			p.API.LogError(constants.ErrorGetUser, "UserID", userID, "Error", appErr.Error())
+			response.EphemeralText = genericErrorMessage
+			break
+		}
+
+		if err := article.HandleNestedFields(serviceNowURL); err != nil {
+			p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
+			response.EphemeralText = genericErrorMessage
+			break
+		}
+
+		post := article.CreateSharingPost(channelID, p.botID, serviceNowURL, p.GetPluginURL(), user.Username)
+		if _, appErr := p.API.CreatePost(post); appErr != nil {
+			p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+			response.EphemeralText = genericErrorMessage
+			break
+		}
+		response.EphemeralText = constants.KnowledgeArticleSharedMessage
+	default:
+		response.EphemeralText = genericErrorMessage
+	}
+
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+// suggestKnowledgeArticles searches for the articles matching the short description of the incident
+// and posts them as a reply to the incident post. The failures are not surfaced, as the incident is already created.
+func (p *Plugin) suggestKnowledgeArticles(client Client, channelID, rootID string, incident *serializer.ServiceNowRecord) []*serializer.KnowledgeArticleSuggestion {
+	if len(incident.ShortDescription) < constants.CharacterThresholdForSearchingRecords {
+		return nil
+	}
+
+	articles, _, err := client.SearchKnowledgeArticlesInServiceNow(incident.ShortDescription)
+	if err != nil {
+		p.API.LogWarn(constants.ErrorSearchingKnowledgeArticles, "Incident ID", incident.SysID, "Error", err.Error())
+		return nil
+	}
+
+	if len(articles) > constants.MaxKnowledgeArticleSuggestions {
+		articles = articles[:constants.MaxKnowledgeArticleSuggestions]
+	}
+
+	serviceNowURL := p.getConfiguration().ServiceNowBaseURL
+	suggestions := make([]*serializer.KnowledgeArticleSuggestion, 0, len(articles))
+	for _, article := range articles {
+		suggestions = append(suggestions, article.GetSuggestion(incident.ShortDescription, serviceNowURL))
+	}
+
+	if len(suggestions) == 0 || rootID == "" {
+		return suggestions
+	}
+
+	post := serializer.CreateKnowledgeSuggestionsPost(suggestions, incident.SysID, incident.Number, p.GetPluginURL())
+	post.UserId = p.botID
+	post.ChannelId = channelID
+	post.RootId = rootID
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+	}
+
+	return suggestions
+}
+
+// handleKnowledgeSolvedAction opens the dialog for marking an incident as solved by a suggested article
+func (p *Plugin) handleKnowledgeSolvedAction(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	incidentID, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordID].(string)
+	articleNumber, _ := postActionIntegrationRequest.Context[constants.ContextNameNumber].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, incidentID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	if err := p.openDialog(model.OpenDialogRequest{
+		TriggerId: postActionIntegrationRequest.TriggerId,
+		URL:       fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathKnowledgeSolvedDialog),
+		Dialog: model.Dialog{
+			CallbackId:       incidentID,
+			Title:            "Mark the incident as solved",
+			IntroductionText: fmt.Sprintf("A work note mentioning the article **%s** will be added to the incident.", articleNumber),
+			SubmitLabel:      "Submit",
+			State:            articleNumber,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Resolve the incident",
+					Name:        constants.DialogElementResolve,
+					Type:        "bool",
+					Optional:    true,
+				},
+				{
+					DisplayName: constants.FormattedFieldLabels[constants.FieldCloseCode],
+					Name:        constants.FieldCloseCode,
+					Type:        "text",
+					Optional:    true,
+					HelpText:    "Required for resolving the incident.",
+				},
+				{
+					DisplayName: constants.FormattedFieldLabels[constants.FieldCloseNotes],
+					Name:        constants.FieldCloseNotes,
+					Type:        "textarea",
+					Optional:    true,
+					Default:     fmt.Sprintf("Solved using the knowledge article %s.", articleNumber),
+					HelpText:    "Required for resolving the incident.",
+				},
+			},
+		},
+	}); err != nil {
+		p.API.LogError(constants.ErrorOpenDialog, "Incident ID", incidentID, "Error", err.Error())
+		response.EphemeralText = genericErrorMessage
+	}
+
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+// handleKnowledgeSolvedDialog adds a work note mentioning the article to the incident and resolves it, if chosen by the user
+func (p *Plugin) handleKnowledgeSolvedDialog(w http.ResponseWriter, r *http.Request) {
+	response := &model.SubmitDialogResponse{}
+	submitDialogRequest := &model.SubmitDialogRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&submitDialogRequest); err != nil {
+		p.API.LogError("Error decoding SubmitDialogRequest params: ", err.Error())
+		response.Error = genericErrorMessage
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	incidentID := submitDialogRequest.CallbackId
+	articleNumber := submitDialogRequest.State
+	resolve, _ := submitDialogRequest.Submission[constants.DialogElementResolve].(bool)
+	closeCode, _ := submitDialogRequest.Submission[constants.FieldCloseCode].(string)
+	closeNotes, _ := submitDialogRequest.Submission[constants.FieldCloseNotes].(string)
+	statePayload := &serializer.ServiceNowUpdateStatePayload{
+		State:      constants.IncidentStateResolved,
+		CloseCode:  closeCode,
+		CloseNotes: closeNotes,
+	}
+	if resolve {
+		if err := statePayload.Validate(); err != nil {
+			response.Error = err.Error()
+			p.returnSubmitDialogResponse(w, response)
+			return
+		}
+
+		// The required fields are checked before adding the work note, so that it is not added again on resubmitting the dialog
+		if fieldErrors := statePayload.GetMissingRequiredFields(constants.RecordTypeIncident); len(fieldErrors) > 0 {
+			response.Errors = getDialogFieldErrors(fieldErrors)
+			p.returnSubmitDialogResponse(w, response)
+			return
+		}
+	}
+
+	client := p.GetClientFromRequest(r)
+	workNote := &serializer.ServiceNowWorkNotePayload{
+		WorkNotes: fmt.Sprintf("Marked as solved by the knowledge article %s from Mattermost.", articleNumber),
+	}
+	if statusCode, err := client.AddWorkNote(constants.RecordTypeIncident, incidentID, workNote); err != nil {
+		p.API.LogError(constants.ErrorAddWorkNote, "Incident ID", incidentID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, submitDialogRequest.UserId, "")
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if !resolve {
+		p.returnSubmitDialogResponse(w, response)
+		return
+	}
+
+	if statusCode, err := client.UpdateStateOfRecordInServiceNow(constants.RecordTypeIncident, incidentID, statePayload); err != nil {
+		var serviceNowErr *ServiceNowError
+		if errors.As(err, &serviceNowErr) {
+			if fieldErrors := serviceNowErr.FieldErrors(); len(fieldErrors) > 0 {
+				p.API.LogDebug("Missing required fields for resolving the incident", "Incident ID", incidentID, "Error", err.Error())
+				response.Error = constants.APIErrorMissingRequiredFields
+				response.Errors = getDialogFieldErrors(fieldErrors)
+				p.returnSubmitDialogResponse(w, response)
+				return
+			}
+		}
+
+		p.API.LogError("Error in resolving the incident", "Incident ID", incidentID, "Error", err.Error())
+		response.Error = p.handleClientError(nil, nil, err, false, statusCode, submitDialogRequest.UserId, "")
+	}
+
+	p.returnSubmitDialogResponse(w, response)
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleKnowledge(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		params           []string
+		setupClient      func(*mock_plugin.Client)
+		expectedMessage  string
+		expectedResponse func(*model.Post) bool
+	}{
+		{
+			description: "HandleKnowledge: articles are listed with their snippets",
+			params:      []string{"reset", "password"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", "reset password").Return([]*serializer.ServiceNowKnowledgeArticle{
+					{SysID: testutils.GetServiceNowSysID(), Number: "KB0010001", ShortDescription: "Reset your password", Text: "<p>Open the <b>portal</b> to reset it.</p>"},
+				}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedResponse: func(post *model.Post) bool {
+				attachments := post.Attachments()
+				return len(attachments) == 1 && attachments[0].Text == "Open the portal to reset it." && len(attachments[0].Actions) == 2
+			},
+		},
+		{
+			description: "HandleKnowledge: no articles found",
+			params:      []string{"unknown"},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", "unknown").Return([]*serializer.ServiceNowKnowledgeArticle{}, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedResponse: func(post *model.Post) bool {
+				return post.Message == fmt.Sprintf(constants.KnowledgeArticleNotFoundMessage, "unknown")
+			},
+		},
+		{
+			description:     "HandleKnowledge: query is too short",
+			params:          []string{"ab"},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: fmt.Sprintf(constants.ErrorSearchTermThreshold, constants.CharacterThresholdForSearchingRecords),
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			defer mockAPI.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			if testCase.expectedResponse != nil {
+				mockAPI.On("SendEphemeralPost", testutils.GetID(), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					assert.True(t, testCase.expectedResponse(args.Get(1).(*model.Post)))
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleKnowledge(&plugin.Context{}, args, testCase.params, nil, false)
+			assert.Equal(t, testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
+
+func TestHandleKnowledgeArticleAction(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathKnowledgeArticleAction)
+	article := &serializer.ServiceNowRecord{
+		SysID:            testutils.GetServiceNowSysID(),
+		Number:           "KB0010001",
+		ShortDescription: "Reset your password",
+		Text:             "<h2>Steps</h2><ol><li>Open the <a href=\"/sp\">portal</a></li></ol>",
+	}
+	for name, test := range map[string]struct {
+		Action               string
+		ArticleID            string
+		SetupAPI             func(*plugintest.API)
+		SetupClient          func(*mock_plugin.Client)
+		ExpectedEphemeralMsg func(string) bool
+	}{
+		"article is viewed": {
+			Action:    constants.KnowledgeActionView,
+			ArticleID: testutils.GetServiceNowSysID(),
+			SetupAPI:  func(_ *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeKnowledge, testutils.GetServiceNowSysID()).Return(article, http.StatusOK, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return strings.HasSuffix(message, "## Steps\n\n1. Open the [portal](https://example.service-now.com/sp)")
+			},
+		},
+		"article is shared in the channel": {
+			Action:    constants.KnowledgeActionShare,
+			ArticleID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("GetUser", testutils.GetID()).Return(&model.User{Username: "mockUser"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					attachments := post.Attachments()
+					return post.ChannelId == testutils.GetChannelID() && len(attachments) == 1 && strings.Contains(attachments[0].Text, "## Steps")
+				})).Return(&model.Post{}, nil)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeKnowledge, testutils.GetServiceNowSysID()).Return(article, http.StatusOK, nil)
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == constants.KnowledgeArticleSharedMessage
+			},
+		},
+		"failed to get the article": {
+			Action:    constants.KnowledgeActionView,
+			ArticleID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeKnowledge, testutils.GetServiceNowSysID()).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == genericErrorMessage
+			},
+		},
+		"invalid article ID": {
+			Action:      constants.KnowledgeActionView,
+			ArticleID:   "invalid",
+			SetupAPI:    func(_ *plugintest.API) {},
+			SetupClient: func(_ *mock_plugin.Client) {},
+			ExpectedEphemeralMsg: func(message string) bool {
+				return message == genericErrorMessage
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			p.setConfiguration(&configuration{ServiceNowBaseURL: "https://example.service-now.com"})
+			client := mock_plugin.NewClient(t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(&serializer.ServiceNowRecord{}), "HandleNestedFields", func(_ *serializer.ServiceNowRecord, _ string) error {
+				return nil
+			})
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId:    testutils.GetID(),
+				ChannelId: testutils.GetChannelID(),
+				Context: map[string]interface{}{
+					constants.ContextNameRecordID: test.ArticleID,
+					constants.ContextNameAction:   test.Action,
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.True(t, test.ExpectedEphemeralMsg(response.EphemeralText), response.EphemeralText)
+		})
+	}
+}
+
+func TestSuggestKnowledgeArticles(t *testing.T) {
+	incident := &serializer.ServiceNowRecord{
+		SysID:            testutils.GetServiceNowSysID(),
+		Number:           "INC0010001",
+		ShortDescription: "Unable to reset password",
+	}
+	articles := []*serializer.ServiceNowKnowledgeArticle{
+		{SysID: "mockArticleID1", Number: "KB0010001", ShortDescription: "Reset your password", Text: "<p>Open the portal.</p>"},
+		{SysID: "mockArticleID2", Number: "KB0010002"},
+		{SysID: "mockArticleID3", Number: "KB0010003"},
+		{SysID: "mockArticleID4", Number: "KB0010004"},
+	}
+	for _, testCase := range []struct {
+		description         string
+		rootID              string
+		setupAPI            func(*plugintest.API)
+		setupClient         func(*mock_plugin.Client)
+		expectedSuggestions int
+	}{
+		{
+			description: "SuggestKnowledgeArticles: top articles are posted as a reply",
+			rootID:      "mockRootID",
+			setupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					attachments := post.Attachments()
+					return post.RootId == "mockRootID" && post.ChannelId == testutils.GetChannelID() && len(attachments) == constants.MaxKnowledgeArticleSuggestions &&
+						attachments[0].Actions[1].Integration.Context[constants.ContextNameRecordID] == testutils.GetServiceNowSysID()
+				})).Return(&model.Post{}, nil)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", incident.ShortDescription).Return(articles, http.StatusOK, nil)
+			},
+			expectedSuggestions: constants.MaxKnowledgeArticleSuggestions,
+		},
+		{
+			description: "SuggestKnowledgeArticles: incident post is not created",
+			setupAPI:    func(_ *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", incident.ShortDescription).Return(articles[:1], http.StatusOK, nil)
+			},
+			expectedSuggestions: 1,
+		},
+		{
+			description: "SuggestKnowledgeArticles: no articles found",
+			rootID:      "mockRootID",
+			setupAPI:    func(_ *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", incident.ShortDescription).Return([]*serializer.ServiceNowKnowledgeArticle{}, http.StatusOK, nil)
+			},
+		},
+		{
+			description: "SuggestKnowledgeArticles: failed to search for the articles",
+			rootID:      "mockRootID",
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("SearchKnowledgeArticlesInServiceNow", incident.ShortDescription).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			defer api.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			testCase.setupAPI(api)
+			testCase.setupClient(client)
+
+			suggestions := p.suggestKnowledgeArticles(client, testutils.GetChannelID(), testCase.rootID, incident)
+			assert.Len(t, suggestions, testCase.expectedSuggestions)
+		})
+	}
+}
+
+func TestHandleKnowledgeSolvedAction(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathKnowledgeSolvedAction)
+	for name, test := range map[string]struct {
+		IncidentID            string
+		SetupAPI              func(*plugintest.API)
+		ExpectedEphemeralText string
+	}{
+		"dialog is opened": {
+			IncidentID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("OpenInteractiveDialog", mock.MatchedBy(func(request model.OpenDialogRequest) bool {
+					return request.Dialog.CallbackId == testutils.GetServiceNowSysID() && request.Dialog.State == "KB0010001" && len(request.Dialog.Elements) == 3
+				})).Return(nil)
+			},
+		},
+		"invalid incident ID": {
+			IncidentID:            "invalid",
+			SetupAPI:              func(_ *plugintest.API) {},
+			ExpectedEphemeralText: genericErrorMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			test.SetupAPI(api)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId: testutils.GetID(),
+				Context: map[string]interface{}{
+					constants.ContextNameRecordID:  test.IncidentID,
+					constants.ContextNameArticleID: "mockArticleID",
+					constants.ContextNameNumber:    "KB0010001",
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedEphemeralText, response.EphemeralText)
+		})
+	}
+}
+
+func TestHandleKnowledgeSolvedDialog(t *testing.T) {
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathKnowledgeSolvedDialog)
+	workNote := &serializer.ServiceNowWorkNotePayload{WorkNotes: "Marked as solved by the knowledge article KB0010001 from Mattermost."}
+	for name, test := range map[string]struct {
+		Submission     map[string]any
+		SetupAPI       func(*plugintest.API)
+		SetupClient    func(*mock_plugin.Client)
+		ExpectedError  string
+		ExpectedErrors map[string]string
+	}{
+		"work note is added": {
+			Submission: map[string]any{},
+			SetupAPI:   func(_ *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("AddWorkNote", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), workNote).Return(http.StatusOK, nil)
+			},
+		},
+		"work note is added and the incident is resolved": {
+			Submission: map[string]any{
+				constants.DialogElementResolve: true,
+				constants.FieldCloseCode:       "Solved (Permanently)",
+				constants.FieldCloseNotes:      "Solved using the knowledge article KB0010001.",
+			},
+			SetupAPI: func(_ *plugintest.API) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("AddWorkNote", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), workNote).Return(http.StatusOK, nil)
+				client.On("UpdateStateOfRecordInServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), &serializer.ServiceNowUpdateStatePayload{
+					State:      constants.IncidentStateResolved,
+					CloseCode:  "Solved (Permanently)",
+					CloseNotes: "Solved using the knowledge article KB0010001.",
+				}).Return(http.StatusOK, nil)
+			},
+		},
+		"required fields for resolving are missing": {
+			Submission: map[string]any{
+				constants.DialogElementResolve: true,
+				constants.FieldCloseNotes:      "mockNotes",
+			},
+			SetupAPI:       func(_ *plugintest.API) {},
+			SetupClient:    func(_ *mock_plugin.Client) {},
+			ExpectedErrors: map[string]string{constants.FieldCloseCode: "Resolution code is required"},
+		},
+		"failed to add the work note": {
+			Submission: map[string]any{},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("AddWorkNote", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), workNote).Return(http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedError: genericErrorMessage,
+		},
+		"failed to resolve the incident": {
+			Submission: map[string]any{
+				constants.DialogElementResolve: true,
+				constants.FieldCloseCode:       "Solved (Permanently)",
+				constants.FieldCloseNotes:      "mockNotes",
+			},
+			SetupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("AddWorkNote", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), workNote).Return(http.StatusOK, nil)
+				client.On("UpdateStateOfRecordInServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID(), mock.AnythingOfType("*serializer.ServiceNowUpdateStatePayload")).Return(http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedError: genericErrorMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := setupPluginForCheckOAuthMiddleware(p, t)
+			test.SetupAPI(api)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+
+			body, err := json.Marshal(&model.SubmitDialogRequest{
+				UserId:     testutils.GetID(),
+				CallbackId: testutils.GetServiceNowSysID(),
+				State:      "KB0010001",
+				Submission: test.Submission,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.SubmitDialogResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedError, response.Error)
+			assert.Equal(t, test.ExpectedErrors, response.Errors)
+		})
+	}
+}
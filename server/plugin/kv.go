@@ -4,8 +4,15 @@
 package plugin
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/pkg/errors"
@@ -25,6 +32,14 @@ var ErrNotFound = kvstore.ErrNotFound
 type Store interface {
 	UserStore
 	OAuth2StateStore
+	PersonalSubscriptionStore
+	SubscriptionSettingsStore
+	ServiceAccountStore
+	LookupCacheStore
+	MuteStore
+	SLAWarningStore
+	InstanceStore
+	ApprovalStore
 }
 
 type UserStore interface {
@@ -32,6 +47,10 @@ type UserStore interface {
 	StoreUser(user *serializer.User) error
 	DeleteUser(mattermostUserID string) error
 	GetAllUsers() ([]*serializer.IncidentCaller, error)
+	LoadMattermostUserIDFromServiceNowUserID(serviceNowUserID string) (string, error)
+	LoadMattermostUserIDFromServiceNowUsername(serviceNowUsername string) (string, error)
+	MigrateUserIndexes() error
+	UserIndexesMigrated() bool
 	DeleteUserTokenOnEncryptionSecretChange()
 	DeleteAllUsersState() bool
 }
@@ -42,11 +61,82 @@ type OAuth2StateStore interface {
 	StoreOAuth2State(state string) error
 }
 
+// PersonalSubscriptionStore manages the personal subscriptions of the users
+type PersonalSubscriptionStore interface {
+	LoadPersonalSubscription(mattermostUserID string) (*serializer.PersonalSubscription, error)
+	StorePersonalSubscription(subscription *serializer.PersonalSubscription) error
+	DeletePersonalSubscription(mattermostUserID string) error
+	MarkPersonalNotificationSent(mattermostUserID, recordID, event string) (bool, error)
+}
+
+// SubscriptionSettingsStore manages the settings of the subscriptions which are not stored in ServiceNow
+type SubscriptionSettingsStore interface {
+	LoadSubscriptionSettings(instanceID, subscriptionID string) (*serializer.SubscriptionSettings, error)
+	StoreSubscriptionSettings(instanceID, subscriptionID string, settings *serializer.SubscriptionSettings) error
+	DeleteSubscriptionSettings(instanceID, subscriptionID string) error
+}
+
+// ServiceAccountStore manages the token stored by a system admin for the service account
+type ServiceAccountStore interface {
+	LoadServiceAccount() (*serializer.ServiceAccount, error)
+	StoreServiceAccount(serviceAccount *serializer.ServiceAccount) error
+	DeleteServiceAccount() error
+}
+
+// LookupCacheStore manages the results of the ServiceNow lookups shared by the cluster nodes
+type LookupCacheStore interface {
+	LoadLookup(key string) ([]byte, error)
+	StoreLookup(key string, data []byte, ttlSeconds int64) error
+}
+
+// MuteStore manages the muted notifications of the channels and the subscriptions
+type MuteStore interface {
+	LoadMute(key string) (*serializer.SubscriptionMute, error)
+	StoreMute(mute *serializer.SubscriptionMute) error
+	DeleteMute(key string) error
+	GetMutesWithSummary() ([]*serializer.SubscriptionMute, error)
+	AddMutedEvent(key string, event *serializer.MutedEvent) error
+	PopMutedEvents(key string) (*serializer.MutedEvents, error)
+}
+
+// SLAWarningStore keeps track of the SLA warnings posted in the channels
+type SLAWarningStore interface {
+	MarkSLAWarningSent(channelID, instanceID, slaID string, threshold int) (bool, error)
+}
+
+// InstanceStore manages the connections of the users to the instances other than the default one and the default instances of the channels
+type InstanceStore interface {
+	LoadInstanceUser(instanceID, mattermostUserID string) (*serializer.User, error)
+	StoreInstanceUser(instanceID string, user *serializer.User) error
+	DeleteInstanceUser(instanceID, mattermostUserID string) error
+	LoadInstanceMattermostUserIDFromServiceNowUserID(instanceID, serviceNowUserID string) (string, error)
+	LoadChannelInstance(channelID string) (string, error)
+	StoreChannelInstance(channelID, instanceID string) error
+}
+
+// ApprovalStore keeps track of the DMs posted to the approvers
+type ApprovalStore interface {
+	LoadApprovalPost(approvalID string) (*serializer.ApprovalPost, error)
+	StoreApprovalPost(approvalPost *serializer.ApprovalPost) error
+	DeleteApprovalPost(approvalID string) error
+}
+
 type pluginStore struct {
-	plugin   *Plugin
-	basicKV  kvstore.KVStore
-	oauth2KV kvstore.KVStore
-	userKV   kvstore.KVStore
+	plugin                 *Plugin
+	basicKV                kvstore.KVStore
+	oauth2KV               kvstore.KVStore
+	userKV                 kvstore.KVStore
+	serviceNowUserIDKV     kvstore.KVStore
+	serviceNowUsernameKV   kvstore.KVStore
+	personalSubscriptionKV kvstore.KVStore
+	subscriptionSettingsKV kvstore.KVStore
+	lookupCacheKV          kvstore.KVStore
+	muteKV                 kvstore.KVStore
+	mutedEventsKV          kvstore.KVStore
+	instanceUserKV         kvstore.KVStore
+	instanceUserIndexKV    kvstore.KVStore
+	channelInstanceKV      kvstore.KVStore
+	approvalPostKV         kvstore.KVStore
 }
 
 func (p *Plugin) NewStore(api plugin.API) Store {
@@ -56,6 +146,18 @@ func (p *Plugin) NewStore(api plugin.API) Store {
 		basicKV:  basicKV,
 		userKV:   kvstore.NewHashedKeyStore(basicKV, constants.UserKeyPrefix),
 		oauth2KV: kvstore.NewHashedKeyStore(kvstore.NewOneTimePluginStore(api, OAuth2KeyExpiration), constants.OAuth2KeyPrefix),
+
+		serviceNowUserIDKV:     kvstore.NewHashedKeyStore(basicKV, constants.ServiceNowUserIDKeyPrefix),
+		serviceNowUsernameKV:   kvstore.NewHashedKeyStore(basicKV, constants.ServiceNowUsernameKeyPrefix),
+		personalSubscriptionKV: kvstore.NewHashedKeyStore(basicKV, constants.PersonalSubscriptionKeyPrefix),
+		subscriptionSettingsKV: kvstore.NewHashedKeyStore(basicKV, constants.SubscriptionSettingsKeyPrefix),
+		lookupCacheKV:          kvstore.NewHashedKeyStore(basicKV, constants.LookupCacheKeyPrefix),
+		muteKV:                 kvstore.NewHashedKeyStore(basicKV, constants.MuteKeyPrefix),
+		mutedEventsKV:          kvstore.NewHashedKeyStore(basicKV, constants.MutedEventsKeyPrefix),
+		instanceUserKV:         kvstore.NewHashedKeyStore(basicKV, constants.InstanceUserKeyPrefix),
+		instanceUserIndexKV:    kvstore.NewHashedKeyStore(basicKV, constants.InstanceUserIndexKeyPrefix),
+		channelInstanceKV:      kvstore.NewHashedKeyStore(basicKV, constants.ChannelInstanceKeyPrefix),
+		approvalPostKV:         kvstore.NewHashedKeyStore(basicKV, constants.ApprovalPostKeyPrefix),
 	}
 }
 
@@ -69,8 +171,11 @@ func (s *pluginStore) LoadUser(mattermostUserID string) (*serializer.User, error
 }
 
 func (s *pluginStore) StoreUser(user *serializer.User) error {
-	err := kvstore.StoreJSON(s.userKV, user.MattermostUserID, user)
-	return err
+	if err := kvstore.StoreJSON(s.userKV, user.MattermostUserID, user); err != nil {
+		return err
+	}
+
+	return s.storeUserInIndexes(user)
 }
 
 func (s *pluginStore) DeleteUser(mattermostUserID string) error {
@@ -79,17 +184,125 @@ func (s *pluginStore) DeleteUser(mattermostUserID string) error {
 		return err
 	}
 
-	err = s.userKV.Delete(u.MattermostUserID)
-	return err
+	if err = s.userKV.Delete(u.MattermostUserID); err != nil {
+		return err
+	}
+
+	if err = s.updateConnectedUsersIndex(func(index map[string]*serializer.IncidentCaller) {
+		delete(index, u.MattermostUserID)
+	}); err != nil {
+		return err
+	}
+
+	if u.ServiceNowUser == nil {
+		return nil
+	}
+
+	// Don't delete the indexes if the ServiceNow user has been connected by some other Mattermost user later
+	if u.ServiceNowUser.UserID != "" {
+		if indexedUserID, loadErr := s.LoadMattermostUserIDFromServiceNowUserID(u.ServiceNowUser.UserID); loadErr == nil && indexedUserID == u.MattermostUserID {
+			if err = s.serviceNowUserIDKV.Delete(u.ServiceNowUser.UserID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if u.ServiceNowUser.Username != "" {
+		if indexedUserID, loadErr := s.LoadMattermostUserIDFromServiceNowUsername(u.ServiceNowUser.Username); loadErr == nil && indexedUserID == u.MattermostUserID {
+			if err = s.serviceNowUsernameKV.Delete(strings.ToLower(u.ServiceNowUser.Username)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *pluginStore) LoadMattermostUserIDFromServiceNowUserID(serviceNowUserID string) (string, error) {
+	data, err := s.serviceNowUserIDKV.Load(serviceNowUserID)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (s *pluginStore) LoadMattermostUserIDFromServiceNowUsername(serviceNowUsername string) (string, error) {
+	data, err := s.serviceNowUsernameKV.Load(strings.ToLower(serviceNowUsername))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
 }
 
+// GetAllUsers returns the connected users from the index of the connected users.
+// The KV store is scanned instead until the users connected before the index was added have been added to it.
 func (s *pluginStore) GetAllUsers() ([]*serializer.IncidentCaller, error) {
-	page := 0
 	users := []*serializer.IncidentCaller{}
+	if s.UserIndexesMigrated() {
+		index := map[string]*serializer.IncidentCaller{}
+		if err := kvstore.LoadJSON(s.basicKV, constants.ConnectedUsersIndexKey, &index); err != nil && err != ErrNotFound {
+			return nil, err
+		}
+
+		for _, user := range index {
+			users = append(users, user)
+		}
+	} else if err := s.forEachStoredUser(func(user *serializer.User) {
+		users = append(users, getIncidentCaller(user))
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return users, nil
+}
+
+// MigrateUserIndexes backfills the index of the connected users and the indexes of the ServiceNow users with the users connected before the indexes were added.
+// It is run only once, when the plugin is activated.
+func (s *pluginStore) MigrateUserIndexes() error {
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.UserIndexesMigrationMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mutex for the migration of the user indexes")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, err = s.basicKV.Load(constants.UserIndexesMigrationKey); err == nil {
+		return nil
+	} else if err != ErrNotFound {
+		return err
+	}
+
+	if err = s.forEachStoredUser(func(user *serializer.User) {
+		if indexErr := s.storeUserInIndexes(user); indexErr != nil {
+			s.plugin.API.LogWarn("Unable to add the user to the ServiceNow user indexes", "UserID", user.MattermostUserID, "Error", indexErr.Error())
+		}
+	}); err != nil {
+		return err
+	}
+
+	return s.basicKV.Store(constants.UserIndexesMigrationKey, []byte{1})
+}
+
+// UserIndexesMigrated returns true if the users connected before the indexes were added have been added to them
+func (s *pluginStore) UserIndexesMigrated() bool {
+	_, err := s.basicKV.Load(constants.UserIndexesMigrationKey)
+	return err == nil
+}
+
+// forEachStoredUser pages through all the keys in the KV store and calls the given function for each stored user
+func (s *pluginStore) forEachStoredUser(f func(user *serializer.User)) error {
+	page := 0
 	for {
 		kvList, err := s.plugin.API.KVList(page, constants.DefaultPerPage)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		for _, key := range kvList {
@@ -106,11 +319,7 @@ func (s *pluginStore) GetAllUsers() ([]*serializer.IncidentCaller, error) {
 					continue
 				}
 
-				users = append(users, &serializer.IncidentCaller{
-					MattermostUserID: user.MattermostUserID,
-					Username:         user.Username,
-					ServiceNowUser:   user.ServiceNowUser,
-				})
+				f(user)
 			}
 		}
 
@@ -121,7 +330,60 @@ func (s *pluginStore) GetAllUsers() ([]*serializer.IncidentCaller, error) {
 		page++
 	}
 
-	return users, nil
+	return nil
+}
+
+// storeUserInIndexes maintains the index of the connected users and the reverse indexes for mapping the ServiceNow users to the Mattermost users
+func (s *pluginStore) storeUserInIndexes(user *serializer.User) error {
+	if err := s.updateConnectedUsersIndex(func(index map[string]*serializer.IncidentCaller) {
+		index[user.MattermostUserID] = getIncidentCaller(user)
+	}); err != nil {
+		return err
+	}
+
+	if user.ServiceNowUser == nil {
+		return nil
+	}
+
+	if user.ServiceNowUser.UserID != "" {
+		if err := s.serviceNowUserIDKV.Store(user.ServiceNowUser.UserID, []byte(user.MattermostUserID)); err != nil {
+			return err
+		}
+	}
+
+	if user.ServiceNowUser.Username != "" {
+		if err := s.serviceNowUsernameKV.Store(strings.ToLower(user.ServiceNowUser.Username), []byte(user.MattermostUserID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *pluginStore) updateConnectedUsersIndex(update func(index map[string]*serializer.IncidentCaller)) error {
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.ConnectedUsersMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mutex for the connected users")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	index := map[string]*serializer.IncidentCaller{}
+	if err = kvstore.LoadJSON(s.basicKV, constants.ConnectedUsersIndexKey, &index); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	update(index)
+	return kvstore.StoreJSON(s.basicKV, constants.ConnectedUsersIndexKey, index)
+}
+
+func getIncidentCaller(user *serializer.User) *serializer.IncidentCaller {
+	return &serializer.IncidentCaller{
+		MattermostUserID: user.MattermostUserID,
+		Username:         user.Username,
+		ServiceNowUser:   user.ServiceNowUser,
+	}
 }
 
 func (s *pluginStore) DeleteUserTokenOnEncryptionSecretChange() {
@@ -144,9 +406,18 @@ func (s *pluginStore) DeleteUserTokenOnEncryptionSecretChange() {
 	for _, user := range users {
 		if err := s.DeleteUser(user.MattermostUserID); err != nil {
 			s.plugin.API.LogWarn("Unable to delete a user on encryption secret change", "UserID", user.MattermostUserID, "Error", err.Error())
-			continue
 		}
 	}
+
+	// The users connected only to the other instances are not present in the list of the users connected to the default instance
+	if err := s.deleteAllInstanceUsers(); err != nil {
+		s.plugin.API.LogError("Unable to delete the connections of the users to the instances on encryption secret change", "Error", err.Error())
+	}
+
+	// The stored token of the service account can't be decrypted using the new secret
+	if err := s.DeleteServiceAccount(); err != nil {
+		s.plugin.API.LogWarn("Unable to delete the service account on encryption secret change", "Error", err.Error())
+	}
 }
 
 /*
@@ -199,3 +470,350 @@ func (s *pluginStore) VerifyOAuth2State(state string) error {
 func (s *pluginStore) StoreOAuth2State(state string) error {
 	return s.oauth2KV.StoreTTL(state, []byte(state), oAuth2StateTimeToLive)
 }
+
+func (s *pluginStore) LoadPersonalSubscription(mattermostUserID string) (*serializer.PersonalSubscription, error) {
+	subscription := serializer.PersonalSubscription{}
+	if err := kvstore.LoadJSON(s.personalSubscriptionKV, mattermostUserID, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (s *pluginStore) StorePersonalSubscription(subscription *serializer.PersonalSubscription) error {
+	return kvstore.StoreJSON(s.personalSubscriptionKV, subscription.MattermostUserID, subscription)
+}
+
+func (s *pluginStore) DeletePersonalSubscription(mattermostUserID string) error {
+	return s.personalSubscriptionKV.Delete(mattermostUserID)
+}
+
+// MarkPersonalNotificationSent returns false if the user has already been notified about the same event of the record recently.
+// ServiceNow sends a separate notification for each Mattermost server and event of a change, so this prevents duplicate DMs.
+func (s *pluginStore) MarkPersonalNotificationSent(mattermostUserID, recordID, event string) (bool, error) {
+	key := fmt.Sprintf("%s%x", constants.PersonalNotificationKeyPrefix, sha256.Sum256([]byte(mattermostUserID+recordID+event)))
+	return s.basicKV.StoreWithOptions(key[:50], []byte{1}, model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: constants.PersonalNotificationDedupeSeconds,
+	})
+}
+
+// MarkSLAWarningSent marks the warning for a threshold of an SLA of an instance as posted in the channel.
+// It returns false if the warning was already posted.
+func (s *pluginStore) MarkSLAWarningSent(channelID, instanceID, slaID string, threshold int) (bool, error) {
+	key := fmt.Sprintf("%s%x", constants.SLAWarningKeyPrefix, sha256.Sum256([]byte(fmt.Sprintf("%s%s%d", channelID, serializer.GetInstanceKey(instanceID, slaID), threshold))))
+	return s.basicKV.StoreWithOptions(key[:50], []byte{1}, model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: constants.SLAWarningDedupeSeconds,
+	})
+}
+
+func (s *pluginStore) LoadSubscriptionSettings(instanceID, subscriptionID string) (*serializer.SubscriptionSettings, error) {
+	settings := serializer.SubscriptionSettings{}
+	if err := kvstore.LoadJSON(s.subscriptionSettingsKV, serializer.GetInstanceKey(instanceID, subscriptionID), &settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (s *pluginStore) StoreSubscriptionSettings(instanceID, subscriptionID string, settings *serializer.SubscriptionSettings) error {
+	return kvstore.StoreJSON(s.subscriptionSettingsKV, serializer.GetInstanceKey(instanceID, subscriptionID), settings)
+}
+
+func (s *pluginStore) DeleteSubscriptionSettings(instanceID, subscriptionID string) error {
+	return s.subscriptionSettingsKV.Delete(serializer.GetInstanceKey(instanceID, subscriptionID))
+}
+
+func (s *pluginStore) LoadServiceAccount() (*serializer.ServiceAccount, error) {
+	serviceAccount := serializer.ServiceAccount{}
+	if err := kvstore.LoadJSON(s.basicKV, constants.ServiceAccountKey, &serviceAccount); err != nil {
+		return nil, err
+	}
+
+	return &serviceAccount, nil
+}
+
+func (s *pluginStore) StoreServiceAccount(serviceAccount *serializer.ServiceAccount) error {
+	return kvstore.StoreJSON(s.basicKV, constants.ServiceAccountKey, serviceAccount)
+}
+
+func (s *pluginStore) DeleteServiceAccount() error {
+	return s.basicKV.Delete(constants.ServiceAccountKey)
+}
+
+func getInstanceUserKey(instanceID, mattermostUserID string) string {
+	return fmt.Sprintf("%s/%s", instanceID, mattermostUserID)
+}
+
+func (s *pluginStore) LoadInstanceUser(instanceID, mattermostUserID string) (*serializer.User, error) {
+	user := serializer.User{}
+	if err := kvstore.LoadJSON(s.instanceUserKV, getInstanceUserKey(instanceID, mattermostUserID), &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// StoreInstanceUser stores the connection of the user to an instance other than the default one.
+// The connected users are indexed by their ServiceNow IDs per instance as these are only unique within an instance.
+func (s *pluginStore) StoreInstanceUser(instanceID string, user *serializer.User) error {
+	if err := kvstore.StoreJSON(s.instanceUserKV, getInstanceUserKey(instanceID, user.MattermostUserID), user); err != nil {
+		return err
+	}
+
+	if user.ServiceNowUser == nil || user.ServiceNowUser.UserID == "" {
+		return nil
+	}
+
+	return s.instanceUserIndexKV.Store(getInstanceUserKey(instanceID, user.ServiceNowUser.UserID), []byte(user.MattermostUserID))
+}
+
+func (s *pluginStore) DeleteInstanceUser(instanceID, mattermostUserID string) error {
+	user, err := s.LoadInstanceUser(instanceID, mattermostUserID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if err = s.instanceUserKV.Delete(getInstanceUserKey(instanceID, mattermostUserID)); err != nil {
+		return err
+	}
+
+	if user == nil || user.ServiceNowUser == nil || user.ServiceNowUser.UserID == "" {
+		return nil
+	}
+
+	// Don't delete the index if the ServiceNow user has been connected by some other Mattermost user later
+	if indexedUserID, loadErr := s.LoadInstanceMattermostUserIDFromServiceNowUserID(instanceID, user.ServiceNowUser.UserID); loadErr == nil && indexedUserID == mattermostUserID {
+		return s.instanceUserIndexKV.Delete(getInstanceUserKey(instanceID, user.ServiceNowUser.UserID))
+	}
+
+	return nil
+}
+
+func (s *pluginStore) LoadInstanceMattermostUserIDFromServiceNowUserID(instanceID, serviceNowUserID string) (string, error) {
+	data, err := s.instanceUserIndexKV.Load(getInstanceUserKey(instanceID, serviceNowUserID))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// deleteAllInstanceUsers deletes the connections of all the users to the instances other than the default one.
+// The keys are listed before deleting them as the deletion changes the pages of the KV store.
+func (s *pluginStore) deleteAllInstanceUsers() error {
+	var instanceUserKeys []string
+	for page := 0; ; page++ {
+		kvList, err := s.plugin.API.KVList(page, constants.DefaultPerPage)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range kvList {
+			if strings.HasPrefix(key, constants.InstanceUserKeyPrefix) {
+				instanceUserKeys = append(instanceUserKeys, strings.TrimPrefix(key, constants.InstanceUserKeyPrefix))
+			}
+		}
+
+		if len(kvList) < constants.DefaultPerPage {
+			break
+		}
+	}
+
+	for _, key := range instanceUserKeys {
+		decodedKey, err := decodeKey(key)
+		if err != nil {
+			s.plugin.API.LogError("Unable to decode key", "Key", key, "Error", err.Error())
+			continue
+		}
+
+		instanceID, mattermostUserID, _ := strings.Cut(decodedKey, "/")
+		if err := s.DeleteInstanceUser(instanceID, mattermostUserID); err != nil {
+			s.plugin.API.LogWarn("Unable to delete the connection of a user to an instance", "UserID", mattermostUserID, "Instance", instanceID, "Error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *pluginStore) LoadChannelInstance(channelID string) (string, error) {
+	data, err := s.channelInstanceKV.Load(channelID)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (s *pluginStore) StoreChannelInstance(channelID, instanceID string) error {
+	return s.channelInstanceKV.Store(channelID, []byte(instanceID))
+}
+
+func (s *pluginStore) LoadApprovalPost(approvalID string) (*serializer.ApprovalPost, error) {
+	approvalPost := serializer.ApprovalPost{}
+	if err := kvstore.LoadJSON(s.approvalPostKV, approvalID, &approvalPost); err != nil {
+		return nil, err
+	}
+
+	return &approvalPost, nil
+}
+
+func (s *pluginStore) StoreApprovalPost(approvalPost *serializer.ApprovalPost) error {
+	return kvstore.StoreJSON(s.approvalPostKV, approvalPost.ApprovalID, approvalPost)
+}
+
+func (s *pluginStore) DeleteApprovalPost(approvalID string) error {
+	return s.approvalPostKV.Delete(approvalID)
+}
+
+func (s *pluginStore) LoadLookup(key string) ([]byte, error) {
+	return s.lookupCacheKV.Load(key)
+}
+
+func (s *pluginStore) StoreLookup(key string, data []byte, ttlSeconds int64) error {
+	return s.lookupCacheKV.StoreTTL(key, data, ttlSeconds)
+}
+
+func (s *pluginStore) LoadMute(key string) (*serializer.SubscriptionMute, error) {
+	mute := serializer.SubscriptionMute{}
+	if err := kvstore.LoadJSON(s.muteKV, key, &mute); err != nil {
+		return nil, err
+	}
+
+	return &mute, nil
+}
+
+// StoreMute stores the mute until it expires. The mutes with a summary are also added to an index
+// so that their summary can be posted after they expire.
+func (s *pluginStore) StoreMute(mute *serializer.SubscriptionMute) error {
+	if err := s.updateMutesWithSummaryIndex(func(index map[string]*serializer.SubscriptionMute) {
+		if mute.Summary {
+			index[mute.Key()] = mute
+		} else {
+			delete(index, mute.Key())
+		}
+	}); err != nil {
+		return err
+	}
+
+	// The notifications muted for the summary of a previous mute would never be posted once the mute has no summary
+	if !mute.Summary {
+		if err := s.deleteMutedEvents(mute.Key()); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(mute)
+	if err != nil {
+		return err
+	}
+
+	ttlSeconds := int64(math.Ceil(time.Until(time.UnixMilli(mute.MutedUntil)).Seconds()))
+	if ttlSeconds <= 0 {
+		return errors.New("mute has already expired")
+	}
+
+	return s.muteKV.StoreTTL(mute.Key(), data, ttlSeconds)
+}
+
+func (s *pluginStore) DeleteMute(key string) error {
+	return s.muteKV.Delete(key)
+}
+
+func (s *pluginStore) GetMutesWithSummary() ([]*serializer.SubscriptionMute, error) {
+	index := map[string]*serializer.SubscriptionMute{}
+	if err := kvstore.LoadJSON(s.basicKV, constants.MutesWithSummaryIndexKey, &index); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	mutes := make([]*serializer.SubscriptionMute, 0, len(index))
+	for _, mute := range index {
+		mutes = append(mutes, mute)
+	}
+
+	return mutes, nil
+}
+
+// AddMutedEvent stores a muted notification for the summary of the mute
+func (s *pluginStore) AddMutedEvent(key string, event *serializer.MutedEvent) error {
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.MutedEventsMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mutex for the muted events")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	mutedEvents := serializer.MutedEvents{}
+	if err = kvstore.LoadJSON(s.mutedEventsKV, key, &mutedEvents); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	mutedEvents.Count++
+	if len(mutedEvents.Events) < constants.MaxMutedEventsInSummary {
+		mutedEvents.Events = append(mutedEvents.Events, event)
+	}
+
+	return kvstore.StoreJSON(s.mutedEventsKV, key, mutedEvents)
+}
+
+// PopMutedEvents returns and deletes the notifications muted for the summary of the mute, and removes the mute from the index of the mutes with a summary
+func (s *pluginStore) PopMutedEvents(key string) (*serializer.MutedEvents, error) {
+	if err := s.updateMutesWithSummaryIndex(func(index map[string]*serializer.SubscriptionMute) {
+		delete(index, key)
+	}); err != nil {
+		return nil, err
+	}
+
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.MutedEventsMutexKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create mutex for the muted events")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	mutedEvents := serializer.MutedEvents{}
+	if err = kvstore.LoadJSON(s.mutedEventsKV, key, &mutedEvents); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	if err = s.mutedEventsKV.Delete(key); err != nil {
+		return nil, err
+	}
+
+	return &mutedEvents, nil
+}
+
+func (s *pluginStore) deleteMutedEvents(key string) error {
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.MutedEventsMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mutex for the muted events")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return s.mutedEventsKV.Delete(key)
+}
+
+func (s *pluginStore) updateMutesWithSummaryIndex(update func(index map[string]*serializer.SubscriptionMute)) error {
+	mutex, err := cluster.NewMutex(s.plugin.API, constants.MutesWithSummaryMutexKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to create mutex for the mutes with summary")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	index := map[string]*serializer.SubscriptionMute{}
+	if err = kvstore.LoadJSON(s.basicKV, constants.MutesWithSummaryIndexKey, &index); err != nil && err != ErrNotFound {
+		return err
+	}
+
+	update(index)
+	return kvstore.StoreJSON(s.basicKV, constants.MutesWithSummaryIndexKey, index)
+}
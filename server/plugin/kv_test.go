@@ -4,11 +4,17 @@
 package plugin
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
@@ -81,7 +87,11 @@ func TestStoreUser(t *testing.T) {
 	} {
 		t.Run(test.description, func(t *testing.T) {
 			defer monkey.UnpatchAll()
-			ps := pluginStore{}
+			ps := pluginStore{plugin: &Plugin{}}
+			patchClusterMutex()
+			monkey.Patch(kvstore.LoadJSON, func(_ kvstore.KVStore, _ string, _ interface{}) error {
+				return nil
+			})
 			test.setupTest()
 
 			err := ps.StoreUser(&serializer.User{})
@@ -90,11 +100,167 @@ func TestStoreUser(t *testing.T) {
 	}
 }
 
+func TestGetAllUsers(t *testing.T) {
+	for _, test := range []struct {
+		description   string
+		migrated      bool
+		setupAPI      func(*plugintest.API)
+		expectedUsers []*serializer.IncidentCaller
+		expectedError bool
+	}{
+		{
+			description: "Users are loaded from the index of the connected users sorted by username",
+			migrated:    true,
+			setupAPI:    func(api *plugintest.API) {},
+			expectedUsers: []*serializer.IncidentCaller{
+				{MattermostUserID: "mockUserID1", Username: "a"},
+				{MattermostUserID: "mockUserID2", Username: "b"},
+			},
+		},
+		{
+			description: "Users are loaded from the KV store sorted by username when the index has not been backfilled",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVList", 0, constants.DefaultPerPage).Return([]string{
+					"user_" + base64.StdEncoding.EncodeToString([]byte("mockUserID2")),
+					constants.UserIndexesMigrationKey,
+					"user_" + base64.StdEncoding.EncodeToString([]byte("mockUserID1")),
+				}, nil)
+			},
+			expectedUsers: []*serializer.IncidentCaller{
+				{MattermostUserID: "mockUserID1", Username: "a"},
+				{MattermostUserID: "mockUserID2", Username: "b"},
+			},
+		},
+		{
+			description: "Error in listing the keys",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVList", 0, constants.DefaultPerPage).Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			api := &plugintest.API{}
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			ps := new(pluginStore)
+			ps.plugin = &Plugin{}
+			ps.plugin.SetAPI(api)
+			monkey.PatchInstanceMethod(reflect.TypeOf(ps), "UserIndexesMigrated", func(*pluginStore) bool {
+				return test.migrated
+			})
+			monkey.Patch(kvstore.LoadJSON, func(_ kvstore.KVStore, key string, v interface{}) error {
+				index := v.(*map[string]*serializer.IncidentCaller)
+				*index = map[string]*serializer.IncidentCaller{
+					"mockUserID2": {MattermostUserID: "mockUserID2", Username: "b"},
+					"mockUserID1": {MattermostUserID: "mockUserID1", Username: "a"},
+				}
+				return nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadUser", func(_ *pluginStore, mattermostUserID string) (*serializer.User, error) {
+				usernames := map[string]string{"mockUserID1": "a", "mockUserID2": "b"}
+				return &serializer.User{MattermostUserID: mattermostUserID, Username: usernames[mattermostUserID]}, nil
+			})
+
+			users, err := ps.GetAllUsers()
+			if test.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, users)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedUsers, users)
+		})
+	}
+}
+
+func TestMigrateUserIndexes(t *testing.T) {
+	for _, test := range []struct {
+		description      string
+		setupAPI         func(*plugintest.API)
+		expectIndexStore bool
+	}{
+		{
+			description: "Migration has already been done",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", constants.UserIndexesMigrationKey).Return([]byte{1}, nil)
+			},
+		},
+		{
+			description: "Users are added to the indexes",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", constants.UserIndexesMigrationKey).Return(nil, nil)
+				api.On("KVList", 0, constants.DefaultPerPage).Return([]string{"user_" + base64.StdEncoding.EncodeToString([]byte(testutils.GetID())), constants.UserIndexesMigrationKey}, nil)
+				api.On("KVGet", constants.ConnectedUsersIndexKey).Return(nil, nil)
+				api.On("KVSet", constants.UserIndexesMigrationKey, []byte{1}).Return(nil)
+			},
+			expectIndexStore: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			api := &plugintest.API{}
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			ps := new(pluginStore)
+			ps.plugin = &Plugin{}
+			ps.plugin.SetAPI(api)
+			ps.basicKV = kvstore.NewPluginStore(api)
+			ps.userKV = kvstore.NewHashedKeyStore(ps.basicKV, constants.UserKeyPrefix)
+			ps.serviceNowUserIDKV = kvstore.NewHashedKeyStore(ps.basicKV, constants.ServiceNowUserIDKeyPrefix)
+			ps.serviceNowUsernameKV = kvstore.NewHashedKeyStore(ps.basicKV, constants.ServiceNowUsernameKeyPrefix)
+			patchClusterMutex()
+
+			monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadUser", func(*pluginStore, string) (*serializer.User, error) {
+				return testutils.GetSerializerUser(), nil
+			})
+
+			storedKeys := []string{}
+			monkey.PatchInstanceMethod(reflect.TypeOf(ps.userKV), "Store", func(_ *kvstore.HashedKeyStore, key string, _ []byte) error {
+				storedKeys = append(storedKeys, key)
+				return nil
+			})
+			monkey.Patch(kvstore.StoreJSON, func(_ kvstore.KVStore, key string, _ interface{}) error {
+				storedKeys = append(storedKeys, key)
+				return nil
+			})
+
+			err := ps.MigrateUserIndexes()
+			assert.NoError(t, err)
+			if test.expectIndexStore {
+				assert.Contains(t, storedKeys, testutils.GetServiceNowSysID())
+				assert.Contains(t, storedKeys, constants.ConnectedUsersIndexKey)
+			} else {
+				assert.Empty(t, storedKeys)
+			}
+		})
+	}
+}
+
+func patchClusterMutex() {
+	var mutex *cluster.Mutex
+	monkey.PatchInstanceMethod(reflect.TypeOf(mutex), "Lock", func(*cluster.Mutex) {})
+	monkey.PatchInstanceMethod(reflect.TypeOf(mutex), "Unlock", func(*cluster.Mutex) {})
+}
+
 func TestDeleteUser(t *testing.T) {
 	defer monkey.UnpatchAll()
 	ps := new(pluginStore)
 	p := Plugin{}
 	ps.userKV = kvstore.NewHashedKeyStore(kvstore.NewPluginStore(p.API), constants.UserKeyPrefix)
+	ps.serviceNowUserIDKV = kvstore.NewHashedKeyStore(kvstore.NewPluginStore(p.API), constants.ServiceNowUserIDKeyPrefix)
+	ps.plugin = &p
+	patchClusterMutex()
+	monkey.Patch(kvstore.LoadJSON, func(_ kvstore.KVStore, _ string, _ interface{}) error {
+		return nil
+	})
+	monkey.Patch(kvstore.StoreJSON, func(_ kvstore.KVStore, _ string, _ interface{}) error {
+		return nil
+	})
 	for _, test := range []struct {
 		description   string
 		setupTest     func()
@@ -115,6 +281,23 @@ func TestDeleteUser(t *testing.T) {
 				monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadUser", func(*pluginStore, string) (*serializer.User, error) {
 					return testutils.GetSerializerUser(), nil
 				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadMattermostUserIDFromServiceNowUserID", func(*pluginStore, string) (string, error) {
+					return testutils.GetID(), nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(ps.userKV), "Delete", func(*kvstore.HashedKeyStore, string) error {
+					return nil
+				})
+			},
+		},
+		{
+			description: "User is deleted but the ServiceNow user is indexed for some other user",
+			setupTest: func() {
+				monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadUser", func(*pluginStore, string) (*serializer.User, error) {
+					return testutils.GetSerializerUser(), nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(ps), "LoadMattermostUserIDFromServiceNowUserID", func(*pluginStore, string) (string, error) {
+					return "mockOtherUserID", nil
+				})
 				monkey.PatchInstanceMethod(reflect.TypeOf(ps.userKV), "Delete", func(*kvstore.HashedKeyStore, string) error {
 					return nil
 				})
@@ -148,6 +331,122 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
+func TestDeleteInstanceUser(t *testing.T) {
+	userKey := constants.InstanceUserKeyPrefix + base64.StdEncoding.EncodeToString([]byte("hr/"+testutils.GetID()))
+	indexKey := constants.InstanceUserIndexKeyPrefix + base64.StdEncoding.EncodeToString([]byte("hr/"+testutils.GetServiceNowSysID()))
+	userData, _ := json.Marshal(&serializer.User{
+		MattermostUserID: testutils.GetID(),
+		ServiceNowUser:   &serializer.ServiceNowUser{UserID: testutils.GetServiceNowSysID()},
+	})
+	for _, test := range []struct {
+		description   string
+		setupAPI      func(*plugintest.API)
+		expectedError bool
+	}{
+		{
+			description: "User and the index of the ServiceNow user are deleted",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", userKey).Return(userData, nil)
+				api.On("KVDelete", userKey).Return(nil)
+				api.On("KVGet", indexKey).Return([]byte(testutils.GetID()), nil)
+				api.On("KVDelete", indexKey).Return(nil)
+			},
+		},
+		{
+			description: "Index of the ServiceNow user connected by some other user is not deleted",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", userKey).Return(userData, nil)
+				api.On("KVDelete", userKey).Return(nil)
+				api.On("KVGet", indexKey).Return([]byte("mockOtherUserID"), nil)
+			},
+		},
+		{
+			description: "User is not connected to the instance",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", userKey).Return(nil, nil)
+				api.On("KVDelete", userKey).Return(nil)
+			},
+		},
+		{
+			description: "Error in loading the user",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVGet", userKey).Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			api := &plugintest.API{}
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			p := &Plugin{}
+			ps := p.NewStore(api)
+
+			err := ps.DeleteInstanceUser("hr", testutils.GetID())
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteAllInstanceUsers(t *testing.T) {
+	for _, test := range []struct {
+		description     string
+		setupAPI        func(*plugintest.API)
+		expectedDeleted []string
+		expectedError   bool
+	}{
+		{
+			description: "Connections of the users to all the instances are deleted",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVList", 0, constants.DefaultPerPage).Return([]string{
+					constants.InstanceUserKeyPrefix + base64.StdEncoding.EncodeToString([]byte("hr/mockUserID1")),
+					"user_" + base64.StdEncoding.EncodeToString([]byte("mockUserID2")),
+					constants.InstanceUserKeyPrefix + base64.StdEncoding.EncodeToString([]byte("finance/mockUserID2")),
+				}, nil)
+			},
+			expectedDeleted: []string{"hr/mockUserID1", "finance/mockUserID2"},
+		},
+		{
+			description: "Error in listing the keys",
+			setupAPI: func(api *plugintest.API) {
+				api.On("KVList", 0, constants.DefaultPerPage).Return(nil, testutils.GetInternalServerAppError())
+			},
+			expectedError: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			api := &plugintest.API{}
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			ps := new(pluginStore)
+			ps.plugin = &Plugin{}
+			ps.plugin.SetAPI(api)
+			var deleted []string
+			monkey.PatchInstanceMethod(reflect.TypeOf(ps), "DeleteInstanceUser", func(_ *pluginStore, instanceID, mattermostUserID string) error {
+				deleted = append(deleted, getInstanceUserKey(instanceID, mattermostUserID))
+				return nil
+			})
+
+			err := ps.deleteAllInstanceUsers()
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDeleted, deleted)
+		})
+	}
+}
+
 func TestVerifyOAuth2State(t *testing.T) {
 	ps := new(pluginStore)
 	p := Plugin{}
@@ -190,3 +489,113 @@ func TestVerifyOAuth2State(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreMute(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		summary              bool
+		expectedMutedDeleted bool
+	}{
+		{
+			description: "Mute with a summary keeps the muted notifications",
+			summary:     true,
+		},
+		{
+			description:          "Mute without a summary deletes the muted notifications of the previous mute",
+			expectedMutedDeleted: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			api := &plugintest.API{}
+			defer api.AssertExpectations(t)
+			api.On("KVSetWithExpiry", mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"), mock.AnythingOfType("int64")).Return(nil).Once()
+			if test.expectedMutedDeleted {
+				api.On("KVDelete", mock.AnythingOfType("string")).Return(nil).Once()
+			}
+
+			p := &Plugin{}
+			p.SetAPI(api)
+			basicKV := kvstore.NewPluginStore(api)
+			ps := pluginStore{
+				plugin:        p,
+				basicKV:       basicKV,
+				muteKV:        kvstore.NewHashedKeyStore(basicKV, constants.MuteKeyPrefix),
+				mutedEventsKV: kvstore.NewHashedKeyStore(basicKV, constants.MutedEventsKeyPrefix),
+			}
+			patchClusterMutex()
+			monkey.Patch(kvstore.LoadJSON, func(_ kvstore.KVStore, _ string, _ interface{}) error {
+				return ErrNotFound
+			})
+
+			var index map[string]*serializer.SubscriptionMute
+			monkey.Patch(kvstore.StoreJSON, func(_ kvstore.KVStore, key string, v interface{}) error {
+				assert.Equal(t, constants.MutesWithSummaryIndexKey, key)
+				index = v.(map[string]*serializer.SubscriptionMute)
+				return nil
+			})
+
+			mute := &serializer.SubscriptionMute{
+				ChannelID:  testutils.GetChannelID(),
+				MutedUntil: time.Now().Add(time.Hour).UnixMilli(),
+				Summary:    test.summary,
+			}
+
+			err := ps.StoreMute(mute)
+			assert.NoError(t, err)
+			assert.Equal(t, test.summary, index[mute.Key()] != nil)
+		})
+	}
+}
+
+func TestAddMutedEvent(t *testing.T) {
+	for _, test := range []struct {
+		description    string
+		storedEvents   *serializer.MutedEvents
+		expectedEvents int
+		expectedCount  int
+	}{
+		{
+			description:    "First muted event is stored",
+			expectedEvents: 1,
+			expectedCount:  1,
+		},
+		{
+			description: "Only the count is updated after the limit of the events is reached",
+			storedEvents: &serializer.MutedEvents{
+				Events: make([]*serializer.MutedEvent, constants.MaxMutedEventsInSummary),
+				Count:  constants.MaxMutedEventsInSummary + 5,
+			},
+			expectedEvents: constants.MaxMutedEventsInSummary,
+			expectedCount:  constants.MaxMutedEventsInSummary + 6,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			ps := pluginStore{
+				plugin: &Plugin{},
+			}
+			patchClusterMutex()
+			monkey.Patch(kvstore.LoadJSON, func(_ kvstore.KVStore, _ string, v interface{}) error {
+				if test.storedEvents == nil {
+					return ErrNotFound
+				}
+
+				*(v.(*serializer.MutedEvents)) = *test.storedEvents
+				return nil
+			})
+
+			var stored serializer.MutedEvents
+			monkey.Patch(kvstore.StoreJSON, func(_ kvstore.KVStore, key string, v interface{}) error {
+				assert.Equal(t, "mockKey", key)
+				stored = v.(serializer.MutedEvents)
+				return nil
+			})
+
+			err := ps.AddMutedEvent("mockKey", &serializer.MutedEvent{})
+			assert.NoError(t, err)
+			assert.Len(t, stored.Events, test.expectedEvents)
+			assert.Equal(t, test.expectedCount, stored.Count)
+		})
+	}
+}
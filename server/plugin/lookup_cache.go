@@ -0,0 +1,122 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LookupCache stores the results of the ServiceNow lookups for a limited time
+type LookupCache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte, ttl time.Duration)
+}
+
+type memoryLookupCacheEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+// memoryLookupCache is an in-memory LRU cache whose entries expire after their TTL
+type memoryLookupCache struct {
+	lock       sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+func newMemoryLookupCache(maxEntries int) *memoryLookupCache {
+	return &memoryLookupCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (c *memoryLookupCache) Get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryLookupCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.data, true
+}
+
+func (c *memoryLookupCache) Set(key string, data []byte, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := &memoryLookupCacheEntry{
+		key:       key,
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryLookupCacheEntry).key)
+	}
+}
+
+// kvLookupCache stores the entries in the KV store so that they are shared by all the cluster nodes
+type kvLookupCache struct {
+	plugin *Plugin
+}
+
+func (c *kvLookupCache) Get(key string) ([]byte, bool) {
+	data, err := c.plugin.store.LoadLookup(key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			c.plugin.API.LogWarn("Unable to load the lookup from the shared cache", "Error", err.Error())
+		}
+
+		return nil, false
+	}
+
+	return data, true
+}
+
+func (c *kvLookupCache) Set(key string, data []byte, ttl time.Duration) {
+	if err := c.plugin.store.StoreLookup(key, data, int64(ttl/time.Second)); err != nil {
+		c.plugin.API.LogWarn("Unable to store the lookup in the shared cache", "Error", err.Error())
+	}
+}
+
+// getLookupCaches returns the caches to be used for the lookups in the order in which they should be checked
+func (p *Plugin) getLookupCaches() []LookupCache {
+	if p.memoryLookupCache == nil {
+		return nil
+	}
+
+	caches := []LookupCache{p.memoryLookupCache}
+	if p.getConfiguration().EnableSharedLookupCache && p.store != nil {
+		caches = append(caches, &kvLookupCache{plugin: p})
+	}
+
+	return caches
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestMemoryLookupCache(t *testing.T) {
+	t.Run("Entries are returned until they expire", func(t *testing.T) {
+		cache := newMemoryLookupCache(10)
+		cache.Set("mockKey", []byte("mockData"), time.Minute)
+		cache.Set("mockExpiredKey", []byte("mockData"), -time.Minute)
+
+		data, ok := cache.Get("mockKey")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("mockData"), data)
+
+		_, ok = cache.Get("mockExpiredKey")
+		assert.False(t, ok)
+		_, ok = cache.Get("mockMissingKey")
+		assert.False(t, ok)
+	})
+
+	t.Run("Least recently used entries are evicted", func(t *testing.T) {
+		cache := newMemoryLookupCache(2)
+		cache.Set("mockKey1", []byte("mockData1"), time.Minute)
+		cache.Set("mockKey2", []byte("mockData2"), time.Minute)
+		_, _ = cache.Get("mockKey1")
+		cache.Set("mockKey3", []byte("mockData3"), time.Minute)
+
+		_, ok := cache.Get("mockKey1")
+		assert.True(t, ok)
+		_, ok = cache.Get("mockKey2")
+		assert.False(t, ok)
+		_, ok = cache.Get("mockKey3")
+		assert.True(t, ok)
+	})
+
+	t.Run("Existing entries are replaced", func(t *testing.T) {
+		cache := newMemoryLookupCache(2)
+		cache.Set("mockKey", []byte("mockData1"), time.Minute)
+		cache.Set("mockKey", []byte("mockData2"), time.Minute)
+
+		data, ok := cache.Get("mockKey")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("mockData2"), data)
+		assert.Equal(t, 1, cache.order.Len())
+	})
+}
+
+func TestKVLookupCache(t *testing.T) {
+	for _, test := range []struct {
+		description  string
+		setupAPI     func(*plugintest.API)
+		setupStore   func(*mock_plugin.Store)
+		expectedData []byte
+		expectedOK   bool
+	}{
+		{
+			description: "Entry is loaded from the store",
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadLookup", "mockKey").Return([]byte("mockData"), nil)
+			},
+			expectedData: []byte("mockData"),
+			expectedOK:   true,
+		},
+		{
+			description: "Entry is not found",
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadLookup", "mockKey").Return(nil, ErrNotFound)
+			},
+		},
+		{
+			description: "Failed to load the entry",
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadLookup", "mockKey").Return(nil, fmt.Errorf("mockError"))
+			},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			test.setupAPI(api)
+			test.setupStore(store)
+			defer api.AssertExpectations(t)
+
+			cache := &kvLookupCache{plugin: p}
+			data, ok := cache.Get("mockKey")
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedData, data)
+		})
+	}
+}
+
+func TestGetLookupCaches(t *testing.T) {
+	p, _ := setupTestPlugin(&plugintest.API{}, &mock_plugin.Store{})
+	assert.Empty(t, p.getLookupCaches())
+
+	p.memoryLookupCache = newMemoryLookupCache(constants.MaxLookupCacheEntries)
+	assert.Len(t, p.getLookupCaches(), 1)
+
+	p.setConfiguration(&configuration{EnableSharedLookupCache: true})
+	assert.Len(t, p.getLookupCaches(), 2)
+}
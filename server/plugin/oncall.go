@@ -0,0 +1,294 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// handleOnCall shows who is on call now and next for an assignment group
+func (p *Plugin) handleOnCall(_ *plugin.Context, args *model.CommandArgs, parameters []string, client Client, isSysAdmin bool) string {
+	groupName := strings.TrimSpace(strings.Trim(strings.Join(parameters, " "), `"`))
+	if groupName == "" {
+		return constants.OnCallGroupRequiredMessage
+	}
+
+	client, err := p.getCommandClient(args.UserId, client)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		group, statusCode, err := client.GetGroupByNameFromServiceNow(groupName)
+		if err != nil {
+			p.API.LogError("Unable to get the group", "Group", groupName, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		if group == nil {
+			p.postCommandResponse(args, fmt.Sprintf(constants.OnCallGroupNotFoundMessage, groupName))
+			return
+		}
+
+		now := time.Now()
+		current, statusCode, err := client.GetOnCallUsersFromServiceNow(group.SysID, now)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetOnCall, "GroupID", group.SysID, "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		next, nextFrom := p.getNextOnCallUsers(client, group.SysID, now, serializer.GetPrimaryOnCallUsers(current))
+		p.postCommandResponse(args, serializer.GetFormattedOnCall(group.Name, p.formatOnCallUsers(current), p.formatOnCallUsers(next), nextFrom))
+	}()
+
+	return genericWaitMessage
+}
+
+// getNextOnCallUsers returns the first users on call after the current ones.
+// The On-Call Scheduling API doesn't return the end of the shifts, so each hour of the next day is checked for the shifts shorter than a day
+// and each of the following days after that. A change found between two days is narrowed down to the hour.
+// The change is only known to have happened by the returned time.
+func (p *Plugin) getNextOnCallUsers(client Client, groupID string, now time.Time, current []*serializer.ServiceNowOnCallUser) ([]*serializer.ServiceNowOnCallUser, string) {
+	probes := []time.Time{}
+	for hour := 1; hour <= constants.OnCallNextHourlyLookupHours; hour++ {
+		probes = append(probes, now.Add(time.Duration(hour)*time.Hour))
+	}
+
+	for day := constants.OnCallNextHourlyLookupHours/24 + 1; day <= constants.OnCallNextLookupDays; day++ {
+		probes = append(probes, now.Add(time.Duration(day)*24*time.Hour))
+	}
+
+	previous := now
+	for _, at := range probes {
+		next, err := p.getPrimaryOnCallUsersAt(client, groupID, at)
+		if err != nil {
+			return nil, ""
+		}
+
+		if isSameOnCall(current, next) {
+			previous = at
+			continue
+		}
+
+		for at.Sub(previous) > time.Hour {
+			middle := previous.Add((at.Sub(previous) / 2).Round(time.Hour))
+			users, err := p.getPrimaryOnCallUsersAt(client, groupID, middle)
+			if err != nil {
+				return nil, ""
+			}
+
+			if isSameOnCall(current, users) {
+				previous = middle
+			} else {
+				at, next = middle, users
+			}
+		}
+
+		return next, at.UTC().Format(constants.OnCallNextTimeLayout)
+	}
+
+	return nil, ""
+}
+
+func (p *Plugin) getPrimaryOnCallUsersAt(client Client, groupID string, at time.Time) ([]*serializer.ServiceNowOnCallUser, error) {
+	users, _, err := client.GetOnCallUsersFromServiceNow(groupID, at)
+	if err != nil {
+		p.API.LogWarn(constants.ErrorGetOnCall, "GroupID", groupID, "Error", err.Error())
+		return nil, err
+	}
+
+	return serializer.GetPrimaryOnCallUsers(users), nil
+}
+
+func isSameOnCall(current, next []*serializer.ServiceNowOnCallUser) bool {
+	if len(current) != len(next) {
+		return false
+	}
+
+	userIDs := map[string]bool{}
+	for _, user := range current {
+		userIDs[user.UserID] = true
+	}
+
+	for _, user := range next {
+		if !userIDs[user.UserID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatOnCallUsers returns the mentions of the users on call who are on Mattermost and the names of the rest
+func (p *Plugin) formatOnCallUsers(users []*serializer.ServiceNowOnCallUser) []string {
+	formatted := make([]string, 0, len(users))
+	for _, user := range users {
+		label := getTextOrNotAvailable(user.Name)
+		if mmUser := p.getOnCallMattermostUser(user); mmUser != nil {
+			label = "@" + mmUser.Username
+		}
+
+		if user.EscalationLevel > 1 {
+			label += fmt.Sprintf(constants.OnCallEscalationLevelSuffix, user.EscalationLevel)
+		}
+		formatted = append(formatted, label)
+	}
+
+	return formatted
+}
+
+// getOnCallMattermostUser returns the Mattermost user who has connected the ServiceNow account of the user on call.
+// The users who haven't connected their accounts are matched by their email.
+func (p *Plugin) getOnCallMattermostUser(user *serializer.ServiceNowOnCallUser) *model.User {
+	if mattermostUserID, err := p.GetMattermostUserIDFromServiceNowUserID(user.UserID); err == nil {
+		if mmUser, appErr := p.API.GetUser(mattermostUserID); appErr == nil {
+			return mmUser
+		}
+	}
+
+	if user.Email == "" {
+		return nil
+	}
+
+	mmUser, appErr := p.API.GetUserByEmail(user.Email)
+	if appErr != nil {
+		return nil
+	}
+
+	return mmUser
+}
+
+// handlePageOnCallAction pages the users on call for the assignment group of an incident.
+// They are mentioned in the channel if they are its members, otherwise they are sent a DM.
+func (p *Plugin) handlePageOnCallAction(w http.ResponseWriter, r *http.Request) {
+	response := &model.PostActionIntegrationResponse{}
+	postActionIntegrationRequest := &model.PostActionIntegrationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&postActionIntegrationRequest); err != nil {
+		p.API.LogError("Error decoding PostActionIntegrationRequest params: ", err.Error())
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	incidentID, _ := postActionIntegrationRequest.Context[constants.ContextNameRecordID].(string)
+	if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, incidentID); err != nil || !valid {
+		response.EphemeralText = genericErrorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	userID := postActionIntegrationRequest.UserId
+	channelID := postActionIntegrationRequest.ChannelId
+	if _, err := p.HasChannelPermissions(userID, channelID); err != nil {
+		response.EphemeralText = err.Error()
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	client, errorMessage := p.getPostActionClient(postActionIntegrationRequest)
+	if client == nil {
+		response.EphemeralText = errorMessage
+		p.returnPostActionIntegrationResponse(w, response)
+		return
+	}
+
+	response.EphemeralText = p.pageOnCall(client, incidentID, userID, channelID)
+	p.returnPostActionIntegrationResponse(w, response)
+}
+
+func (p *Plugin) pageOnCall(client Client, incidentID, userID, channelID string) string {
+	incident, statusCode, err := client.GetRecordFromServiceNow(constants.RecordTypeIncident, incidentID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetRecord, "Incident ID", incidentID, "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	groupID, groupName := incident.GetAssignmentGroup()
+	if groupID == "" {
+		return constants.IncidentNotAssignedMessage
+	}
+
+	users, statusCode, err := client.GetOnCallUsersFromServiceNow(groupID, time.Now())
+	if err != nil {
+		p.API.LogError(constants.ErrorGetOnCall, "GroupID", groupID, "Error", err.Error())
+		return p.handleClientError(nil, nil, err, false, statusCode, userID, "")
+	}
+
+	primary := serializer.GetPrimaryOnCallUsers(users)
+	if len(primary) == 0 {
+		return fmt.Sprintf(constants.NoOneOnCallMessage, groupName)
+	}
+
+	pager, appErr := p.API.GetUser(userID)
+	if appErr != nil {
+		p.API.LogError(constants.ErrorGetUser, "UserID", userID, "Error", appErr.Error())
+		return genericErrorMessage
+	}
+
+	link := fmt.Sprintf(constants.PathRecord, p.getServiceNowURL(client), constants.RecordTypeIncident, incident.SysID, constants.RecordTypeIncident)
+	var mentions, paged, notPaged []string
+	for _, user := range primary {
+		mmUser := p.getOnCallMattermostUser(user)
+		if mmUser == nil {
+			notPaged = append(notPaged, getTextOrNotAvailable(user.Name))
+			continue
+		}
+
+		if _, appErr := p.API.GetChannelMember(channelID, mmUser.Id); appErr == nil {
+			mentions = append(mentions, "@"+mmUser.Username)
+			continue
+		}
+
+		if _, err := p.DM(mmUser.Id, constants.OnCallPageDirectMessage, pager.Username, incident.Number, link, incident.ShortDescription); err != nil {
+			notPaged = append(notPaged, getTextOrNotAvailable(user.Name))
+			continue
+		}
+		paged = append(paged, "@"+mmUser.Username)
+	}
+
+	if len(mentions) > 0 {
+		post := &model.Post{
+			ChannelId: channelID,
+			UserId:    p.botID,
+			Message:   fmt.Sprintf(constants.OnCallPageChannelMessage, strings.Join(mentions, " "), pager.Username, incident.Number, link, incident.ShortDescription),
+		}
+		if _, appErr := p.API.CreatePost(post); appErr != nil {
+			p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+			return genericErrorMessage
+		}
+		paged = append(paged, mentions...)
+	}
+
+	if len(paged) == 0 {
+		return fmt.Sprintf(constants.OnCallNotPagedMessage, groupName, strings.Join(notPaged, ", "))
+	}
+
+	message := fmt.Sprintf(constants.OnCallPagedMessage, groupName, strings.Join(paged, ", "))
+	if len(notPaged) > 0 {
+		message += " " + fmt.Sprintf(constants.OnCallNotPagedMessage, groupName, strings.Join(notPaged, ", "))
+	}
+
+	return message
+}
+
+func getTextOrNotAvailable(text string) string {
+	if text == "" {
+		return constants.NotAvailableText
+	}
+
+	return text
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleOnCall(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	group := &serializer.ServiceNowGroup{SysID: "mockGroupID", Name: "Network"}
+	for _, testCase := range []struct {
+		description       string
+		params            []string
+		setupAPI          func(*plugintest.API)
+		setupStore        func(*mock_plugin.Store)
+		setupClient       func(*mock_plugin.Client)
+		expectedMessage   string
+		expectedPostCheck func(string) bool
+	}{
+		{
+			description:     "HandleOnCall: assignment group is missing",
+			params:          []string{},
+			setupAPI:        func(_ *plugintest.API) {},
+			setupStore:      func(_ *mock_plugin.Store) {},
+			setupClient:     func(_ *mock_plugin.Client) {},
+			expectedMessage: constants.OnCallGroupRequiredMessage,
+		},
+		{
+			description: "HandleOnCall: assignment group doesn't exist",
+			params:      []string{`"Network`, `team"`},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetGroupByNameFromServiceNow", "Network team").Return(nil, http.StatusOK, nil)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return message == fmt.Sprintf(constants.OnCallGroupNotFoundMessage, "Network team")
+			},
+		},
+		{
+			description: "HandleOnCall: users on call now and next are shown",
+			params:      []string{"Network"},
+			setupAPI: func(api *plugintest.API) {
+				api.On("GetUser", "mockMattermostUserID").Return(&model.User{Id: "mockMattermostUserID", Username: "alice"}, nil)
+				api.On("GetUserByEmail", "carol@example.com").Return(nil, testutils.GetBadRequestAppError())
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID1").Return("mockMattermostUserID", nil)
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID2").Return("", ErrNotFound)
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID3").Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(true)
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetGroupByNameFromServiceNow", "Network").Return(group, http.StatusOK, nil)
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return([]*serializer.ServiceNowOnCallUser{
+					{UserID: "mockUserID1", EscalationLevel: 1, Name: "Alice"},
+					{UserID: "mockUserID2", EscalationLevel: 2, Name: "Bob"},
+				}, http.StatusOK, nil).Twice()
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return([]*serializer.ServiceNowOnCallUser{
+					{UserID: "mockUserID3", EscalationLevel: 1, Name: "Carol", Email: "carol@example.com"},
+				}, http.StatusOK, nil).Once()
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return strings.HasPrefix(message, "#### On call for Network\n**Now:** @alice, Bob (escalation level 2)\n**Next:** Carol, on call by ")
+			},
+		},
+		{
+			description: "HandleOnCall: no one is on call",
+			params:      []string{"Network"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetGroupByNameFromServiceNow", "Network").Return(group, http.StatusOK, nil)
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return([]*serializer.ServiceNowOnCallUser{}, http.StatusOK, nil).Times(constants.OnCallNextHourlyLookupHours + constants.OnCallNextLookupDays)
+			},
+			expectedMessage: genericWaitMessage,
+			expectedPostCheck: func(message string) bool {
+				return message == "#### On call for Network\n**Now:** No one is on call.\n**Next:** No change in the upcoming days."
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, store)
+			testCase.setupAPI(mockAPI)
+			defer mockAPI.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			if testCase.expectedPostCheck != nil {
+				mockAPI.On("SendEphemeralPost", testutils.GetID(), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+					message := args.Get(1).(*model.Post).Message
+					assert.True(t, testCase.expectedPostCheck(message), message)
+				}).Once().Return(&model.Post{})
+			}
+
+			resp := p.handleOnCall(&plugin.Context{}, args, testCase.params, nil, false)
+			assert.Equal(t, testCase.expectedMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
+
+func TestGetNextOnCallUsers(t *testing.T) {
+	now := time.Date(2036, 10, 20, 9, 0, 0, 0, time.UTC)
+	alice := []*serializer.ServiceNowOnCallUser{{UserID: "mockUserID1", EscalationLevel: 1}}
+	bob := []*serializer.ServiceNowOnCallUser{{UserID: "mockUserID2", EscalationLevel: 1}}
+	for _, testCase := range []struct {
+		description  string
+		changeAfter  time.Duration
+		err          error
+		expectedNext []*serializer.ServiceNowOnCallUser
+		expectedAt   string
+	}{
+		{
+			description:  "Shift shorter than a day is found at the hour of the change",
+			changeAfter:  12 * time.Hour,
+			expectedNext: bob,
+			expectedAt:   "Oct 20 21:00",
+		},
+		{
+			description:  "Change found between two days is narrowed down to the hour",
+			changeAfter:  50 * time.Hour,
+			expectedNext: bob,
+			expectedAt:   "Oct 22 11:00",
+		},
+		{
+			description: "No change in the upcoming days",
+			changeAfter: 30 * 24 * time.Hour,
+		},
+		{
+			description: "Error in getting the users on call",
+			err:         errors.New("mockError"),
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			if testCase.err != nil {
+				mockAPI.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			}
+			defer mockAPI.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return(func(_ string, at time.Time) []*serializer.ServiceNowOnCallUser {
+				if at.Sub(now) >= testCase.changeAfter {
+					return bob
+				}
+				return alice
+			}, http.StatusOK, testCase.err)
+
+			next, at := p.getNextOnCallUsers(client, "mockGroupID", now, alice)
+			assert.Equal(t, testCase.expectedNext, next)
+			assert.Equal(t, testCase.expectedAt, at)
+		})
+	}
+}
+
+func TestHandlePageOnCallAction(t *testing.T) {
+	defer monkey.UnpatchAll()
+	requestURL := fmt.Sprintf("%s%s", constants.PathPrefix, constants.PathPageOnCallAction)
+	incident := &serializer.ServiceNowRecord{
+		SysID:            testutils.GetServiceNowSysID(),
+		Number:           "INC0010001",
+		ShortDescription: "Disk full",
+		AssignmentGroup: map[string]interface{}{
+			"display_value": "Network",
+			"link":          "https://example.service-now.com/api/now/table/sys_user_group/mockGroupID",
+		},
+	}
+	for name, test := range map[string]struct {
+		IncidentID      string
+		SetupAPI        func(*plugintest.API)
+		SetupStore      func(*mock_plugin.Store)
+		SetupClient     func(*mock_plugin.Client)
+		ExpectedMessage string
+	}{
+		"users on call are mentioned in the channel or sent a DM": {
+			IncidentID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("GetUser", testutils.GetID()).Return(&model.User{Username: "pager"}, nil)
+				api.On("GetUser", "mockMattermostUserID1").Return(&model.User{Id: "mockMattermostUserID1", Username: "alice"}, nil)
+				api.On("GetUserByEmail", "bob@example.com").Return(&model.User{Id: "mockMattermostUserID2", Username: "bob"}, nil)
+				api.On("GetChannelMember", testutils.GetChannelID(), "mockMattermostUserID1").Return(&model.ChannelMember{}, nil)
+				api.On("GetChannelMember", testutils.GetChannelID(), "mockMattermostUserID2").Return(nil, testutils.GetBadRequestAppError())
+				api.On("GetDirectChannel", "mockMattermostUserID2", mock.AnythingOfType("string")).Return(&model.Channel{Id: "mockDMChannelID"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == "mockDMChannelID" && strings.HasPrefix(post.Message, "You are being paged by @pager for the incident [INC0010001]")
+				})).Return(&model.Post{Id: "mockPostID"}, nil)
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID() && strings.HasPrefix(post.Message, "@alice, you are being paged by @pager")
+				})).Return(&model.Post{Id: "mockPostID"}, nil)
+			},
+			SetupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID1").Return("mockMattermostUserID1", nil)
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID2").Return("", ErrNotFound)
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockUserID3").Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(true)
+			},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(incident, http.StatusOK, nil)
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return([]*serializer.ServiceNowOnCallUser{
+					{UserID: "mockUserID1", EscalationLevel: 1},
+					{UserID: "mockUserID2", EscalationLevel: 1, Email: "bob@example.com"},
+					{UserID: "mockUserID3", EscalationLevel: 1, Name: "Carol"},
+					{UserID: "mockUserID4", EscalationLevel: 2, Name: "Dave"},
+				}, http.StatusOK, nil)
+			},
+			ExpectedMessage: "Paged the users on call for Network: @bob, @alice. These users on call for Network couldn't be paged on Mattermost: Carol.",
+		},
+		"incident is not assigned to any group": {
+			IncidentID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowRecord{AssignmentGroup: ""}, http.StatusOK, nil)
+			},
+			ExpectedMessage: constants.IncidentNotAssignedMessage,
+		},
+		"no one is on call": {
+			IncidentID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(incident, http.StatusOK, nil)
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return([]*serializer.ServiceNowOnCallUser{}, http.StatusOK, nil)
+			},
+			ExpectedMessage: fmt.Sprintf(constants.NoOneOnCallMessage, "Network"),
+		},
+		"failed to get the on-call users": {
+			IncidentID: testutils.GetServiceNowSysID(),
+			SetupAPI: func(api *plugintest.API) {
+				api.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			SetupStore: func(_ *mock_plugin.Store) {},
+			SetupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(incident, http.StatusOK, nil)
+				client.On("GetOnCallUsersFromServiceNow", "mockGroupID", mock.AnythingOfType("time.Time")).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			ExpectedMessage: genericErrorMessage,
+		},
+		"invalid incident ID": {
+			IncidentID:      "invalid",
+			SetupAPI:        func(_ *plugintest.API) {},
+			SetupStore:      func(_ *mock_plugin.Store) {},
+			SetupClient:     func(_ *mock_plugin.Client) {},
+			ExpectedMessage: genericErrorMessage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{ServiceNowBaseURL: "https://example.service-now.com"})
+			client := mock_plugin.NewClient(t)
+			test.SetupAPI(api)
+			test.SetupStore(store)
+			test.SetupClient(client)
+			defer api.AssertExpectations(t)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+
+			body, err := json.Marshal(&model.PostActionIntegrationRequest{
+				UserId:    testutils.GetID(),
+				ChannelId: testutils.GetChannelID(),
+				Context: map[string]interface{}{
+					constants.ContextNameRecordID: test.IncidentID,
+				},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, requestURL, bytes.NewBuffer(body))
+			r.Header.Add(constants.HeaderMattermostUserID, testutils.GetID())
+			p.ServeHTTP(nil, w, r)
+
+			result := w.Result()
+			require.NotNil(t, result)
+			defer result.Body.Close()
+
+			response := &model.PostActionIntegrationResponse{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(response))
+			assert.Equal(t, test.ExpectedMessage, response.EphemeralText)
+		})
+	}
+}
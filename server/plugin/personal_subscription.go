@@ -0,0 +1,111 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// GetPersonalSubscription returns the personal subscription of the user.
+// Users who have never configured their personal subscription are notified about the approvals requested from them.
+func (p *Plugin) GetPersonalSubscription(mattermostUserID string) (*serializer.PersonalSubscription, error) {
+	subscription, err := p.store.LoadPersonalSubscription(mattermostUserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return &serializer.PersonalSubscription{
+				MattermostUserID: mattermostUserID,
+				Events:           []string{constants.PersonalSubscriptionEventApprovals},
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// UpdatePersonalSubscription stores the events of the user's personal subscription
+func (p *Plugin) UpdatePersonalSubscription(mattermostUserID string, events []string) (*serializer.PersonalSubscription, error) {
+	subscription := &serializer.PersonalSubscription{
+		MattermostUserID: mattermostUserID,
+		Events:           events,
+	}
+
+	if err := p.store.StorePersonalSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// NotifyPersonalSubscribers sends a DM to the users whose personal subscriptions match the event.
+// ServiceNow sends the members of the assignment group along with the event, so the group memberships are always current.
+func (p *Plugin) NotifyPersonalSubscribers(event *serializer.ServiceNowEvent) {
+	recipients := map[string][]string{}
+	switch event.EventOccurred {
+	case constants.SubscriptionEventCreated, constants.SubscriptionEventAssignedTo, constants.SubscriptionEventAssignmentGroup:
+		if event.AssignedToID != "" {
+			p.addPersonalSubscriptionRecipient(recipients, event.AssignedToID, constants.PersonalSubscriptionEventAssignedToMe)
+		}
+
+		for _, memberID := range event.AssignmentGroupMemberIDs {
+			p.addPersonalSubscriptionRecipient(recipients, memberID, constants.PersonalSubscriptionEventAssignedToMyGroups)
+		}
+	case constants.SubscriptionEventCommented:
+		if event.OpenedByID != "" {
+			p.addPersonalSubscriptionRecipient(recipients, event.OpenedByID, constants.PersonalSubscriptionEventCommentedOnMine)
+		}
+	case constants.SubscriptionEventStage:
+		if event.RequestedForID != "" {
+			p.addPersonalSubscriptionRecipient(recipients, event.RequestedForID, constants.PersonalSubscriptionEventMyRequests)
+		}
+	}
+
+	for mattermostUserID, personalEvents := range recipients {
+		subscription, err := p.GetPersonalSubscription(mattermostUserID)
+		if err != nil {
+			p.API.LogError(constants.ErrorGetPersonalSubscription, "UserID", mattermostUserID, "Error", err.Error())
+			continue
+		}
+
+		matchedEvent := ""
+		for _, personalEvent := range personalEvents {
+			if !subscription.HasEvent(personalEvent) {
+				continue
+			}
+
+			matchedEvent = personalEvent
+			break
+		}
+
+		if matchedEvent == "" {
+			continue
+		}
+
+		if notify, err := p.store.MarkPersonalNotificationSent(mattermostUserID, event.RecordID, event.EventOccurred); err != nil || !notify {
+			continue
+		}
+
+		post := event.CreateNotificationPost(p.botID, p.getConfiguration().ServiceNowBaseURL, p.GetPluginURL())
+		post.Message = constants.FormattedPersonalSubscriptionEvents[matchedEvent]
+		if _, err := p.DMPost(mattermostUserID, post); err != nil {
+			p.API.LogError(constants.ErrorCreatePost, "UserID", mattermostUserID, "Error", err.Error())
+		}
+	}
+}
+
+func (p *Plugin) addPersonalSubscriptionRecipient(recipients map[string][]string, serviceNowUserID, personalEvent string) {
+	mattermostUserID, err := p.GetMattermostUserIDFromServiceNowUserID(serviceNowUserID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			p.API.LogError("Unable to get the Mattermost user for the ServiceNow user", "ServiceNowUserID", serviceNowUserID, "Error", err.Error())
+		}
+		return
+	}
+
+	recipients[mattermostUserID] = append(recipients[mattermostUserID], personalEvent)
+}
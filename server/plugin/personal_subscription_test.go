@@ -0,0 +1,210 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetPersonalSubscription(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		setupStore           func(*mock_plugin.Store)
+		expectedEvents       []string
+		expectedErrorMessage string
+	}{
+		{
+			description: "Personal subscription is loaded from the store",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{
+					MattermostUserID: testutils.GetID(),
+					Events:           []string{constants.PersonalSubscriptionEventAssignedToMe},
+				}, nil)
+			},
+			expectedEvents: []string{constants.PersonalSubscriptionEventAssignedToMe},
+		},
+		{
+			description: "Default personal subscription is returned for the users who never configured it",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(nil, ErrNotFound)
+			},
+			expectedEvents: []string{constants.PersonalSubscriptionEventApprovals},
+		},
+		{
+			description: "Error occurred while loading the personal subscription",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(nil, fmt.Errorf("mockErrMessage"))
+			},
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p.store = store
+
+			subscription, err := p.GetPersonalSubscription(testutils.GetID())
+			if test.expectedErrorMessage != "" {
+				require.EqualError(t, err, test.expectedErrorMessage)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedEvents, subscription.Events)
+		})
+	}
+}
+
+func TestUpdatePersonalSubscription(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		events               []string
+		setupStore           func(*mock_plugin.Store)
+		expectedErrorMessage string
+	}{
+		{
+			description: "Personal subscription is stored",
+			events:      []string{constants.PersonalSubscriptionEventAssignedToMe, constants.PersonalSubscriptionEventAssignedToMyGroups},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StorePersonalSubscription", mock.AnythingOfType("*serializer.PersonalSubscription")).Return(nil)
+			},
+		},
+		{
+			description: "Error occurred while storing the personal subscription",
+			events:      []string{constants.PersonalSubscriptionEventApprovals},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StorePersonalSubscription", mock.AnythingOfType("*serializer.PersonalSubscription")).Return(fmt.Errorf("mockErrMessage"))
+			},
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p.store = store
+
+			subscription, err := p.UpdatePersonalSubscription(testutils.GetID(), test.events)
+			if test.expectedErrorMessage != "" {
+				require.EqualError(t, err, test.expectedErrorMessage)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.events, subscription.Events)
+		})
+	}
+}
+
+func TestNotifyPersonalSubscribers(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		event       *serializer.ServiceNowEvent
+		setupStore  func(*mock_plugin.Store)
+		expectDM    bool
+	}{
+		{
+			description: "Assignee is notified",
+			event: &serializer.ServiceNowEvent{
+				RecordID:      testutils.GetServiceNowSysID(),
+				EventOccurred: constants.SubscriptionEventAssignedTo,
+				AssignedToID:  testutils.GetServiceNowSysID(),
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{
+					Events: []string{constants.PersonalSubscriptionEventAssignedToMe},
+				}, nil)
+				s.On("MarkPersonalNotificationSent", testutils.GetID(), testutils.GetServiceNowSysID(), constants.SubscriptionEventAssignedTo).Return(true, nil)
+			},
+			expectDM: true,
+		},
+		{
+			description: "Assignee is not subscribed",
+			event: &serializer.ServiceNowEvent{
+				RecordID:      testutils.GetServiceNowSysID(),
+				EventOccurred: constants.SubscriptionEventAssignedTo,
+				AssignedToID:  testutils.GetServiceNowSysID(),
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(nil, ErrNotFound)
+			},
+		},
+		{
+			description: "Group member is notified",
+			event: &serializer.ServiceNowEvent{
+				RecordID:                 testutils.GetServiceNowSysID(),
+				EventOccurred:            constants.SubscriptionEventAssignmentGroup,
+				AssignmentGroupID:        "mockGroupID",
+				AssignmentGroupMemberIDs: []string{testutils.GetServiceNowSysID(), "mockMemberID"},
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadMattermostUserIDFromServiceNowUserID", "mockMemberID").Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(true)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{
+					Events: []string{constants.PersonalSubscriptionEventAssignedToMyGroups},
+				}, nil)
+				s.On("MarkPersonalNotificationSent", testutils.GetID(), testutils.GetServiceNowSysID(), constants.SubscriptionEventAssignmentGroup).Return(true, nil)
+			},
+			expectDM: true,
+		},
+		{
+			description: "Opener is not notified again for the same event",
+			event: &serializer.ServiceNowEvent{
+				RecordID:      testutils.GetServiceNowSysID(),
+				EventOccurred: constants.SubscriptionEventCommented,
+				OpenedByID:    testutils.GetServiceNowSysID(),
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+				s.On("LoadPersonalSubscription", testutils.GetID()).Return(&serializer.PersonalSubscription{
+					Events: []string{constants.PersonalSubscriptionEventCommentedOnMine},
+				}, nil)
+				s.On("MarkPersonalNotificationSent", testutils.GetID(), testutils.GetServiceNowSysID(), constants.SubscriptionEventCommented).Return(false, nil)
+			},
+		},
+		{
+			description: "Event not relevant for the personal subscriptions",
+			event: &serializer.ServiceNowEvent{
+				RecordID:      testutils.GetServiceNowSysID(),
+				EventOccurred: constants.SubscriptionEventPriority,
+				AssignedToID:  testutils.GetServiceNowSysID(),
+			},
+			setupStore: func(s *mock_plugin.Store) {},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			p.setConfiguration(&configuration{})
+			api := &plugintest.API{}
+			defer api.AssertExpectations(t)
+			if test.expectDM {
+				api.On("GetDirectChannel", testutils.GetID(), mock.Anything).Return(testutils.GetChannel(model.ChannelTypeDirect), nil)
+				api.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(testutils.GetPost(), nil)
+			}
+			p.SetAPI(api)
+
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p.store = store
+
+			p.NotifyPersonalSubscribers(test.event)
+		})
+	}
+}
@@ -4,14 +4,15 @@
 package plugin
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/mattermost/mattermost/server/public/pluginapi/cluster"
 	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
@@ -35,6 +36,15 @@ type Plugin struct {
 	store           Store
 	CommandHandlers map[string]CommandHandleFunc
 
+	// memoryLookupCache caches the lookups made in ServiceNow on this node
+	memoryLookupCache *memoryLookupCache
+
+	// muteSummaryJob posts the summaries of the expired mutes
+	muteSummaryJob *cluster.Job
+
+	// slaWarningJob posts the warnings for the SLAs of the subscribed records
+	slaWarningJob *cluster.Job
+
 	// Telemetry package copied inside repository, should be changed
 	// to pluginapi's one (0.1.3+) when min_server_version is safe to point at 7.x
 	telemetryClient telemetry.Client
@@ -43,7 +53,9 @@ type Plugin struct {
 
 // NewPlugin returns an instance of a Plugin.
 func NewPlugin() *Plugin {
-	p := &Plugin{}
+	p := &Plugin{
+		memoryLookupCache: newMemoryLookupCache(constants.MaxLookupCacheEntries),
+	}
 
 	p.CommandHandlers = map[string]CommandHandleFunc{
 		constants.CommandDisconnect:     p.handleDisconnect,
@@ -51,6 +63,13 @@ func NewPlugin() *Plugin {
 		constants.CommandUnsubscribe:    p.handleDeleteSubscription,
 		constants.CommandSearchAndShare: p.handleSearchAndShare,
 		constants.CommandIncident:       p.handleIncident,
+		constants.CommandAdmin:          p.handleAdmin,
+		constants.CommandCatalog:        p.handleCatalog,
+		constants.CommandRequests:       p.handleRequests,
+		constants.CommandKnowledge:      p.handleKnowledge,
+		constants.CommandCI:             p.handleCI,
+		constants.CommandChanges:        p.handleChanges,
+		constants.CommandOnCall:         p.handleOnCall,
 	}
 
 	return p
@@ -61,6 +80,11 @@ func (p *Plugin) ServeHTTP(_ *plugin.Context, w http.ResponseWriter, r *http.Req
 	p.router.ServeHTTP(w, r)
 }
 
+// UserHasBeenDeactivated notifies the channel admins about the subscriptions owned by the deactivated user
+func (p *Plugin) UserHasBeenDeactivated(_ *plugin.Context, user *model.User) {
+	go p.HandleSubscriptionsOfDeactivatedUser(user)
+}
+
 func (p *Plugin) GetSiteURL() string {
 	return p.getConfiguration().MattermostSiteURL
 }
@@ -73,14 +97,8 @@ func (p *Plugin) GetPluginURL() string {
 	return strings.TrimRight(p.GetSiteURL(), "/") + p.GetPluginURLPath()
 }
 
+// NewOAuth2Config returns the OAuth2 config of the default instance
 func (p *Plugin) NewOAuth2Config() *oauth2.Config {
-	return &oauth2.Config{
-		ClientID:     p.getConfiguration().ServiceNowOAuthClientID,
-		ClientSecret: p.getConfiguration().ServiceNowOAuthClientSecret,
-		RedirectURL:  fmt.Sprintf("%s%s", p.GetPluginURL(), constants.PathOAuth2Complete),
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  fmt.Sprintf("%s/oauth_auth.do", p.getConfiguration().ServiceNowBaseURL),
-			TokenURL: fmt.Sprintf("%s/oauth_token.do", p.getConfiguration().ServiceNowBaseURL),
-		},
-	}
+	instance, _ := p.getConfiguration().GetInstance(constants.DefaultInstanceID)
+	return p.newInstanceOAuth2Config(instance)
 }
@@ -0,0 +1,77 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// PopulateRecordsForSubscriptions fetches the number and short description of the records of the subscriptions.
+// The records of each record type are fetched in batches instead of making a call for each of them.
+// The records fetched recently are served from the lookup caches when the client is a cached client.
+func (p *Plugin) PopulateRecordsForSubscriptions(subscriptions []*serializer.SubscriptionResponse, client Client) {
+	recordIDs := map[string][]string{}
+	for _, subscription := range subscriptions {
+		if subscription.Type == constants.SubscriptionTypeRecord {
+			recordIDs[subscription.RecordType] = append(recordIDs[subscription.RecordType], subscription.RecordID)
+		}
+	}
+
+	failedRecordTypes := map[string]bool{}
+	records := map[string]*serializer.ServiceNowPartialRecord{}
+	for recordType, sysIDs := range recordIDs {
+		sysIDs = getUniqueValues(sysIDs)
+		for start := 0; start < len(sysIDs); start += constants.MaxRecordsPerBatch {
+			end := start + constants.MaxRecordsPerBatch
+			if end > len(sysIDs) {
+				end = len(sysIDs)
+			}
+
+			batch, _, err := client.GetRecordsFromServiceNow(recordType, sysIDs[start:end])
+			if err != nil {
+				p.API.LogError("Error in getting records from ServiceNow", "Record type", recordType, "Error", err.Error())
+				failedRecordTypes[recordType] = true
+				continue
+			}
+
+			for _, record := range batch {
+				records[getRecordLookupKey(recordType, record.SysID)] = record
+			}
+		}
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.Type != constants.SubscriptionTypeRecord {
+			continue
+		}
+
+		if record := records[getRecordLookupKey(subscription.RecordType, subscription.RecordID)]; record != nil {
+			subscription.Number = record.Number
+			subscription.ShortDescription = record.ShortDescription
+			continue
+		}
+
+		// The records which are not returned by ServiceNow are restricted by the ACLs for the user and are left empty
+		if failedRecordTypes[subscription.RecordType] {
+			subscription.Number = constants.NotAvailableText
+			subscription.ShortDescription = constants.NotAvailableText
+		}
+	}
+}
+
+func getUniqueValues(values []string) []string {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		unique = append(unique, value)
+	}
+
+	return unique
+}
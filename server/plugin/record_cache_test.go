@@ -0,0 +1,114 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func getRecordSubscription(recordID string) *serializer.SubscriptionResponse {
+	subscription := testutils.GetSubscription(constants.SubscriptionTypeRecord)
+	subscription.RecordID = recordID
+	subscription.Number = ""
+	subscription.ShortDescription = ""
+	return subscription
+}
+
+func TestPopulateRecordsForSubscriptions(t *testing.T) {
+	for _, test := range []struct {
+		description              string
+		subscriptions            []*serializer.SubscriptionResponse
+		setupAPI                 func(*plugintest.API)
+		setupClient              func(*mock_plugin.Client)
+		expectedNumbers          []string
+		expectedShortDescription []string
+	}{
+		{
+			description:   "Records are fetched in a single call for each record type",
+			subscriptions: []*serializer.SubscriptionResponse{getRecordSubscription("mockSysID1"), getRecordSubscription("mockSysID2"), getRecordSubscription("mockSysID1")},
+			setupAPI:      func(api *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{"mockSysID1", "mockSysID2"}).Return(
+					[]*serializer.ServiceNowPartialRecord{
+						{SysID: "mockSysID1", Number: "PRB1", ShortDescription: "mockDescription1"},
+						{SysID: "mockSysID2", Number: "PRB2", ShortDescription: "mockDescription2"},
+					}, http.StatusOK, nil,
+				).Once()
+			},
+			expectedNumbers:          []string{"PRB1", "PRB2", "PRB1"},
+			expectedShortDescription: []string{"mockDescription1", "mockDescription2", "mockDescription1"},
+		},
+		{
+			description:   "Records not returned by ServiceNow are left empty",
+			subscriptions: []*serializer.SubscriptionResponse{getRecordSubscription("mockSysID1"), getRecordSubscription("mockSysID2")},
+			setupAPI:      func(api *plugintest.API) {},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{"mockSysID1", "mockSysID2"}).Return(
+					[]*serializer.ServiceNowPartialRecord{
+						{SysID: "mockSysID1", Number: "PRB1", ShortDescription: "mockDescription1"},
+					}, http.StatusOK, nil,
+				).Once()
+			},
+			expectedNumbers:          []string{"PRB1", ""},
+			expectedShortDescription: []string{"mockDescription1", ""},
+		},
+		{
+			description:   "Failed to get the records",
+			subscriptions: []*serializer.SubscriptionResponse{getRecordSubscription("mockSysID1"), testutils.GetSubscription(constants.SubscriptionTypeBulk)},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, []string{"mockSysID1"}).Return(
+					nil, http.StatusInternalServerError, fmt.Errorf("mockError"),
+				).Once()
+			},
+			expectedNumbers:          []string{constants.NotAvailableText, testutils.GetServiceNowNumber()},
+			expectedShortDescription: []string{constants.NotAvailableText, testutils.GetServiceNowShortDescription()},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			client := &mock_plugin.Client{}
+			test.setupAPI(api)
+			test.setupClient(client)
+			defer api.AssertExpectations(t)
+			defer client.AssertExpectations(t)
+
+			p.PopulateRecordsForSubscriptions(test.subscriptions, client)
+
+			for i, subscription := range test.subscriptions {
+				assert.Equal(t, test.expectedNumbers[i], subscription.Number)
+				assert.Equal(t, test.expectedShortDescription[i], subscription.ShortDescription)
+			}
+		})
+	}
+}
+
+func TestPopulateRecordsForSubscriptionsInBatches(t *testing.T) {
+	p, _ := setupTestPlugin(&plugintest.API{}, nil)
+	client := &mock_plugin.Client{}
+	subscriptions := make([]*serializer.SubscriptionResponse, constants.MaxRecordsPerBatch+1)
+	for i := range subscriptions {
+		subscriptions[i] = getRecordSubscription(fmt.Sprintf("mockSysID%d", i))
+	}
+
+	client.On("GetRecordsFromServiceNow", constants.RecordTypeProblem, mock.AnythingOfType("[]string")).Return(
+		[]*serializer.ServiceNowPartialRecord{}, http.StatusOK, nil,
+	).Twice()
+	defer client.AssertExpectations(t)
+
+	p.PopulateRecordsForSubscriptions(subscriptions, client)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// handleRequests lists the open items requested for the user along with their stage and approval
+func (p *Plugin) handleRequests(_ *plugin.Context, args *model.CommandArgs, _ []string, client Client, isSysAdmin bool) string {
+	user, err := p.GetInstanceUser(args.UserId, getInstanceID(client))
+	if err != nil {
+		p.API.LogError(constants.ErrorGetUser, "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	client, err = p.getCommandClient(args.UserId, client)
+	if err != nil {
+		p.API.LogError("Unable to get the client for the user", "Error", err.Error())
+		return genericErrorMessage
+	}
+
+	go func() {
+		serviceNowUser := user.ServiceNowUser
+		if serviceNowUser == nil {
+			mmUser, appErr := p.API.GetUser(args.UserId)
+			if appErr != nil {
+				p.API.LogError(constants.ErrorGetUser, "Error", appErr.Error())
+				p.postCommandResponse(args, genericErrorMessage)
+				return
+			}
+
+			var statusCode int
+			serviceNowUser, statusCode, err = client.GetMe(mmUser.Email)
+			if err != nil {
+				p.API.LogError("Unable to get the ServiceNow user", "Error", err.Error())
+				p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+				return
+			}
+		}
+
+		requestItems, statusCode, err := client.GetOpenRequestItemsFromServiceNow(serviceNowUser.UserID)
+		if err != nil {
+			p.API.LogError("Unable to get the requested items", "Error", err.Error())
+			p.postCommandResponse(args, p.handleClientError(nil, nil, err, isSysAdmin, statusCode, args.UserId, ""))
+			return
+		}
+
+		message := constants.NoOpenRequestsMessage
+		if len(requestItems) > 0 {
+			message = serializer.GetFormattedRequestItems(requestItems, p.getServiceNowURL(client))
+		}
+
+		if subscription, err := p.GetPersonalSubscription(args.UserId); err == nil && !subscription.HasEvent(constants.PersonalSubscriptionEventMyRequests) {
+			message += fmt.Sprintf(constants.RequestsSubscribeFooter, constants.PersonalSubscriptionEventMyRequests)
+		}
+
+		p.postCommandResponse(args, message)
+	}()
+
+	return genericWaitMessage
+}
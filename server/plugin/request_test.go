@@ -0,0 +1,98 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestHandleRequests(t *testing.T) {
+	args := &model.CommandArgs{
+		UserId:    testutils.GetID(),
+		ChannelId: testutils.GetChannelID(),
+	}
+	for _, testCase := range []struct {
+		description      string
+		events           []string
+		setupClient      func(*mock_plugin.Client)
+		expectedResponse func(string) bool
+	}{
+		{
+			description: "HandleRequests: open requests are listed along with the hint for subscribing",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetOpenRequestItemsFromServiceNow", testutils.GetServiceNowSysID()).Return([]*serializer.ServiceNowRequestItem{
+					{SysID: testutils.GetServiceNowSysID(), Number: "RITM0010001", ShortDescription: "Laptop", Stage: "Waiting for Approval", Approval: "Requested", Request: "REQ0010001"},
+				}, http.StatusOK, nil)
+			},
+			expectedResponse: func(message string) bool {
+				return strings.Contains(message, "|Laptop|REQ0010001|Waiting for Approval|Requested|N/A|") &&
+					strings.Contains(message, fmt.Sprintf(constants.RequestsSubscribeFooter, constants.PersonalSubscriptionEventMyRequests))
+			},
+		},
+		{
+			description: "HandleRequests: no open requests",
+			events:      []string{constants.PersonalSubscriptionEventMyRequests},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetOpenRequestItemsFromServiceNow", testutils.GetServiceNowSysID()).Return([]*serializer.ServiceNowRequestItem{}, http.StatusOK, nil)
+			},
+			expectedResponse: func(message string) bool {
+				return message == constants.NoOpenRequestsMessage
+			},
+		},
+		{
+			description: "HandleRequests: failed to get the requested items",
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetOpenRequestItemsFromServiceNow", testutils.GetServiceNowSysID()).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			expectedResponse: func(message string) bool {
+				return message == genericErrorMessage
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			p, mockAPI := setupTestPlugin(&plugintest.API{}, nil)
+			defer mockAPI.AssertExpectations(t)
+			mockAPI.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetUser", func(_ *Plugin, _ string) (*serializer.User, error) {
+				return testutils.GetSerializerUser(), nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetPersonalSubscription", func(_ *Plugin, _ string) (*serializer.PersonalSubscription, error) {
+				return &serializer.PersonalSubscription{Events: testCase.events}, nil
+			})
+
+			mockAPI.On("SendEphemeralPost", testutils.GetID(), mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+				post := args.Get(1).(*model.Post)
+				assert.True(t, testCase.expectedResponse(post.Message), post.Message)
+			}).Once().Return(&model.Post{})
+
+			resp := p.handleRequests(&plugin.Context{}, args, nil, nil, false)
+			assert.Equal(t, genericWaitMessage, resp)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// ErrServiceAccountNotConfigured is returned when the service account is disabled or its token has not been stored by an admin
+var ErrServiceAccountNotConfigured = errors.New(constants.ErrorServiceAccountNotConfigured)
+
+// GetServiceAccountClient returns a read-only client authenticated as the service account configured by the admin
+func (p *Plugin) GetServiceAccountClient() (Client, error) {
+	config := p.getConfiguration()
+	ctx := context.Background()
+	switch config.ServiceAccountMode {
+	case constants.ServiceAccountModeClientCredentials:
+		credentials := &clientcredentials.Config{
+			ClientID:     config.ServiceNowOAuthClientID,
+			ClientSecret: config.ServiceNowOAuthClientSecret,
+			TokenURL:     fmt.Sprintf("%s/oauth_token.do", config.ServiceNowBaseURL),
+		}
+
+		identity := fmt.Sprintf("%s:%s", constants.ServiceAccountModeClientCredentials, config.ServiceNowOAuthClientID)
+		return p.NewCachedClient(p.NewServiceAccountClient(ctx, credentials.Client(ctx), identity), identity), nil
+	case constants.ServiceAccountModeAdminToken:
+		serviceAccount, err := p.store.LoadServiceAccount()
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, ErrServiceAccountNotConfigured
+			}
+
+			return nil, err
+		}
+
+		token, err := p.ParseAuthToken(serviceAccount.OAuth2Token)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenSource := &serviceAccountTokenSource{
+			plugin:         p,
+			source:         p.NewOAuth2Config().TokenSource(ctx, token),
+			serviceAccount: serviceAccount,
+			accessToken:    token.AccessToken,
+		}
+
+		identity := fmt.Sprintf("%s:%s", constants.ServiceAccountModeAdminToken, serviceAccount.ServiceNowUsername)
+		return p.NewCachedClient(p.NewServiceAccountClient(ctx, oauth2.NewClient(ctx, tokenSource), identity), identity), nil
+	default:
+		return nil, ErrServiceAccountNotConfigured
+	}
+}
+
+// serviceAccountTokenSource stores the token of the service account whenever it is refreshed.
+// Otherwise, every client would refresh the expired token again, and the rotated refresh tokens would be lost.
+type serviceAccountTokenSource struct {
+	plugin         *Plugin
+	source         oauth2.TokenSource
+	serviceAccount *serializer.ServiceAccount
+	accessToken    string
+	mutex          sync.Mutex
+}
+
+func (s *serviceAccountTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if token.AccessToken == s.accessToken {
+		return token, nil
+	}
+
+	s.accessToken = token.AccessToken
+	encryptedToken, err := s.plugin.NewEncodedAuthToken(token)
+	if err != nil {
+		s.plugin.API.LogWarn("Unable to encode the refreshed token of the service account", "Error", err.Error())
+		return token, nil
+	}
+
+	s.serviceAccount.OAuth2Token = encryptedToken
+	if err := s.plugin.store.StoreServiceAccount(s.serviceAccount); err != nil {
+		s.plugin.API.LogWarn("Unable to store the refreshed token of the service account", "Error", err.Error())
+	}
+
+	return token, nil
+}
+
+// GetReadOnlyClient returns the service account client if it is configured, otherwise the given client is returned
+func (p *Plugin) GetReadOnlyClient(client Client) Client {
+	serviceAccountClient, err := p.GetServiceAccountClient()
+	if err != nil {
+		if !errors.Is(err, ErrServiceAccountNotConfigured) {
+			p.API.LogWarn("Unable to get the service account client", "Error", err.Error())
+		}
+
+		return client
+	}
+
+	return serviceAccountClient
+}
+
+// GetClientForSubscriptionOwner returns the client of the user who created a subscription.
+// The service account is used for the subscriptions whose owner is no longer connected to ServiceNow.
+func (p *Plugin) GetClientForSubscriptionOwner(mattermostUserID string) (Client, error) {
+	client, err := p.GetClientFromMattermostUserID(mattermostUserID)
+	if err == nil {
+		return client, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	return p.GetServiceAccountClient()
+}
+
+// SetServiceAccountFromUser stores the token of the connected user as the token of the service account
+func (p *Plugin) SetServiceAccountFromUser(user *serializer.User) (*serializer.ServiceAccount, error) {
+	serviceAccount := &serializer.ServiceAccount{
+		OAuth2Token:      user.OAuth2Token,
+		MattermostUserID: user.MattermostUserID,
+		UpdatedAt:        time.Now().UnixMilli(),
+	}
+
+	if user.ServiceNowUser != nil {
+		serviceAccount.ServiceNowUsername = user.ServiceNowUser.Username
+	}
+
+	if err := p.store.StoreServiceAccount(serviceAccount); err != nil {
+		return nil, err
+	}
+
+	return serviceAccount, nil
+}
+
+// GetServiceAccountStatus returns the formatted status of the service account
+func (p *Plugin) GetServiceAccountStatus() string {
+	mode := p.getConfiguration().ServiceAccountMode
+	switch mode {
+	case constants.ServiceAccountModeClientCredentials:
+		return fmt.Sprintf("The service account is using the OAuth client credentials of the client `%s`.", p.getConfiguration().ServiceNowOAuthClientID)
+	case constants.ServiceAccountModeAdminToken:
+		serviceAccount, err := p.store.LoadServiceAccount()
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return "The service account is using an admin token, but no token has been stored yet. Run `/servicenow admin service-account set` to use your token."
+			}
+
+			p.API.LogError(constants.ErrorGetServiceAccount, "Error", err.Error())
+			return genericErrorMessage
+		}
+
+		return fmt.Sprintf("The service account is using the token of the ServiceNow user `%s` stored by a system admin on %s.", serviceAccount.ServiceNowUsername, time.UnixMilli(serviceAccount.UpdatedAt).UTC().Format(time.RFC1123))
+	default:
+		return "The service account is disabled. It can be enabled from **System Console > Plugins > ServiceNow Plugin > Service Account Mode**."
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetServiceAccountClient(t *testing.T) {
+	defer monkey.UnpatchAll()
+	for _, test := range []struct {
+		description          string
+		mode                 string
+		setupStore           func(*mock_plugin.Store)
+		setupPlugin          func(*Plugin)
+		expectedIdentity     string
+		expectedErrorMessage string
+	}{
+		{
+			description:          "Service account is disabled",
+			mode:                 constants.ServiceAccountModeDisabled,
+			setupStore:           func(s *mock_plugin.Store) {},
+			setupPlugin:          func(p *Plugin) {},
+			expectedErrorMessage: constants.ErrorServiceAccountNotConfigured,
+		},
+		{
+			description:      "Service account is using the client credentials",
+			mode:             constants.ServiceAccountModeClientCredentials,
+			setupStore:       func(s *mock_plugin.Store) {},
+			setupPlugin:      func(p *Plugin) {},
+			expectedIdentity: "client_credentials:mockServiceNowOAuthClientID",
+		},
+		{
+			description: "Service account is using an admin token",
+			mode:        constants.ServiceAccountModeAdminToken,
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadServiceAccount").Return(&serializer.ServiceAccount{
+					OAuth2Token:        "mockToken",
+					ServiceNowUsername: "mockUsername",
+				}, nil)
+			},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "ParseAuthToken", func(_ *Plugin, _ string) (*oauth2.Token, error) {
+					return &oauth2.Token{}, nil
+				})
+			},
+			expectedIdentity: "admin_token:mockUsername",
+		},
+		{
+			description: "Admin token has not been stored",
+			mode:        constants.ServiceAccountModeAdminToken,
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadServiceAccount").Return(nil, ErrNotFound)
+			},
+			setupPlugin:          func(p *Plugin) {},
+			expectedErrorMessage: constants.ErrorServiceAccountNotConfigured,
+		},
+		{
+			description: "Error occurred while loading the admin token",
+			mode:        constants.ServiceAccountModeAdminToken,
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadServiceAccount").Return(nil, fmt.Errorf("mockErrMessage"))
+			},
+			setupPlugin:          func(p *Plugin) {},
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p, _ := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{
+				ServiceNowBaseURL:       "mockServiceNowBaseURL",
+				ServiceNowOAuthClientID: "mockServiceNowOAuthClientID",
+				ServiceAccountMode:      test.mode,
+			})
+			test.setupPlugin(p)
+
+			serviceAccountClient, err := p.GetServiceAccountClient()
+			if test.expectedErrorMessage != "" {
+				assert.EqualError(t, err, test.expectedErrorMessage)
+				assert.Nil(t, serviceAccountClient)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedIdentity, serviceAccountClient.(*client).identity)
+		})
+	}
+}
+
+func TestServiceAccountTokenSource(t *testing.T) {
+	defer monkey.UnpatchAll()
+	for _, test := range []struct {
+		description string
+		accessToken string
+		setupStore  func(*mock_plugin.Store)
+	}{
+		{
+			description: "Token has not been refreshed",
+			accessToken: "mockAccessToken",
+			setupStore:  func(s *mock_plugin.Store) {},
+		},
+		{
+			description: "Refreshed token is stored",
+			accessToken: "mockRefreshedAccessToken",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreServiceAccount", &serializer.ServiceAccount{
+					OAuth2Token:        "mockEncodedToken",
+					ServiceNowUsername: "mockUsername",
+				}).Return(nil)
+			},
+		},
+		{
+			description: "Error occurred while storing the refreshed token",
+			accessToken: "mockRefreshedAccessToken",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreServiceAccount", mock.AnythingOfType("*serializer.ServiceAccount")).Return(fmt.Errorf("mockErrMessage"))
+			},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			api := &plugintest.API{}
+			api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			p, _ := setupTestPlugin(api, store)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewEncodedAuthToken", func(_ *Plugin, _ *oauth2.Token) (string, error) {
+				return "mockEncodedToken", nil
+			})
+
+			tokenSource := &serviceAccountTokenSource{
+				plugin:         p,
+				source:         oauth2.StaticTokenSource(&oauth2.Token{AccessToken: test.accessToken}),
+				serviceAccount: &serializer.ServiceAccount{OAuth2Token: "mockToken", ServiceNowUsername: "mockUsername"},
+				accessToken:    "mockAccessToken",
+			}
+
+			token, err := tokenSource.Token()
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.accessToken, token.AccessToken)
+			assert.Equal(t, test.accessToken, tokenSource.accessToken)
+		})
+	}
+}
+
+func TestGetClientForSubscriptionOwner(t *testing.T) {
+	defer monkey.UnpatchAll()
+	ownerClient := &client{}
+	serviceAccountClient := &client{identity: "mockIdentity"}
+	for _, test := range []struct {
+		description          string
+		ownerErr             error
+		expectedClient       Client
+		expectedErrorMessage string
+	}{
+		{
+			description:    "Owner of the subscription is connected",
+			expectedClient: ownerClient,
+		},
+		{
+			description:    "Owner of the subscription is no longer connected",
+			ownerErr:       ErrNotFound,
+			expectedClient: serviceAccountClient,
+		},
+		{
+			description:          "Error occurred while getting the owner of the subscription",
+			ownerErr:             fmt.Errorf("mockErrMessage"),
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p, _ := setupTestPlugin(&plugintest.API{}, nil)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				if test.ownerErr != nil {
+					return nil, test.ownerErr
+				}
+				return ownerClient, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetServiceAccountClient", func(_ *Plugin) (Client, error) {
+				return serviceAccountClient, nil
+			})
+
+			c, err := p.GetClientForSubscriptionOwner(testutils.GetID())
+			if test.expectedErrorMessage != "" {
+				assert.EqualError(t, err, test.expectedErrorMessage)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedClient, c)
+		})
+	}
+}
+
+func TestSetServiceAccountFromUser(t *testing.T) {
+	store := mock_plugin.NewStore(t)
+	store.On("StoreServiceAccount", mock.AnythingOfType("*serializer.ServiceAccount")).Return(nil)
+	p, _ := setupTestPlugin(&plugintest.API{}, store)
+
+	user := testutils.GetSerializerUser()
+	user.ServiceNowUser.Username = "mockUsername"
+	serviceAccount, err := p.SetServiceAccountFromUser(user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.OAuth2Token, serviceAccount.OAuth2Token)
+	assert.Equal(t, user.MattermostUserID, serviceAccount.MattermostUserID)
+	assert.Equal(t, "mockUsername", serviceAccount.ServiceNowUsername)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// PostSLAWarnings posts a warning in the channels subscribed to the records whose active SLAs have crossed one of the configured thresholds.
+// It runs as a cluster job, so the SLAs are polled once for the whole cluster using the service account.
+func (p *Plugin) PostSLAWarnings() {
+	if len(p.getConfiguration().SLAThresholds) == 0 {
+		return
+	}
+
+	client, err := p.GetServiceAccountClient()
+	if err != nil {
+		if !errors.Is(err, ErrServiceAccountNotConfigured) {
+			p.API.LogWarn("Unable to get the service account client", "Error", err.Error())
+		}
+		return
+	}
+
+	subscriptions, _, err := p.GetAllSubscriptionPages(client, "", "", constants.SubscriptionTypeRecord)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptions, "Error", err.Error())
+		return
+	}
+
+	recordIDs := []string{}
+	subscriptionsByRecord := map[string][]*serializer.SubscriptionResponse{}
+	for _, subscription := range subscriptions {
+		if subscriptionsByRecord[subscription.RecordID] == nil {
+			recordIDs = append(recordIDs, subscription.RecordID)
+		}
+		subscriptionsByRecord[subscription.RecordID] = append(subscriptionsByRecord[subscription.RecordID], subscription)
+	}
+
+	serviceNowURL := p.getConfiguration().ServiceNowBaseURL
+	for start := 0; start < len(recordIDs); start += constants.MaxRecordsPerBatch {
+		end := start + constants.MaxRecordsPerBatch
+		if end > len(recordIDs) {
+			end = len(recordIDs)
+		}
+
+		slas, _, err := client.GetActiveSLAsFromServiceNow(recordIDs[start:end])
+		if err != nil {
+			p.API.LogError(constants.ErrorGetSLAs, "Error", err.Error())
+			continue
+		}
+
+		for _, sla := range slas {
+			for _, subscription := range subscriptionsByRecord[sla.RecordID] {
+				if event := sla.ToEvent(subscription); p.ShouldPostSLAWarning(event) {
+					p.PostNotification(event, serviceNowURL)
+				}
+			}
+		}
+	}
+}
+
+// ShouldPostSLAWarning checks if the SLA of the event has crossed a threshold whose warning is not posted in the channel yet.
+// The SLAs are polled repeatedly, so only the highest crossed threshold is warned once.
+func (p *Plugin) ShouldPostSLAWarning(event *serializer.ServiceNowEvent) bool {
+	threshold := GetCrossedSLAThreshold(event.SLAPercentage, p.getConfiguration().SLAThresholds)
+	if threshold == 0 || event.SLAID == "" {
+		return false
+	}
+
+	marked, err := p.store.MarkSLAWarningSent(event.ChannelID, event.InstanceID, event.SLAID, threshold)
+	if err != nil {
+		p.API.LogError(constants.ErrorMarkSLAWarning, "SLAID", event.SLAID, "Error", err.Error())
+		return false
+	}
+
+	return marked
+}
+
+// GetCrossedSLAThreshold returns the highest threshold reached by the percentage of an SLA, or 0 if none is reached
+func GetCrossedSLAThreshold(percentage string, thresholds []int) int {
+	value, err := strconv.ParseFloat(strings.TrimSpace(percentage), 64)
+	if err != nil {
+		return 0
+	}
+
+	crossed := 0
+	for _, threshold := range thresholds {
+		if value >= float64(threshold) {
+			crossed = threshold
+		}
+	}
+
+	return crossed
+}
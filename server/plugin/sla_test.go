@@ -0,0 +1,259 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetCrossedSLAThreshold(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		percentage  string
+		thresholds  []int
+		expected    int
+	}{
+		{
+			description: "no threshold is crossed",
+			percentage:  "74.99",
+			thresholds:  []int{75, 90},
+		},
+		{
+			description: "threshold is reached",
+			percentage:  "75",
+			thresholds:  []int{75, 90},
+			expected:    75,
+		},
+		{
+			description: "highest crossed threshold is returned",
+			percentage:  "112.3",
+			thresholds:  []int{75, 90},
+			expected:    90,
+		},
+		{
+			description: "no thresholds are configured",
+			percentage:  "95",
+		},
+		{
+			description: "invalid percentage",
+			percentage:  "mockPercentage",
+			thresholds:  []int{75, 90},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, GetCrossedSLAThreshold(testCase.percentage, testCase.thresholds))
+		})
+	}
+}
+
+func TestPostSLAWarnings(t *testing.T) {
+	defer monkey.UnpatchAll()
+	subscription := &serializer.SubscriptionResponse{
+		SysID:      testutils.GetServiceNowSysID(),
+		ChannelID:  testutils.GetChannelID(),
+		UserID:     testutils.GetID(),
+		RecordType: constants.RecordTypeIncident,
+		RecordID:   "mockRecordID",
+		Type:       constants.SubscriptionTypeRecord,
+	}
+	sla := &serializer.ServiceNowTaskSLA{
+		SysID:      "mockSLAID",
+		Name:       "Priority 1 resolution",
+		Percentage: "91.27",
+		TimeLeft:   "23 Minutes",
+		RecordID:   "mockRecordID",
+		Number:     "INC0000001",
+	}
+	for _, testCase := range []struct {
+		description         string
+		thresholds          []int
+		serviceAccountError error
+		setupAPI            func(*plugintest.API)
+		setupClient         func(*mock_plugin.Client)
+		setupStore          func(*mock_plugin.Store)
+	}{
+		{
+			description: "SLA has crossed a threshold",
+			thresholds:  []int{75, 90},
+			setupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID() && strings.Contains(post.Attachments()[0].Text, "Priority 1 resolution has reached 91% of its time")
+				})).Return(&model.Post{}, nil)
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetAllSubscriptions", "", "", constants.SubscriptionTypeRecord, fmt.Sprint(constants.MaxPerPage), "0").Return([]*serializer.SubscriptionResponse{subscription}, http.StatusOK, nil)
+				c.On("GetActiveSLAsFromServiceNow", []string{"mockRecordID"}).Return([]*serializer.ServiceNowTaskSLA{sla}, http.StatusOK, nil)
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("MarkSLAWarningSent", testutils.GetChannelID(), "", "mockSLAID", 90).Return(true, nil)
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(nil, ErrNotFound)
+				s.On("LoadMute", mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+			},
+		},
+		{
+			description: "warning is already posted",
+			thresholds:  []int{75, 90},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetAllSubscriptions", "", "", constants.SubscriptionTypeRecord, fmt.Sprint(constants.MaxPerPage), "0").Return([]*serializer.SubscriptionResponse{subscription}, http.StatusOK, nil)
+				c.On("GetActiveSLAsFromServiceNow", []string{"mockRecordID"}).Return([]*serializer.ServiceNowTaskSLA{sla}, http.StatusOK, nil)
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("MarkSLAWarningSent", testutils.GetChannelID(), "", "mockSLAID", 90).Return(false, nil)
+			},
+		},
+		{
+			description: "warnings are disabled",
+			setupAPI:    func(_ *plugintest.API) {},
+			setupClient: func(_ *mock_plugin.Client) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+		},
+		{
+			description:         "service account is not configured",
+			thresholds:          []int{75, 90},
+			serviceAccountError: ErrServiceAccountNotConfigured,
+			setupAPI:            func(_ *plugintest.API) {},
+			setupClient:         func(_ *mock_plugin.Client) {},
+			setupStore:          func(_ *mock_plugin.Store) {},
+		},
+		{
+			description: "failed to get the subscriptions",
+			thresholds:  []int{75, 90},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetAllSubscriptions", "", "", constants.SubscriptionTypeRecord, fmt.Sprint(constants.MaxPerPage), "0").Return(nil, http.StatusForbidden, errors.New("mockError"))
+			},
+			setupStore: func(_ *mock_plugin.Store) {},
+		},
+		{
+			description: "failed to get the SLAs",
+			thresholds:  []int{75, 90},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetAllSubscriptions", "", "", constants.SubscriptionTypeRecord, fmt.Sprint(constants.MaxPerPage), "0").Return([]*serializer.SubscriptionResponse{subscription}, http.StatusOK, nil)
+				c.On("GetActiveSLAsFromServiceNow", []string{"mockRecordID"}).Return(nil, http.StatusInternalServerError, errors.New("mockError"))
+			},
+			setupStore: func(_ *mock_plugin.Store) {},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			client := mock_plugin.NewClient(t)
+			testCase.setupClient(client)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{SLAThresholds: testCase.thresholds})
+			testCase.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetServiceAccountClient", func(_ *Plugin) (Client, error) {
+				if testCase.serviceAccountError != nil {
+					return nil, testCase.serviceAccountError
+				}
+				return client, nil
+			})
+
+			p.PostSLAWarnings()
+		})
+	}
+}
+
+func TestShouldPostSLAWarning(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		event       *serializer.ServiceNowEvent
+		setupAPI    func(*plugintest.API)
+		setupStore  func(*mock_plugin.Store)
+		expected    bool
+	}{
+		{
+			description: "SLA has crossed a threshold for the first time",
+			event:       &serializer.ServiceNowEvent{ChannelID: testutils.GetChannelID(), SLAID: testutils.GetServiceNowSysID(), SLAPercentage: "91.2"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("MarkSLAWarningSent", testutils.GetChannelID(), "", testutils.GetServiceNowSysID(), 90).Return(true, nil)
+			},
+			expected: true,
+		},
+		{
+			description: "warning is already posted",
+			event:       &serializer.ServiceNowEvent{ChannelID: testutils.GetChannelID(), SLAID: testutils.GetServiceNowSysID(), SLAPercentage: "80"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("MarkSLAWarningSent", testutils.GetChannelID(), "", testutils.GetServiceNowSysID(), 75).Return(false, nil)
+			},
+		},
+		{
+			description: "SLA has not crossed any threshold",
+			event:       &serializer.ServiceNowEvent{ChannelID: testutils.GetChannelID(), SLAID: testutils.GetServiceNowSysID(), SLAPercentage: "50"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+		},
+		{
+			description: "SLA ID is missing",
+			event:       &serializer.ServiceNowEvent{ChannelID: testutils.GetChannelID(), SLAPercentage: "95"},
+			setupAPI:    func(_ *plugintest.API) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+		},
+		{
+			description: "failed to mark the warning as sent",
+			event:       &serializer.ServiceNowEvent{ChannelID: testutils.GetChannelID(), SLAID: testutils.GetServiceNowSysID(), SLAPercentage: "95"},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("MarkSLAWarningSent", testutils.GetChannelID(), "", testutils.GetServiceNowSysID(), 90).Return(false, errors.New("mockError"))
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			testCase.setupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			p.setConfiguration(&configuration{SLAThresholds: []int{75, 90}})
+			testCase.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			assert.Equal(t, testCase.expected, p.ShouldPostSLAWarning(testCase.event))
+		})
+	}
+}
+
+func TestCreateSLAWarningPost(t *testing.T) {
+	event := &serializer.ServiceNowEvent{
+		ChannelID:     testutils.GetChannelID(),
+		RecordType:    constants.RecordTypeIncident,
+		EventOccurred: constants.SubscriptionEventSLA,
+		SLAName:       "Priority 1 resolution",
+		SLAPercentage: "91.27",
+		SLATimeLeft:   "23 Minutes",
+		SLABreached:   false,
+	}
+
+	attachment := event.CreateNotificationPost("mockBotID", "https://example.service-now.com", "https://example.com/plugins/servicenow").Attachments()[0]
+	assert.True(t, strings.Contains(attachment.Text, "Priority 1 resolution has reached 91% of its time"), attachment.Text)
+	fields := attachment.Fields
+	assert.Equal(t, "Priority 1 resolution: 91%", fields[len(fields)-2].Value)
+	assert.Equal(t, "23 Minutes", fields[len(fields)-1].Value)
+}
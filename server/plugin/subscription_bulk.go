@@ -0,0 +1,129 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// BulkUpdateSubscriptions applies the action of the request on each of its subscriptions using a bounded number of goroutines.
+// When the request has a channel instead of the subscription IDs, the action is applied on all the subscriptions of the channel.
+func (p *Plugin) BulkUpdateSubscriptions(client Client, mattermostUserID string, request *serializer.BulkSubscriptionRequest) (*serializer.BulkSubscriptionResult, int, error) {
+	var subscriptions []*serializer.SubscriptionResponse
+	subscriptionIDs := getUniqueValues(request.SubscriptionIDs)
+	if request.ChannelID != "" {
+		var statusCode int
+		var err error
+		// Only the deactivated subscriptions can be reactivated
+		if request.Action == constants.BulkActionReactivate {
+			subscriptions, statusCode, err = p.GetAllInactiveSubscriptionPages(client, request.ChannelID)
+		} else {
+			subscriptions, statusCode, err = p.GetAllSubscriptionPages(client, request.ChannelID, "", "")
+		}
+		if err != nil {
+			return nil, statusCode, err
+		}
+
+		subscriptionIDs = make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			subscriptionIDs = append(subscriptionIDs, subscription.SysID)
+		}
+	}
+
+	result := &serializer.BulkSubscriptionResult{
+		Action: request.Action,
+		Items:  make([]*serializer.BulkSubscriptionItem, len(subscriptionIDs)),
+	}
+
+	runWithWorkerPool(len(subscriptionIDs), constants.SubscriptionsWorkerPoolSize, func(i int) {
+		item := &serializer.BulkSubscriptionItem{
+			SubscriptionID: subscriptionIDs[i],
+			Status:         constants.BulkStatusSucceeded,
+		}
+
+		var subscription *serializer.SubscriptionResponse
+		if request.ChannelID != "" {
+			subscription = subscriptions[i]
+		}
+
+		if err := p.bulkUpdateSubscription(client, mattermostUserID, subscriptionIDs[i], subscription, request); err != nil {
+			item.Status = constants.BulkStatusFailed
+			item.Error = err.Error()
+		}
+
+		result.Items[i] = item
+	})
+
+	return result, http.StatusOK, nil
+}
+
+// bulkUpdateSubscription applies the action of the request on a single subscription, which is fetched from ServiceNow when it is nil
+func (p *Plugin) bulkUpdateSubscription(client Client, mattermostUserID, subscriptionID string, subscription *serializer.SubscriptionResponse, request *serializer.BulkSubscriptionRequest) error {
+	if subscription == nil {
+		var statusCode int
+		var err error
+		subscription, statusCode, err = client.GetSubscription(subscriptionID)
+		if err != nil {
+			if statusCode == http.StatusNotFound {
+				return errors.New(constants.ErrorSubscriptionNotFound)
+			}
+
+			p.API.LogError("Unable to get subscription", "SubscriptionID", subscriptionID, "Error", err.Error())
+			return err
+		}
+	}
+
+	if _, err := p.HasPublicOrPrivateChannelPermissions(mattermostUserID, subscription.ChannelID); err != nil {
+		return err
+	}
+
+	switch request.Action {
+	case constants.BulkActionDelete:
+		if _, err := client.DeleteSubscription(subscriptionID); err != nil {
+			p.API.LogError(constants.ErrorDeleteSubscription, "SubscriptionID", subscriptionID, "Error", err.Error())
+			return err
+		}
+
+		if err := p.store.DeleteSubscriptionSettings(getInstanceID(client), subscriptionID); err != nil {
+			p.API.LogWarn("Unable to delete the settings of the subscription", "SubscriptionID", subscriptionID, "Error", err.Error())
+		}
+
+		return nil
+	case constants.BulkActionDeactivate:
+		isActive := false
+		return p.editSubscriptionInBulk(client, subscriptionID, &serializer.SubscriptionPayload{IsActive: &isActive})
+	case constants.BulkActionReactivate:
+		// A subscription can't be reactivated when another active subscription already exists for the same record in the channel
+		exists, _, err := client.CheckForDuplicateSubscription(subscription.GetPayloadForImport(subscription.ChannelID, p.getConfiguration().MattermostSiteURL))
+		if err != nil {
+			p.API.LogError("Error in checking for duplicate subscription", "Error", err.Error())
+			return err
+		}
+
+		if exists {
+			return errors.New(constants.ErrorDuplicateActiveSubscription)
+		}
+
+		isActive := true
+		return p.editSubscriptionInBulk(client, subscriptionID, &serializer.SubscriptionPayload{IsActive: &isActive})
+	case constants.BulkActionEditEvents:
+		return p.editSubscriptionInBulk(client, subscriptionID, &serializer.SubscriptionPayload{SubscriptionEvents: &request.SubscriptionEvents})
+	default:
+		return errors.New("action is not valid")
+	}
+}
+
+func (p *Plugin) editSubscriptionInBulk(client Client, subscriptionID string, payload *serializer.SubscriptionPayload) error {
+	if _, _, err := client.EditSubscription(subscriptionID, payload); err != nil {
+		p.API.LogError(constants.ErrorEditingSubscription, "SubscriptionID", subscriptionID, "Error", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestBulkUpdateSubscriptions(t *testing.T) {
+	otherSysID := "abcdef0123456789abcdef0123456789"
+	for _, test := range []struct {
+		description      string
+		request          *serializer.BulkSubscriptionRequest
+		hasPermission    bool
+		setupClient      func(*mock_plugin.Client)
+		setupStore       func(*mock_plugin.Store)
+		setupPlugin      func(*Plugin)
+		expectedStatuses []string
+		expectedErrors   []string
+		expectedErr      string
+	}{
+		{
+			description: "Given subscriptions are deleted along with their settings",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:          constants.BulkActionDelete,
+				SubscriptionIDs: []string{testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()},
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil).Once()
+				client.On("DeleteSubscription", testutils.GetServiceNowSysID()).Return(http.StatusOK, nil).Once()
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("DeleteSubscriptionSettings", constants.DefaultInstanceID, testutils.GetServiceNowSysID()).Return(nil).Once()
+			},
+			setupPlugin:      func(_ *Plugin) {},
+			expectedStatuses: []string{constants.BulkStatusSucceeded},
+			expectedErrors:   []string{""},
+		},
+		{
+			description: "Missing subscriptions and the subscriptions of the channels without permissions fail",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:          constants.BulkActionDeactivate,
+				SubscriptionIDs: []string{testutils.GetServiceNowSysID(), otherSysID},
+			},
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(nil, http.StatusNotFound, fmt.Errorf("mockError")).Once()
+				client.On("GetSubscription", otherSysID).Return(testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil).Once()
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			setupPlugin:      func(_ *Plugin) {},
+			expectedStatuses: []string{constants.BulkStatusFailed, constants.BulkStatusFailed},
+			expectedErrors:   []string{constants.ErrorSubscriptionNotFound, constants.ErrorInsufficientPermissions},
+		},
+		{
+			description: "All the subscriptions of the channel are deactivated",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:    constants.BulkActionDeactivate,
+				ChannelID: testutils.GetChannelID(),
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("EditSubscription", testutils.GetServiceNowSysID(), mock.MatchedBy(func(payload *serializer.SubscriptionPayload) bool {
+					return payload.IsActive != nil && !*payload.IsActive && payload.SubscriptionEvents == nil
+				})).Return(&serializer.SubscriptionResponse{}, http.StatusOK, nil).Twice()
+			},
+			setupStore: func(_ *mock_plugin.Store) {},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetAllSubscriptionPages", func(_ *Plugin, _ Client, channelID, _, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+					assert.Equal(t, testutils.GetChannelID(), channelID)
+					return testutils.GetSubscriptions(2), http.StatusOK, nil
+				})
+			},
+			expectedStatuses: []string{constants.BulkStatusSucceeded, constants.BulkStatusSucceeded},
+			expectedErrors:   []string{"", ""},
+		},
+		{
+			description: "Deactivated subscriptions having an active duplicate are not reactivated",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:    constants.BulkActionReactivate,
+				ChannelID: testutils.GetChannelID(),
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(true, http.StatusOK, nil).Once()
+			},
+			setupStore: func(_ *mock_plugin.Store) {},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetAllInactiveSubscriptionPages", func(_ *Plugin, _ Client, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+					return testutils.GetSubscriptions(1), http.StatusOK, nil
+				})
+			},
+			expectedStatuses: []string{constants.BulkStatusFailed},
+			expectedErrors:   []string{constants.ErrorDuplicateActiveSubscription},
+		},
+		{
+			description: "Deactivated subscriptions are reactivated",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:          constants.BulkActionReactivate,
+				SubscriptionIDs: []string{testutils.GetServiceNowSysID()},
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil).Once()
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(false, http.StatusOK, nil).Once()
+				client.On("EditSubscription", testutils.GetServiceNowSysID(), mock.MatchedBy(func(payload *serializer.SubscriptionPayload) bool {
+					return payload.IsActive != nil && *payload.IsActive
+				})).Return(&serializer.SubscriptionResponse{}, http.StatusOK, nil).Once()
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			setupPlugin:      func(_ *Plugin) {},
+			expectedStatuses: []string{constants.BulkStatusSucceeded},
+			expectedErrors:   []string{""},
+		},
+		{
+			description: "Failed to edit the events of the subscription",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:             constants.BulkActionEditEvents,
+				SubscriptionIDs:    []string{testutils.GetServiceNowSysID()},
+				SubscriptionEvents: constants.SubscriptionEventState,
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("GetSubscription", testutils.GetServiceNowSysID()).Return(testutils.GetSubscription(constants.SubscriptionTypeBulk), http.StatusOK, nil).Once()
+				client.On("EditSubscription", testutils.GetServiceNowSysID(), mock.MatchedBy(func(payload *serializer.SubscriptionPayload) bool {
+					return *payload.SubscriptionEvents == constants.SubscriptionEventState && payload.IsActive == nil
+				})).Return(nil, http.StatusInternalServerError, fmt.Errorf("mockError")).Once()
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			setupPlugin:      func(_ *Plugin) {},
+			expectedStatuses: []string{constants.BulkStatusFailed},
+			expectedErrors:   []string{"mockError"},
+		},
+		{
+			description: "Failed to get the subscriptions of the channel",
+			request: &serializer.BulkSubscriptionRequest{
+				Action:    constants.BulkActionDelete,
+				ChannelID: testutils.GetChannelID(),
+			},
+			setupClient: func(_ *mock_plugin.Client) {},
+			setupStore:  func(_ *mock_plugin.Store) {},
+			setupPlugin: func(p *Plugin) {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetAllSubscriptionPages", func(_ *Plugin, _ Client, _, _, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+					return nil, http.StatusInternalServerError, fmt.Errorf("mockError")
+				})
+			},
+			expectedErr: "mockError",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			store := mock_plugin.NewStore(t)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			api.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return().Maybe()
+			client := mock_plugin.NewClient(t)
+			test.setupClient(client)
+			test.setupStore(store)
+			test.setupPlugin(p)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+				if test.hasPermission {
+					return http.StatusOK, nil
+				}
+
+				return http.StatusForbidden, fmt.Errorf(constants.ErrorInsufficientPermissions)
+			})
+
+			result, _, err := p.BulkUpdateSubscriptions(client, testutils.GetID(), test.request)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.request.Action, result.Action)
+			require.Len(t, result.Items, len(test.expectedStatuses))
+			for i, item := range result.Items {
+				assert.Equal(t, test.expectedStatuses[i], item.Status)
+				assert.Equal(t, test.expectedErrors[i], item.Error)
+			}
+		})
+	}
+}
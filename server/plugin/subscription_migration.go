@@ -0,0 +1,244 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+var subscriptionExportCSVHeader = []string{"sys_id", "channel_id", "user_id", "type", "record_type", "record_id", "subscription_events", "mentions"}
+
+// GetSubscriptionsForExport returns all the subscriptions, optionally filtered by a channel or a team, along with their settings
+func (p *Plugin) GetSubscriptionsForExport(client Client, channelID, teamID string) ([]*serializer.SubscriptionResponse, int, error) {
+	subscriptions, statusCode, err := p.GetAllSubscriptionPages(client, channelID, "", "")
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	if teamID != "" {
+		channelTeams := map[string]string{}
+		n := 0
+		for _, subscription := range subscriptions {
+			if _, ok := channelTeams[subscription.ChannelID]; !ok {
+				channel, appErr := p.API.GetChannel(subscription.ChannelID)
+				if appErr != nil {
+					p.API.LogWarn("Unable to get the channel of the subscription", "ChannelID", subscription.ChannelID, "Error", appErr.Error())
+				} else {
+					channelTeams[subscription.ChannelID] = channel.TeamId
+				}
+			}
+
+			if channelTeams[subscription.ChannelID] == teamID {
+				subscriptions[n] = subscription
+				n++
+			}
+		}
+
+		subscriptions = subscriptions[:n]
+	}
+
+	for _, subscription := range subscriptions {
+		settings, err := p.GetSubscriptionSettings(subscription.InstanceID, subscription.SysID)
+		if err != nil {
+			p.API.LogWarn(constants.ErrorGetSubscriptionSettings, "SubscriptionID", subscription.SysID, "Error", err.Error())
+			continue
+		}
+
+		subscription.SetSettings(settings)
+	}
+
+	return subscriptions, statusCode, nil
+}
+
+// EncodeSubscriptionsForExport encodes the subscriptions in a format which can be imported again
+func EncodeSubscriptionsForExport(subscriptions []*serializer.SubscriptionResponse, format string) ([]byte, error) {
+	switch format {
+	case constants.ExportFormatJSON:
+		if subscriptions == nil {
+			subscriptions = []*serializer.SubscriptionResponse{}
+		}
+
+		return json.MarshalIndent(subscriptions, "", "  ")
+	case constants.ExportFormatCSV:
+		buf := &bytes.Buffer{}
+		w := csv.NewWriter(buf)
+		if err := w.Write(subscriptionExportCSVHeader); err != nil {
+			return nil, err
+		}
+
+		for _, subscription := range subscriptions {
+			if err := w.Write([]string{
+				subscription.SysID,
+				subscription.ChannelID,
+				subscription.UserID,
+				subscription.Type,
+				subscription.RecordType,
+				subscription.RecordID,
+				subscription.SubscriptionEvents,
+				subscription.Mentions,
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.New(constants.ErrorInvalidExportFormat)
+	}
+}
+
+// DecodeSubscriptionsForImport decodes the subscriptions exported using EncodeSubscriptionsForExport
+func DecodeSubscriptionsForImport(data []byte, format string) ([]*serializer.SubscriptionResponse, error) {
+	switch format {
+	case constants.ExportFormatJSON:
+		var subscriptions []*serializer.SubscriptionResponse
+		if err := json.Unmarshal(data, &subscriptions); err != nil {
+			return nil, err
+		}
+
+		return subscriptions, nil
+	case constants.ExportFormatCSV:
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = len(subscriptionExportCSVHeader)
+		if _, err := r.Read(); err != nil {
+			return nil, errors.Wrap(err, "failed to read the header")
+		}
+
+		var subscriptions []*serializer.SubscriptionResponse
+		for {
+			row, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			subscriptions = append(subscriptions, &serializer.SubscriptionResponse{
+				SysID:              row[0],
+				ChannelID:          row[1],
+				UserID:             row[2],
+				Type:               row[3],
+				RecordType:         row[4],
+				RecordID:           row[5],
+				SubscriptionEvents: row[6],
+				Mentions:           row[7],
+			})
+		}
+
+		return subscriptions, nil
+	default:
+		return nil, errors.New(constants.ErrorInvalidExportFormat)
+	}
+}
+
+// ImportSubscriptions creates the given subscriptions in the channels to which their channels are mapped.
+// The subscriptions which already exist in the channels are reported as duplicates and are not created again.
+// When dryRun is true, the subscriptions are only validated.
+func (p *Plugin) ImportSubscriptions(client Client, subscriptions []*serializer.SubscriptionResponse, channelMap map[string]string, dryRun bool) *serializer.SubscriptionImportResult {
+	result := &serializer.SubscriptionImportResult{
+		DryRun: dryRun,
+		Items:  make([]*serializer.SubscriptionImportItem, 0, len(subscriptions)),
+	}
+
+	siteURL := p.getConfiguration().MattermostSiteURL
+	imported := map[string]bool{}
+	for _, subscription := range subscriptions {
+		channelID := subscription.ChannelID
+		if mappedChannelID, ok := channelMap[channelID]; ok {
+			channelID = mappedChannelID
+		}
+
+		item := &serializer.SubscriptionImportItem{
+			SourceID:  subscription.SysID,
+			ChannelID: channelID,
+		}
+		result.Items = append(result.Items, item)
+
+		payload := subscription.GetPayloadForImport(channelID, siteURL)
+		payload.InstanceID = getInstanceID(client)
+		if err := payload.IsValidForCreation(siteURL); err != nil {
+			item.Status = constants.ImportStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+
+		if _, err := p.HasPublicOrPrivateChannelPermissions(subscription.UserID, channelID); err != nil {
+			item.Status = constants.ImportStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s/%s", channelID, *payload.Type, *payload.RecordType, *payload.RecordID)
+		if imported[key] {
+			item.Status = constants.ImportStatusDuplicate
+			item.Error = constants.ErrorDuplicateInImport
+			continue
+		}
+		imported[key] = true
+
+		exists, _, err := client.CheckForDuplicateSubscription(payload)
+		if err != nil {
+			p.API.LogError("Error in checking for duplicate subscription", "Error", err.Error())
+			item.Status = constants.ImportStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+
+		if exists {
+			item.Status = constants.ImportStatusDuplicate
+			continue
+		}
+
+		if dryRun {
+			item.Status = constants.ImportStatusReady
+			continue
+		}
+
+		settings := payload.TakeSettings()
+		created, _, err := client.CreateSubscription(payload)
+		if err != nil {
+			p.API.LogError("Error in creating subscription", "Error", err.Error())
+			item.Status = constants.ImportStatusFailed
+			item.Error = err.Error()
+			continue
+		}
+
+		if settings != nil {
+			if err = p.store.StoreSubscriptionSettings(payload.InstanceID, created.SysID, settings); err != nil {
+				p.API.LogError(constants.ErrorStoreSubscriptionSettings, "SubscriptionID", created.SysID, "Error", err.Error())
+			}
+		}
+
+		item.Status = constants.ImportStatusCreated
+		item.SubscriptionID = created.SysID
+	}
+
+	return result
+}
+
+// CloneChannelSubscriptions creates a copy of all the subscriptions of a channel in another channel
+func (p *Plugin) CloneChannelSubscriptions(client Client, fromChannelID, toChannelID string, dryRun bool) (*serializer.SubscriptionImportResult, int, error) {
+	subscriptions, statusCode, err := p.GetSubscriptionsForExport(client, fromChannelID, "")
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return p.ImportSubscriptions(client, subscriptions, map[string]string{fromChannelID: toChannelID}, dryRun), statusCode, nil
+}
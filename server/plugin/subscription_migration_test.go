@@ -0,0 +1,231 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestEncodeAndDecodeSubscriptionsForExport(t *testing.T) {
+	subscription := testutils.GetSubscription(constants.SubscriptionTypeRecord)
+	subscription.RecordID = testutils.GetServiceNowSysID()
+	subscription.Mentions = constants.MentionSettingAssignee
+	for _, format := range []string{constants.ExportFormatJSON, constants.ExportFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			data, err := EncodeSubscriptionsForExport([]*serializer.SubscriptionResponse{subscription}, format)
+			require.NoError(t, err)
+
+			subscriptions, err := DecodeSubscriptionsForImport(data, format)
+			require.NoError(t, err)
+			require.Len(t, subscriptions, 1)
+			assert.Equal(t, subscription.SysID, subscriptions[0].SysID)
+			assert.Equal(t, subscription.ChannelID, subscriptions[0].ChannelID)
+			assert.Equal(t, subscription.UserID, subscriptions[0].UserID)
+			assert.Equal(t, subscription.Type, subscriptions[0].Type)
+			assert.Equal(t, subscription.RecordType, subscriptions[0].RecordType)
+			assert.Equal(t, subscription.RecordID, subscriptions[0].RecordID)
+			assert.Equal(t, subscription.SubscriptionEvents, subscriptions[0].SubscriptionEvents)
+			assert.Equal(t, subscription.Mentions, subscriptions[0].Mentions)
+		})
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := EncodeSubscriptionsForExport(nil, "xml")
+		assert.EqualError(t, err, constants.ErrorInvalidExportFormat)
+
+		_, err = DecodeSubscriptionsForImport(nil, "xml")
+		assert.EqualError(t, err, constants.ErrorInvalidExportFormat)
+	})
+
+	t.Run("invalid CSV", func(t *testing.T) {
+		_, err := DecodeSubscriptionsForImport([]byte("sys_id,channel_id\nmockSysID,mockChannelID"), constants.ExportFormatCSV)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSubscriptionsForExport(t *testing.T) {
+	defer monkey.UnpatchAll()
+	store := &mock_plugin.Store{}
+	store.On("LoadSubscriptionSettings", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+	p, api := setupTestPlugin(&plugintest.API{}, store)
+	client := mock_plugin.NewClient(t)
+
+	subscriptions := testutils.GetSubscriptions(2)
+	subscriptions[1].ChannelID = testutils.GetChannelID()
+	monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetAllSubscriptionPages", func(_ *Plugin, _ Client, _, _, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+		return subscriptions, http.StatusOK, nil
+	})
+
+	api.On("GetChannel", testutils.GetID()).Return(&model.Channel{Id: testutils.GetID(), TeamId: "mockTeamID"}, nil).Once()
+	api.On("GetChannel", testutils.GetChannelID()).Return(&model.Channel{Id: testutils.GetChannelID(), TeamId: "mockOtherTeamID"}, nil).Once()
+	defer api.AssertExpectations(t)
+
+	exported, _, err := p.GetSubscriptionsForExport(client, "", "mockTeamID")
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	assert.Equal(t, testutils.GetID(), exported[0].ChannelID)
+	assert.Equal(t, constants.MentionSettingOff, exported[0].Mentions)
+}
+
+func TestImportSubscriptions(t *testing.T) {
+	mentions := constants.MentionSettingAssignee
+	for _, test := range []struct {
+		description      string
+		dryRun           bool
+		channelMap       map[string]string
+		getSubscriptions func() []*serializer.SubscriptionResponse
+		hasPermission    bool
+		setupClient      func(*mock_plugin.Client)
+		setupStore       func(*mock_plugin.Store)
+		expectedStatuses []string
+		expectedChannel  string
+	}{
+		{
+			description: "Dry run reports the subscriptions which can be imported",
+			dryRun:      true,
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				return []*serializer.SubscriptionResponse{testutils.GetSubscription(constants.SubscriptionTypeBulk)}
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(false, http.StatusOK, nil)
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedStatuses: []string{constants.ImportStatusReady},
+			expectedChannel:  testutils.GetID(),
+		},
+		{
+			description: "Subscriptions are created in the mapped channels along with their settings",
+			channelMap:  map[string]string{testutils.GetID(): testutils.GetChannelID()},
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				subscription := testutils.GetSubscription(constants.SubscriptionTypeBulk)
+				subscription.Mentions = mentions
+				return []*serializer.SubscriptionResponse{subscription}
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(false, http.StatusOK, nil)
+				client.On("CreateSubscription", mock.MatchedBy(func(payload *serializer.SubscriptionPayload) bool {
+					return *payload.ChannelID == testutils.GetChannelID() && payload.Mentions == nil
+				})).Return(&serializer.SubscriptionResponse{SysID: "mockNewSysID"}, http.StatusOK, nil)
+			},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("StoreSubscriptionSettings", constants.DefaultInstanceID, "mockNewSysID", &serializer.SubscriptionSettings{Mentions: mentions}).Return(nil)
+			},
+			expectedStatuses: []string{constants.ImportStatusCreated},
+			expectedChannel:  testutils.GetChannelID(),
+		},
+		{
+			description: "Duplicate subscriptions are not created",
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				return testutils.GetSubscriptions(1)
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(true, http.StatusOK, nil)
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedStatuses: []string{constants.ImportStatusDuplicate},
+			expectedChannel:  testutils.GetID(),
+		},
+		{
+			description: "Subscriptions repeated in the import are reported as duplicates",
+			dryRun:      true,
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				return []*serializer.SubscriptionResponse{testutils.GetSubscription(constants.SubscriptionTypeBulk), testutils.GetSubscription(constants.SubscriptionTypeBulk)}
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(false, http.StatusOK, nil).Once()
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedStatuses: []string{constants.ImportStatusReady, constants.ImportStatusDuplicate},
+			expectedChannel:  testutils.GetID(),
+		},
+		{
+			description: "Invalid subscriptions and the owners without the channel permissions fail",
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				return []*serializer.SubscriptionResponse{testutils.GetSubscription(constants.SubscriptionTypeRecord), testutils.GetSubscription(constants.SubscriptionTypeBulk)}
+			},
+			setupClient:      func(_ *mock_plugin.Client) {},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedStatuses: []string{constants.ImportStatusFailed, constants.ImportStatusFailed},
+			expectedChannel:  testutils.GetID(),
+		},
+		{
+			description: "Failed to create the subscription",
+			getSubscriptions: func() []*serializer.SubscriptionResponse {
+				return testutils.GetSubscriptions(1)
+			},
+			hasPermission: true,
+			setupClient: func(client *mock_plugin.Client) {
+				client.On("CheckForDuplicateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(false, http.StatusOK, nil)
+				client.On("CreateSubscription", mock.AnythingOfType("*serializer.SubscriptionPayload")).Return(nil, http.StatusInternalServerError, fmt.Errorf("mockError"))
+			},
+			setupStore:       func(_ *mock_plugin.Store) {},
+			expectedStatuses: []string{constants.ImportStatusFailed},
+			expectedChannel:  testutils.GetID(),
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			defer monkey.UnpatchAll()
+			store := mock_plugin.NewStore(t)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			api.On("LogError", testutils.GetMockArgumentsWithType("string", 3)...).Return().Maybe()
+			client := mock_plugin.NewClient(t)
+			test.setupClient(client)
+			test.setupStore(store)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+				if test.hasPermission {
+					return http.StatusOK, nil
+				}
+
+				return http.StatusForbidden, fmt.Errorf(constants.ErrorInsufficientPermissions)
+			})
+
+			result := p.ImportSubscriptions(client, test.getSubscriptions(), test.channelMap, test.dryRun)
+			assert.Equal(t, test.dryRun, result.DryRun)
+			require.Len(t, result.Items, len(test.expectedStatuses))
+			for i, item := range result.Items {
+				assert.Equal(t, test.expectedStatuses[i], item.Status)
+				assert.Equal(t, test.expectedChannel, item.ChannelID)
+			}
+		})
+	}
+}
+
+func TestCloneChannelSubscriptions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	p, _ := setupTestPlugin(&plugintest.API{}, nil)
+	client := mock_plugin.NewClient(t)
+	monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetSubscriptionsForExport", func(_ *Plugin, _ Client, channelID, _ string) ([]*serializer.SubscriptionResponse, int, error) {
+		assert.Equal(t, testutils.GetID(), channelID)
+		return []*serializer.SubscriptionResponse{testutils.GetSubscription(constants.SubscriptionTypeBulk)}, http.StatusOK, nil
+	})
+	monkey.PatchInstanceMethod(reflect.TypeOf(p), "HasPublicOrPrivateChannelPermissions", func(_ *Plugin, _, _ string) (int, error) {
+		return http.StatusOK, nil
+	})
+	client.On("CheckForDuplicateSubscription", mock.MatchedBy(func(payload *serializer.SubscriptionPayload) bool {
+		return *payload.ChannelID == testutils.GetChannelID()
+	})).Return(false, http.StatusOK, nil)
+
+	result, _, err := p.CloneChannelSubscriptions(client, testutils.GetID(), testutils.GetChannelID(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Count(constants.ImportStatusReady))
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// ParseMuteDuration parses a duration like 30m or 2h. Days are also supported, like 1d.
+func ParseMuteDuration(value string) (time.Duration, error) {
+	duration, err := parseDurationWithDays(value)
+	if err != nil || duration <= 0 || duration > constants.MaxMuteDuration {
+		return 0, errors.New(constants.ErrorInvalidMuteDuration)
+	}
+
+	return duration, nil
+}
+
+// parseDurationWithDays parses a duration of the time package, extended with the days like 1d
+func parseDurationWithDays(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		return time.Duration(count) * 24 * time.Hour, err
+	}
+
+	return time.ParseDuration(value)
+}
+
+// GetNotificationMute returns the active mute of the subscription or the channel of the event, if any
+func (p *Plugin) GetNotificationMute(event *serializer.ServiceNowEvent) *serializer.SubscriptionMute {
+	keys := []string{serializer.GetMuteKey(event.ChannelID, "", "")}
+	if event.SubscriptionID != "" {
+		keys = append(keys, serializer.GetMuteKey(event.ChannelID, event.InstanceID, event.SubscriptionID))
+	}
+
+	for _, key := range keys {
+		mute, err := p.store.LoadMute(key)
+		if err != nil {
+			if err != ErrNotFound {
+				p.API.LogWarn(constants.ErrorGetMute, "Key", key, "Error", err.Error())
+			}
+			continue
+		}
+
+		// The mute is stored with a TTL, but it can be removed a bit later than its expiry
+		if mute.MutedUntil > time.Now().UnixMilli() {
+			return mute
+		}
+	}
+
+	return nil
+}
+
+// HandleMutedNotification stores the muted notification if it is to be summarized when the mute expires
+func (p *Plugin) HandleMutedNotification(mute *serializer.SubscriptionMute, event *serializer.ServiceNowEvent) {
+	if !mute.Summary {
+		return
+	}
+
+	if err := p.store.AddMutedEvent(mute.Key(), event.GetMutedEvent()); err != nil {
+		p.API.LogError(constants.ErrorStoreMutedEvent, "Key", mute.Key(), "Error", err.Error())
+	}
+}
+
+// UnmuteNotifications removes the mute before it expires and posts its summary
+func (p *Plugin) UnmuteNotifications(channelID, instanceID, subscriptionID string) (*serializer.SubscriptionMute, error) {
+	key := serializer.GetMuteKey(channelID, instanceID, subscriptionID)
+	mute, err := p.store.LoadMute(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.store.DeleteMute(key); err != nil {
+		return nil, err
+	}
+
+	if mute.Summary {
+		p.postMuteSummary(mute)
+	}
+
+	return mute, nil
+}
+
+// PostExpiredMuteSummaries posts the summaries of the mutes which have expired. It is run periodically by a cluster job.
+func (p *Plugin) PostExpiredMuteSummaries() {
+	mutes, err := p.store.GetMutesWithSummary()
+	if err != nil {
+		p.API.LogError(constants.ErrorGetMute, "Error", err.Error())
+		return
+	}
+
+	now := time.Now().UnixMilli()
+	for _, mute := range mutes {
+		if mute.MutedUntil <= now {
+			p.postMuteSummary(mute)
+		}
+	}
+}
+
+func (p *Plugin) postMuteSummary(mute *serializer.SubscriptionMute) {
+	mutedEvents, err := p.store.PopMutedEvents(mute.Key())
+	if err != nil {
+		p.API.LogError("Unable to get the muted notifications", "Key", mute.Key(), "Error", err.Error())
+		return
+	}
+
+	if mutedEvents.Count == 0 {
+		return
+	}
+
+	post := mute.CreateMuteSummaryPost(p.botID, p.getInstanceBaseURL(mute.InstanceID), mutedEvents)
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.API.LogError(constants.ErrorCreatePost, "Error", appErr.Error())
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestParseMuteDuration(t *testing.T) {
+	for _, test := range []struct {
+		value            string
+		expectedDuration time.Duration
+		expectedErr      bool
+	}{
+		{value: "30m", expectedDuration: 30 * time.Minute},
+		{value: "2h", expectedDuration: 2 * time.Hour},
+		{value: "1d", expectedDuration: 24 * time.Hour},
+		{value: "7d", expectedDuration: 7 * 24 * time.Hour},
+		{value: "8d", expectedErr: true},
+		{value: "0h", expectedErr: true},
+		{value: "-1h", expectedErr: true},
+		{value: "xd", expectedErr: true},
+		{value: "invalid", expectedErr: true},
+	} {
+		t.Run(test.value, func(t *testing.T) {
+			duration, err := ParseMuteDuration(test.value)
+			if test.expectedErr {
+				assert.EqualError(t, err, constants.ErrorInvalidMuteDuration)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedDuration, duration)
+		})
+	}
+}
+
+func TestGetNotificationMute(t *testing.T) {
+	channelKey := serializer.GetMuteKey(testutils.GetChannelID(), "", "")
+	subscriptionKey := serializer.GetMuteKey(testutils.GetChannelID(), "", testutils.GetServiceNowSysID())
+	for _, test := range []struct {
+		description string
+		instanceID  string
+		setupStore  func(*mock_plugin.Store)
+		setupAPI    func(*plugintest.API)
+		expectMute  bool
+	}{
+		{
+			description: "Channel is muted",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", channelKey).Return(&serializer.SubscriptionMute{MutedUntil: time.Now().Add(time.Hour).UnixMilli()}, nil)
+			},
+			setupAPI:   func(_ *plugintest.API) {},
+			expectMute: true,
+		},
+		{
+			description: "Subscription is muted",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", channelKey).Return(nil, ErrNotFound)
+				s.On("LoadMute", subscriptionKey).Return(&serializer.SubscriptionMute{MutedUntil: time.Now().Add(time.Hour).UnixMilli()}, nil)
+			},
+			setupAPI:   func(_ *plugintest.API) {},
+			expectMute: true,
+		},
+		{
+			description: "Subscription with the same ID in another instance is not muted",
+			instanceID:  "mockInstance",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", channelKey).Return(nil, ErrNotFound)
+				s.On("LoadMute", serializer.GetMuteKey(testutils.GetChannelID(), "mockInstance", testutils.GetServiceNowSysID())).Return(nil, ErrNotFound)
+			},
+			setupAPI: func(_ *plugintest.API) {},
+		},
+		{
+			description: "Mute has expired",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", channelKey).Return(&serializer.SubscriptionMute{MutedUntil: time.Now().Add(-time.Minute).UnixMilli()}, nil)
+				s.On("LoadMute", subscriptionKey).Return(nil, ErrNotFound)
+			},
+			setupAPI: func(_ *plugintest.API) {},
+		},
+		{
+			description: "Failed to load the mute",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", channelKey).Return(nil, fmt.Errorf("mockError"))
+				s.On("LoadMute", subscriptionKey).Return(nil, ErrNotFound)
+			},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogWarn", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			mute := p.GetNotificationMute(&serializer.ServiceNowEvent{
+				SubscriptionID: testutils.GetServiceNowSysID(),
+				ChannelID:      testutils.GetChannelID(),
+				InstanceID:     test.instanceID,
+			})
+			assert.Equal(t, test.expectMute, mute != nil)
+		})
+	}
+}
+
+func TestUnmuteNotifications(t *testing.T) {
+	key := serializer.GetMuteKey(testutils.GetChannelID(), "", "")
+	for _, test := range []struct {
+		description string
+		setupStore  func(*mock_plugin.Store)
+		setupAPI    func(*plugintest.API)
+		expectedErr error
+	}{
+		{
+			description: "Summary of the muted notifications is posted",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", key).Return(&serializer.SubscriptionMute{ChannelID: testutils.GetChannelID(), Summary: true}, nil)
+				s.On("DeleteMute", key).Return(nil)
+				s.On("PopMutedEvents", key).Return(&serializer.MutedEvents{
+					Events: []*serializer.MutedEvent{{Number: testutils.GetServiceNowNumber(), Event: constants.SubscriptionEventState}},
+					Count:  2,
+				}, nil)
+			},
+			setupAPI: func(api *plugintest.API) {
+				api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+					return post.ChannelId == testutils.GetChannelID() && strings.Contains(post.Message, "2 notification(s) of this channel were muted.") && strings.Contains(post.Message, "1 more notification(s) are not shown.")
+				})).Return(&model.Post{}, nil)
+			},
+		},
+		{
+			description: "Nothing is posted when no notifications were muted",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", key).Return(&serializer.SubscriptionMute{ChannelID: testutils.GetChannelID(), Summary: true}, nil)
+				s.On("DeleteMute", key).Return(nil)
+				s.On("PopMutedEvents", key).Return(&serializer.MutedEvents{}, nil)
+			},
+			setupAPI: func(_ *plugintest.API) {},
+		},
+		{
+			description: "Channel is not muted",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMute", key).Return(nil, ErrNotFound)
+			},
+			setupAPI:    func(_ *plugintest.API) {},
+			expectedErr: ErrNotFound,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p, api := setupTestPlugin(&plugintest.API{}, store)
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			_, err := p.UnmuteNotifications(testutils.GetChannelID(), "", "")
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestPostExpiredMuteSummaries(t *testing.T) {
+	store := mock_plugin.NewStore(t)
+	p, api := setupTestPlugin(&plugintest.API{}, store)
+	defer api.AssertExpectations(t)
+
+	expired := &serializer.SubscriptionMute{ChannelID: testutils.GetChannelID(), SubscriptionID: testutils.GetServiceNowSysID(), MutedUntil: time.Now().Add(-time.Minute).UnixMilli(), Summary: true}
+	active := &serializer.SubscriptionMute{ChannelID: testutils.GetID(), MutedUntil: time.Now().Add(time.Hour).UnixMilli(), Summary: true}
+	store.On("GetMutesWithSummary").Return([]*serializer.SubscriptionMute{expired, active}, nil)
+	store.On("PopMutedEvents", expired.Key()).Return(&serializer.MutedEvents{
+		Events: []*serializer.MutedEvent{{Number: testutils.GetServiceNowNumber(), Event: constants.SubscriptionEventState}},
+		Count:  1,
+	}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == testutils.GetChannelID() && strings.Contains(post.Message, testutils.GetServiceNowSysID())
+	})).Return(&model.Post{}, nil).Once()
+
+	p.PostExpiredMuteSummaries()
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// TransferSubscription changes the owner of the subscription in ServiceNow
+func (p *Plugin) TransferSubscription(client Client, subscriptionID, mattermostUserID string) (*serializer.SubscriptionResponse, int, error) {
+	return client.EditSubscription(subscriptionID, &serializer.SubscriptionPayload{
+		UserID: &mattermostUserID,
+	})
+}
+
+// GetAllSubscriptionsOfUser returns all the active subscriptions owned by the user
+func (p *Plugin) GetAllSubscriptionsOfUser(client Client, mattermostUserID string) ([]*serializer.SubscriptionResponse, int, error) {
+	return p.GetAllSubscriptionPages(client, "", mattermostUserID, "")
+}
+
+// GetNewSubscriptionOwner returns the active Mattermost user with the given username who can own the subscriptions of the channel
+func (p *Plugin) GetNewSubscriptionOwner(username, channelID string) (*model.User, error) {
+	user, appErr := p.API.GetUserByUsername(strings.TrimPrefix(username, "@"))
+	if appErr != nil {
+		p.API.LogDebug(constants.ErrorGetUserByUsername, "Username", username, "Error", appErr.Error())
+		return nil, fmt.Errorf("user %s doesn't exist", username)
+	}
+
+	if user.DeleteAt != 0 || user.IsBot {
+		return nil, fmt.Errorf("user %s can't own subscriptions", username)
+	}
+
+	if channelID != "" {
+		if _, err := p.HasChannelPermissions(user.Id, channelID); err != nil {
+			return nil, fmt.Errorf("user %s doesn't have permissions for the channel of the subscription", username)
+		}
+	}
+
+	return user, nil
+}
+
+// CanManageSubscriptionOwner checks if the user is the owner of the subscription, an admin of its channel or a system admin
+func (p *Plugin) CanManageSubscriptionOwner(mattermostUserID string, subscription *serializer.SubscriptionResponse, isSysAdmin bool) bool {
+	if isSysAdmin || subscription.UserID == mattermostUserID {
+		return true
+	}
+
+	return p.API.HasPermissionToChannel(mattermostUserID, subscription.ChannelID, model.PermissionManageChannelRoles)
+}
+
+// HandleSubscriptionsOfDeactivatedUser notifies the admins of the channels having the subscriptions owned by a deactivated user
+func (p *Plugin) HandleSubscriptionsOfDeactivatedUser(user *model.User) {
+	client, err := p.GetClientForSubscriptionOwner(user.Id)
+	if err != nil {
+		if !errors.Is(err, ErrServiceAccountNotConfigured) {
+			p.API.LogError("Unable to get the client for the subscriptions of the deactivated user", "UserID", user.Id, "Error", err.Error())
+		}
+		return
+	}
+
+	subscriptions, _, err := p.GetAllSubscriptionsOfUser(client, user.Id)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptions, "UserID", user.Id, "Error", err.Error())
+		return
+	}
+
+	channelSubscriptions := map[string][]string{}
+	for _, subscription := range subscriptions {
+		channelSubscriptions[subscription.ChannelID] = append(channelSubscriptions[subscription.ChannelID], subscription.SysID)
+	}
+
+	for channelID, subscriptionIDs := range channelSubscriptions {
+		p.notifyChannelAdminsAboutOrphanedSubscriptions(channelID, user.Username, subscriptionIDs)
+	}
+}
+
+func (p *Plugin) notifyChannelAdminsAboutOrphanedSubscriptions(channelID, username string, subscriptionIDs []string) {
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		p.API.LogError(constants.ErrorGetChannel, "ChannelID", channelID, "Error", appErr.Error())
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("The user @%s has been deactivated and owns the following ServiceNow subscriptions in the channel ~%s:\n", username, channel.Name))
+	for _, subscriptionID := range subscriptionIDs {
+		sb.WriteString(fmt.Sprintf("* `%s`\n", subscriptionID))
+	}
+	sb.WriteString("\nRun `/servicenow subscriptions transfer <subscription_id> @user` to transfer a subscription to another user.")
+
+	for page := 0; ; page++ {
+		members, appErr := p.API.GetChannelMembers(channelID, page, constants.MaxPerPage)
+		if appErr != nil {
+			p.API.LogError("Unable to get the members of the channel", "ChannelID", channelID, "Error", appErr.Error())
+			return
+		}
+
+		for _, member := range members {
+			if !member.SchemeAdmin {
+				continue
+			}
+
+			if _, err := p.DMPost(member.UserId, &model.Post{Message: sb.String()}); err != nil {
+				p.API.LogError(constants.ErrorCreatePost, "UserID", member.UserId, "Error", err.Error())
+			}
+		}
+
+		if len(members) < constants.MaxPerPage {
+			return
+		}
+	}
+}
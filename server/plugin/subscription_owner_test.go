@@ -0,0 +1,128 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetAllSubscriptionsOfUser(t *testing.T) {
+	t.Run("Subscriptions are fetched from all the pages", func(t *testing.T) {
+		p := &Plugin{}
+		client := mock_plugin.NewClient(t)
+		client.On("GetAllSubscriptions", "", testutils.GetID(), "", fmt.Sprint(constants.MaxPerPage), "0").Return(testutils.GetSubscriptions(constants.MaxPerPage), 200, nil)
+		client.On("GetAllSubscriptions", "", testutils.GetID(), "", fmt.Sprint(constants.MaxPerPage), fmt.Sprint(constants.MaxPerPage)).Return(testutils.GetSubscriptions(2), 200, nil)
+
+		subscriptions, _, err := p.GetAllSubscriptionsOfUser(client, testutils.GetID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, constants.MaxPerPage+2, len(subscriptions))
+	})
+
+	t.Run("Error occurred while getting the subscriptions", func(t *testing.T) {
+		p := &Plugin{}
+		client := mock_plugin.NewClient(t)
+		client.On("GetAllSubscriptions", "", testutils.GetID(), "", fmt.Sprint(constants.MaxPerPage), "0").Return(nil, 500, fmt.Errorf("mockErrMessage"))
+
+		subscriptions, statusCode, err := p.GetAllSubscriptionsOfUser(client, testutils.GetID())
+
+		assert.EqualError(t, err, "mockErrMessage")
+		assert.Equal(t, 500, statusCode)
+		assert.Nil(t, subscriptions)
+	})
+}
+
+func TestGetNewSubscriptionOwner(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		setupAPI             func(*plugintest.API)
+		expectedErrorMessage string
+	}{
+		{
+			description: "User can own the subscriptions of the channel",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: testutils.GetID(), Username: "mockUsername"}, nil)
+				a.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(true)
+			},
+		},
+		{
+			description: "User doesn't exist",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(nil, testutils.GetBadRequestAppError())
+				a.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			expectedErrorMessage: "user @mockUsername doesn't exist",
+		},
+		{
+			description: "User is deactivated",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: testutils.GetID(), DeleteAt: 1}, nil)
+			},
+			expectedErrorMessage: "user @mockUsername can't own subscriptions",
+		},
+		{
+			description: "User doesn't have permissions for the channel",
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUserByUsername", "mockUsername").Return(&model.User{Id: testutils.GetID()}, nil)
+				a.On("HasPermissionToChannel", testutils.GetID(), testutils.GetChannelID(), model.PermissionCreatePost).Return(false)
+				a.On("LogDebug", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			expectedErrorMessage: "user @mockUsername doesn't have permissions for the channel of the subscription",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			user, err := p.GetNewSubscriptionOwner("@mockUsername", testutils.GetChannelID())
+			if test.expectedErrorMessage != "" {
+				assert.EqualError(t, err, test.expectedErrorMessage)
+				assert.Nil(t, user)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testutils.GetID(), user.Id)
+		})
+	}
+}
+
+func TestHandleSubscriptionsOfDeactivatedUser(t *testing.T) {
+	defer monkey.UnpatchAll()
+	p, api := setupTestPlugin(&plugintest.API{}, nil)
+	client := mock_plugin.NewClient(t)
+	client.On("GetAllSubscriptions", "", testutils.GetID(), "", fmt.Sprint(constants.MaxPerPage), "0").Return(testutils.GetSubscriptions(2), 200, nil)
+	monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientForSubscriptionOwner", func(_ *Plugin, _ string) (Client, error) {
+		return client, nil
+	})
+
+	var dmRecipients []string
+	monkey.PatchInstanceMethod(reflect.TypeOf(p), "DMPost", func(_ *Plugin, mattermostUserID string, _ *model.Post) (string, error) {
+		dmRecipients = append(dmRecipients, mattermostUserID)
+		return "", nil
+	})
+
+	api.On("GetChannel", testutils.GetID()).Return(&model.Channel{Id: testutils.GetID(), Name: "mockChannel"}, nil)
+	api.On("GetChannelMembers", testutils.GetID(), 0, constants.MaxPerPage).Return(model.ChannelMembers{
+		{UserId: "mockAdminID", SchemeAdmin: true},
+		{UserId: "mockMemberID"},
+	}, nil)
+	defer api.AssertExpectations(t)
+
+	p.HandleSubscriptionsOfDeactivatedUser(&model.User{Id: testutils.GetID(), Username: "mockUsername"})
+
+	assert.Equal(t, []string{"mockAdminID"}, dmRecipients)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+)
+
+// GetSubscriptionSettings returns the settings of the subscription of the instance stored by the plugin.
+// The default settings are returned for the subscriptions whose settings have never been updated.
+func (p *Plugin) GetSubscriptionSettings(instanceID, subscriptionID string) (*serializer.SubscriptionSettings, error) {
+	settings, err := p.store.LoadSubscriptionSettings(instanceID, subscriptionID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return &serializer.SubscriptionSettings{
+				Mentions: constants.MentionSettingOff,
+			}, nil
+		}
+
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// StoreSubscriptionMentions updates the mention setting of the subscription, keeping the rest of its settings
+func (p *Plugin) StoreSubscriptionMentions(instanceID, subscriptionID, mentions string) error {
+	settings, err := p.GetSubscriptionSettings(instanceID, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	settings.Mentions = mentions
+	return p.store.StoreSubscriptionSettings(instanceID, subscriptionID, settings)
+}
+
+// MatchesConfigurationItemFilter checks if the record of the event belongs to the configuration item the subscription is filtered by.
+// The events of the subscriptions without the filter always match.
+func (p *Plugin) MatchesConfigurationItemFilter(event *serializer.ServiceNowEvent) bool {
+	if event.SubscriptionID == "" {
+		return true
+	}
+
+	settings, err := p.GetSubscriptionSettings(event.InstanceID, event.SubscriptionID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptionSettings, "SubscriptionID", event.SubscriptionID, "Error", err.Error())
+		return true
+	}
+
+	return settings.ConfigurationItemID == "" || settings.ConfigurationItemID == event.ConfigurationItemID
+}
+
+// GetNotificationMentions returns the usernames of the connected Mattermost users to be mentioned in the notification for the event
+func (p *Plugin) GetNotificationMentions(event *serializer.ServiceNowEvent) []string {
+	if event.SubscriptionID == "" {
+		return nil
+	}
+
+	settings, err := p.GetSubscriptionSettings(event.InstanceID, event.SubscriptionID)
+	if err != nil {
+		p.API.LogError(constants.ErrorGetSubscriptionSettings, "SubscriptionID", event.SubscriptionID, "Error", err.Error())
+		return nil
+	}
+
+	if settings.Mentions == constants.MentionSettingOff {
+		return nil
+	}
+
+	serviceNowUserIDs := []string{}
+	if event.AssignedToID != "" {
+		serviceNowUserIDs = append(serviceNowUserIDs, event.AssignedToID)
+	}
+
+	if settings.Mentions == constants.MentionSettingAssigneeAndGroup && event.AssignmentGroupID != "" {
+		// The members of the group are fetched from the instance of the subscription using the token of the user who created it
+		client, err := p.GetInstanceClientForSubscriptionOwner(event.UserID, event.InstanceID)
+		if err != nil {
+			p.API.LogError("Unable to get the client for the subscription creator", "UserID", event.UserID, "InstanceID", event.InstanceID, "Error", err.Error())
+		} else {
+			memberIDs, _, err := client.GetGroupMembersFromServiceNow(event.AssignmentGroupID)
+			switch {
+			case err != nil:
+				p.API.LogError("Unable to get the members of the assignment group", "GroupID", event.AssignmentGroupID, "Error", err.Error())
+			case len(memberIDs) > constants.MaxGroupMentions:
+				p.API.LogDebug("Not mentioning the members of the assignment group as it is too large", "GroupID", event.AssignmentGroupID)
+			default:
+				serviceNowUserIDs = append(serviceNowUserIDs, memberIDs...)
+			}
+		}
+	}
+
+	usernames := []string{}
+	mentioned := map[string]bool{}
+	for _, serviceNowUserID := range serviceNowUserIDs {
+		mattermostUserID, err := p.GetInstanceMattermostUserIDFromServiceNowUserID(event.InstanceID, serviceNowUserID)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				p.API.LogError("Unable to get the Mattermost user for the ServiceNow user", "ServiceNowUserID", serviceNowUserID, "Error", err.Error())
+			}
+			continue
+		}
+
+		if mentioned[mattermostUserID] {
+			continue
+		}
+
+		user, appErr := p.API.GetUser(mattermostUserID)
+		if appErr != nil {
+			p.API.LogError("Error in getting user", "UserID", mattermostUserID, "Error", appErr.Error())
+			continue
+		}
+
+		mentioned[mattermostUserID] = true
+		usernames = append(usernames, user.Username)
+	}
+
+	return usernames
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetSubscriptionSettings(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		setupStore           func(*mock_plugin.Store)
+		expectedMentions     string
+		expectedErrorMessage string
+	}{
+		{
+			description: "Subscription settings are loaded from the store",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{
+					Mentions: constants.MentionSettingAssignee,
+				}, nil)
+			},
+			expectedMentions: constants.MentionSettingAssignee,
+		},
+		{
+			description: "Default subscription settings are returned for the subscriptions which were never updated",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(nil, ErrNotFound)
+			},
+			expectedMentions: constants.MentionSettingOff,
+		},
+		{
+			description: "Error occurred while loading the subscription settings",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(nil, fmt.Errorf("mockErrMessage"))
+			},
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p.store = store
+
+			settings, err := p.GetSubscriptionSettings("", testutils.GetServiceNowSysID())
+			if test.expectedErrorMessage != "" {
+				assert.EqualError(t, err, test.expectedErrorMessage)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedMentions, settings.Mentions)
+		})
+	}
+}
+
+func TestGetNotificationMentions(t *testing.T) {
+	defer monkey.UnpatchAll()
+	for _, test := range []struct {
+		description       string
+		event             *serializer.ServiceNowEvent
+		mentions          string
+		setupAPI          func(*plugintest.API)
+		setupClient       func(*mock_plugin.Client)
+		expectedUsernames []string
+	}{
+		{
+			description:       "Event is not related to a subscription",
+			event:             &serializer.ServiceNowEvent{},
+			setupAPI:          func(a *plugintest.API) {},
+			setupClient:       func(c *mock_plugin.Client) {},
+			expectedUsernames: nil,
+		},
+		{
+			description: "Mentions are turned off for the subscription",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID: testutils.GetServiceNowSysID(),
+				AssignedToID:   "mockAssigneeID",
+			},
+			mentions:          constants.MentionSettingOff,
+			setupAPI:          func(a *plugintest.API) {},
+			setupClient:       func(c *mock_plugin.Client) {},
+			expectedUsernames: nil,
+		},
+		{
+			description: "Assignee is mentioned",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID:    testutils.GetServiceNowSysID(),
+				AssignedToID:      "mockAssigneeID",
+				AssignmentGroupID: "mockGroupID",
+			},
+			mentions: constants.MentionSettingAssignee,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", "mockAssigneeMMID").Return(&model.User{Username: "assignee"}, nil)
+			},
+			setupClient:       func(c *mock_plugin.Client) {},
+			expectedUsernames: []string{"assignee"},
+		},
+		{
+			description: "Assignee and the connected members of the assignment group are mentioned once",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID:    testutils.GetServiceNowSysID(),
+				AssignedToID:      "mockAssigneeID",
+				AssignmentGroupID: "mockGroupID",
+			},
+			mentions: constants.MentionSettingAssigneeAndGroup,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", "mockAssigneeMMID").Return(&model.User{Username: "assignee"}, nil)
+				a.On("GetUser", "mockMemberMMID").Return(&model.User{Username: "member"}, nil)
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetGroupMembersFromServiceNow", "mockGroupID").Return([]string{"mockAssigneeID", "mockMemberID", "mockUnconnectedID"}, 0, nil)
+			},
+			expectedUsernames: []string{"assignee", "member"},
+		},
+		{
+			description: "Members of an assignment group which is too large are not mentioned",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID:    testutils.GetServiceNowSysID(),
+				AssignedToID:      "mockAssigneeID",
+				AssignmentGroupID: "mockGroupID",
+			},
+			mentions: constants.MentionSettingAssigneeAndGroup,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", "mockAssigneeMMID").Return(&model.User{Username: "assignee"}, nil)
+				a.On("LogDebug", testutils.GetMockArgumentsWithType("string", 3)...).Return()
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetGroupMembersFromServiceNow", "mockGroupID").Return(make([]string, constants.MaxGroupMentions+1), 0, nil)
+			},
+			expectedUsernames: []string{"assignee"},
+		},
+		{
+			description: "Error occurred while getting the members of the assignment group",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID:    testutils.GetServiceNowSysID(),
+				AssignedToID:      "mockAssigneeID",
+				AssignmentGroupID: "mockGroupID",
+			},
+			mentions: constants.MentionSettingAssigneeAndGroup,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", "mockAssigneeMMID").Return(&model.User{Username: "assignee"}, nil)
+				a.On("LogError", testutils.GetMockArgumentsWithType("string", 5)...).Return()
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetGroupMembersFromServiceNow", "mockGroupID").Return(nil, 0, fmt.Errorf("mockErrMessage"))
+			},
+			expectedUsernames: []string{"assignee"},
+		},
+		{
+			description: "Assignee and the members of the assignment group are looked up in the instance of the subscription",
+			event: &serializer.ServiceNowEvent{
+				SubscriptionID:    testutils.GetServiceNowSysID(),
+				InstanceID:        "hr",
+				AssignedToID:      "mockAssigneeID",
+				AssignmentGroupID: "mockGroupID",
+			},
+			mentions: constants.MentionSettingAssigneeAndGroup,
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", "mockHRAssigneeMMID").Return(&model.User{Username: "hrassignee"}, nil)
+				a.On("GetUser", "mockHRMemberMMID").Return(&model.User{Username: "hrmember"}, nil)
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetGroupMembersFromServiceNow", "mockGroupID").Return([]string{"mockMemberID", "mockUnconnectedID"}, 0, nil)
+			},
+			expectedUsernames: []string{"hrassignee", "hrmember"},
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p, api := setupTestPlugin(&plugintest.API{}, nil)
+			store := &mock_plugin.Store{}
+			store.On("LoadSubscriptionSettings", test.event.InstanceID, testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{Mentions: test.mentions}, nil)
+			store.On("LoadMattermostUserIDFromServiceNowUserID", "mockAssigneeID").Return("mockAssigneeMMID", nil)
+			store.On("LoadMattermostUserIDFromServiceNowUserID", "mockMemberID").Return("mockMemberMMID", nil)
+			store.On("LoadMattermostUserIDFromServiceNowUserID", "mockUnconnectedID").Return("", ErrNotFound)
+			store.On("LoadInstanceMattermostUserIDFromServiceNowUserID", "hr", "mockAssigneeID").Return("mockHRAssigneeMMID", nil)
+			store.On("LoadInstanceMattermostUserIDFromServiceNowUserID", "hr", "mockMemberID").Return("mockHRMemberMMID", nil)
+			store.On("LoadInstanceMattermostUserIDFromServiceNowUserID", "hr", "mockUnconnectedID").Return("", ErrNotFound)
+			store.On("UserIndexesMigrated").Return(true)
+			p.store = store
+
+			client := mock_plugin.NewClient(t)
+			test.setupClient(client)
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientForSubscriptionOwner", func(_ *Plugin, _ string) (Client, error) {
+				return client, nil
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetInstanceClient", func(_ *Plugin, _, instanceID string) (Client, error) {
+				if instanceID != "hr" {
+					return nil, ErrNotFound
+				}
+				return client, nil
+			})
+
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+
+			usernames := p.GetNotificationMentions(test.event)
+			assert.Equal(t, test.expectedUsernames, usernames)
+		})
+	}
+}
+
+func TestMatchesConfigurationItemFilter(t *testing.T) {
+	for _, test := range []struct {
+		description    string
+		event          *serializer.ServiceNowEvent
+		setupStore     func(*mock_plugin.Store)
+		expectedResult bool
+	}{
+		{
+			description: "Record of the configuration item of the subscription",
+			event:       &serializer.ServiceNowEvent{SubscriptionID: testutils.GetServiceNowSysID(), ConfigurationItemID: "mockItemID"},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{ConfigurationItemID: "mockItemID"}, nil)
+			},
+			expectedResult: true,
+		},
+		{
+			description: "Record of another configuration item",
+			event:       &serializer.ServiceNowEvent{SubscriptionID: testutils.GetServiceNowSysID(), ConfigurationItemID: "mockOtherItemID"},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(&serializer.SubscriptionSettings{ConfigurationItemID: "mockItemID"}, nil)
+			},
+		},
+		{
+			description: "Subscription without the configuration item filter",
+			event:       &serializer.ServiceNowEvent{SubscriptionID: testutils.GetServiceNowSysID()},
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadSubscriptionSettings", "", testutils.GetServiceNowSysID()).Return(nil, ErrNotFound)
+			},
+			expectedResult: true,
+		},
+		{
+			description:    "Event without a subscription",
+			event:          &serializer.ServiceNowEvent{},
+			setupStore:     func(_ *mock_plugin.Store) {},
+			expectedResult: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			store := mock_plugin.NewStore(t)
+			test.setupStore(store)
+			p.store = store
+
+			assert.Equal(t, test.expectedResult, p.MatchesConfigurationItemFilter(test.event))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// recordLinkPathRegex matches the path of the links to the records in the classic, the nav_to.do and the Next Experience formats
+const recordLinkPathRegex = `/(?:nav_to\.do\?uri=%2F|nav_to\.do\?uri=/?|now/nav/ui/classic/params/target/)?([a-z_]+)\.do(?:\?|%3F)sys_id(?:=|%3D)([0-9a-f]{32})`
+
+type recordLink struct {
+	RecordType string
+	SysID      string
+}
+
+// MessageHasBeenPosted unfurls the links to the ServiceNow records in the message by updating the post once it has been posted.
+// The records are fetched using the client of the poster, falling back to the service account if the poster is not connected.
+func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
+	if post.UserId == p.botID || post.IsSystemMessage() || len(post.Attachments()) > 0 {
+		return
+	}
+
+	links := p.getRecordLinks(post.Message)
+	if len(links) == 0 {
+		return
+	}
+
+	client, err := p.getUnfurlClient(post.UserId)
+	if err != nil {
+		if !errors.Is(err, ErrServiceAccountNotConfigured) {
+			p.API.LogDebug("Unable to get the client for the link preview", "UserID", post.UserId, "Error", err.Error())
+		}
+		return
+	}
+
+	serviceNowURL := p.getConfiguration().ServiceNowBaseURL
+	var attachments []*model.SlackAttachment
+	for _, link := range links {
+		record, _, err := client.GetRecordFromServiceNow(link.RecordType, link.SysID)
+		if err != nil {
+			p.API.LogDebug("Unable to get the record for the link preview", "RecordType", link.RecordType, "RecordID", link.SysID, "Error", err.Error())
+			continue
+		}
+
+		record.RecordType = link.RecordType
+		if err := record.HandleNestedFields(serviceNowURL); err != nil {
+			p.API.LogError(constants.ErrorHandlingNestedFields, "Error", err.Error())
+			continue
+		}
+
+		attachments = append(attachments, record.CreateSharingPost(post.ChannelId, p.botID, serviceNowURL, p.GetPluginURL(), "").Attachments()...)
+	}
+
+	if len(attachments) == 0 {
+		return
+	}
+
+	post = post.Clone()
+	model.ParseSlackAttachment(post, attachments)
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		p.API.LogWarn("Unable to update the post with the link previews", "PostID", post.Id, "Error", appErr.Error())
+	}
+}
+
+// getUnfurlClient returns the client of the poster, or the client of the service account if the poster is not connected
+func (p *Plugin) getUnfurlClient(mattermostUserID string) (Client, error) {
+	client, err := p.GetClientFromMattermostUserID(mattermostUserID)
+	if err == nil {
+		return client, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		p.API.LogDebug("Unable to get the client of the poster for the link preview", "UserID", mattermostUserID, "Error", err.Error())
+	}
+
+	return p.GetServiceAccountClient()
+}
+
+// getRecordLinks returns the distinct links to the records of the default ServiceNow instance in the message
+func (p *Plugin) getRecordLinks(message string) []*recordLink {
+	baseURL := strings.TrimSuffix(p.getConfiguration().ServiceNowBaseURL, "/")
+	if baseURL == "" || !strings.Contains(strings.ToLower(message), strings.ToLower(baseURL)) {
+		return nil
+	}
+
+	linkRegex, err := regexp.Compile("(?i)" + regexp.QuoteMeta(baseURL) + recordLinkPathRegex)
+	if err != nil {
+		p.API.LogError("Unable to compile the regex for the record links", "Error", err.Error())
+		return nil
+	}
+
+	var links []*recordLink
+	found := map[string]bool{}
+	for _, match := range linkRegex.FindAllStringSubmatch(message, -1) {
+		recordType, sysID := strings.ToLower(match[1]), strings.ToLower(match[2])
+		if !constants.ValidRecordTypesForSearching[recordType] || found[sysID] {
+			continue
+		}
+
+		found[sysID] = true
+		links = append(links, &recordLink{
+			RecordType: recordType,
+			SysID:      sysID,
+		})
+
+		if len(links) == constants.MaxRecordLinkPreviews {
+			break
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"bou.ke/monkey"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest/mock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+	mock_plugin "github.com/mattermost/mattermost-plugin-servicenow/server/mocks"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
+	"github.com/mattermost/mattermost-plugin-servicenow/server/testutils"
+)
+
+func TestGetRecordLinks(t *testing.T) {
+	for _, test := range []struct {
+		description   string
+		message       string
+		expectedLinks []*recordLink
+	}{
+		{
+			description: "Message without links",
+			message:     "mockMessage",
+		},
+		{
+			description: "Links to the records in all the formats are found once",
+			message: fmt.Sprintf("https://mockservicenow.com/incident.do?sys_id=%s https://mockservicenow.com/nav_to.do?uri=incident.do?sys_id=%s https://mockservicenow.com/now/nav/ui/classic/params/target/change_request.do%%3Fsys_id%%3D%s",
+				testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID(), "ab5d4f60807861110da0ef4be7c1ed0d"),
+			expectedLinks: []*recordLink{
+				{RecordType: constants.RecordTypeIncident, SysID: testutils.GetServiceNowSysID()},
+				{RecordType: constants.RecordTypeChangeRequest, SysID: "ab5d4f60807861110da0ef4be7c1ed0d"},
+			},
+		},
+		{
+			description: "Links to other instances and unsupported record types are ignored",
+			message:     fmt.Sprintf("https://otherservicenow.com/incident.do?sys_id=%s https://mockservicenow.com/sys_user.do?sys_id=%s", testutils.GetServiceNowSysID(), testutils.GetServiceNowSysID()),
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := &Plugin{}
+			p.setConfiguration(&configuration{
+				ServiceNowBaseURL: "https://mockservicenow.com/",
+			})
+
+			assert.Equal(t, test.expectedLinks, p.getRecordLinks(test.message))
+		})
+	}
+}
+
+func TestMessageHasBeenPosted(t *testing.T) {
+	defer monkey.UnpatchAll()
+	message := fmt.Sprintf("Please look at https://mockservicenow.com/incident.do?sys_id=%s", testutils.GetServiceNowSysID())
+	for _, test := range []struct {
+		description         string
+		post                *model.Post
+		setupAPI            func(*plugintest.API)
+		setupClient         func(*mock_plugin.Client)
+		posterConnected     bool
+		serviceAccountErr   error
+		expectedAttachments int
+	}{
+		{
+			description: "Link is unfurled using the client of the poster",
+			post:        &model.Post{Id: "mockPostID", UserId: testutils.GetID(), ChannelId: testutils.GetChannelID(), Message: message},
+			setupAPI: func(api *plugintest.API) {
+				api.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil)
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowRecord{
+					SysID:  testutils.GetServiceNowSysID(),
+					Number: "INC0000001",
+				}, 0, nil)
+			},
+			posterConnected:     true,
+			expectedAttachments: 1,
+		},
+		{
+			description: "Link is unfurled using the service account when the poster is not connected",
+			post:        &model.Post{Id: "mockPostID", UserId: testutils.GetID(), ChannelId: testutils.GetChannelID(), Message: message},
+			setupAPI: func(api *plugintest.API) {
+				api.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil)
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(&serializer.ServiceNowRecord{
+					SysID:  testutils.GetServiceNowSysID(),
+					Number: "INC0000001",
+				}, 0, nil)
+			},
+			expectedAttachments: 1,
+		},
+		{
+			description: "Message posted by the bot is not unfurled",
+			post:        &model.Post{UserId: "mockBotID", ChannelId: testutils.GetChannelID(), Message: message},
+			setupAPI:    func(api *plugintest.API) {},
+			setupClient: func(c *mock_plugin.Client) {},
+		},
+		{
+			description: "Message without links",
+			post:        &model.Post{UserId: testutils.GetID(), ChannelId: testutils.GetChannelID(), Message: "mockMessage"},
+			setupAPI:    func(api *plugintest.API) {},
+			setupClient: func(c *mock_plugin.Client) {},
+		},
+		{
+			description:       "Neither the poster is connected nor the service account is configured",
+			post:              &model.Post{UserId: testutils.GetID(), ChannelId: testutils.GetChannelID(), Message: message},
+			setupAPI:          func(api *plugintest.API) {},
+			setupClient:       func(c *mock_plugin.Client) {},
+			serviceAccountErr: ErrServiceAccountNotConfigured,
+		},
+		{
+			description: "Error occurred while getting the record",
+			post:        &model.Post{UserId: testutils.GetID(), ChannelId: testutils.GetChannelID(), Message: message},
+			setupAPI: func(api *plugintest.API) {
+				api.On("LogDebug", testutils.GetMockArgumentsWithType("string", 7)...).Return()
+			},
+			setupClient: func(c *mock_plugin.Client) {
+				c.On("GetRecordFromServiceNow", constants.RecordTypeIncident, testutils.GetServiceNowSysID()).Return(nil, 0, fmt.Errorf("mockErrMessage"))
+			},
+			posterConnected: true,
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			api := &plugintest.API{}
+			test.setupAPI(api)
+			defer api.AssertExpectations(t)
+			client := mock_plugin.NewClient(t)
+			test.setupClient(client)
+
+			p, _ := setupTestPlugin(api, nil)
+			p.botID = "mockBotID"
+			p.setConfiguration(&configuration{
+				ServiceNowBaseURL: "https://mockservicenow.com",
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetClientFromMattermostUserID", func(_ *Plugin, _ string) (Client, error) {
+				if test.posterConnected {
+					return client, nil
+				}
+				return nil, ErrNotFound
+			})
+			monkey.PatchInstanceMethod(reflect.TypeOf(p), "GetServiceAccountClient", func(_ *Plugin) (Client, error) {
+				if test.posterConnected {
+					assert.Fail(t, "the service account must not be used when the poster is connected")
+				}
+				if test.serviceAccountErr != nil {
+					return nil, test.serviceAccountErr
+				}
+				return client, nil
+			})
+
+			p.MessageHasBeenPosted(&plugin.Context{}, test.post)
+			if test.expectedAttachments == 0 {
+				api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+				return
+			}
+
+			updatedPost := api.Calls[len(api.Calls)-1].Arguments.Get(0).(*model.Post)
+			assert.Len(t, updatedPost.Attachments(), test.expectedAttachments)
+			assert.Equal(t, test.post.Message, updatedPost.Message)
+			assert.Equal(t, test.post.Id, updatedPost.Id)
+		})
+	}
+}
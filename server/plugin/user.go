@@ -16,14 +16,25 @@ import (
 	"github.com/mattermost/mattermost-plugin-servicenow/server/serializer"
 )
 
-func (p *Plugin) InitOAuth2(mattermostUserID string) (string, error) {
-	if _, err := p.GetUser(mattermostUserID); err == nil {
+// InitOAuth2 returns the URL for connecting the user to the given instance.
+// The ID of the instance is appended to the state when it is not the default one.
+func (p *Plugin) InitOAuth2(mattermostUserID, instanceID string) (string, error) {
+	instance, err := p.getConfiguration().GetInstance(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = p.GetInstanceUser(mattermostUserID, instance.ID); err == nil {
 		return "", fmt.Errorf(constants.ErrorUserAlreadyConnected)
 	}
 
-	conf := p.NewOAuth2Config()
+	conf := p.newInstanceOAuth2Config(instance)
 	state := fmt.Sprintf("%v_%v", model.NewId()[0:15], mattermostUserID)
-	if err := p.store.StoreOAuth2State(state); err != nil {
+	if instance.ID != constants.DefaultInstanceID {
+		state = fmt.Sprintf("%v_%v", state, instance.ID)
+	}
+
+	if err = p.store.StoreOAuth2State(state); err != nil {
 		return "", err
 	}
 
@@ -35,17 +46,27 @@ func (p *Plugin) CompleteOAuth2(authedUserID, code, state string) error {
 		return errors.New(constants.ErrorMissingUserCodeState)
 	}
 
-	oconf := p.NewOAuth2Config()
-
 	if err := p.store.VerifyOAuth2State(state); err != nil {
 		return errors.WithMessage(err, "missing stored state")
 	}
 
-	mattermostUserID := strings.Split(state, "_")[1]
+	stateParts := strings.Split(state, "_")
+	mattermostUserID := stateParts[1]
 	if mattermostUserID != authedUserID {
 		return errors.New(constants.ErrorUserIDMismatchInOAuth)
 	}
 
+	instanceID := constants.DefaultInstanceID
+	if len(stateParts) > 2 {
+		instanceID = stateParts[2]
+	}
+
+	instance, err := p.getConfiguration().GetInstance(instanceID)
+	if err != nil {
+		return err
+	}
+	oconf := p.newInstanceOAuth2Config(instance)
+
 	user, userErr := p.API.GetUser(mattermostUserID)
 	if userErr != nil {
 		return errors.Wrap(userErr, fmt.Sprintf("unable to get user for userID: %s", mattermostUserID))
@@ -62,7 +83,11 @@ func (p *Plugin) CompleteOAuth2(authedUserID, code, state string) error {
 		return err
 	}
 
-	client := p.NewClient(ctx, token)
+	client := p.NewClient(ctx, token, mattermostUserID)
+	if instance.ID != constants.DefaultInstanceID {
+		client = p.NewInstanceClient(ctx, instance, token, mattermostUserID)
+	}
+
 	serviceNowUser, _, err := client.GetMe(user.Email)
 	if err != nil {
 		return err
@@ -75,7 +100,12 @@ func (p *Plugin) CompleteOAuth2(authedUserID, code, state string) error {
 		ServiceNowUser:   serviceNowUser,
 	}
 
-	if err = p.store.StoreUser(u); err != nil {
+	if instance.ID != constants.DefaultInstanceID {
+		err = p.store.StoreInstanceUser(instance.ID, u)
+	} else {
+		err = p.store.StoreUser(u)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -95,6 +125,46 @@ func (p *Plugin) GetUser(mattermostUserID string) (*serializer.User, error) {
 }
 
 func (p *Plugin) DisconnectUser(mattermostUserID string) error {
-	err := p.store.DeleteUser(mattermostUserID)
-	return err
+	if err := p.store.DeleteUser(mattermostUserID); err != nil {
+		return err
+	}
+
+	if err := p.store.DeletePersonalSubscription(mattermostUserID); err != nil && !errors.Is(err, ErrNotFound) {
+		p.API.LogWarn("Unable to delete the personal subscription of the user", "UserID", mattermostUserID, "Error", err.Error())
+	}
+
+	return nil
+}
+
+// GetMattermostUserIDFromServiceNowUserID returns the ID of the connected Mattermost user linked with the given ServiceNow user
+func (p *Plugin) GetMattermostUserIDFromServiceNowUserID(serviceNowUserID string) (string, error) {
+	mattermostUserID, err := p.store.LoadMattermostUserIDFromServiceNowUserID(serviceNowUserID)
+	if err == nil || !errors.Is(err, ErrNotFound) || p.store.UserIndexesMigrated() {
+		return mattermostUserID, err
+	}
+
+	// Users connected before the reverse index was added are not present in it until the migration succeeds, so look for them and add them to the index
+	users, err := p.store.GetAllUsers()
+	if err != nil {
+		return "", err
+	}
+
+	for _, user := range users {
+		if user.ServiceNowUser == nil || user.ServiceNowUser.UserID != serviceNowUserID {
+			continue
+		}
+
+		storedUser, err := p.store.LoadUser(user.MattermostUserID)
+		if err != nil {
+			return "", err
+		}
+
+		if err := p.store.StoreUser(storedUser); err != nil {
+			p.API.LogWarn("Unable to add the user to the ServiceNow user index", "UserID", user.MattermostUserID, "Error", err.Error())
+		}
+
+		return user.MattermostUserID, nil
+	}
+
+	return "", ErrNotFound
 }
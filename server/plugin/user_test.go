@@ -62,7 +62,7 @@ func TestInitOAuth2(t *testing.T) {
 			test.setupStore(store)
 			p.store = store
 
-			res, err := p.InitOAuth2(testutils.GetID())
+			res, err := p.InitOAuth2(testutils.GetID(), "")
 			if test.expectedErrorMessage != "" {
 				require.Equal(t, "", res)
 				require.NotNil(t, err)
@@ -110,7 +110,37 @@ func TestCompleteOAuth2(t *testing.T) {
 				monkey.PatchInstanceMethod(reflect.TypeOf(p), "DM", func(_ *Plugin, _, _ string, _ ...interface{}) (string, error) {
 					return "", nil
 				})
-				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token) Client {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token, _ string) Client {
+					return &mock_plugin.Client{}
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(client), "GetMe", func(_ *mock_plugin.Client, _ string) (*serializer.ServiceNowUser, int, error) {
+					return testutils.GetServiceNowUser(), http.StatusOK, nil
+				})
+			},
+		},
+		"success for an additional instance": {
+			authenticatedUserID: mockUserID,
+			code:                mockCode,
+			state:               mockState + "_hr",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("VerifyOAuth2State", mockState+"_hr").Return(nil)
+				s.On("StoreInstanceUser", "hr", mock.AnythingOfType("*serializer.User")).Return(nil)
+			},
+			setupAPI: func(a *plugintest.API) {
+				a.On("GetUser", mockUserID).Return(testutils.GetUser(model.SystemAdminRoleId), nil)
+			},
+			setupPlugin: func(p *Plugin) {
+				p.setConfiguration(getMockInstancesConfiguration())
+				monkey.PatchInstanceMethod(reflect.TypeOf(&oauth2.Config{}), "Exchange", func(_ *oauth2.Config, _ context.Context, _ string, _ ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+					return &oauth2.Token{}, nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewEncodedAuthToken", func(_ *Plugin, _ *oauth2.Token) (string, error) {
+					return mockToken, nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "DM", func(_ *Plugin, _, _ string, _ ...interface{}) (string, error) {
+					return "", nil
+				})
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewInstanceClient", func(_ *Plugin, _ context.Context, _ *serviceNowInstance, _ *oauth2.Token, _ string) Client {
 					return &mock_plugin.Client{}
 				})
 				monkey.PatchInstanceMethod(reflect.TypeOf(client), "GetMe", func(_ *mock_plugin.Client, _ string) (*serializer.ServiceNowUser, int, error) {
@@ -216,7 +246,7 @@ func TestCompleteOAuth2(t *testing.T) {
 				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewEncodedAuthToken", func(_ *Plugin, _ *oauth2.Token) (string, error) {
 					return mockToken, nil
 				})
-				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token) Client {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token, _ string) Client {
 					return &mock_plugin.Client{}
 				})
 				monkey.PatchInstanceMethod(reflect.TypeOf(client), "GetMe", func(_ *mock_plugin.Client, _ string) (*serializer.ServiceNowUser, int, error) {
@@ -243,7 +273,7 @@ func TestCompleteOAuth2(t *testing.T) {
 				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewEncodedAuthToken", func(_ *Plugin, _ *oauth2.Token) (string, error) {
 					return mockToken, nil
 				})
-				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token) Client {
+				monkey.PatchInstanceMethod(reflect.TypeOf(p), "NewClient", func(_ *Plugin, _ context.Context, _ *oauth2.Token, _ string) Client {
 					return &mock_plugin.Client{}
 				})
 				monkey.PatchInstanceMethod(reflect.TypeOf(client), "GetMe", func(_ *mock_plugin.Client, _ string) (*serializer.ServiceNowUser, int, error) {
@@ -273,3 +303,77 @@ func TestCompleteOAuth2(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMattermostUserIDFromServiceNowUserID(t *testing.T) {
+	for _, test := range []struct {
+		description          string
+		setupStore           func(*mock_plugin.Store)
+		expectedUserID       string
+		expectedErrorMessage string
+	}{
+		{
+			description: "User is found in the index",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return(testutils.GetID(), nil)
+			},
+			expectedUserID: testutils.GetID(),
+		},
+		{
+			description: "User is not indexed and is added to the index",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(false)
+				s.On("GetAllUsers").Return([]*serializer.IncidentCaller{
+					{
+						MattermostUserID: testutils.GetID(),
+						ServiceNowUser:   testutils.GetServiceNowUser(),
+					},
+				}, nil)
+				s.On("LoadUser", testutils.GetID()).Return(testutils.GetSerializerUser(), nil)
+				s.On("StoreUser", testutils.GetSerializerUser()).Return(nil)
+			},
+			expectedUserID: testutils.GetID(),
+		},
+		{
+			description: "User is not connected",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(false)
+				s.On("GetAllUsers").Return([]*serializer.IncidentCaller{}, nil)
+			},
+			expectedErrorMessage: ErrNotFound.Error(),
+		},
+		{
+			description: "User is not connected and the indexes have been migrated",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return("", ErrNotFound)
+				s.On("UserIndexesMigrated").Return(true)
+			},
+			expectedErrorMessage: ErrNotFound.Error(),
+		},
+		{
+			description: "Error occurred while loading the index",
+			setupStore: func(s *mock_plugin.Store) {
+				s.On("LoadMattermostUserIDFromServiceNowUserID", testutils.GetServiceNowSysID()).Return("", fmt.Errorf("mockErrMessage"))
+			},
+			expectedErrorMessage: "mockErrMessage",
+		},
+	} {
+		t.Run(test.description, func(t *testing.T) {
+			p := Plugin{}
+			store := mock_plugin.NewStore(t)
+
+			test.setupStore(store)
+			p.store = store
+
+			userID, err := p.GetMattermostUserIDFromServiceNowUserID(testutils.GetServiceNowSysID())
+			if test.expectedErrorMessage != "" {
+				require.NotNil(t, err)
+				require.Equal(t, test.expectedErrorMessage, err.Error())
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, test.expectedUserID, userID)
+			}
+		})
+	}
+}
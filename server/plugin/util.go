@@ -4,10 +4,14 @@
 package plugin
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -88,6 +92,42 @@ func (p *Plugin) writeJSONArray(w http.ResponseWriter, statusCode int, v interfa
 	w.WriteHeader(statusCode)
 }
 
+// ParseSubscriptionsToCSV returns the subscriptions as a CSV file
+func ParseSubscriptionsToCSV(subscriptions []*serializer.SubscriptionResponse) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write([]string{"Subscription ID", "Type", "Record Type", "Record Number", "Record Short Description", "Events", "Created By", "Channel"}); err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		subscriptionType := constants.FilterTypeRecord
+		if subscription.Type == constants.SubscriptionTypeBulk {
+			subscriptionType = constants.FilterTypeBulk
+		}
+
+		if err := w.Write([]string{
+			subscription.SysID,
+			subscriptionType,
+			constants.FormattedRecordTypes[subscription.RecordType],
+			subscription.Number,
+			subscription.ShortDescription,
+			serializer.GetFormattedSubscriptionEvents(subscription.SubscriptionEvents),
+			subscription.UserName,
+			subscription.ChannelName,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 func ParseSubscriptionsToCommandResponse(subscriptions []*serializer.SubscriptionResponse) string {
 	var sb strings.Builder
 	var recordSubscriptions strings.Builder
@@ -139,7 +179,80 @@ func GetPageAndPerPage(r *http.Request) (page, perPage int) {
 func (p *Plugin) GetClientFromRequest(r *http.Request) Client {
 	ctx := r.Context()
 	token := ctx.Value(constants.ContextTokenKey).(*oauth2.Token)
-	return p.NewClient(ctx, token)
+	mattermostUserID := r.Header.Get(constants.HeaderMattermostUserID)
+	if instanceID, _ := ctx.Value(constants.ContextInstanceKey).(string); instanceID != "" && instanceID != constants.DefaultInstanceID {
+		if instance, err := p.getConfiguration().GetInstance(instanceID); err == nil {
+			return p.NewInstanceClient(ctx, instance, token, mattermostUserID)
+		}
+	}
+
+	return p.NewCachedClient(p.NewClient(ctx, token, mattermostUserID), mattermostUserID)
+}
+
+// GetClientFromMattermostUserID returns the client for the connected Mattermost user using their stored token
+func (p *Plugin) GetClientFromMattermostUserID(mattermostUserID string) (Client, error) {
+	user, err := p.GetUser(mattermostUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.ParseAuthToken(user.OAuth2Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.NewCachedClient(p.NewClient(context.Background(), token, mattermostUserID), mattermostUserID), nil
+}
+
+// GetAllSubscriptionPages returns the active subscriptions from all the pages of the results in ServiceNow
+func (p *Plugin) GetAllSubscriptionPages(client Client, channelID, userID, subscriptionType string) ([]*serializer.SubscriptionResponse, int, error) {
+	return getAllSubscriptionPages(func(limit, offset string) ([]*serializer.SubscriptionResponse, int, error) {
+		return client.GetAllSubscriptions(channelID, userID, subscriptionType, limit, offset)
+	})
+}
+
+// GetAllInactiveSubscriptionPages returns the deactivated subscriptions of the channel from all the pages of the results in ServiceNow
+func (p *Plugin) GetAllInactiveSubscriptionPages(client Client, channelID string) ([]*serializer.SubscriptionResponse, int, error) {
+	return getAllSubscriptionPages(func(limit, offset string) ([]*serializer.SubscriptionResponse, int, error) {
+		return client.GetInactiveSubscriptions(channelID, limit, offset)
+	})
+}
+
+func getAllSubscriptionPages(getPage func(limit, offset string) ([]*serializer.SubscriptionResponse, int, error)) ([]*serializer.SubscriptionResponse, int, error) {
+	var subscriptions []*serializer.SubscriptionResponse
+	for page := 0; ; page++ {
+		result, statusCode, err := getPage(fmt.Sprint(constants.MaxPerPage), fmt.Sprint(page*constants.MaxPerPage))
+		if err != nil {
+			return nil, statusCode, err
+		}
+
+		subscriptions = append(subscriptions, result...)
+		if len(result) < constants.MaxPerPage {
+			return subscriptions, statusCode, nil
+		}
+	}
+}
+
+// runWithWorkerPool calls f for each index in [0, count) using at most the given number of goroutines
+func runWithWorkerPool(count, workers int, f func(int)) {
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers && i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				f(index)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
 }
 
 func (p *Plugin) GetRecordFromServiceNowForSubscription(subscription *serializer.SubscriptionResponse, client Client, wg *sync.WaitGroup) {
@@ -340,3 +453,13 @@ func (p *Plugin) HasPublicOrPrivateChannelPermissions(userID, channelID string)
 
 	return http.StatusOK, nil
 }
+
+func getSortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
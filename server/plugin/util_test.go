@@ -69,6 +69,51 @@ func TestParseSubscriptionsToCommandResponse(t *testing.T) {
 	}
 }
 
+func TestParseSubscriptionsToCSV(t *testing.T) {
+	subscriptions := []*serializer.SubscriptionResponse{
+		{
+			SysID:              "mockSysID",
+			Type:               constants.SubscriptionTypeRecord,
+			Number:             "mockNumber",
+			UserName:           "mockUser",
+			ChannelName:        "mockChannel",
+			ShortDescription:   "mock, description",
+			RecordType:         constants.RecordTypeIncident,
+			SubscriptionEvents: constants.SubscriptionEventState,
+		},
+		{
+			SysID:              "mockSysID",
+			Type:               constants.SubscriptionTypeBulk,
+			UserName:           "mockUser",
+			ChannelName:        "mockChannel",
+			RecordType:         constants.RecordTypeProblem,
+			SubscriptionEvents: constants.SubscriptionEventState + "," + constants.SubscriptionEventPriority,
+		},
+	}
+
+	data, err := ParseSubscriptionsToCSV(subscriptions)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Subscription ID,Type,Record Type,Record Number,Record Short Description,Events,Created By,Channel\n"+
+		"mockSysID,record,Incident,mockNumber,\"mock, description\",State changed,mockUser,mockChannel\n"+
+		"mockSysID,bulk,Problem,,,\"State changed, Priority changed\",mockUser,mockChannel\n", string(data))
+}
+
+func TestRunWithWorkerPool(t *testing.T) {
+	for _, count := range []int{0, 3, 25} {
+		t.Run(fmt.Sprintf("RunWithWorkerPool: %d items", count), func(t *testing.T) {
+			results := make([]bool, count)
+			runWithWorkerPool(count, constants.SubscriptionsWorkerPoolSize, func(i int) {
+				results[i] = true
+			})
+
+			for _, result := range results {
+				assert.True(t, result)
+			}
+		})
+	}
+}
+
 func TestIsAuthorizedSysAdmin(t *testing.T) {
 	defer monkey.UnpatchAll()
 	for _, testCase := range []struct {
@@ -0,0 +1,59 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiagnosticCheck is the result of a single check of the configuration of the plugin
+type DiagnosticCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// DiagnosticReport contains the results of all the checks run for a ServiceNow instance
+type DiagnosticReport struct {
+	Instance string             `json:"instance"`
+	BaseURL  string             `json:"base_url"`
+	Passed   bool               `json:"passed"`
+	Checks   []*DiagnosticCheck `json:"checks"`
+}
+
+// ServiceNowApp is the application installed in ServiceNow by the update set
+type ServiceNowApp struct {
+	Version string `json:"version"`
+}
+
+type ServiceNowAppResult struct {
+	Result []*ServiceNowApp `json:"result"`
+}
+
+// AddCheck adds the result of a check to the report. The report only passes if all of its checks pass.
+func (r *DiagnosticReport) AddCheck(check *DiagnosticCheck) {
+	r.Passed = check.Passed && (len(r.Checks) == 0 || r.Passed)
+	r.Checks = append(r.Checks, check)
+}
+
+// Format returns the report as a Markdown table
+func (r *DiagnosticReport) Format() string {
+	result := "All checks passed."
+	if !r.Passed {
+		result = "Some checks failed."
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("#### Diagnostics of the ServiceNow instance `%s`\n%s %s\n| Check | Result | Details |\n| :--- | :--- | :--- |", r.Instance, r.BaseURL, result))
+	for _, check := range r.Checks {
+		status := ":white_check_mark: Pass"
+		if !check.Passed {
+			status = ":x: Fail"
+		}
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|", check.Name, status, strings.ReplaceAll(check.Message, "|", "\\|")))
+	}
+
+	return sb.String()
+}
@@ -5,9 +5,17 @@ package serializer
 
 // Error struct to store error ids and error message.
 type APIErrorResponse struct {
-	ID         string `json:"id"`
-	Message    string `json:"message"`
-	StatusCode int    `json:"-"`
+	ID          string        `json:"id"`
+	Message     string        `json:"message"`
+	StatusCode  int           `json:"-"`
+	FieldErrors []*FieldError `json:"field_errors,omitempty"`
+}
+
+// FieldError describes a problem with a single field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Label   string `json:"label"`
+	Message string `json:"message"`
 }
 
 func (a *APIErrorResponse) Error() string {
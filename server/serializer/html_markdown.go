@@ -0,0 +1,310 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	whitespaceRegex    = regexp.MustCompile(`\s+`)
+	extraNewLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// markdownConverter converts the HTML of the knowledge articles to Markdown.
+// Only the elements commonly used in the articles are converted and the rest are replaced by their text.
+type markdownConverter struct {
+	sb      strings.Builder
+	baseURL string
+	lists   []*markdownList
+	inPre   bool
+}
+
+type markdownList struct {
+	ordered bool
+	index   int
+}
+
+// ConvertHTMLToMarkdown returns the Markdown for the given HTML. The relative links are resolved using the base URL.
+func ConvertHTMLToMarkdown(htmlText, baseURL string) string {
+	doc, err := html.Parse(strings.NewReader(htmlText))
+	if err != nil {
+		return htmlText
+	}
+
+	c := &markdownConverter{baseURL: strings.TrimSuffix(baseURL, "/")}
+	c.convert(doc)
+
+	lines := strings.Split(c.sb.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.TrimSpace(extraNewLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// ConvertHTMLToText returns the text of the given HTML without any formatting
+func ConvertHTMLToText(htmlText string) string {
+	doc, err := html.Parse(strings.NewReader(htmlText))
+	if err != nil {
+		return htmlText
+	}
+
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+			sb.WriteString(" ")
+		case n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style):
+			return
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(sb.String(), " "))
+}
+
+func (c *markdownConverter) convert(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		c.writeText(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		c.convertChildren(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head:
+	case atom.Br:
+		c.sb.WriteString("\n")
+	case atom.P, atom.Div, atom.Section, atom.Article:
+		c.writeBlock(func() { c.convertChildren(n) })
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		c.writeBlock(func() {
+			c.sb.WriteString(strings.Repeat("#", level) + " ")
+			c.convertChildren(n)
+		})
+	case atom.Strong, atom.B:
+		c.writeInline("**", n)
+	case atom.Em, atom.I:
+		c.writeInline("_", n)
+	case atom.Del, atom.S, atom.Strike:
+		c.writeInline("~~", n)
+	case atom.Code:
+		if c.inPre {
+			c.convertChildren(n)
+			return
+		}
+		c.writeInline("`", n)
+	case atom.Pre:
+		c.writeBlock(func() {
+			c.inPre = true
+			c.sb.WriteString("```\n")
+			c.convertChildren(n)
+			c.sb.WriteString("\n```")
+			c.inPre = false
+		})
+	case atom.A:
+		c.writeLink(n)
+	case atom.Img:
+		c.writeImage(n)
+	case atom.Ul, atom.Ol:
+		c.lists = append(c.lists, &markdownList{ordered: n.DataAtom == atom.Ol})
+		c.writeBlock(func() { c.convertChildren(n) })
+		c.lists = c.lists[:len(c.lists)-1]
+	case atom.Li:
+		c.writeListItem(n)
+	case atom.Blockquote:
+		c.writeBlock(func() {
+			inner := &markdownConverter{baseURL: c.baseURL}
+			inner.convertChildren(n)
+			for _, line := range strings.Split(strings.TrimSpace(inner.sb.String()), "\n") {
+				c.sb.WriteString("> " + line + "\n")
+			}
+		})
+	case atom.Table:
+		c.writeBlock(func() { c.writeTable(n) })
+	case atom.Hr:
+		c.writeBlock(func() { c.sb.WriteString("---") })
+	default:
+		c.convertChildren(n)
+	}
+}
+
+func (c *markdownConverter) convertChildren(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.convert(child)
+	}
+}
+
+func (c *markdownConverter) writeText(text string) {
+	if c.inPre {
+		c.sb.WriteString(text)
+		return
+	}
+
+	text = whitespaceRegex.ReplaceAllString(text, " ")
+	if strings.HasSuffix(c.sb.String(), "\n") || c.sb.Len() == 0 {
+		text = strings.TrimLeft(text, " ")
+	}
+	c.sb.WriteString(text)
+}
+
+// writeBlock separates the block elements from their siblings by a blank line
+func (c *markdownConverter) writeBlock(write func()) {
+	if len(c.lists) == 0 {
+		c.sb.WriteString("\n\n")
+	}
+	write()
+	if len(c.lists) == 0 {
+		c.sb.WriteString("\n\n")
+	}
+}
+
+func (c *markdownConverter) writeInline(marker string, n *html.Node) {
+	inner := &markdownConverter{baseURL: c.baseURL, lists: c.lists}
+	inner.convertChildren(n)
+	text := strings.TrimSpace(inner.sb.String())
+	if text == "" {
+		return
+	}
+
+	c.sb.WriteString(marker + text + marker)
+}
+
+func (c *markdownConverter) writeLink(n *html.Node) {
+	inner := &markdownConverter{baseURL: c.baseURL, lists: c.lists}
+	inner.convertChildren(n)
+	text := strings.TrimSpace(inner.sb.String())
+	href := c.resolveURL(getAttribute(n, "href"))
+	switch {
+	case href == "" || strings.HasPrefix(href, "#"):
+		c.sb.WriteString(text)
+	case text == "":
+		c.sb.WriteString(href)
+	default:
+		c.sb.WriteString(fmt.Sprintf("[%s](%s)", text, href))
+	}
+}
+
+func (c *markdownConverter) writeImage(n *html.Node) {
+	src := c.resolveURL(getAttribute(n, "src"))
+	if src == "" {
+		return
+	}
+
+	alt := getAttribute(n, "alt")
+	if alt == "" {
+		alt = "image"
+	}
+
+	// The images are linked instead of being embedded, as they need the user to be logged in to ServiceNow
+	c.sb.WriteString(fmt.Sprintf("[%s](%s)", alt, src))
+}
+
+func (c *markdownConverter) writeListItem(n *html.Node) {
+	if len(c.lists) == 0 {
+		c.convertChildren(n)
+		return
+	}
+
+	list := c.lists[len(c.lists)-1]
+	marker := "-"
+	if list.ordered {
+		list.index++
+		marker = fmt.Sprintf("%d.", list.index)
+	}
+
+	if !strings.HasSuffix(c.sb.String(), "\n") && c.sb.Len() > 0 {
+		c.sb.WriteString("\n")
+	}
+	c.sb.WriteString(strings.Repeat("    ", len(c.lists)-1) + marker + " ")
+	c.convertChildren(n)
+	c.sb.WriteString("\n")
+}
+
+func (c *markdownConverter) writeTable(n *html.Node) {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.Tr {
+			var cells []string
+			for cell := node.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type != html.ElementNode || (cell.DataAtom != atom.Td && cell.DataAtom != atom.Th) {
+					continue
+				}
+
+				inner := &markdownConverter{baseURL: c.baseURL}
+				inner.convertChildren(cell)
+				text := whitespaceRegex.ReplaceAllString(strings.TrimSpace(inner.sb.String()), " ")
+				cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+			}
+			rows = append(rows, cells)
+			return
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		c.sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			c.sb.WriteString(strings.Repeat("| --- ", columns) + "|\n")
+		}
+	}
+}
+
+func (c *markdownConverter) resolveURL(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") || strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") {
+		return link
+	}
+
+	if strings.HasPrefix(strings.ToLower(link), "javascript:") {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s", c.baseURL, strings.TrimPrefix(link, "/"))
+}
+
+func getAttribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
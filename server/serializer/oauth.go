@@ -6,3 +6,9 @@ package serializer
 type ConnectedResponse struct {
 	Connected bool `json:"connected"`
 }
+
+// OAuthErrorResponse is the error returned by the token endpoint of ServiceNow
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// PersonalSubscription contains the events for which a user is notified through a DM by the bot
+type PersonalSubscription struct {
+	MattermostUserID string   `json:"mattermost_user_id"`
+	Events           []string `json:"events"`
+}
+
+type PersonalSubscriptionPayload struct {
+	Events []string `json:"events"`
+}
+
+type ServiceNowGroupMembership struct {
+	GroupID string `json:"group"`
+	UserID  string `json:"user"`
+}
+
+type ServiceNowGroupMembershipsResult struct {
+	Result []*ServiceNowGroupMembership `json:"result"`
+}
+
+func PersonalSubscriptionPayloadFromJSON(data io.Reader) (*PersonalSubscriptionPayload, error) {
+	var psp *PersonalSubscriptionPayload
+	if err := json.NewDecoder(data).Decode(&psp); err != nil {
+		return nil, err
+	}
+
+	return psp, nil
+}
+
+func (psp *PersonalSubscriptionPayload) Validate() error {
+	for _, event := range psp.Events {
+		if !constants.ValidPersonalSubscriptionEvents[event] {
+			return fmt.Errorf("personal subscription event %s is not valid", event)
+		}
+	}
+
+	return nil
+}
+
+func (ps *PersonalSubscription) HasEvent(event string) bool {
+	for _, e := range ps.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps *PersonalSubscription) GetFormattedPersonalSubscription() string {
+	if len(ps.Events) == 0 {
+		return "You don't have any personal subscriptions."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#### Personal subscriptions\nYou will receive a DM for:\n")
+	for _, event := range ps.Events {
+		sb.WriteString(fmt.Sprintf("* %s (`%s`)\n", constants.FormattedPersonalSubscriptionEvents[event], event))
+	}
+
+	return sb.String()
+}
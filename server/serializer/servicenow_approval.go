@@ -0,0 +1,137 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+type ServiceNowApprovalEvent struct {
+	ApprovalID       string `json:"sys_id"`
+	ApproverID       string `json:"approver"`
+	State            string `json:"state"`
+	DocumentID       string `json:"document_id"`
+	DocumentTable    string `json:"source_table"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	RequestedBy      string `json:"requested_by"`
+}
+
+// ApprovalPost links an approval to the DM posted to its approver
+type ApprovalPost struct {
+	ApprovalID       string `json:"approval_id"`
+	ApproverID       string `json:"approver_id"`
+	MattermostUserID string `json:"mattermost_user_id"`
+	PostID           string `json:"post_id"`
+}
+
+type ServiceNowApprovalPayload struct {
+	State    string `json:"state"`
+	Comments string `json:"comments,omitempty"`
+}
+
+func ServiceNowApprovalEventFromJSON(data io.Reader) (*ServiceNowApprovalEvent, error) {
+	var ae *ServiceNowApprovalEvent
+	if err := json.NewDecoder(data).Decode(&ae); err != nil {
+		return nil, err
+	}
+
+	return ae, nil
+}
+
+func (ae *ServiceNowApprovalEvent) CreateApprovalPost(botID, serviceNowURL, pluginURL string) *model.Post {
+	post := &model.Post{
+		UserId: botID,
+	}
+
+	if ae.RequestedBy == "" {
+		ae.RequestedBy = constants.NotAvailableText
+	}
+
+	recordType := constants.FormattedRecordTypes[ae.DocumentTable]
+	if recordType == "" {
+		recordType = cases.Title(language.Und).String(strings.ReplaceAll(ae.DocumentTable, "_", " "))
+	}
+
+	context := map[string]interface{}{
+		constants.ContextNameApprovalID: ae.ApprovalID,
+		constants.ContextNameNumber:     ae.Number,
+	}
+
+	titleLink := fmt.Sprintf(constants.PathRecord, serviceNowURL, ae.DocumentTable, ae.DocumentID, ae.DocumentTable)
+	slackAttachment := &model.SlackAttachment{
+		Title: fmt.Sprintf("[%s](%s): %s", ae.Number, titleLink, ae.ShortDescription),
+		Text:  "**Your approval has been requested**",
+		Fields: []*model.SlackAttachmentField{
+			{
+				Title: "Record",
+				Value: recordType,
+				Short: true,
+			},
+			{
+				Title: "Requested by",
+				Value: ae.RequestedBy,
+				Short: true,
+			},
+		},
+		Actions: []*model.PostAction{
+			{
+				Type:  model.PostActionTypeButton,
+				Name:  "Approve",
+				Style: "good",
+				Integration: &model.PostActionIntegration{
+					URL:     fmt.Sprintf("%s%s", pluginURL, constants.PathApprovalAction),
+					Context: withApprovalAction(context, constants.ApprovalStateApproved),
+				},
+			},
+			{
+				Type:  model.PostActionTypeButton,
+				Name:  "Reject",
+				Style: "danger",
+				Integration: &model.PostActionIntegration{
+					URL:     fmt.Sprintf("%s%s", pluginURL, constants.PathApprovalAction),
+					Context: withApprovalAction(context, constants.ApprovalStateRejected),
+				},
+			},
+		},
+	}
+
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{slackAttachment})
+	return post
+}
+
+// UpdateApprovalPostWithOutcome removes the approval buttons from the post and records who acted on the approval
+func UpdateApprovalPostWithOutcome(post *model.Post, state, username string) {
+	attachments := post.Attachments()
+	for _, attachment := range attachments {
+		attachment.Actions = nil
+		attachment.Text = ""
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+			Title: "Outcome",
+			Value: fmt.Sprintf("%s by @%s", cases.Title(language.Und).String(state), username),
+		})
+	}
+
+	model.ParseSlackAttachment(post, attachments)
+}
+
+func withApprovalAction(context map[string]interface{}, action string) map[string]interface{} {
+	contextWithAction := map[string]interface{}{
+		constants.ContextNameAction: action,
+	}
+	for key, value := range context {
+		contextWithAction[key] = value
+	}
+
+	return contextWithAction
+}
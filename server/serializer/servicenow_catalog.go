@@ -3,6 +3,15 @@
 
 package serializer
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
 type ServiceNowCatalogItem struct {
 	SysID            string   `json:"sys_id"`
 	Name             string   `json:"name"`
@@ -19,3 +28,184 @@ type Category struct {
 type ServiceNowCatalogItemsResult struct {
 	Result []*ServiceNowCatalogItem `json:"result"`
 }
+
+// ServiceNowCatalogItemDetails contains the variables which are to be filled for ordering a catalog item
+type ServiceNowCatalogItemDetails struct {
+	SysID            string                       `json:"sys_id"`
+	Name             string                       `json:"name"`
+	ShortDescription string                       `json:"short_description"`
+	Variables        []*ServiceNowCatalogVariable `json:"variables"`
+}
+
+type ServiceNowCatalogItemDetailsResult struct {
+	Result *ServiceNowCatalogItemDetails `json:"result"`
+}
+
+type ServiceNowCatalogVariable struct {
+	Name      string                     `json:"name"`
+	Label     string                     `json:"label"`
+	Type      int                        `json:"type"`
+	Mandatory bool                       `json:"mandatory"`
+	ReadOnly  bool                       `json:"read_only"`
+	HelpText  string                     `json:"help_text"`
+	Value     interface{}                `json:"value"`
+	Choices   []*ServiceNowCatalogChoice `json:"choices"`
+
+	// Table and encoded query restricting the records which can be selected for the reference variables
+	Reference          string `json:"reference"`
+	ReferenceQualifier string `json:"ref_qualifier"`
+}
+
+type ServiceNowCatalogChoice struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// ServiceNowReferenceRecord is a record which can be selected for a reference variable
+type ServiceNowReferenceRecord struct {
+	SysID string `json:"sys_id"`
+}
+
+type ServiceNowReferenceRecordsResult struct {
+	Result []*ServiceNowReferenceRecord `json:"result"`
+}
+
+// ServiceNowDictionaryEntry is a field of a table, used for finding the field holding the display value of its records
+type ServiceNowDictionaryEntry struct {
+	Element string `json:"element"`
+}
+
+type ServiceNowDictionaryEntriesResult struct {
+	Result []*ServiceNowDictionaryEntry `json:"result"`
+}
+
+type ServiceNowCatalogOrderPayload struct {
+	Quantity  string            `json:"sysparm_quantity"`
+	Variables map[string]string `json:"variables"`
+}
+
+// ServiceNowCatalogOrder is the request created in ServiceNow on ordering the catalog items
+type ServiceNowCatalogOrder struct {
+	SysID         string `json:"sys_id"`
+	Number        string `json:"number"`
+	RequestID     string `json:"request_id"`
+	RequestNumber string `json:"request_number"`
+	Table         string `json:"table"`
+}
+
+type ServiceNowCatalogOrderResult struct {
+	Result *ServiceNowCatalogOrder `json:"result"`
+}
+
+type ServiceNowCart struct {
+	CartID string `json:"cart_id"`
+}
+
+type ServiceNowCartResult struct {
+	Result *ServiceNowCart `json:"result"`
+}
+
+// IsLayout returns true for the variables which only arrange the other variables and can't be filled
+func (v *ServiceNowCatalogVariable) IsLayout() bool {
+	switch v.Type {
+	case constants.CatalogVariableTypeLabel, constants.CatalogVariableTypeBreak, constants.CatalogVariableTypeContainerStart,
+		constants.CatalogVariableTypeContainerEnd, constants.CatalogVariableTypeContainerSplit:
+		return true
+	}
+
+	return false
+}
+
+// IsSupported returns true for the variables which can be filled in an interactive dialog
+func (v *ServiceNowCatalogVariable) IsSupported() bool {
+	switch v.Type {
+	case constants.CatalogVariableTypeYesNo, constants.CatalogVariableTypeMultiLineText, constants.CatalogVariableTypeMultipleChoice,
+		constants.CatalogVariableTypeSelectBox, constants.CatalogVariableTypeSingleLineText, constants.CatalogVariableTypeCheckBox,
+		constants.CatalogVariableTypeWideSingleLineText, constants.CatalogVariableTypeLookupSelectBox,
+		constants.CatalogVariableTypeEmail, constants.CatalogVariableTypeReference:
+		return true
+	}
+
+	return false
+}
+
+// IsUserReference returns true for the reference variables whose values are ServiceNow users
+func (v *ServiceNowCatalogVariable) IsUserReference() bool {
+	return v.Type == constants.CatalogVariableTypeReference && v.Reference == constants.RecordTypeUser
+}
+
+// GetReferenceQualifier returns the reference qualifier of the variable if it's an encoded query.
+// The script qualifiers can only be evaluated by ServiceNow, so they are not applied.
+func (v *ServiceNowCatalogVariable) GetReferenceQualifier() string {
+	if strings.HasPrefix(v.ReferenceQualifier, constants.CatalogReferenceScriptPrefix) {
+		return ""
+	}
+
+	return v.ReferenceQualifier
+}
+
+// GetDefaultValue returns the default value of the variable as a string
+func (v *ServiceNowCatalogVariable) GetDefaultValue() string {
+	switch value := v.Value.(type) {
+	case string:
+		return value
+	case bool:
+		return fmt.Sprint(value)
+	}
+
+	return ""
+}
+
+// GetOrderID returns the ID of the request created on ordering, which is returned in different fields by the ServiceNow APIs
+func (o *ServiceNowCatalogOrder) GetOrderID() string {
+	if o.RequestID != "" {
+		return o.RequestID
+	}
+
+	return o.SysID
+}
+
+// GetOrderNumber returns the number of the request created on ordering
+func (o *ServiceNowCatalogOrder) GetOrderNumber() string {
+	if o.RequestNumber != "" {
+		return o.RequestNumber
+	}
+
+	return o.Number
+}
+
+// CreateCatalogSearchPost returns the post listing the catalog items with the buttons for ordering them
+func CreateCatalogSearchPost(items []*ServiceNowCatalogItem, serviceNowURL, pluginURL string) *model.Post {
+	post := &model.Post{
+		Message: fmt.Sprintf("Found %d catalog item(s).", len(items)),
+	}
+
+	attachments := make([]*model.SlackAttachment, 0, len(items))
+	for _, item := range items {
+		text := item.ShortDescription
+		if item.Price != "" {
+			text = fmt.Sprintf("%s\n**Price:** %s", text, item.Price)
+		}
+
+		attachments = append(attachments, &model.SlackAttachment{
+			Title:     item.Name,
+			TitleLink: fmt.Sprintf(constants.PathCatalogItem, serviceNowURL, item.SysID),
+			Text:      text,
+			Actions: []*model.PostAction{
+				{
+					Type: model.PostActionTypeButton,
+					Name: "Order",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("%s%s", pluginURL, constants.PathCatalogOrderAction),
+						Context: map[string]interface{}{
+							constants.ContextNameItemID: item.SysID,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	model.ParseSlackAttachment(post, attachments)
+	return post
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+const changeTimeLayout = "15:04 Jan 2"
+
+// ServiceNowChangeRequest is a change request scheduled in ServiceNow.
+// Its dates are in UTC and its state is the value of the state instead of its label.
+type ServiceNowChangeRequest struct {
+	SysID             string `json:"sys_id"`
+	Number            string `json:"number"`
+	ShortDescription  string `json:"short_description"`
+	State             string `json:"state"`
+	StartDate         string `json:"start_date"`
+	EndDate           string `json:"end_date"`
+	ConfigurationItem string `json:"cmdb_ci.name"`
+}
+
+type ServiceNowChangeRequestsResult struct {
+	Result []*ServiceNowChangeRequest `json:"result"`
+}
+
+// ChangeConflict is a pair of changes whose schedules overlap
+type ChangeConflict struct {
+	First  *ServiceNowChangeRequest
+	Second *ServiceNowChangeRequest
+}
+
+// GetSchedule returns the planned start and end of the change
+func (c *ServiceNowChangeRequest) GetSchedule() (start, end time.Time, err error) {
+	if start, err = time.Parse(constants.ServiceNowDateTimeLayout, c.StartDate); err != nil {
+		return start, end, err
+	}
+
+	end, err = time.Parse(constants.ServiceNowDateTimeLayout, c.EndDate)
+	return start, end, err
+}
+
+// GetChangeConflicts returns the pairs of changes whose schedules overlap.
+// The changes without a valid schedule are ignored.
+func GetChangeConflicts(changes []*ServiceNowChangeRequest) []*ChangeConflict {
+	conflicts := []*ChangeConflict{}
+	for i, first := range changes {
+		firstStart, firstEnd, err := first.GetSchedule()
+		if err != nil {
+			continue
+		}
+
+		for _, second := range changes[i+1:] {
+			secondStart, secondEnd, err := second.GetSchedule()
+			if err != nil {
+				continue
+			}
+
+			if firstStart.Before(secondEnd) && secondStart.Before(firstEnd) {
+				conflicts = append(conflicts, &ChangeConflict{First: first, Second: second})
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// GetFormattedScheduledChanges returns the calendar of the scheduled changes, grouped by the day of their start
+func GetFormattedScheduledChanges(changes []*ServiceNowChangeRequest, from, to time.Time, serviceNowURL string) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("#### Scheduled changes from %s to %s (UTC)", from.UTC().Format(changeTimeLayout), to.UTC().Format(changeTimeLayout)))
+	day := ""
+	for _, change := range changes {
+		start, end, err := change.GetSchedule()
+		if err != nil {
+			continue
+		}
+
+		// The changes which started before the window are shown under its first day along with the date of their start
+		startDay, startTime := start.Format("Monday, Jan 2"), start.Format("15:04")
+		if start.Before(from) {
+			startDay, startTime = from.UTC().Format("Monday, Jan 2"), start.Format(changeTimeLayout)
+		}
+
+		if startDay != day {
+			day = startDay
+			sb.WriteString(fmt.Sprintf("\n\n##### %s\n", day))
+			sb.WriteString("| Start | End | Number | Short description | Configuration item | State |\n| :----|:--------| :--------| :--------| :--------| :--------|")
+		}
+
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeChangeRequest, change.SysID, constants.RecordTypeChangeRequest)
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|[%s](%s)|%s|%s|%s|", startTime, end.Format(changeTimeLayout), change.Number, link,
+			strings.ReplaceAll(change.ShortDescription, "|", "\\|"), getTextOrNotAvailable(change.ConfigurationItem), getFormattedChangeState(change.State)))
+	}
+
+	return sb.String()
+}
+
+// GetFormattedChangeConflicts returns the table of the changes of the configuration item scheduled during the window,
+// followed by the table of the pairs of those changes whose schedules overlap each other, if any
+func GetFormattedChangeConflicts(configurationItem, windowText string, changes []*ServiceNowChangeRequest, serviceNowURL string) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("#### Changes of %s conflicting with the next %s\n", configurationItem, windowText))
+	sb.WriteString("| Change | Scheduled (UTC) | State |\n| :----|:--------| :--------|")
+	for _, change := range changes {
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|", getChangeLink(change, serviceNowURL), getFormattedChangeSchedule(change), getFormattedChangeState(change.State)))
+	}
+
+	conflicts := GetChangeConflicts(changes)
+	if len(conflicts) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n\n##### Changes overlapping each other\n")
+	sb.WriteString("| Change | Scheduled (UTC) | Overlaps with | Scheduled (UTC) |\n| :----|:--------| :--------| :--------|")
+	for _, conflict := range conflicts {
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|%s|", getChangeLink(conflict.First, serviceNowURL), getFormattedChangeSchedule(conflict.First),
+			getChangeLink(conflict.Second, serviceNowURL), getFormattedChangeSchedule(conflict.Second)))
+	}
+
+	return sb.String()
+}
+
+func getChangeLink(change *ServiceNowChangeRequest, serviceNowURL string) string {
+	link := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeChangeRequest, change.SysID, constants.RecordTypeChangeRequest)
+	return fmt.Sprintf("[%s](%s): %s", change.Number, link, strings.ReplaceAll(change.ShortDescription, "|", "\\|"))
+}
+
+func getFormattedChangeSchedule(change *ServiceNowChangeRequest) string {
+	start, end, err := change.GetSchedule()
+	if err != nil {
+		return constants.NotAvailableText
+	}
+
+	return fmt.Sprintf("%s - %s", start.Format(changeTimeLayout), end.Format(changeTimeLayout))
+}
+
+func getFormattedChangeState(state string) string {
+	if label, ok := constants.FormattedChangeStates[state]; ok {
+		return label
+	}
+
+	return getTextOrNotAvailable(state)
+}
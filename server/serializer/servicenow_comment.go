@@ -20,6 +20,11 @@ type ServiceNowCommentPayload struct {
 	Comments string `json:"comments"`
 }
 
+// ServiceNowWorkNotePayload is used for adding a work note, which is only visible to the agents
+type ServiceNowWorkNotePayload struct {
+	WorkNotes string `json:"work_notes"`
+}
+
 type ServiceNowCommentsResult struct {
 	Result *ServiceNowComment `json:"result"`
 }
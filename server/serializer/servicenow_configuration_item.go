@@ -0,0 +1,96 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// ServiceNowConfigurationItem is a record of the CMDB, which can belong to any of the subclasses of cmdb_ci like servers and services
+type ServiceNowConfigurationItem struct {
+	SysID             string `json:"sys_id"`
+	Name              string `json:"name"`
+	ClassName         string `json:"sys_class_name"`
+	OwnedBy           string `json:"owned_by"`
+	SupportGroup      string `json:"support_group"`
+	OperationalStatus string `json:"operational_status"`
+	Environment       string `json:"environment"`
+}
+
+type ServiceNowConfigurationItemsResult struct {
+	Result []*ServiceNowConfigurationItem `json:"result"`
+}
+
+// CreateConfigurationItemSearchPost returns the post listing the configuration items found for a search term with the buttons for using them
+func CreateConfigurationItemSearchPost(items []*ServiceNowConfigurationItem, searchTerm, serviceNowURL, pluginURL string) *model.Post {
+	post := &model.Post{
+		Message: fmt.Sprintf("Found %d configuration item(s) matching %q.", len(items), searchTerm),
+	}
+
+	attachments := make([]*model.SlackAttachment, 0, len(items))
+	for _, item := range items {
+		attachments = append(attachments, &model.SlackAttachment{
+			Title:     item.Name,
+			TitleLink: fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeConfigurationItem, item.SysID, constants.RecordTypeConfigurationItem),
+			Fields: []*model.SlackAttachmentField{
+				{Title: "Class", Value: getTextOrNotAvailable(item.ClassName), Short: true},
+				{Title: "Operational status", Value: getTextOrNotAvailable(item.OperationalStatus), Short: true},
+				{Title: "Owned by", Value: getTextOrNotAvailable(item.OwnedBy), Short: true},
+				{Title: "Support group", Value: getTextOrNotAvailable(item.SupportGroup), Short: true},
+				{Title: "Environment", Value: getTextOrNotAvailable(item.Environment), Short: true},
+			},
+			Actions: []*model.PostAction{
+				GetConfigurationItemAction("Share in channel", constants.ConfigurationItemActionShare, item.SysID, "", pluginURL),
+				GetConfigurationItemAction("Open incidents and changes", constants.ConfigurationItemActionRecords, item.SysID, "", pluginURL),
+				GetConfigurationItemAction("Subscribe to incidents", constants.ConfigurationItemActionSubscribe, item.SysID, "", pluginURL),
+			},
+		})
+	}
+
+	model.ParseSlackAttachment(post, attachments)
+	return post
+}
+
+// GetConfigurationItemAction returns the button for applying an action on a configuration item of the given instance
+func GetConfigurationItemAction(name, action, itemID, instanceID, pluginURL string) *model.PostAction {
+	return &model.PostAction{
+		Type: model.PostActionTypeButton,
+		Name: name,
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("%s%s", pluginURL, constants.PathConfigItemAction),
+			Context: withInstance(map[string]interface{}{
+				constants.ContextNameRecordID: itemID,
+				constants.ContextNameAction:   action,
+			}, instanceID),
+		},
+	}
+}
+
+// GetFormattedConfigurationItemRecords returns the tables of the open incidents and changes of a configuration item
+func GetFormattedConfigurationItemRecords(itemName string, incidents, changes []*ServiceNowRecord, serviceNowURL string) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("#### Open incidents of %s\n", itemName))
+	writeConfigurationItemRecords(&sb, incidents, constants.RecordTypeIncident, serviceNowURL)
+	sb.WriteString(fmt.Sprintf("\n\n#### Open changes of %s\n", itemName))
+	writeConfigurationItemRecords(&sb, changes, constants.RecordTypeChangeRequest, serviceNowURL)
+	return sb.String()
+}
+
+func writeConfigurationItemRecords(sb *strings.Builder, records []*ServiceNowRecord, recordType, serviceNowURL string) {
+	if len(records) == 0 {
+		sb.WriteString("None")
+		return
+	}
+
+	sb.WriteString("| Number | Short description | State | Priority |\n| :----|:--------| :--------| :--------|")
+	for _, record := range records {
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, recordType, record.SysID, recordType)
+		sb.WriteString(fmt.Sprintf("\n|[%s](%s)|%s|%s|%s|", record.Number, link, strings.ReplaceAll(record.ShortDescription, "|", "\\|"), getTextOrNotAvailable(record.State), getTextOrNotAvailable(record.Priority)))
+	}
+}
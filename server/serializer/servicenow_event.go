@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
@@ -28,7 +29,30 @@ type ServiceNowEvent struct {
 	Priority         string `json:"priority"`
 	AssignedTo       string `json:"assigned_to"`
 	AssignmentGroup  string `json:"assignment_group"`
+	Stage            string `json:"stage"`
 	EventOccurred    string `json:"event_occurred"`
+
+	// sys_ids of the referenced users and groups, used for the personal subscriptions
+	AssignedToID      string `json:"assigned_to_sys_id"`
+	AssignmentGroupID string `json:"assignment_group_sys_id"`
+	OpenedByID        string `json:"opened_by_sys_id"`
+	RequestedForID    string `json:"requested_for_sys_id"`
+
+	// sys_ids of the members of the assignment group when the event occurred, only sent with the personal notifications
+	AssignmentGroupMemberIDs []string `json:"assignment_group_member_sys_ids"`
+
+	// sys_id of the configuration item of the record, used for filtering the notifications of the bulk subscriptions
+	ConfigurationItemID string `json:"cmdb_ci_sys_id"`
+
+	// The most critical active SLA of the record. For the SLA events, it is the SLA whose percentage has changed.
+	SLAID         string `json:"sla_sys_id"`
+	SLAName       string `json:"sla_name"`
+	SLAPercentage string `json:"sla_percentage"`
+	SLATimeLeft   string `json:"sla_time_left"`
+	SLABreached   bool   `json:"sla_has_breached"`
+
+	// ID of the instance which sent the event, set from the webhook secret of the request
+	InstanceID string `json:"-"`
 }
 
 func ServiceNowEventFromJSON(data io.Reader) (*ServiceNowEvent, error) {
@@ -40,6 +64,16 @@ func ServiceNowEventFromJSON(data io.Reader) (*ServiceNowEvent, error) {
 	return se, nil
 }
 
+// GetMentionsMessage returns the message for mentioning the given Mattermost users in the notification post
+func GetMentionsMessage(usernames []string) string {
+	mentions := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		mentions = append(mentions, "@"+username)
+	}
+
+	return strings.Join(mentions, " ")
+}
+
 func (se *ServiceNowEvent) CreateNotificationPost(botID, serviceNowURL, pluginURL string) *model.Post {
 	post := &model.Post{
 		ChannelId: se.ChannelID,
@@ -60,10 +94,10 @@ func (se *ServiceNowEvent) CreateNotificationPost(botID, serviceNowURL, pluginUR
 			Name: "Add and view comments",
 			Integration: &model.PostActionIntegration{
 				URL: fmt.Sprintf("%s%s", pluginURL, constants.PathOpenCommentModal),
-				Context: map[string]interface{}{
+				Context: withInstance(map[string]interface{}{
 					constants.ContextNameRecordType: se.RecordType,
 					constants.ContextNameRecordID:   se.RecordID,
-				},
+				}, se.InstanceID),
 			},
 		})
 	}
@@ -74,48 +108,98 @@ func (se *ServiceNowEvent) CreateNotificationPost(botID, serviceNowURL, pluginUR
 			Name: "Update State",
 			Integration: &model.PostActionIntegration{
 				URL: fmt.Sprintf("%s%s", pluginURL, constants.PathOpenStateModal),
-				Context: map[string]interface{}{
+				Context: withInstance(map[string]interface{}{
 					constants.ContextNameRecordType: se.RecordType,
 					constants.ContextNameRecordID:   se.RecordID,
-				},
+				}, se.InstanceID),
 			},
 		})
 	}
 
+	if se.RecordType == constants.RecordTypeIncident {
+		actions = append(actions, GetPageOnCallAction(se.RecordID, se.InstanceID, pluginURL))
+	}
+
+	fields := []*model.SlackAttachmentField{
+		{
+			Title: "Record",
+			Value: se.RecordTypeName,
+			Short: true,
+		},
+		{
+			Title: "State",
+			Value: se.State,
+			Short: true,
+		},
+		{
+			Title: "Priority",
+			Value: se.Priority,
+			Short: true,
+		},
+		{
+			Title: "Assigned to",
+			Value: se.AssignedTo,
+			Short: true,
+		},
+		{
+			Title: "Assignment group",
+			Value: se.AssignmentGroup,
+			Short: true,
+		},
+	}
+
+	// Only the requested items have a stage
+	if se.Stage != "" {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: "Stage",
+			Value: se.Stage,
+			Short: true,
+		})
+	}
+
+	if se.SLAName != "" {
+		fields = append(fields, se.getSLAFields()...)
+	}
+
+	text := fmt.Sprintf("**Event: %s**", constants.FormattedEventNames[se.EventOccurred])
+	if se.EventOccurred == constants.SubscriptionEventSLA {
+		text = fmt.Sprintf(":warning: **SLA warning: %s has reached %s%% of its time**", se.SLAName, se.GetSLAPercentage())
+	}
+
 	titleLink := fmt.Sprintf(constants.PathRecord, serviceNowURL, se.RecordType, se.RecordID, se.RecordType)
 	slackAttachment := &model.SlackAttachment{
-		Title: fmt.Sprintf("[%s](%s): %s", se.Number, titleLink, se.ShortDescription),
-		Text:  fmt.Sprintf("**Event: %s**", constants.FormattedEventNames[se.EventOccurred]),
-		Fields: []*model.SlackAttachmentField{
-			{
-				Title: "Record",
-				Value: se.RecordTypeName,
-				Short: true,
-			},
-			{
-				Title: "State",
-				Value: se.State,
-				Short: true,
-			},
-			{
-				Title: "Priority",
-				Value: se.Priority,
-				Short: true,
-			},
-			{
-				Title: "Assigned to",
-				Value: se.AssignedTo,
-				Short: true,
-			},
-			{
-				Title: "Assignment group",
-				Value: se.AssignmentGroup,
-				Short: true,
-			},
-		},
+		Title:   fmt.Sprintf("[%s](%s): %s", se.Number, titleLink, se.ShortDescription),
+		Text:    text,
+		Fields:  fields,
 		Actions: actions,
 	}
 
 	model.ParseSlackAttachment(post, []*model.SlackAttachment{slackAttachment})
 	return post
 }
+
+// GetSLAPercentage returns the elapsed percentage of the SLA without the decimals sent by ServiceNow
+func (se *ServiceNowEvent) GetSLAPercentage() string {
+	percentage, _, _ := strings.Cut(strings.TrimSpace(se.SLAPercentage), ".")
+	return percentage
+}
+
+func (se *ServiceNowEvent) getSLAFields() []*model.SlackAttachmentField {
+	status := fmt.Sprintf("%s: %s%%", se.SLAName, se.GetSLAPercentage())
+	if se.SLABreached {
+		status += " (breached)"
+	}
+
+	return []*model.SlackAttachmentField{
+		{
+			Title: "SLA",
+			Value: status,
+			Short: true,
+		},
+		{
+			Title: "SLA time left",
+			Value: getTextOrNotAvailable(se.SLATimeLeft),
+			Short: true,
+		},
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+// ServiceNowGroup is an assignment group of ServiceNow
+type ServiceNowGroup struct {
+	SysID string `json:"sys_id"`
+	Name  string `json:"name"`
+}
+
+type ServiceNowGroupsResult struct {
+	Result []*ServiceNowGroup `json:"result"`
+}
@@ -27,6 +27,8 @@ type IncidentPayload struct {
 	Description      string `json:"description"`
 	Caller           string `json:"caller_id"`
 	ChannelID        string `json:"channel_id"`
+	Urgency          string `json:"urgency,omitempty"`
+	AssignmentGroup  string `json:"assignment_group,omitempty"`
 }
 
 type IncidentResponse struct {
@@ -0,0 +1,178 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+type ServiceNowKnowledgeArticle struct {
+	SysID            string `json:"sys_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Text             string `json:"text"`
+	KnowledgeBase    string `json:"kb_knowledge_base"`
+	Category         string `json:"kb_category"`
+}
+
+type ServiceNowKnowledgeArticlesResult struct {
+	Result []*ServiceNowKnowledgeArticle `json:"result"`
+}
+
+// KnowledgeArticleSuggestion is an article suggested for solving an incident created from Mattermost
+type KnowledgeArticleSuggestion struct {
+	SysID            string `json:"sys_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Snippet          string `json:"snippet"`
+	Link             string `json:"link"`
+}
+
+// IncidentCreatedResponse is the incident created from Mattermost along with the articles suggested for solving it
+type IncidentCreatedResponse struct {
+	*ServiceNowRecord
+	SuggestedArticles []*KnowledgeArticleSuggestion `json:"suggested_articles,omitempty"`
+}
+
+// GetSnippet returns the part of the article around the first word of the query found in it
+func (ka *ServiceNowKnowledgeArticle) GetSnippet(query string) string {
+	text := []rune(ConvertHTMLToText(ka.Text))
+	if len(text) <= constants.KnowledgeSnippetLength {
+		return string(text)
+	}
+
+	lowerText := strings.ToLower(string(text))
+	start := 0
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if index := strings.Index(lowerText, word); index >= 0 {
+			// Show some of the text before the match for the context
+			start = len([]rune(lowerText[:index])) - constants.KnowledgeSnippetLength/4
+			break
+		}
+	}
+
+	if start < 0 {
+		start = 0
+	}
+	if start+constants.KnowledgeSnippetLength > len(text) {
+		start = len(text) - constants.KnowledgeSnippetLength
+	}
+
+	snippet := strings.TrimFunc(string(text[start:start+constants.KnowledgeSnippetLength]), unicode.IsSpace)
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if start+constants.KnowledgeSnippetLength < len(text) {
+		snippet += "..."
+	}
+
+	return snippet
+}
+
+// GetSuggestion returns the article as a suggestion for the given query
+func (ka *ServiceNowKnowledgeArticle) GetSuggestion(query, serviceNowURL string) *KnowledgeArticleSuggestion {
+	return &KnowledgeArticleSuggestion{
+		SysID:            ka.SysID,
+		Number:           ka.Number,
+		ShortDescription: ka.ShortDescription,
+		Snippet:          ka.GetSnippet(query),
+		Link:             fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeKnowledge, ka.SysID, constants.RecordTypeKnowledge),
+	}
+}
+
+// GetKnowledgeArticleMarkdown returns the body of an article as Markdown, truncated to fit in a post
+func GetKnowledgeArticleMarkdown(htmlText, serviceNowURL, articleLink string) string {
+	text := ConvertHTMLToMarkdown(htmlText, serviceNowURL)
+	if runes := []rune(text); len(runes) > constants.MaxKnowledgeArticleLength {
+		text = fmt.Sprintf("%s...\n\n[Read the full article in ServiceNow](%s)", string(runes[:constants.MaxKnowledgeArticleLength]), articleLink)
+	}
+
+	return text
+}
+
+// CreateKnowledgeSearchPost returns the post listing the articles found for a query with the buttons for viewing and sharing them
+func CreateKnowledgeSearchPost(articles []*ServiceNowKnowledgeArticle, query, serviceNowURL, pluginURL string) *model.Post {
+	post := &model.Post{
+		Message: fmt.Sprintf("Found %d knowledge article(s) for %q.", len(articles), query),
+	}
+
+	attachments := make([]*model.SlackAttachment, 0, len(articles))
+	for _, article := range articles {
+		var fields []*model.SlackAttachmentField
+		if article.KnowledgeBase != "" {
+			fields = append(fields, &model.SlackAttachmentField{Title: "Knowledge Base", Value: article.KnowledgeBase, Short: true})
+		}
+		if article.Category != "" {
+			fields = append(fields, &model.SlackAttachmentField{Title: "Category", Value: article.Category, Short: true})
+		}
+
+		attachments = append(attachments, &model.SlackAttachment{
+			Title:     fmt.Sprintf("%s: %s", article.Number, article.ShortDescription),
+			TitleLink: fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeKnowledge, article.SysID, constants.RecordTypeKnowledge),
+			Text:      article.GetSnippet(query),
+			Fields:    fields,
+			Actions: []*model.PostAction{
+				getKnowledgeArticleAction("View article", constants.KnowledgeActionView, article.SysID, pluginURL),
+				getKnowledgeArticleAction("Share in channel", constants.KnowledgeActionShare, article.SysID, pluginURL),
+			},
+		})
+	}
+
+	model.ParseSlackAttachment(post, attachments)
+	return post
+}
+
+func getKnowledgeArticleAction(name, action, articleID, pluginURL string) *model.PostAction {
+	return &model.PostAction{
+		Type: model.PostActionTypeButton,
+		Name: name,
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("%s%s", pluginURL, constants.PathKnowledgeArticleAction),
+			Context: map[string]interface{}{
+				constants.ContextNameRecordID: articleID,
+				constants.ContextNameAction:   action,
+			},
+		},
+	}
+}
+
+// CreateKnowledgeSuggestionsPost returns the reply to an incident post suggesting the articles which might solve the incident
+func CreateKnowledgeSuggestionsPost(suggestions []*KnowledgeArticleSuggestion, incidentID, incidentNumber, pluginURL string) *model.Post {
+	post := &model.Post{
+		Message: fmt.Sprintf("These knowledge articles might help with solving **%s**:", incidentNumber),
+	}
+
+	attachments := make([]*model.SlackAttachment, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		attachments = append(attachments, &model.SlackAttachment{
+			Title:     fmt.Sprintf("%s: %s", suggestion.Number, suggestion.ShortDescription),
+			TitleLink: suggestion.Link,
+			Text:      suggestion.Snippet,
+			Actions: []*model.PostAction{
+				getKnowledgeArticleAction("View article", constants.KnowledgeActionView, suggestion.SysID, pluginURL),
+				{
+					Type: model.PostActionTypeButton,
+					Name: "This solved it",
+					Integration: &model.PostActionIntegration{
+						URL: fmt.Sprintf("%s%s", pluginURL, constants.PathKnowledgeSolvedAction),
+						Context: map[string]interface{}{
+							constants.ContextNameRecordID:  incidentID,
+							constants.ContextNameArticleID: suggestion.SysID,
+							constants.ContextNameNumber:    suggestion.Number,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	model.ParseSlackAttachment(post, attachments)
+	return post
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceNowOnCallUser is a user on call for a group as returned by the On-Call Scheduling API of ServiceNow.
+// The name and email are not returned by the API and are filled from the users of ServiceNow.
+type ServiceNowOnCallUser struct {
+	UserID          string `json:"userId"`
+	EscalationLevel int    `json:"escalation_level"`
+	Rota            string `json:"rota"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+}
+
+type ServiceNowOnCallResult struct {
+	Result []*ServiceNowOnCallUser `json:"result"`
+}
+
+// ServiceNowUserDetails contains the details of a ServiceNow user used for finding their Mattermost account
+type ServiceNowUserDetails struct {
+	SysID string `json:"sys_id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type ServiceNowUserDetailsResult struct {
+	Result []*ServiceNowUserDetails `json:"result"`
+}
+
+// GetPrimaryOnCallUsers returns the users on call at the first escalation level, who are the ones to be paged
+func GetPrimaryOnCallUsers(users []*ServiceNowOnCallUser) []*ServiceNowOnCallUser {
+	primary := []*ServiceNowOnCallUser{}
+	for _, user := range users {
+		if len(primary) > 0 && user.EscalationLevel > primary[0].EscalationLevel {
+			continue
+		}
+
+		if len(primary) > 0 && user.EscalationLevel < primary[0].EscalationLevel {
+			primary = primary[:0]
+		}
+		primary = append(primary, user)
+	}
+
+	return primary
+}
+
+// GetFormattedOnCall returns the message showing who is on call now and next for a group.
+// The users are already formatted as Mattermost mentions or ServiceNow names.
+func GetFormattedOnCall(groupName string, current, next []string, nextFrom string) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("#### On call for %s\n", groupName))
+	if len(current) == 0 {
+		sb.WriteString("**Now:** No one is on call.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("**Now:** %s\n", strings.Join(current, ", ")))
+	}
+
+	if len(next) == 0 {
+		sb.WriteString("**Next:** No change in the upcoming days.")
+	} else {
+		sb.WriteString(fmt.Sprintf("**Next:** %s, on call by %s (UTC)", strings.Join(next, ", "), nextFrom))
+	}
+
+	return sb.String()
+}
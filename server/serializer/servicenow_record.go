@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -18,6 +19,7 @@ type ServiceNowPartialRecord struct {
 	SysID            string `json:"sys_id"`
 	Number           string `json:"number"`
 	ShortDescription string `json:"short_description"`
+	Name             string `json:"name,omitempty"`
 }
 
 type ServiceNowRecord struct {
@@ -34,6 +36,22 @@ type ServiceNowRecord struct {
 	KnowledgeBase    interface{} `json:"kb_knowledge_base,omitempty"`
 	Category         interface{} `json:"kb_category,omitempty"`
 	Author           interface{} `json:"author,omitempty"`
+	Stage            string      `json:"stage,omitempty"`
+	Approval         string      `json:"approval,omitempty"`
+	DueDate          string      `json:"due_date,omitempty"`
+	RequestedFor     interface{} `json:"requested_for,omitempty"`
+	Text             string      `json:"text,omitempty"`
+
+	// Fields of the configuration items
+	Name              string      `json:"name,omitempty"`
+	ClassName         string      `json:"sys_class_name,omitempty"`
+	OwnedBy           interface{} `json:"owned_by,omitempty"`
+	SupportGroup      interface{} `json:"support_group,omitempty"`
+	OperationalStatus string      `json:"operational_status,omitempty"`
+	Environment       string      `json:"environment,omitempty"`
+
+	// ID of the instance the record was fetched from, added to the context of the buttons of its post
+	InstanceID string `json:"-"`
 }
 
 type NestedField struct {
@@ -45,6 +63,10 @@ type ServiceNowPartialRecordsResult struct {
 	Result []*ServiceNowPartialRecord `json:"result"`
 }
 
+type ServiceNowRecordsResult struct {
+	Result []*ServiceNowRecord `json:"result"`
+}
+
 type ServiceNowRecordResult struct {
 	Result *ServiceNowRecord `json:"result"`
 }
@@ -76,7 +98,8 @@ func (sr *ServiceNowRecord) CreateSharingPost(channelID, botID, serviceNowURL, p
 	titleLink := fmt.Sprintf("%s/nav_to.do?uri=%s.do?sys_id=%s", serviceNowURL, sr.RecordType, sr.SysID)
 	fields := []*model.SlackAttachmentField{}
 
-	if sr.RecordType == constants.RecordTypeKnowledge {
+	switch sr.RecordType {
+	case constants.RecordTypeKnowledge:
 		fields = append(fields, []*model.SlackAttachmentField{
 			{
 				Title: "Knowledge Base",
@@ -95,7 +118,53 @@ func (sr *ServiceNowRecord) CreateSharingPost(channelID, botID, serviceNowURL, p
 				Value: sr.Author,
 			},
 		}...)
-	} else {
+	case constants.RecordTypeRequest, constants.RecordTypeRequestItem:
+		fields = append(fields, []*model.SlackAttachmentField{
+			{
+				Title: "Stage",
+				Value: getTextOrNotAvailable(sr.Stage),
+			},
+			{
+				Title: "Approval",
+				Value: getTextOrNotAvailable(sr.Approval),
+			},
+			{
+				Title: "Requested for",
+				Value: sr.RequestedFor,
+			},
+			{
+				Title: "Due date",
+				Value: getTextOrNotAvailable(sr.DueDate),
+			},
+			{
+				Title: "Assigned to",
+				Value: sr.AssignedTo,
+			},
+		}...)
+	case constants.RecordTypeConfigurationItem:
+		fields = append(fields, []*model.SlackAttachmentField{
+			{
+				Title: "Class",
+				Value: getTextOrNotAvailable(sr.ClassName),
+			},
+			{
+				Title: "Owned by",
+				Value: sr.OwnedBy,
+			},
+			{
+				Title: "Support group",
+				Value: sr.SupportGroup,
+			},
+			{
+				Title: "Operational status",
+				Value: getTextOrNotAvailable(sr.OperationalStatus),
+			},
+			{
+				Title: "Environment",
+				Value: getTextOrNotAvailable(sr.Environment),
+			},
+		}...)
+	default:
 		fields = append(fields, []*model.SlackAttachmentField{
 			{
 				Title: "State",
@@ -123,10 +192,10 @@ func (sr *ServiceNowRecord) CreateSharingPost(channelID, botID, serviceNowURL, p
 			Name: "Add and view comments",
 			Integration: &model.PostActionIntegration{
 				URL: fmt.Sprintf("%s%s", pluginURL, constants.PathOpenCommentModal),
-				Context: map[string]interface{}{
+				Context: withInstance(map[string]interface{}{
 					constants.ContextNameRecordType: sr.RecordType,
 					constants.ContextNameRecordID:   sr.SysID,
-				},
+				}, sr.InstanceID),
 			},
 		})
 	}
@@ -137,20 +206,42 @@ func (sr *ServiceNowRecord) CreateSharingPost(channelID, botID, serviceNowURL, p
 			Name: "Update State",
 			Integration: &model.PostActionIntegration{
 				URL: fmt.Sprintf("%s%s", pluginURL, constants.PathOpenStateModal),
-				Context: map[string]interface{}{
+				Context: withInstance(map[string]interface{}{
 					constants.ContextNameRecordType: sr.RecordType,
 					constants.ContextNameRecordID:   sr.SysID,
-				},
+				}, sr.InstanceID),
 			},
 		})
 	}
 
+	if sr.RecordType == constants.RecordTypeIncident {
+		actions = append(actions, GetPageOnCallAction(sr.SysID, sr.InstanceID, pluginURL))
+	}
+
+	title := fmt.Sprintf("[%s](%s): %s", sr.Number, titleLink, sr.ShortDescription)
+	if sr.RecordType == constants.RecordTypeConfigurationItem {
+		actions = append(actions,
+			GetConfigurationItemAction("Open incidents and changes", constants.ConfigurationItemActionRecords, sr.SysID, sr.InstanceID, pluginURL),
+			GetConfigurationItemAction("Subscribe to incidents", constants.ConfigurationItemActionSubscribe, sr.SysID, sr.InstanceID, pluginURL),
+		)
+
+		// The configuration items are identified by their name, as they don't have a number
+		title = fmt.Sprintf("[%s](%s)", sr.Name, titleLink)
+		if sr.ShortDescription != "" {
+			title += ": " + sr.ShortDescription
+		}
+	}
+
 	slackAttachment := &model.SlackAttachment{
-		Title:   fmt.Sprintf("[%s](%s): %s", sr.Number, titleLink, sr.ShortDescription),
+		Title:   title,
 		Fields:  fields,
 		Actions: actions,
 	}
 
+	if sr.RecordType == constants.RecordTypeKnowledge && sr.Text != "" {
+		slackAttachment.Text = GetKnowledgeArticleMarkdown(sr.Text, serviceNowURL, titleLink)
+	}
+
 	if sharedByUsername != "" {
 		slackAttachment.Pretext = fmt.Sprintf("Shared by @%s", sharedByUsername)
 	}
@@ -159,9 +250,49 @@ func (sr *ServiceNowRecord) CreateSharingPost(channelID, botID, serviceNowURL, p
 	return post
 }
 
+// GetAssignmentGroup returns the sys_id and the name of the assignment group of a record fetched with the display values
+func (sr *ServiceNowRecord) GetAssignmentGroup() (groupID, groupName string) {
+	fieldMap, ok := sr.AssignmentGroup.(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+
+	field := &NestedField{}
+	if err := field.LoadFromMap(fieldMap); err != nil || field.Link == "" {
+		return "", ""
+	}
+
+	return path.Base(field.Link), field.DisplayValue
+}
+
+// GetPageOnCallAction returns the button for paging the users on call for the assignment group of an incident
+func GetPageOnCallAction(incidentID, instanceID, pluginURL string) *model.PostAction {
+	return &model.PostAction{
+		Type: model.PostActionTypeButton,
+		Name: "Page on-call",
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("%s%s", pluginURL, constants.PathPageOnCallAction),
+			Context: withInstance(map[string]interface{}{
+				constants.ContextNameRecordID: incidentID,
+			}, instanceID),
+		},
+	}
+}
+
+// withInstance adds the instance to the context of a button, so that the action is applied on the instance the post came from.
+// The context of the buttons of the default instance is left unchanged.
+func withInstance(context map[string]interface{}, instanceID string) map[string]interface{} {
+	if instanceID != "" && instanceID != constants.DefaultInstanceID {
+		context[constants.ContextNameInstanceID] = instanceID
+	}
+
+	return context
+}
+
 func (sr *ServiceNowRecord) HandleNestedFields(serviceNowURL string) error {
 	var err error
-	if sr.RecordType == constants.RecordTypeKnowledge {
+	switch sr.RecordType {
+	case constants.RecordTypeKnowledge:
 		sr.KnowledgeBase, err = GetNestedFieldValue(sr.KnowledgeBase, constants.FieldKnowledgeBase, serviceNowURL)
 		if err != nil {
 			return fmt.Errorf("%w : kb_knowledge_base", err)
@@ -174,7 +305,16 @@ func (sr *ServiceNowRecord) HandleNestedFields(serviceNowURL string) error {
 		if err != nil {
 			return fmt.Errorf("%w : author", err)
 		}
-	} else {
+	case constants.RecordTypeConfigurationItem:
+		sr.OwnedBy, err = GetNestedFieldValue(sr.OwnedBy, constants.FieldOwnedBy, serviceNowURL)
+		if err != nil {
+			return fmt.Errorf("%w : owned_by", err)
+		}
+		sr.SupportGroup, err = GetNestedFieldValue(sr.SupportGroup, constants.FieldSupportGroup, serviceNowURL)
+		if err != nil {
+			return fmt.Errorf("%w : support_group", err)
+		}
+	default:
 		sr.AssignedTo, err = GetNestedFieldValue(sr.AssignedTo, constants.FieldAssignedTo, serviceNowURL)
 		if err != nil {
 			return fmt.Errorf("%w : assigned_to", err)
@@ -183,6 +323,13 @@ func (sr *ServiceNowRecord) HandleNestedFields(serviceNowURL string) error {
 		if err != nil {
 			return fmt.Errorf("%w : assignment_group", err)
 		}
+
+		if sr.RecordType == constants.RecordTypeRequest || sr.RecordType == constants.RecordTypeRequestItem {
+			sr.RequestedFor, err = GetNestedFieldValue(sr.RequestedFor, constants.FieldRequestedFor, serviceNowURL)
+			if err != nil {
+				return fmt.Errorf("%w : requested_for", err)
+			}
+		}
 	}
 
 	return err
@@ -206,9 +353,9 @@ func GetNestedFieldValue(field interface{}, fieldType, serviceNowURL string) (st
 	sysID := GetSysID(nf.Link)
 	url := serviceNowURL
 	switch fieldType {
-	case constants.FieldAssignedTo:
+	case constants.FieldAssignedTo, constants.FieldRequestedFor, constants.FieldOwnedBy:
 		url += fmt.Sprintf(constants.PathSysUser, sysID)
-	case constants.FieldAssignmentGroup:
+	case constants.FieldAssignmentGroup, constants.FieldSupportGroup:
 		url += fmt.Sprintf(constants.PathSysUserGroup, sysID)
 	case constants.FieldKnowledgeBase:
 		url += fmt.Sprintf(constants.PathKnowledgeBase, sysID)
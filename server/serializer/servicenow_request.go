@@ -0,0 +1,76 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// ServiceNowRequestItem is an item of a service catalog request, also known as a RITM
+type ServiceNowRequestItem struct {
+	SysID            string `json:"sys_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Stage            string `json:"stage"`
+	State            string `json:"state"`
+	Approval         string `json:"approval"`
+	DueDate          string `json:"due_date"`
+	Request          string `json:"request"`
+}
+
+type ServiceNowRequestItemsResult struct {
+	Result []*ServiceNowRequestItem `json:"result"`
+}
+
+// CreateCatalogOrderPost returns the post containing the request created for a catalog item along with its items
+func CreateCatalogOrderPost(itemName, serviceNowURL string, order *ServiceNowCatalogOrder, requestItems []*ServiceNowRequestItem) *model.Post {
+	post := &model.Post{}
+	titleLink := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeRequest, order.GetOrderID(), constants.RecordTypeRequest)
+	fields := make([]*model.SlackAttachmentField, 0, len(requestItems))
+	for _, requestItem := range requestItems {
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeRequestItem, requestItem.SysID, constants.RecordTypeRequestItem)
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: requestItem.ShortDescription,
+			Value: fmt.Sprintf("[%s](%s)\n**Stage:** %s", requestItem.Number, link, getTextOrNotAvailable(requestItem.Stage)),
+			Short: true,
+		})
+	}
+
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{
+		{
+			Title:  fmt.Sprintf("[%s](%s): %s", order.GetOrderNumber(), titleLink, itemName),
+			Text:   "**Your request has been submitted**",
+			Fields: fields,
+		},
+	})
+
+	return post
+}
+
+// GetFormattedRequestItems returns the table of the requested items along with their stage and approval
+func GetFormattedRequestItems(requestItems []*ServiceNowRequestItem, serviceNowURL string) string {
+	sb := strings.Builder{}
+	sb.WriteString("#### Your open requests\n")
+	sb.WriteString("| Number | Item | Request | Stage | Approval | Due date |\n| :----|:--------| :--------| :--------| :--------| :--------|")
+	for _, requestItem := range requestItems {
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeRequestItem, requestItem.SysID, constants.RecordTypeRequestItem)
+		sb.WriteString(fmt.Sprintf("\n|[%s](%s)|%s|%s|%s|%s|%s|", requestItem.Number, link, strings.ReplaceAll(requestItem.ShortDescription, "|", "\\|"),
+			getTextOrNotAvailable(requestItem.Request), getTextOrNotAvailable(requestItem.Stage), getTextOrNotAvailable(requestItem.Approval), getTextOrNotAvailable(requestItem.DueDate)))
+	}
+
+	return sb.String()
+}
+
+func getTextOrNotAvailable(text string) string {
+	if text == "" {
+		return constants.NotAvailableText
+	}
+
+	return text
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// ServiceNowTaskSLA is an active SLA of a record along with the fields of the record shown in the SLA warnings
+type ServiceNowTaskSLA struct {
+	SysID            string `json:"sys_id"`
+	Name             string `json:"sla"`
+	Percentage       string `json:"business_percentage"`
+	TimeLeft         string `json:"business_time_left"`
+	HasBreached      string `json:"has_breached"`
+	RecordID         string `json:"task.sys_id"`
+	RecordTypeName   string `json:"task.sys_class_name"`
+	Number           string `json:"task.number"`
+	ShortDescription string `json:"task.short_description"`
+	State            string `json:"task.state"`
+	Priority         string `json:"task.priority"`
+	AssignedTo       string `json:"task.assigned_to"`
+	AssignmentGroup  string `json:"task.assignment_group"`
+}
+
+type ServiceNowTaskSLAsResult struct {
+	Result []*ServiceNowTaskSLA `json:"result"`
+}
+
+// ToEvent returns the SLA event of the record for the channel of the subscription
+func (s *ServiceNowTaskSLA) ToEvent(subscription *SubscriptionResponse) *ServiceNowEvent {
+	return &ServiceNowEvent{
+		SubscriptionID:   subscription.SysID,
+		InstanceID:       subscription.InstanceID,
+		RecordID:         s.RecordID,
+		ChannelID:        subscription.ChannelID,
+		UserID:           subscription.UserID,
+		SubscriptionType: subscription.Type,
+		RecordType:       subscription.RecordType,
+		RecordTypeName:   s.RecordTypeName,
+		Events:           subscription.SubscriptionEvents,
+		Number:           s.Number,
+		ShortDescription: s.ShortDescription,
+		State:            s.State,
+		Priority:         s.Priority,
+		AssignedTo:       s.AssignedTo,
+		AssignmentGroup:  s.AssignmentGroup,
+		EventOccurred:    constants.SubscriptionEventSLA,
+		SLAID:            s.SysID,
+		SLAName:          s.Name,
+		SLAPercentage:    s.Percentage,
+		SLATimeLeft:      s.TimeLeft,
+		SLABreached:      s.HasBreached == "true",
+	}
+}
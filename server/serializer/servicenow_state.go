@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
 )
 
 type ServiceNowState struct {
-	Label string `json:"label"`
-	Value string `json:"value"`
+	Label          string   `json:"label"`
+	Value          string   `json:"value"`
+	RequiredFields []string `json:"required_fields,omitempty"`
 }
 
 type ServiceNowStatesResult struct {
@@ -20,7 +23,9 @@ type ServiceNowStatesResult struct {
 }
 
 type ServiceNowUpdateStatePayload struct {
-	State string `json:"state"`
+	State      string `json:"state"`
+	CloseCode  string `json:"close_code,omitempty"`
+	CloseNotes string `json:"close_notes,omitempty"`
 }
 
 func ServiceNowStatePayloadFromJSON(data io.Reader) (*ServiceNowUpdateStatePayload, error) {
@@ -38,5 +43,36 @@ func (s *ServiceNowUpdateStatePayload) Validate() error {
 		return fmt.Errorf("state value cannot be empty")
 	}
 
+	s.CloseCode = strings.TrimSpace(s.CloseCode)
+	s.CloseNotes = strings.TrimSpace(s.CloseNotes)
 	return nil
 }
+
+// GetMissingRequiredFields returns the fields which are mandatory for moving a record of the given type into the target state but are not present in the payload
+func (s *ServiceNowUpdateStatePayload) GetMissingRequiredFields(recordType string) []*FieldError {
+	var fieldErrors []*FieldError
+	for _, field := range constants.StateTransitionRequiredFields[recordType][s.State] {
+		if s.getFieldValue(field) != "" {
+			continue
+		}
+
+		fieldErrors = append(fieldErrors, &FieldError{
+			Field:   field,
+			Label:   constants.FormattedFieldLabels[field],
+			Message: fmt.Sprintf("%s is required", constants.FormattedFieldLabels[field]),
+		})
+	}
+
+	return fieldErrors
+}
+
+func (s *ServiceNowUpdateStatePayload) getFieldValue(field string) string {
+	switch field {
+	case constants.FieldCloseCode:
+		return s.CloseCode
+	case constants.FieldCloseNotes:
+		return s.CloseNotes
+	}
+
+	return ""
+}
@@ -27,6 +27,20 @@ type SubscriptionPayload struct {
 	SubscriptionEvents *string `json:"subscription_events"`
 	RecordNumber       *string `json:"record_number"`
 	ServerURL          *string `json:"server_url"`
+	// Mentions and the configuration item are stored by the plugin and are not sent to ServiceNow
+	Mentions              *string `json:"mentions,omitempty"`
+	ConfigurationItemID   *string `json:"cmdb_ci,omitempty"`
+	ConfigurationItemName *string `json:"cmdb_ci_name,omitempty"`
+	// InstanceID is the instance in which the subscription is created. It is set by the plugin from the client used for the request.
+	InstanceID string `json:"-"`
+}
+
+// SubscriptionSettings contains the settings of a subscription which are stored by the plugin
+type SubscriptionSettings struct {
+	Mentions string `json:"mentions"`
+	// ConfigurationItemID filters the notifications of a bulk subscription to the records of a single configuration item
+	ConfigurationItemID   string `json:"cmdb_ci,omitempty"`
+	ConfigurationItemName string `json:"cmdb_ci_name,omitempty"`
 }
 
 type SubscriptionResponse struct {
@@ -43,6 +57,23 @@ type SubscriptionResponse struct {
 	IsActive           string `json:"is_active"`
 	Number             string `json:"number"`
 	ShortDescription   string `json:"short_description"`
+	Mentions           string `json:"mentions,omitempty"`
+	// The configuration item filter of a bulk subscription, which is stored by the plugin
+	ConfigurationItemID   string `json:"cmdb_ci,omitempty"`
+	ConfigurationItemName string `json:"cmdb_ci_name,omitempty"`
+	// InstanceID is the instance the subscription was fetched from. It is set by the client and is not returned by ServiceNow.
+	InstanceID string `json:"instance_id,omitempty"`
+}
+
+// GetInstanceKey returns the key under which the plugin stores the data of a subscription, or of another record, of an instance.
+// The sys_ids are only unique within an instance, so they are prefixed with the ID of the instances other than the default one.
+// The keys of the default instance are left unchanged so that the data stored before the instances were added is still found.
+func GetInstanceKey(instanceID, id string) string {
+	if instanceID == "" || instanceID == constants.DefaultInstanceID {
+		return id
+	}
+
+	return fmt.Sprintf("%s/%s", instanceID, id)
 }
 
 func (s *SubscriptionResponse) GetFormattedSubscription() string {
@@ -101,6 +132,10 @@ func (s *SubscriptionPayload) IsValidForUpdation(siteURL string) error {
 	if s.ServerURL != nil && *s.ServerURL != siteURL {
 		return fmt.Errorf("serverURL is different from the site URL")
 	}
+
+	if s.Mentions != nil && !constants.ValidMentionSettings[*s.Mentions] {
+		return fmt.Errorf("mentions is not valid")
+	}
 	return nil
 }
 
@@ -132,7 +167,12 @@ func (s *SubscriptionPayload) IsValidForCreation(siteURL string) error {
 			return fmt.Errorf("recordID is not valid")
 		}
 	} else {
+		// The bulk subscriptions filtered by a configuration item store its sys_id as the record,
+		// so they aren't duplicates of the other bulk subscriptions of the channel
 		recordID := ""
+		if s.ConfigurationItemID != nil {
+			recordID = *s.ConfigurationItemID
+		}
 		s.RecordID = &recordID
 	}
 
@@ -166,9 +206,54 @@ func (s *SubscriptionPayload) IsValidForCreation(siteURL string) error {
 		return fmt.Errorf("serverURL is different from the site URL")
 	}
 
+	if s.Mentions != nil && !constants.ValidMentionSettings[*s.Mentions] {
+		return fmt.Errorf("mentions is not valid")
+	}
+
+	if s.ConfigurationItemID != nil {
+		if *s.Type != constants.SubscriptionTypeBulk || *s.RecordType != constants.RecordTypeIncident {
+			return fmt.Errorf("configuration item can only be set for the bulk subscriptions of incidents")
+		}
+
+		if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, *s.ConfigurationItemID); err != nil || !valid {
+			return fmt.Errorf("configuration item is not valid")
+		}
+	}
+
 	return nil
 }
 
+// TakeSettings removes the settings stored by the plugin from the payload and returns them.
+// Nil is returned when the payload doesn't contain any of the settings.
+func (s *SubscriptionPayload) TakeSettings() *SubscriptionSettings {
+	if s.Mentions == nil && s.ConfigurationItemID == nil {
+		return nil
+	}
+
+	settings := &SubscriptionSettings{Mentions: constants.MentionSettingOff}
+	if s.Mentions != nil {
+		settings.Mentions = *s.Mentions
+	}
+	if s.ConfigurationItemID != nil {
+		settings.ConfigurationItemID = *s.ConfigurationItemID
+	}
+	if s.ConfigurationItemName != nil {
+		settings.ConfigurationItemName = *s.ConfigurationItemName
+	}
+
+	s.Mentions = nil
+	s.ConfigurationItemID = nil
+	s.ConfigurationItemName = nil
+	return settings
+}
+
+// SetSettings sets the settings stored by the plugin in the subscription
+func (s *SubscriptionResponse) SetSettings(settings *SubscriptionSettings) {
+	s.Mentions = settings.Mentions
+	s.ConfigurationItemID = settings.ConfigurationItemID
+	s.ConfigurationItemName = settings.ConfigurationItemName
+}
+
 func SubscriptionFromJSON(data io.Reader) (*SubscriptionPayload, error) {
 	var sp *SubscriptionPayload
 	if err := json.NewDecoder(data).Decode(&sp); err != nil {
@@ -223,6 +308,13 @@ func (s *SubscriptionResponse) CreateSubscriptionCreatedPost(botID, serviceNowUR
 		},
 	}
 
+	if s.ConfigurationItemID != "" {
+		slackAttachment.Fields = append(slackAttachment.Fields, &model.SlackAttachmentField{
+			Title: "Configuration item",
+			Value: fmt.Sprintf("[%s](%s)", s.ConfigurationItemName, fmt.Sprintf(constants.PathRecord, serviceNowURL, constants.RecordTypeConfigurationItem, s.ConfigurationItemID, constants.RecordTypeConfigurationItem)),
+		})
+	}
+
 	model.ParseSlackAttachment(post, []*model.SlackAttachment{slackAttachment})
 	return post
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+var formattedBulkActions = map[string]string{
+	constants.BulkActionDelete:     "Deleted",
+	constants.BulkActionDeactivate: "Deactivated",
+	constants.BulkActionReactivate: "Reactivated",
+	constants.BulkActionEditEvents: "Updated the events of",
+}
+
+// BulkSubscriptionRequest contains the action to be applied on the given subscriptions or on all the subscriptions of a channel
+type BulkSubscriptionRequest struct {
+	Action             string   `json:"action"`
+	SubscriptionIDs    []string `json:"subscription_ids"`
+	ChannelID          string   `json:"channel_id"`
+	SubscriptionEvents string   `json:"subscription_events"`
+}
+
+// BulkSubscriptionItem is the result of applying the action on a single subscription
+type BulkSubscriptionItem struct {
+	SubscriptionID string `json:"subscription_id"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// BulkSubscriptionResult is the result of applying the action on all the subscriptions of the request
+type BulkSubscriptionResult struct {
+	Action string                  `json:"action"`
+	Items  []*BulkSubscriptionItem `json:"items"`
+}
+
+func BulkSubscriptionRequestFromJSON(data io.Reader) (*BulkSubscriptionRequest, error) {
+	var r *BulkSubscriptionRequest
+	if err := json.NewDecoder(data).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *BulkSubscriptionRequest) IsValid() error {
+	if !constants.ValidBulkActions[r.Action] {
+		return fmt.Errorf("action is not valid")
+	}
+
+	if (len(r.SubscriptionIDs) == 0) == (r.ChannelID == "") {
+		return fmt.Errorf("either subscriptionIDs or channelID is required")
+	}
+
+	if r.ChannelID != "" && !model.IsValidId(r.ChannelID) {
+		return fmt.Errorf("channelID is not valid")
+	}
+
+	for _, subscriptionID := range r.SubscriptionIDs {
+		if valid, err := regexp.MatchString(constants.ServiceNowSysIDRegex, subscriptionID); err != nil || !valid {
+			return fmt.Errorf("subscription ID %s is not valid", subscriptionID)
+		}
+	}
+
+	if r.Action != constants.BulkActionEditEvents {
+		return nil
+	}
+
+	if r.SubscriptionEvents == "" {
+		return fmt.Errorf("subscriptionEvents is required")
+	}
+
+	for _, event := range strings.Split(r.SubscriptionEvents, ",") {
+		event = strings.TrimSpace(event)
+		if !constants.ValidSubscriptionEvents[event] {
+			return fmt.Errorf("subscription event %s is not valid", event)
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of subscriptions having the given status
+func (r *BulkSubscriptionResult) Count(status string) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// GetFormattedResult returns the summary of the result along with a table of the status of each subscription
+func (r *BulkSubscriptionResult) GetFormattedResult() string {
+	if len(r.Items) == 0 {
+		return "No subscriptions found."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %d subscription(s). %d failure(s) found.", formattedBulkActions[r.Action], r.Count(constants.BulkStatusSucceeded), r.Count(constants.BulkStatusFailed)))
+	sb.WriteString("\n| Subscription ID | Status | Error |\n| :----|:--------|:--------|")
+	for _, item := range r.Items {
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|", item.SubscriptionID, item.Status, item.Error))
+	}
+
+	return sb.String()
+}
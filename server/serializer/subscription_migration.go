@@ -0,0 +1,126 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// SubscriptionImportRequest contains the exported subscriptions to be imported
+type SubscriptionImportRequest struct {
+	Format     string            `json:"format"`
+	Data       string            `json:"data"`
+	ChannelMap map[string]string `json:"channel_map"`
+	DryRun     bool              `json:"dry_run"`
+}
+
+// SubscriptionCloneRequest contains the channels between which the subscriptions are to be cloned
+type SubscriptionCloneRequest struct {
+	FromChannelID string `json:"from_channel_id"`
+	ToChannelID   string `json:"to_channel_id"`
+	DryRun        bool   `json:"dry_run"`
+}
+
+// SubscriptionImportItem is the result of importing a single subscription
+type SubscriptionImportItem struct {
+	SourceID       string `json:"source_id"`
+	ChannelID      string `json:"channel_id"`
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+}
+
+// SubscriptionImportResult is the result of importing or cloning the subscriptions
+type SubscriptionImportResult struct {
+	DryRun bool                      `json:"dry_run"`
+	Items  []*SubscriptionImportItem `json:"items"`
+}
+
+func SubscriptionImportRequestFromJSON(data io.Reader) (*SubscriptionImportRequest, error) {
+	var r *SubscriptionImportRequest
+	if err := json.NewDecoder(data).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func SubscriptionCloneRequestFromJSON(data io.Reader) (*SubscriptionCloneRequest, error) {
+	var r *SubscriptionCloneRequest
+	if err := json.NewDecoder(data).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Count returns the number of subscriptions having the given status
+func (r *SubscriptionImportResult) Count(status string) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// GetPayloadForImport returns the payload for creating a copy of the subscription in the given channel
+func (s *SubscriptionResponse) GetPayloadForImport(channelID, serverURL string) *SubscriptionPayload {
+	isActive := true
+	payload := &SubscriptionPayload{
+		ChannelID:          &channelID,
+		UserID:             &s.UserID,
+		Type:               &s.Type,
+		RecordType:         &s.RecordType,
+		RecordID:           &s.RecordID,
+		IsActive:           &isActive,
+		SubscriptionEvents: &s.SubscriptionEvents,
+		ServerURL:          &serverURL,
+	}
+
+	if s.Mentions != "" {
+		payload.Mentions = &s.Mentions
+	}
+
+	if s.ConfigurationItemID != "" {
+		payload.ConfigurationItemID = &s.ConfigurationItemID
+		payload.ConfigurationItemName = &s.ConfigurationItemName
+	}
+
+	return payload
+}
+
+// GetFormattedResult returns the summary of the result along with a table of the subscriptions which were not imported
+func (r *SubscriptionImportResult) GetFormattedResult() string {
+	var sb strings.Builder
+	duplicates := r.Count(constants.ImportStatusDuplicate)
+	failures := r.Count(constants.ImportStatusFailed)
+	if r.DryRun {
+		sb.WriteString(fmt.Sprintf("Dry run: %d subscription(s) can be imported, %d duplicate(s) and %d failure(s) found.", r.Count(constants.ImportStatusReady), duplicates, failures))
+	} else {
+		sb.WriteString(fmt.Sprintf("Imported %d subscription(s). Skipped %d duplicate(s) and %d failure(s).", r.Count(constants.ImportStatusCreated), duplicates, failures))
+	}
+
+	if duplicates+failures == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n| Subscription ID | Channel ID | Status | Error |\n| :----|:--------|:--------|:--------|")
+	for _, item := range r.Items {
+		if item.Status != constants.ImportStatusDuplicate && item.Status != constants.ImportStatusFailed {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("\n|%s|%s|%s|%s|", item.SourceID, item.ChannelID, item.Status, item.Error))
+	}
+
+	return sb.String()
+}
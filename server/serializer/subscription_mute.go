@@ -0,0 +1,88 @@
+// Copyright (c) 2022-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package serializer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-servicenow/server/constants"
+)
+
+// SubscriptionMute contains the details of a channel or a single subscription whose notifications are muted
+type SubscriptionMute struct {
+	ChannelID      string `json:"channel_id"`
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	InstanceID     string `json:"instance_id,omitempty"`
+	MutedBy        string `json:"muted_by"`
+	MutedUntil     int64  `json:"muted_until"`
+	Summary        bool   `json:"summary"`
+}
+
+// MutedEvent is a notification which was not posted as its subscription was muted
+type MutedEvent struct {
+	RecordType       string `json:"record_type"`
+	RecordID         string `json:"record_id"`
+	Number           string `json:"number"`
+	ShortDescription string `json:"short_description"`
+	Event            string `json:"event"`
+}
+
+// MutedEvents contains the notifications muted for a channel or a subscription.
+// Only the first few events are kept, but all of them are counted.
+type MutedEvents struct {
+	Events []*MutedEvent `json:"events"`
+	Count  int           `json:"count"`
+}
+
+// GetMuteKey returns the key of the mute of a subscription of an instance, or of the channel if the subscription ID is empty
+func GetMuteKey(channelID, instanceID, subscriptionID string) string {
+	if subscriptionID != "" {
+		return fmt.Sprintf("subscription/%s", GetInstanceKey(instanceID, subscriptionID))
+	}
+
+	return fmt.Sprintf("channel/%s", channelID)
+}
+
+func (m *SubscriptionMute) Key() string {
+	return GetMuteKey(m.ChannelID, m.InstanceID, m.SubscriptionID)
+}
+
+func (se *ServiceNowEvent) GetMutedEvent() *MutedEvent {
+	return &MutedEvent{
+		RecordType:       se.RecordType,
+		RecordID:         se.RecordID,
+		Number:           se.Number,
+		ShortDescription: se.ShortDescription,
+		Event:            se.EventOccurred,
+	}
+}
+
+// CreateMuteSummaryPost returns the post summarizing the notifications which were muted
+func (m *SubscriptionMute) CreateMuteSummaryPost(botID, serviceNowURL string, mutedEvents *MutedEvents) *model.Post {
+	var sb strings.Builder
+	if m.SubscriptionID != "" {
+		sb.WriteString(fmt.Sprintf("%d notification(s) of the subscription with ID %s were muted.", mutedEvents.Count, m.SubscriptionID))
+	} else {
+		sb.WriteString(fmt.Sprintf("%d notification(s) of this channel were muted.", mutedEvents.Count))
+	}
+
+	sb.WriteString("\n| Record | Short description | Event |\n| :----|:--------|:--------|")
+	for _, event := range mutedEvents.Events {
+		link := fmt.Sprintf(constants.PathRecord, serviceNowURL, event.RecordType, event.RecordID, event.RecordType)
+		sb.WriteString(fmt.Sprintf("\n|[%s](%s)|%s|%s|", event.Number, link, event.ShortDescription, constants.FormattedEventNames[event.Event]))
+	}
+
+	if hidden := mutedEvents.Count - len(mutedEvents.Events); hidden > 0 {
+		sb.WriteString(fmt.Sprintf("\n\n%d more notification(s) are not shown.", hidden))
+	}
+
+	return &model.Post{
+		ChannelId: m.ChannelID,
+		UserId:    botID,
+		Message:   sb.String(),
+	}
+}
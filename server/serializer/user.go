@@ -18,3 +18,11 @@ type User struct {
 	Username         string
 	ServiceNowUser   *ServiceNowUser
 }
+
+// ServiceAccount contains the token stored by a system admin to be used for the read-only calls made by the plugin
+type ServiceAccount struct {
+	OAuth2Token        string `json:"oauth2_token"`
+	MattermostUserID   string `json:"mattermost_user_id"`
+	ServiceNowUsername string `json:"servicenow_username"`
+	UpdatedAt          int64  `json:"updated_at"`
+}
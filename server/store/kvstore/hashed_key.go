@@ -20,6 +20,15 @@ type HashedKeyStore struct {
 
 var _ KVStore = (*HashedKeyStore)(nil)
 
+// hashedKeyPrefixes are the prefixes for which the keys are hashed instead of encoded, as the keys can be too long after encoding
+var hashedKeyPrefixes = map[string]bool{
+	constants.OAuth2KeyPrefix:             true,
+	constants.ServiceNowUsernameKeyPrefix: true,
+	constants.LookupCacheKeyPrefix:        true,
+	constants.MuteKeyPrefix:               true,
+	constants.MutedEventsKeyPrefix:        true,
+}
+
 func NewHashedKeyStore(s KVStore, prefix string) KVStore {
 	return &HashedKeyStore{
 		store:  s,
@@ -28,7 +37,7 @@ func NewHashedKeyStore(s KVStore, prefix string) KVStore {
 }
 
 func (s HashedKeyStore) Load(key string) ([]byte, error) {
-	if s.prefix == constants.OAuth2KeyPrefix {
+	if hashedKeyPrefixes[s.prefix] {
 		return s.store.Load(hashKey(s.prefix, key))
 	}
 
@@ -36,7 +45,7 @@ func (s HashedKeyStore) Load(key string) ([]byte, error) {
 }
 
 func (s HashedKeyStore) Store(key string, data []byte) error {
-	if s.prefix == constants.OAuth2KeyPrefix {
+	if hashedKeyPrefixes[s.prefix] {
 		return s.store.Store(hashKey(s.prefix, key), data)
 	}
 
@@ -44,7 +53,7 @@ func (s HashedKeyStore) Store(key string, data []byte) error {
 }
 
 func (s HashedKeyStore) StoreTTL(key string, data []byte, ttlSeconds int64) error {
-	if s.prefix == constants.OAuth2KeyPrefix {
+	if hashedKeyPrefixes[s.prefix] {
 		return s.store.StoreTTL(hashKey(s.prefix, key), data, ttlSeconds)
 	}
 
@@ -52,7 +61,7 @@ func (s HashedKeyStore) StoreTTL(key string, data []byte, ttlSeconds int64) erro
 }
 
 func (s HashedKeyStore) StoreWithOptions(key string, value []byte, opts model.PluginKVSetOptions) (bool, error) {
-	if s.prefix == constants.OAuth2KeyPrefix {
+	if hashedKeyPrefixes[s.prefix] {
 		return s.store.StoreWithOptions(hashKey(s.prefix, key), value, opts)
 	}
 
@@ -60,7 +69,7 @@ func (s HashedKeyStore) StoreWithOptions(key string, value []byte, opts model.Pl
 }
 
 func (s HashedKeyStore) Delete(key string) error {
-	if s.prefix == constants.OAuth2KeyPrefix {
+	if hashedKeyPrefixes[s.prefix] {
 		return s.store.Delete(hashKey(s.prefix, key))
 	}
 
@@ -96,6 +96,14 @@ const EventsPanel = forwardRef<HTMLDivElement, EventsPanelProps>(({
                     onChange={(selected: boolean) => handleSelectedEventsChange(selected, SubscriptionEvents.ASSIGNED_TO)}
                     className='margin-bottom-20'
                 />
+                {recordType === RecordType.REQUEST_ITEM && (
+                    <Checkbox
+                        checked={subscriptionEvents.includes(SubscriptionEvents.STAGE)}
+                        label={SubscriptionEventLabels[SubscriptionEvents.STAGE]}
+                        onChange={(selected: boolean) => handleSelectedEventsChange(selected, SubscriptionEvents.STAGE)}
+                        className='margin-bottom-20'
+                    />
+                )}
                 <Checkbox
                     checked={subscriptionEvents.includes(SubscriptionEvents.ASSIGNMENT_GROUP)}
                     label={SubscriptionEventLabels[SubscriptionEvents.ASSIGNMENT_GROUP]}
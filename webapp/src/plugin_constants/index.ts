@@ -37,13 +37,9 @@ const SubscriptionUpdatedMsg = 'Subscription updated successfully!';
 const DeleteSubscriptionHeading = 'Confirm Subscription Delete';
 const DeleteSubscriptionMsg = 'Are you sure you want to delete the subscription?';
 const RecordSharedMsg = 'Record shared successfully!';
-const StateUpdatedMsg = 'State updated successfully!';
 const CharThresholdToSuggestChannel = 0;
 const RequiredMsg = 'Required';
 const NoSubscriptionPresent = 'No more subscriptions present.';
-const CommentsHeading = 'Comments';
-const NoCommentsPresent = 'No more comments present.';
-const CommentsNotFound = 'No comments found.';
 const EmptyFieldsInServiceNow = 'N/A';
 const IncidentCreatedMsg = 'Incident created successfully!';
 const ChannelPanelToggleLabel = 'Subscribe to the new incident';
@@ -57,6 +53,7 @@ export enum SubscriptionEvents {
     COMMENTED = 'commented',
     ASSIGNED_TO = 'assigned_to',
     ASSIGNMENT_GROUP = 'assignment_group',
+    STAGE = 'stage',
 }
 
 export enum SubscriptionType {
@@ -72,6 +69,8 @@ export enum RecordType {
     TASK = 'task',
     CHANGE_TASK = 'change_task',
     FOLLOW_ON_TASK = 'cert_follow_on_task',
+    REQUEST = 'sc_request',
+    REQUEST_ITEM = 'sc_req_item',
 }
 
 export const SubscriptionEventsMap: Record<string, SubscriptionEvents> = {
@@ -81,6 +80,7 @@ export const SubscriptionEventsMap: Record<string, SubscriptionEvents> = {
     commented: SubscriptionEvents.COMMENTED,
     assigned_to: SubscriptionEvents.ASSIGNED_TO,
     assignment_group: SubscriptionEvents.ASSIGNMENT_GROUP,
+    stage: SubscriptionEvents.STAGE,
 };
 
 const SubscriptionsConfigErrorTitle = 'It seems that subscriptions for ServiceNow have not been configured properly.';
@@ -105,6 +105,8 @@ export const RecordTypeLabelMap: Record<RecordType, string> = {
     [RecordType.TASK]: 'Task',
     [RecordType.CHANGE_TASK]: 'Change Task',
     [RecordType.FOLLOW_ON_TASK]: 'Follow On Task',
+    [RecordType.REQUEST]: 'Request',
+    [RecordType.REQUEST_ITEM]: 'Requested Item',
 };
 
 const recordTypeOptions: DropdownOptionType[] = [
@@ -120,6 +122,10 @@ const recordTypeOptions: DropdownOptionType[] = [
         label: RecordTypeLabelMap[RecordType.CHANGE_REQUEST],
         value: RecordType.CHANGE_REQUEST,
     },
+    {
+        label: RecordTypeLabelMap[RecordType.REQUEST_ITEM],
+        value: RecordType.REQUEST_ITEM,
+    },
 ];
 
 const shareRecordTypeOptions: DropdownOptionType[] = recordTypeOptions.concat([
@@ -139,6 +145,10 @@ const shareRecordTypeOptions: DropdownOptionType[] = recordTypeOptions.concat([
         label: RecordTypeLabelMap[RecordType.FOLLOW_ON_TASK],
         value: RecordType.FOLLOW_ON_TASK,
     },
+    {
+        label: RecordTypeLabelMap[RecordType.REQUEST],
+        value: RecordType.REQUEST,
+    },
 ]);
 
 export enum RecordDataLabelConfigKey {
@@ -224,6 +234,7 @@ export const SubscriptionEventLabels: Record<SubscriptionEvents, string> = {
     [SubscriptionEvents.COMMENTED]: 'New comment',
     [SubscriptionEvents.ASSIGNED_TO]: 'Assigned to changed',
     [SubscriptionEvents.ASSIGNMENT_GROUP]: 'Assignment group changed',
+    [SubscriptionEvents.STAGE]: 'Stage changed',
 };
 
 // Plugin api service (RTK query) configs
@@ -268,31 +279,11 @@ const pluginApiServiceConfigs: Record<ApiServiceName, PluginApiService> = {
         method: 'GET',
         apiServiceName: 'getConfig',
     },
-    getComments: {
-        path: '/comments',
-        method: 'GET',
-        apiServiceName: 'getComments',
-    },
-    addComments: {
-        path: '/comments',
-        method: 'POST',
-        apiServiceName: 'addComments',
-    },
     shareRecord: {
         path: '/share',
         method: 'POST',
         apiServiceName: 'shareRecord',
     },
-    getStates: {
-        path: '/states',
-        method: 'GET',
-        apiServiceName: 'getStates',
-    },
-    updateState: {
-        path: '/states',
-        method: 'PATCH',
-        apiServiceName: 'updateState',
-    },
     getUsers: {
         path: '/users',
         method: 'GET',
@@ -357,15 +348,11 @@ export default {
     DeleteSubscriptionHeading,
     DeleteSubscriptionMsg,
     RecordSharedMsg,
-    StateUpdatedMsg,
     CharThresholdToSuggestChannel,
     RequiredMsg,
     recordTypeOptions,
     shareRecordTypeOptions,
     NoSubscriptionPresent,
-    CommentsHeading,
-    NoCommentsPresent,
-    CommentsNotFound,
     SubscriptionFilters,
     DefaultSubscriptionFilters,
     SubscriptionFilterCreatedByOptions,
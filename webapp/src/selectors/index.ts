@@ -9,8 +9,4 @@ export const isEditSubscriptionModalOpen = (state: PluginState): boolean => stat
 
 export const isShareRecordModalOpen = (state: PluginState): boolean => state.globalModalReducer.modalId === 'shareRecord';
 
-export const isCommentModalOpen = (state: PluginState): boolean => state.globalModalReducer.modalId === 'addOrViewComments';
-
-export const isUpdateStateModalOpen = (state: PluginState): boolean => state.globalModalReducer.modalId === 'updateState';
-
 export const isCreateIncidentModalOpen = (state: PluginState): boolean => state.globalModalReducer.modalId === 'createIncident';
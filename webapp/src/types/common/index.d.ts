@@ -6,7 +6,7 @@
 */
 
 // TODO: Create an enum for the below modal Ids
-type ModalId = 'addSubscription' | 'editSubscription' | 'shareRecord' | 'addOrViewComments' | 'updateState' | 'createIncident' | null
+type ModalId = 'addSubscription' | 'editSubscription' | 'shareRecord' | 'createIncident' | null
 type SubscriptionType = import('../../plugin_constants').SubscriptionType;
 type RecordType = import('../../plugin_constants').RecordType;
 
@@ -85,11 +85,6 @@ type LinkData = {
     link: string;
 }
 
-type StateData = {
-    label: string;
-    value: string;
-}
-
 type DropdownOptionType = {
     label?: string | JSX.Element;
     value: string;
@@ -20,16 +20,6 @@ type GetRecordParams = {
     recordId: string;
 }
 
-type GetStatesParams = {
-    recordType: RecordType;
-}
-
-type UpdateStatePayload = {
-    recordType: RecordType;
-    recordId: string;
-    state: string;
-}
-
 type CreateSubscriptionPayload = {
     server_url: string;
     is_active: boolean;
@@ -62,12 +52,6 @@ type EditSubscriptionPayload = {
     record_number: string;
 }
 
-type CommentsPayload = {
-    record_type: string;
-    record_id: string;
-    comments?: string;
-}
-
 type ShareRecordPayload = {
     record_type: ShareRecordType;
     sys_id: string;
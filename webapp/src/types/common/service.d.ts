@@ -13,10 +13,6 @@ type ApiServiceName =
     'deleteSubscription' |
     'getConfig' |
     'shareRecord' |
-    'getComments' |
-    'addComments' |
-    'getStates' |
-    'updateState' |
     'getUsers' |
     'createIncident' |
     'getConnectedUser';
@@ -40,7 +36,4 @@ type APIPayloadType =
     FetchSubscriptionsParams |
     EditSubscriptionPayload |
     ShareRecordPayload |
-    CommentsPayload |
-    GetStatesParams |
-    UpdateStateParams |
     string;
@@ -9,12 +9,7 @@ type ReduxState = {
 
 type GlobalModalState = {
     modalId: ModalId;
-    data?: EditSubscriptionData | CommentAndStateModalData | IncidentModalData | null;
-}
-
-type CommentModalState = {
-    open: boolean;
-    data?: CommentAndStateModalData;
+    data?: EditSubscriptionData | IncidentModalData | null;
 }
 
 type ConnectedState = {
@@ -34,16 +29,6 @@ type ShareRecordModalState = {
     open: boolean;
 }
 
-type UpdateStateModalReduxState = {
-    open: boolean;
-    data?: CommentAndStateModalData;
-}
-
-type CommentAndStateModalData = {
-    recordType: RecordType;
-    recordId: string;
-}
-
 type IncidentModalData = {
     description: string;
     senderId: string;
@@ -74,31 +74,3 @@ export function handleOpenShareRecordModal(store: Store<GlobalState, Action<Reco
         store.dispatch(setGlobalModalState({modalId: 'shareRecord'}) as Action);
     };
 }
-
-export function handleOpenCommentModal(store: Store<GlobalState, Action<Record<string, unknown>>>) {
-    return (msg: WebsocketEventParams) => {
-        const {data} = msg;
-        const commentModalData: CommentAndStateModalData = {
-            recordType: data.record_type as RecordType,
-            recordId: data.record_id,
-        };
-        store.dispatch(setGlobalModalState({modalId: 'addOrViewComments', data: commentModalData}) as Action);
-    };
-}
-
-export function handleOpenUpdateStateModal(store: Store<GlobalState, Action<Record<string, unknown>>>) {
-    return (msg: WebsocketEventParams) => {
-        const {data} = msg;
-        const updateStateModalData: CommentAndStateModalData = {
-            recordType: data.record_type as RecordType,
-            recordId: data.record_id,
-        };
-        store.dispatch(setGlobalModalState({modalId: 'updateState', data: updateStateModalData}) as Action);
-    };
-}
-
-export function handleOpenIncidentModal(store: Store<GlobalState, Action<Record<string, unknown>>>) {
-    return (_: WebsocketEventParams) => {
-        store.dispatch(setGlobalModalState({modalId: 'createIncident'}) as Action);
-    };
-}